@@ -58,6 +58,8 @@ type StackConfig struct {
 type LifecycleConfig struct {
 	URI     string `toml:"uri"`
 	Version string `toml:"version"`
+	// SHA256 is the checksum the lifecycle tarball must match
+	SHA256 string `toml:"sha256"`
 }
 
 // RunConfig set of run image configuration
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"path/filepath"
+
 	"github.com/heroku/color"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -13,6 +15,7 @@ import (
 	"github.com/buildpacks/pack/internal/term"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
 )
 
 // ConfigurableLogger defines behavior required by the PackCommand
@@ -33,7 +36,13 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 		return nil, err
 	}
 
-	packClient, err := initClient(logger, cfg)
+	packHome, err := config.PackHome()
+	if err != nil {
+		return nil, err
+	}
+
+	localMirror := mirror.New(filepath.Join(packHome, "mirror"))
+	packClient, err := initClient(logger, cfg, localMirror)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +71,9 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 				if flag, err := fs.GetBool("timestamps"); err == nil {
 					logger.WantTime(flag)
 				}
+				if flag, err := fs.GetBool("offline"); err == nil {
+					localMirror.SetOffline(flag)
+				}
 			}
 		},
 	}
@@ -71,12 +83,16 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	rootCmd.PersistentFlags().Bool("timestamps", false, "Enable timestamps in output")
 	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Show less output")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Show more output")
+	rootCmd.PersistentFlags().Bool("offline", false, "Resolve images, downloads and registry lookups from the local mirror instead of the network")
 	rootCmd.Flags().Bool("version", false, "Show current 'pack' version")
 
 	commands.AddHelpFlag(rootCmd, "pack")
 
 	rootCmd.AddCommand(commands.Build(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.Detect(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.Run(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewBuilderCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.NewCacheCommand(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewBuildpackCommand(logger, cfg, packClient, buildpackage.NewConfigReader()))
 	rootCmd.AddCommand(commands.NewExtensionCommand(logger, cfg, packClient, buildpackage.NewConfigReader()))
 	rootCmd.AddCommand(commands.NewConfigCommand(logger, cfg, cfgPath, packClient))
@@ -84,6 +100,8 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 	rootCmd.AddCommand(commands.NewStackCommand(logger))
 	rootCmd.AddCommand(commands.Rebase(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.NewSBOMCommand(logger, cfg, packClient))
+	rootCmd.AddCommand(commands.Lint(logger, packClient.Version()))
+	rootCmd.AddCommand(commands.NewMirrorCommand(logger, cfg, packClient))
 
 	rootCmd.AddCommand(commands.InspectBuildpack(logger, cfg, packClient))
 	rootCmd.AddCommand(commands.InspectBuilder(logger, cfg, packClient, builderwriter.NewFactory()))
@@ -107,11 +125,6 @@ func NewPackCommand(logger ConfigurableLogger) (*cobra.Command, error) {
 		rootCmd.AddCommand(commands.NewManifestCommand(logger, packClient))
 	}
 
-	packHome, err := config.PackHome()
-	if err != nil {
-		return nil, err
-	}
-
 	rootCmd.AddCommand(commands.CompletionCommand(logger, packHome))
 	rootCmd.AddCommand(commands.Report(logger, packClient.Version(), cfgPath))
 	rootCmd.AddCommand(commands.Version(logger, packClient.Version()))
@@ -137,7 +150,7 @@ func initConfig() (config.Config, string, error) {
 	return cfg, path, nil
 }
 
-func initClient(logger logging.Logger, cfg config.Config) (*client.Client, error) {
+func initClient(logger logging.Logger, cfg config.Config, localMirror *mirror.Mirror) (*client.Client, error) {
 	if err := client.ProcessDockerContext(logger); err != nil {
 		return nil, err
 	}
@@ -149,8 +162,8 @@ func initClient(logger logging.Logger, cfg config.Config) (*client.Client, error
 
 	// If we got a docker client from SSH, use it directly
 	if dc != nil {
-		return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithDockerClient(dc))
+		return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithMirror(localMirror), client.WithDockerClient(dc))
 	}
 
-	return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors))
+	return client.NewClient(client.WithLogger(logger), client.WithExperimental(cfg.Experimental), client.WithRegistryMirrors(cfg.RegistryMirrors), client.WithMirror(localMirror))
 }
@@ -1,14 +1,17 @@
 package build
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle/buildpack"
 	"github.com/buildpacks/lifecycle/platform/files"
 	dcontainer "github.com/moby/moby/api/types/container"
 	dockerClient "github.com/moby/moby/client"
@@ -22,6 +25,7 @@ import (
 	"github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/events"
 )
 
 type ContainerOperation func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error
@@ -95,6 +99,78 @@ func CopyOutToMaybe(src, dest string) ContainerOperation {
 	}, src)
 }
 
+// DownloadCache copies the contents of a synced cache from remote storage into the container at dst.
+// Nothing is copied if no cache has been stored yet.
+func DownloadCache(syncedCache SyncedCache, dst string) ContainerOperation {
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		reader, err := syncedCache.Download(ctx)
+		if err != nil {
+			return errors.Wrap(err, "downloading cache")
+		}
+		if reader == nil {
+			return nil
+		}
+		defer reader.Close()
+
+		_, err = ctrClient.CopyToContainer(ctx, containerID, dockerClient.CopyToContainerOptions{
+			DestinationPath: dst,
+			Content:         reader,
+		})
+		return errors.Wrap(err, "copying cache into container")
+	}
+}
+
+// UploadCache copies the container directory src to the remote storage of a synced cache.
+func UploadCache(syncedCache SyncedCache, src string) ContainerOperation {
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		result, err := ctrClient.CopyFromContainer(ctx, containerID, dockerClient.CopyFromContainerOptions{SourcePath: src})
+		if err != nil {
+			return errors.Wrap(err, "copying cache from container")
+		}
+		defer result.Content.Close()
+
+		return errors.Wrap(syncedCache.Upload(ctx, result.Content), "uploading cache")
+	}
+}
+
+// EmitDetectedGroup reports the group of buildpacks and extensions the detector selected, read from the group.toml
+// at groupPath, to handler. With passed set, each of them is also reported as passing detection, for phases that
+// don't log the result of each buildpack, which the detector only does at debug level.
+func EmitDetectedGroup(handler events.Handler, phase, groupPath string, passed bool) ContainerOperation {
+	return func(ctrClient DockerClient, ctx context.Context, containerID string, stdout, stderr io.Writer) error {
+		result, err := ctrClient.CopyFromContainer(ctx, containerID, dockerClient.CopyFromContainerOptions{SourcePath: groupPath})
+		if err != nil {
+			return errors.Wrap(err, "copying group.toml from container")
+		}
+		defer result.Content.Close()
+
+		tr := tar.NewReader(result.Content)
+		if _, err := tr.Next(); err != nil {
+			return errors.Wrap(err, "reading group.toml")
+		}
+		var group buildpack.Group
+		if _, err := toml.NewDecoder(tr).Decode(&group); err != nil {
+			return errors.Wrap(err, "decoding group.toml")
+		}
+
+		var members []string
+		for _, el := range group.Group {
+			members = append(members, el.ID+"@"+el.Version)
+		}
+		for _, el := range group.GroupExtensions {
+			members = append(members, el.ID+"@"+el.Version)
+		}
+
+		if passed {
+			for _, member := range members {
+				handler(events.Event{Type: events.BuildpackDetected, Time: time.Now(), Phase: phase, Buildpack: member, Result: events.ResultPass})
+			}
+		}
+		handler(events.Event{Type: events.GroupSelected, Time: time.Now(), Phase: phase, Buildpacks: members})
+		return nil
+	}
+}
+
 // CopyDir copies a local directory (src) to the destination on the container while filtering files and changing it's UID/GID.
 // if includeRoot is set the UID/GID will be set on the dst directory.
 func CopyDir(src, dst string, uid, gid int, os string, includeRoot bool, fileFilter func(string) bool) ContainerOperation {
@@ -0,0 +1,141 @@
+package build
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/pack/pkg/events"
+)
+
+var (
+	detectResultsRegex   = regexp.MustCompile(`^======== Results ========$`)
+	detectOutputRegex    = regexp.MustCompile(`^======== (?:Output|Error): (\S+@\S+) ========$`)
+	detectResultRegex    = regexp.MustCompile(`^(pass|fail|skip|err):\s+(\S+@\S+)`)
+	restoringRegex       = regexp.MustCompile(`^Restoring (?:data|metadata) for "([^"]+)" from (cache|app image)$`)
+	reusingLayerRegex    = regexp.MustCompile(`^Reusing (cache )?layer '([^']+)'$`)
+	addingLayerRegex     = regexp.MustCompile(`^Adding (cache )?layer '([^']+)'$`)
+	layerCacheMissingMsg = "Layer cache not found"
+)
+
+// eventWriter passes phase output through to the underlying writer while
+// turning recognised lifecycle log lines into events
+type eventWriter struct {
+	out     io.Writer
+	phase   string
+	handler events.Handler
+	buf     bytes.Buffer
+
+	group int
+
+	// detect output is logged for every buildpack in a group before the group results
+	outputs       map[string]*strings.Builder
+	outputFor     string
+	resultsLogged bool
+}
+
+func newEventWriter(out io.Writer, phase string, handler events.Handler) *eventWriter {
+	return &eventWriter{
+		out:     out,
+		phase:   phase,
+		handler: handler,
+	}
+}
+
+func (w *eventWriter) Write(data []byte) (int, error) {
+	w.buf.Write(data)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// incomplete line, keep it for the next write
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+		w.parse(line)
+	}
+	return w.out.Write(data)
+}
+
+// Close parses any pending output and closes the underlying writer if possible
+func (w *eventWriter) Close() error {
+	if w.buf.Len() > 0 {
+		w.parse(w.buf.String())
+		w.buf.Reset()
+	}
+	if closer, ok := w.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (w *eventWriter) parse(line string) {
+	if m := detectOutputRegex.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+		if w.resultsLogged || w.outputs == nil {
+			w.outputs = map[string]*strings.Builder{}
+			w.resultsLogged = false
+		}
+		w.outputFor = m[1]
+		if _, ok := w.outputs[w.outputFor]; !ok {
+			w.outputs[w.outputFor] = &strings.Builder{}
+		}
+		return
+	}
+	if w.outputFor != "" {
+		if !detectResultsRegex.MatchString(strings.TrimSpace(line)) {
+			w.outputs[w.outputFor].WriteString(line)
+			return
+		}
+		w.outputFor = ""
+	}
+
+	line = strings.TrimSpace(line)
+
+	switch {
+	case detectResultsRegex.MatchString(line):
+		if w.resultsLogged {
+			// no output was logged for this group
+			w.outputs = nil
+		}
+		w.group++
+		w.resultsLogged = true
+	case line == layerCacheMissingMsg:
+		w.emit(events.Event{Type: events.CacheMissed})
+	default:
+		if m := detectResultRegex.FindStringSubmatch(line); m != nil {
+			result := m[1]
+			if result == "err" {
+				result = events.ResultError
+			}
+			w.emit(events.Event{Type: events.BuildpackDetected, Group: w.group, Buildpack: m[2], Result: result, Output: w.detectOutput(m[2])})
+		} else if m := restoringRegex.FindStringSubmatch(line); m != nil {
+			source := events.SourceCache
+			if m[2] == "app image" {
+				source = events.SourceAppImage
+			}
+			w.emit(events.Event{Type: events.LayerRestored, Layer: m[1], Source: source})
+		} else if m := reusingLayerRegex.FindStringSubmatch(line); m != nil {
+			w.emit(events.Event{Type: events.LayerReused, Layer: m[2], Cache: m[1] != ""})
+		} else if m := addingLayerRegex.FindStringSubmatch(line); m != nil {
+			w.emit(events.Event{Type: events.LayerAdded, Layer: m[2], Cache: m[1] != ""})
+		}
+	}
+}
+
+func (w *eventWriter) detectOutput(buildpack string) string {
+	if !w.resultsLogged {
+		return ""
+	}
+	if output, ok := w.outputs[buildpack]; ok {
+		return strings.TrimRight(output.String(), "\n")
+	}
+	return ""
+}
+
+func (w *eventWriter) emit(e events.Event) {
+	e.Time = time.Now()
+	e.Phase = w.phase
+	w.handler(e)
+}
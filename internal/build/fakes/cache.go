@@ -2,6 +2,7 @@ package fakes
 
 import (
 	"context"
+	"io"
 
 	"github.com/buildpacks/pack/pkg/cache"
 )
@@ -33,3 +34,38 @@ func (f *FakeCache) Name() string {
 	f.NameCallCount++
 	return f.ReturnForName
 }
+
+type FakeSyncedCache struct {
+	FakeCache
+
+	ReturnForDownload io.ReadCloser
+	ErrorForDownload  error
+	ErrorForUpload    error
+
+	DownloadCallCount     int
+	ClearStagingCallCount int
+	Uploaded              []byte
+}
+
+func NewFakeSyncedCache() *FakeSyncedCache {
+	return &FakeSyncedCache{}
+}
+
+func (f *FakeSyncedCache) Download(ctx context.Context) (io.ReadCloser, error) {
+	f.DownloadCallCount++
+	return f.ReturnForDownload, f.ErrorForDownload
+}
+
+func (f *FakeSyncedCache) Upload(ctx context.Context, content io.Reader) error {
+	var err error
+	f.Uploaded, err = io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	return f.ErrorForUpload
+}
+
+func (f *FakeSyncedCache) ClearStaging(ctx context.Context) error {
+	f.ClearStagingCallCount++
+	return nil
+}
@@ -5,6 +5,13 @@ import "context"
 type FakePhase struct {
 	CleanupCallCount int
 	RunCallCount     int
+
+	ReturnForExitCode       int
+	ReturnForBytesCopiedIn  int64
+	ReturnForBytesCopiedOut int64
+
+	// RunFunc, when set, is called by Run
+	RunFunc func(ctx context.Context) error
 }
 
 func (p *FakePhase) Cleanup() error {
@@ -16,5 +23,12 @@ func (p *FakePhase) Cleanup() error {
 func (p *FakePhase) Run(ctx context.Context) error {
 	p.RunCallCount++
 
+	if p.RunFunc != nil {
+		return p.RunFunc(ctx)
+	}
 	return nil
 }
+
+func (p *FakePhase) Report() (int, int64, int64) {
+	return p.ReturnForExitCode, p.ReturnForBytesCopiedIn, p.ReturnForBytesCopiedOut
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -22,7 +23,9 @@ import (
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/metrics"
 )
 
 const (
@@ -42,6 +45,8 @@ type LifecycleExecution struct {
 	mountPaths   mountPaths
 	opts         LifecycleOptions
 	tmpDir       string
+	metricsMu    sync.Mutex
+	metrics      metrics.Build
 }
 
 func NewLifecycleExecution(logger logging.Logger, docker DockerClient, tmpDir string, opts LifecycleOptions) (*LifecycleExecution, error) {
@@ -141,7 +146,7 @@ func (l *LifecycleExecution) AppPath() string {
 	return l.opts.AppPath
 }
 
-func (l LifecycleExecution) AppDir() string {
+func (l *LifecycleExecution) AppDir() string {
 	return l.mountPaths.appDir()
 }
 
@@ -167,46 +172,74 @@ func (l *LifecycleExecution) PrevImageName() string {
 
 const maxNetworkRemoveRetries = 2
 
+// Metrics returns the metrics recorded for the phases run so far. Phases still running have an exit code of -1.
+func (l *LifecycleExecution) Metrics() metrics.Build {
+	l.metricsMu.Lock()
+	defer l.metricsMu.Unlock()
+
+	m := l.metrics
+	m.Phases = append([]metrics.Phase(nil), l.metrics.Phases...)
+	return m
+}
+
 func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseFactoryCreator) error {
-	phaseFactory := phaseFactoryCreator(l)
+	start := time.Now()
+	l.metricsMu.Lock()
+	l.metrics.StartTime = start
+	l.metricsMu.Unlock()
 
-	var buildCache Cache
-	if l.opts.CacheImage != "" || (l.opts.Cache.Build.Format == cache.CacheImage) {
-		cacheImageName := l.opts.CacheImage
-		if cacheImageName == "" {
-			cacheImageName = l.opts.Cache.Build.Source
-		}
-		cacheImage, err := name.ParseReference(cacheImageName, name.WeakValidation)
-		if err != nil {
-			return fmt.Errorf("invalid cache image name: %s", err)
-		}
-		buildCache = cache.NewImageCache(cacheImage, l.docker)
-	} else {
-		switch l.opts.Cache.Build.Format {
-		case cache.CacheVolume:
-			var err error
-			buildCache, err = cache.NewVolumeCache(l.opts.Image, l.opts.Cache.Build, "build", l.docker, l.logger)
+	err := l.run(ctx, phaseFactoryCreator)
+
+	l.metricsMu.Lock()
+	l.metrics.DurationMS = time.Since(start).Milliseconds()
+	l.metricsMu.Unlock()
+
+	buildMetrics := l.Metrics()
+	if l.opts.MetricsHandler != nil {
+		l.opts.MetricsHandler(buildMetrics)
+	}
+	if l.opts.ReportDestinationDir != "" {
+		if writeErr := buildMetrics.WriteToDir(l.opts.ReportDestinationDir); writeErr != nil {
 			if err != nil {
+				l.logger.Warnf("failed to write %s: %s", metrics.FileName, writeErr)
 				return err
 			}
-			l.logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
-		case cache.CacheBind:
-			buildCache = cache.NewBindCache(l.opts.Cache.Build, l.docker)
-			l.logger.Debugf("Using build cache dir %s", style.Symbol(buildCache.Name()))
+			return errors.Wrapf(writeErr, "writing %s", metrics.FileName)
 		}
 	}
+	return err
+}
+
+func (l *LifecycleExecution) run(ctx context.Context, phaseFactoryCreator PhaseFactoryCreator) error {
+	phaseFactory := phaseFactoryCreator(l)
+
+	buildCache, err := NewBuildCache(l.opts, l.docker, l.logger)
+	if err != nil {
+		return err
+	}
+	if buildCache.Type() == cache.S3 && l.os == "windows" {
+		return errors.New("s3 build cache is not supported for Windows builds")
+	}
 
 	if l.opts.ClearCache {
 		if err := buildCache.Clear(ctx); err != nil {
 			return errors.Wrap(err, "clearing build cache")
 		}
 		l.logger.Debugf("Build cache %s cleared", style.Symbol(buildCache.Name()))
+	} else if syncedCache, ok := buildCache.(SyncedCache); ok {
+		// the staging volume is kept between builds, and is emptied so it only holds what's restored from remote
+		// storage, rather than files since removed from it
+		if err := syncedCache.ClearStaging(ctx); err != nil {
+			return errors.Wrap(err, "clearing build cache staging volume")
+		}
 	}
 
 	launchCache, err := cache.NewVolumeCache(l.opts.Image, l.opts.Cache.Launch, "launch", l.docker, l.logger)
 	if err != nil {
 		return err
 	}
+	l.recordCacheUse(buildCache, "build")
+	l.recordCacheUse(launchCache, "launch")
 
 	if l.opts.Network == "" {
 		// start an ephemeral bridge network
@@ -260,6 +293,10 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 			}
 		}
 
+		if l.opts.DetectOnly {
+			return nil
+		}
+
 		var kanikoCache Cache
 		if l.PlatformAPI().AtLeast("0.12") {
 			// lifecycle 0.17.0 (introduces support for Platform API 0.12) and above will ensure that
@@ -269,6 +306,7 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 			if err != nil {
 				return err
 			}
+			l.recordCacheUse(kanikoCache, "kaniko")
 		} else {
 			switch {
 			case buildCache.Type() == cache.Volume:
@@ -339,6 +377,10 @@ func (l *LifecycleExecution) Run(ctx context.Context, phaseFactoryCreator PhaseF
 			return err
 		}
 
+		if l.opts.BuildOnly {
+			return nil
+		}
+
 		l.logger.Info(style.Step("EXPORTING"))
 		return l.Export(ctx, buildCache, launchCache, kanikoCache, phaseFactory)
 	}
@@ -423,7 +465,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, buildCache, launchCache
 	case cache.Image:
 		flags = append(flags, "-cache-image", buildCache.Name())
 		cacheBindOp = WithBinds(l.opts.Volumes...)
-	case cache.Volume, cache.Bind:
+	case cache.Volume, cache.Bind, cache.S3:
 		cacheBindOp = WithBinds(append(l.opts.Volumes, fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))...)
 	}
 
@@ -437,6 +479,8 @@ func (l *LifecycleExecution) Create(ctx context.Context, buildCache, launchCache
 		WithArgs(l.opts.Image.String()),
 		WithNetwork(l.opts.Network),
 		cacheBindOp,
+		l.withCacheDownload(buildCache),
+		l.withCacheUpload(buildCache),
 		WithContainerOperations(WriteProjectMetadata(l.mountPaths.projectPath(), l.opts.ProjectMetadata, l.os)),
 		WithContainerOperations(CopyDir(l.opts.AppPath, l.mountPaths.appDir(), l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, true, l.opts.FileFilter)),
 		If(l.opts.SBOMDestinationDir != "", WithPostContainerRunOperations(
@@ -448,6 +492,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, buildCache, launchCache
 		If(l.opts.Interactive, WithPostContainerRunOperations(
 			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
 			CopyOut(l.opts.Termui.ReadLayers, l.mountPaths.layersDir(), l.mountPaths.appDir()))),
+		l.withDetectedGroupEvents("creator", l.logger.IsVerbose()),
 		withEnv,
 	}
 
@@ -475,8 +520,7 @@ func (l *LifecycleExecution) Create(ctx context.Context, buildCache, launchCache
 	}
 
 	create := phaseFactory.New(NewPhaseConfigProvider("creator", l, opts...))
-	defer create.Cleanup()
-	return create.Run(ctx)
+	return l.runPhase(ctx, "creator", create)
 }
 
 func (l *LifecycleExecution) Detect(ctx context.Context, phaseFactory PhaseFactory) error {
@@ -492,7 +536,7 @@ func (l *LifecycleExecution) Detect(ctx context.Context, phaseFactory PhaseFacto
 		l,
 		WithLogPrefix("detector"),
 		WithArgs(
-			l.withLogLevel()...,
+			l.detectLogLevel()...,
 		),
 		WithNetwork(l.opts.Network),
 		WithBinds(l.opts.Volumes...),
@@ -505,12 +549,16 @@ func (l *LifecycleExecution) Detect(ctx context.Context, phaseFactory PhaseFacto
 			CopyOutToMaybe(filepath.Join(l.mountPaths.layersDir(), "analyzed.toml"), l.tmpDir))),
 		If(l.hasExtensions(), WithPostContainerRunOperations(
 			CopyOutToMaybe(filepath.Join(l.mountPaths.layersDir(), "generated"), l.tmpDir))),
+		If(l.opts.DetectDestinationDir != "", WithPostContainerRunOperations(
+			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
+			CopyOutTo(l.mountPaths.groupPath(), l.opts.DetectDestinationDir),
+			CopyOutTo(l.mountPaths.planPath(), l.opts.DetectDestinationDir))),
+		l.withDetectedGroupEvents("detector", l.opts.DetectOnly || l.logger.IsVerbose()),
 		envOp,
 	)
 
 	detect := phaseFactory.New(configProvider)
-	defer detect.Cleanup()
-	return detect.Run(ctx)
+	return l.runPhase(ctx, "detector", detect)
 }
 
 func (l *LifecycleExecution) extensionsAreExperimental() bool {
@@ -531,7 +579,7 @@ func (l *LifecycleExecution) Restore(ctx context.Context, buildCache Cache, kani
 	case cache.Image:
 		flags = append(flags, "-cache-image", buildCache.Name())
 		registryImages = append(registryImages, buildCache.Name())
-	case cache.Volume:
+	case cache.Volume, cache.S3:
 		flags = append(flags, "-cache-dir", l.mountPaths.cacheDir())
 		cacheBindOp = WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
 	}
@@ -605,6 +653,8 @@ func (l *LifecycleExecution) Restore(ctx context.Context, buildCache Cache, kani
 		),
 		WithNetwork(l.opts.Network),
 		cacheBindOp,
+		// older analyzers read the cache, so it has already been downloaded
+		If(l.platformAPI.AtLeast("0.7"), l.withCacheDownload(buildCache)),
 		dockerOp,
 		flagsOp,
 		kanikoCacheBindOp,
@@ -616,8 +666,7 @@ func (l *LifecycleExecution) Restore(ctx context.Context, buildCache Cache, kani
 	)
 
 	restore := phaseFactory.New(configProvider)
-	defer restore.Cleanup()
-	return restore.Run(ctx)
+	return l.runPhase(ctx, "restorer", restore)
 }
 
 func (l *LifecycleExecution) Analyze(ctx context.Context, buildCache, launchCache Cache, phaseFactory PhaseFactory) error {
@@ -626,6 +675,7 @@ func (l *LifecycleExecution) Analyze(ctx context.Context, buildCache, launchCach
 	platformAPILessThan07 := l.platformAPI.LessThan("0.7")
 
 	cacheBindOp := NullOp()
+	cacheDownloadOp := NullOp()
 	if l.opts.ClearCache {
 		if platformAPILessThan07 || l.platformAPI.AtLeast("0.9") {
 			args = prependArg("-skip-layers", args)
@@ -634,10 +684,11 @@ func (l *LifecycleExecution) Analyze(ctx context.Context, buildCache, launchCach
 		switch buildCache.Type() {
 		case cache.Image:
 			flags = append(flags, "-cache-image", buildCache.Name())
-		case cache.Volume:
+		case cache.Volume, cache.S3:
 			if platformAPILessThan07 {
 				args = append([]string{"-cache-dir", l.mountPaths.cacheDir()}, args...)
 				cacheBindOp = WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
+				cacheDownloadOp = l.withCacheDownload(buildCache)
 			}
 		}
 	}
@@ -736,6 +787,7 @@ func (l *LifecycleExecution) Analyze(ctx context.Context, buildCache, launchCach
 			WithNetwork(l.opts.Network),
 			flagsOp,
 			cacheBindOp,
+			cacheDownloadOp,
 			stackOp,
 			runOp,
 			layoutOp,
@@ -759,6 +811,7 @@ func (l *LifecycleExecution) Analyze(ctx context.Context, buildCache, launchCach
 			flagsOp,
 			WithNetwork(l.opts.Network),
 			cacheBindOp,
+			cacheDownloadOp,
 			stackOp,
 			runOp,
 		)
@@ -766,8 +819,7 @@ func (l *LifecycleExecution) Analyze(ctx context.Context, buildCache, launchCach
 		analyze = phaseFactory.New(configProvider)
 	}
 
-	defer analyze.Cleanup()
-	return analyze.Run(ctx)
+	return l.runPhase(ctx, "analyzer", analyze)
 }
 
 func (l *LifecycleExecution) Build(ctx context.Context, phaseFactory PhaseFactory) error {
@@ -780,11 +832,13 @@ func (l *LifecycleExecution) Build(ctx context.Context, phaseFactory PhaseFactor
 		WithNetwork(l.opts.Network),
 		WithBinds(l.opts.Volumes...),
 		WithFlags(flags...),
+		If(l.opts.BuildDestinationDir != "", WithPostContainerRunOperations(
+			EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
+			CopyOutTo(l.mountPaths.layersDir(), l.opts.BuildDestinationDir))),
 	)
 
 	build := phaseFactory.New(configProvider)
-	defer build.Cleanup()
-	return build.Run(ctx)
+	return l.runPhase(ctx, "builder", build)
 }
 
 func (l *LifecycleExecution) ExtendBuild(ctx context.Context, kanikoCache Cache, phaseFactory PhaseFactory, experimental bool) error {
@@ -804,8 +858,7 @@ func (l *LifecycleExecution) ExtendBuild(ctx context.Context, kanikoCache Cache,
 	)
 
 	extend := phaseFactory.New(configProvider)
-	defer extend.Cleanup()
-	return extend.Run(ctx)
+	return l.runPhase(ctx, "extender (build)", extend)
 }
 
 func (l *LifecycleExecution) ExtendRun(ctx context.Context, kanikoCache Cache, phaseFactory PhaseFactory, runImageName string, experimental bool) error {
@@ -826,8 +879,7 @@ func (l *LifecycleExecution) ExtendRun(ctx context.Context, kanikoCache Cache, p
 	)
 
 	extend := phaseFactory.New(configProvider)
-	defer extend.Cleanup()
-	return extend.Run(ctx)
+	return l.runPhase(ctx, "extender (run)", extend)
 }
 
 func determineDefaultProcessType(platformAPI *api.Version, providedValue string) string {
@@ -883,7 +935,7 @@ func (l *LifecycleExecution) Export(ctx context.Context, buildCache, launchCache
 	switch buildCache.Type() {
 	case cache.Image:
 		flags = append(flags, "-cache-image", buildCache.Name())
-	case cache.Volume:
+	case cache.Volume, cache.S3:
 		cacheBindOp = WithBinds(fmt.Sprintf("%s:%s", buildCache.Name(), l.mountPaths.cacheDir()))
 	}
 
@@ -907,6 +959,7 @@ func (l *LifecycleExecution) Export(ctx context.Context, buildCache, launchCache
 		WithNetwork(l.opts.Network),
 		cacheBindOp,
 		kanikoCacheBindOp,
+		l.withCacheUpload(buildCache),
 		WithContainerOperations(WriteStackToml(l.mountPaths.stackPath(), l.opts.Builder.Stack(), l.os)),
 		WithContainerOperations(WriteRunToml(l.mountPaths.runPath(), l.opts.Builder.RunImages(), l.os)),
 		WithContainerOperations(WriteProjectMetadata(l.mountPaths.projectPath(), l.opts.ProjectMetadata, l.os)),
@@ -955,8 +1008,65 @@ func (l *LifecycleExecution) Export(ctx context.Context, buildCache, launchCache
 		export = phaseFactory.New(NewPhaseConfigProvider("exporter", l, opts...))
 	}
 
-	defer export.Cleanup()
-	return export.Run(ctx)
+	return l.runPhase(ctx, "exporter", export)
+}
+
+// runPhase runs the given phase, recording its metrics and emitting events marking its start and finish, and cleans it up afterwards
+func (l *LifecycleExecution) runPhase(ctx context.Context, name string, phase RunnerCleaner) error {
+	defer phase.Cleanup()
+
+	l.emit(events.Event{Type: events.PhaseStarted, Phase: name})
+	start := time.Now()
+
+	// phases run concurrently are recorded in the order they started
+	phaseMetrics := metrics.Phase{Name: name, StartTime: start, ExitCode: -1}
+	l.metricsMu.Lock()
+	slot := len(l.metrics.Phases)
+	l.metrics.Phases = append(l.metrics.Phases, phaseMetrics)
+	l.metricsMu.Unlock()
+
+	err := phase.Run(ctx)
+	duration := time.Since(start).Milliseconds()
+
+	phaseMetrics.DurationMS = duration
+	if reporter, ok := phase.(PhaseReporter); ok {
+		phaseMetrics.ExitCode, phaseMetrics.BytesCopiedIn, phaseMetrics.BytesCopiedOut = reporter.Report()
+	}
+	finished := events.Event{Type: events.PhaseFinished, Phase: name, DurationMS: duration}
+	if err != nil {
+		phaseMetrics.Error = err.Error()
+		finished.Error = err.Error()
+	}
+
+	l.metricsMu.Lock()
+	l.metrics.Phases[slot] = phaseMetrics
+	l.metricsMu.Unlock()
+	l.emit(finished)
+
+	return err
+}
+
+func (l *LifecycleExecution) emit(e events.Event) {
+	if l.opts.EventHandler == nil {
+		return
+	}
+	e.Time = time.Now()
+	l.opts.EventHandler(e)
+}
+
+// outputEventHandler returns the handler of the events parsed from the output of the phases, if any
+func (l *LifecycleExecution) outputEventHandler() events.Handler {
+	if l.opts.LayerHandler == nil {
+		return l.opts.EventHandler
+	}
+	return func(e events.Event) {
+		if l.opts.EventHandler != nil {
+			l.opts.EventHandler(e)
+		}
+		if e.Type == events.LayerAdded || e.Type == events.LayerReused {
+			l.opts.LayerHandler(e)
+		}
+	}
 }
 
 func (l *LifecycleExecution) withLogLevel(args ...string) []string {
@@ -966,10 +1076,86 @@ func (l *LifecycleExecution) withLogLevel(args ...string) []string {
 	return args
 }
 
+func (l *LifecycleExecution) detectLogLevel() []string {
+	if l.opts.DetectOnly && !l.logger.IsVerbose() {
+		return []string{"-log-level", "debug"}
+	}
+	return l.withLogLevel()
+}
+
+// withDetectedGroupEvents reports the group the detector selected once the phase exits. The detector logs the
+// result of each buildpack only at debug level, so when the phase doesn't, the buildpacks of the group are
+// reported as passing.
+func (l *LifecycleExecution) withDetectedGroupEvents(phase string, debug bool) PhaseConfigProviderOperation {
+	return If(l.opts.EventHandler != nil, WithPostContainerRunOperations(
+		EnsureVolumeAccess(l.opts.Builder.UID(), l.opts.Builder.GID(), l.os, l.layersVolume, l.appVolume),
+		EmitDetectedGroup(l.opts.EventHandler, phase, l.mountPaths.groupPath(), !debug)))
+}
+
 func (l *LifecycleExecution) hasExtensions() bool {
 	return len(l.opts.Builder.OrderExtensions()) > 0
 }
 
+// recordCacheUse notes that the cache was used for this build, so it can be listed and pruned later
+func (l *LifecycleExecution) recordCacheUse(c Cache, kind string) {
+	if err := l.opts.CacheUsage.RecordUse(l.opts.Image, c.Name(), c.Type(), kind, time.Now()); err != nil {
+		l.logger.Debugf("Unable to record use of cache %s: %s", style.Symbol(c.Name()), err)
+	}
+}
+
+// withCacheDownload populates a synced build cache from remote storage before the phase runs
+func (l *LifecycleExecution) withCacheDownload(buildCache Cache) PhaseConfigProviderOperation {
+	if syncedCache, ok := buildCache.(SyncedCache); ok {
+		return WithContainerOperations(DownloadCache(syncedCache, l.mountPaths.cacheParentDir()))
+	}
+	return NullOp()
+}
+
+// withCacheUpload stores a synced build cache in remote storage once the phase succeeds
+func (l *LifecycleExecution) withCacheUpload(buildCache Cache) PhaseConfigProviderOperation {
+	if syncedCache, ok := buildCache.(SyncedCache); ok {
+		return WithPostContainerRunOperations(UploadCache(syncedCache, l.mountPaths.cacheDir()))
+	}
+	return NullOp()
+}
+
+// NewBuildCache returns the build cache a lifecycle run with the given options will use
+func NewBuildCache(opts LifecycleOptions, docker DockerClient, logger logging.Logger) (Cache, error) {
+	if opts.CacheImage != "" || (opts.Cache.Build.Format == cache.CacheImage) {
+		cacheImageName := opts.CacheImage
+		if cacheImageName == "" {
+			cacheImageName = opts.Cache.Build.Source
+		}
+		cacheImage, err := name.ParseReference(cacheImageName, name.WeakValidation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache image name: %s", err)
+		}
+		return cache.NewImageCache(cacheImage, docker), nil
+	}
+
+	switch opts.Cache.Build.Format {
+	case cache.CacheVolume:
+		buildCache, err := cache.NewVolumeCache(opts.Image, opts.Cache.Build, "build", docker, logger)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("Using build cache volume %s", style.Symbol(buildCache.Name()))
+		return buildCache, nil
+	case cache.CacheBind:
+		buildCache := cache.NewBindCache(opts.Cache.Build, docker)
+		logger.Debugf("Using build cache dir %s", style.Symbol(buildCache.Name()))
+		return buildCache, nil
+	case cache.CacheS3:
+		buildCache, err := cache.NewS3Cache(opts.Image, opts.Cache.Build, docker, logger)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("Using build cache %s staged in volume %s", style.Symbol(buildCache.Location()), style.Symbol(buildCache.Name()))
+		return buildCache, nil
+	}
+	return nil, nil
+}
+
 func (l *LifecycleExecution) hasExtensionsForBuild() bool {
 	if !l.hasExtensions() {
 		return false
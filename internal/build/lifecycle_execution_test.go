@@ -1,8 +1,10 @@
 package build_test
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -30,7 +32,9 @@ import (
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/metrics"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -68,7 +72,7 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 		providedOrderExt     dist.Order
 
 		lifecycle        *build.LifecycleExecution
-		fakeBuildCache   = newFakeVolumeCache()
+		fakeBuildCache   build.Cache = newFakeVolumeCache()
 		fakeLaunchCache  *fakes.FakeCache
 		fakeKanikoCache  *fakes.FakeCache
 		fakePhase        *fakes.FakePhase
@@ -295,6 +299,31 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			fakePhaseFactory = fakes.NewFakePhaseFactory()
 		})
 
+		when("Run with an s3 build cache", func() {
+			it("clears the staging volume, so it only holds what's downloaded", func() {
+				opts := build.LifecycleOptions{
+					RunImage:   "test",
+					Image:      imageName,
+					Builder:    fakeBuilder,
+					UseCreator: true,
+					Termui:     fakeTermui,
+				}
+				opts.Cache.Build = cache.CacheInfo{Format: cache.CacheS3, Source: "some-bucket", Endpoint: "http://localhost:9000", Region: "eu-west-1"}
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, "some-temp-dir", opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertNil(t, err)
+
+				buildCache, err := cache.NewS3Cache(imageName, opts.Cache.Build, docker, logger)
+				h.AssertNil(t, err)
+				h.AssertEq(t, docker.removedVolumes, []string{buildCache.Name()})
+			})
+		})
+
 		when("Run using creator", func() {
 			it("succeeds", func() {
 				opts := build.LifecycleOptions{
@@ -326,6 +355,83 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				}
 			})
 
+			it("records metrics for the phase", func() {
+				reportDir := t.TempDir()
+				var received metrics.Build
+				opts := build.LifecycleOptions{
+					RunImage:             "test",
+					Image:                imageName,
+					Builder:              fakeBuilder,
+					UseCreator:           true,
+					Termui:               fakeTermui,
+					ReportDestinationDir: reportDir,
+					MetricsHandler: func(m metrics.Build) {
+						received = m
+					},
+				}
+				fakePhaseFactory = fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(&fakes.FakePhase{
+					ReturnForExitCode:       0,
+					ReturnForBytesCopiedIn:  1024,
+					ReturnForBytesCopiedOut: 512,
+				}))
+
+				lifecycle, err := build.NewLifecycleExecution(logger, docker, "some-temp-dir", opts)
+				h.AssertNil(t, err)
+
+				err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(received.Phases), 1)
+				h.AssertEq(t, received.Phases[0].Name, "creator")
+				h.AssertEq(t, received.Phases[0].ExitCode, 0)
+				h.AssertEq(t, received.Phases[0].BytesCopiedIn, int64(1024))
+				h.AssertEq(t, received.Phases[0].BytesCopiedOut, int64(512))
+				h.AssertEq(t, received.Phases[0].Error, "")
+				h.AssertEq(t, lifecycle.Metrics(), received)
+
+				contents, err := os.ReadFile(filepath.Join(reportDir, "build-metrics.json"))
+				h.AssertNil(t, err)
+				var written metrics.Build
+				h.AssertNil(t, json.Unmarshal(contents, &written))
+				h.AssertEq(t, written.Phases[0].Name, "creator")
+				h.AssertEq(t, written.Phases[0].BytesCopiedIn, int64(1024))
+			})
+
+			it("records phases when they start", func() {
+				var (
+					lifecycle *build.LifecycleExecution
+					running   metrics.Build
+				)
+				fakePhaseFactory = fakes.NewFakePhaseFactory(fakes.WhichReturnsForNew(&fakes.FakePhase{
+					RunFunc: func(ctx context.Context) error {
+						running = lifecycle.Metrics()
+						return nil
+					},
+				}))
+
+				var err error
+				lifecycle, err = build.NewLifecycleExecution(logger, docker, "some-temp-dir", build.LifecycleOptions{
+					RunImage:   "test",
+					Image:      imageName,
+					Builder:    fakeBuilder,
+					UseCreator: true,
+					Termui:     fakeTermui,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertNil(t, lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+					return fakePhaseFactory
+				}))
+
+				h.AssertEq(t, len(running.Phases), 1)
+				h.AssertEq(t, running.Phases[0].Name, "creator")
+				h.AssertEq(t, running.Phases[0].ExitCode, -1)
+				h.AssertEq(t, len(lifecycle.Metrics().Phases), 1)
+				h.AssertEq(t, lifecycle.Metrics().Phases[0].ExitCode, 0)
+			})
+
 			when("Run with workspace dir", func() {
 				it("succeeds", func() {
 					opts := build.LifecycleOptions{
@@ -480,6 +586,64 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("detect only", func() {
+				it("stops after the detector", func() {
+					fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithSupportedPlatformAPIs([]*api.Version{api.MustParse("0.7")}))
+					h.AssertNil(t, err)
+
+					opts := build.LifecycleOptions{
+						RunImage:   "test",
+						Image:      imageName,
+						Builder:    fakeBuilder,
+						UseCreator: false,
+						DetectOnly: true,
+						Termui:     fakeTermui,
+					}
+
+					lifecycle, err := build.NewLifecycleExecution(logger, docker, "some-temp-dir", opts)
+					h.AssertNil(t, err)
+
+					err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+						return fakePhaseFactory
+					})
+					h.AssertNil(t, err)
+
+					h.AssertEq(t, len(fakePhaseFactory.NewCalledWithProvider), 2)
+					h.AssertEq(t, fakePhaseFactory.NewCalledWithProvider[0].Name(), "analyzer")
+					h.AssertEq(t, fakePhaseFactory.NewCalledWithProvider[1].Name(), "detector")
+				})
+			})
+
+			when("build only", func() {
+				it("stops after the builder", func() {
+					fakeBuilder, err := fakes.NewFakeBuilder(fakes.WithSupportedPlatformAPIs([]*api.Version{api.MustParse("0.7")}))
+					h.AssertNil(t, err)
+
+					opts := build.LifecycleOptions{
+						RunImage:   "test",
+						Image:      imageName,
+						Builder:    fakeBuilder,
+						UseCreator: false,
+						BuildOnly:  true,
+						Termui:     fakeTermui,
+					}
+
+					lifecycle, err := build.NewLifecycleExecution(logger, docker, "some-temp-dir", opts)
+					h.AssertNil(t, err)
+
+					err = lifecycle.Run(context.Background(), func(execution *build.LifecycleExecution) build.PhaseFactory {
+						return fakePhaseFactory
+					})
+					h.AssertNil(t, err)
+
+					var phases []string
+					for _, entry := range fakePhaseFactory.NewCalledWithProvider {
+						phases = append(phases, entry.Name())
+					}
+					h.AssertEq(t, phases, []string{"analyzer", "detector", "restorer", "builder"})
+				})
+			})
+
 			it("succeeds", func() {
 				opts := build.LifecycleOptions{
 					Publish:      false,
@@ -927,6 +1091,17 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("using an s3 cache", func() {
+			fakeBuildCache = newFakeS3Cache()
+
+			it("syncs the cache volume with the bucket", func() {
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "some-cache:/cache")
+				h.AssertFunctionName(t, configProvider.ContainerOps()[0], "DownloadCache")
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 1)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "UploadCache")
+			})
+		})
+
 		when("using a cache image", func() {
 			providedClearCache = true
 			fakeBuildCache = newFakeImageCache()
@@ -1386,6 +1561,45 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			)
 		})
 
+		when("an event handler is provided and the logger isn't verbose", func() {
+			var received []events.Event
+
+			it.Before(func() {
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.EventHandler = func(e events.Event) {
+						received = append(received, e)
+					}
+				})
+				fakePhaseFactory := fakes.NewFakePhaseFactory()
+
+				h.AssertNil(t, lifecycle.Detect(context.Background(), fakePhaseFactory))
+				configProvider = fakePhaseFactory.NewCalledWithProvider[len(fakePhaseFactory.NewCalledWithProvider)-1]
+				received = nil
+			})
+
+			it("runs the detector at its usual log level", func() {
+				h.AssertSliceNotContains(t, configProvider.ContainerConfig().Cmd, "-log-level")
+			})
+
+			it("reports the buildpacks of the group in group.toml as passing", func() {
+				ops := configProvider.PostContainerRunOps()
+				h.AssertFunctionName(t, ops[len(ops)-1], "EmitDetectedGroup")
+
+				docker := &groupDockerClient{group: "[[group]]\n  id = \"some/bp\"\n  version = \"1.0.0\"\n\n[[group-extensions]]\n  id = \"some/ext\"\n  version = \"2.0.0\"\n"}
+				h.AssertNil(t, ops[len(ops)-1](docker, context.Background(), "some-container", io.Discard, io.Discard))
+				h.AssertEq(t, docker.copiedFrom, "/layers/group.toml")
+
+				h.AssertEq(t, len(received), 3)
+				h.AssertEq(t, received[0].Type, events.BuildpackDetected)
+				h.AssertEq(t, received[0].Phase, "detector")
+				h.AssertEq(t, received[0].Buildpack, "some/bp@1.0.0")
+				h.AssertEq(t, received[0].Result, events.ResultPass)
+				h.AssertEq(t, received[1].Buildpack, "some/ext@2.0.0")
+				h.AssertEq(t, received[2].Type, events.GroupSelected)
+				h.AssertEq(t, received[2].Buildpacks, []string{"some/bp@1.0.0", "some/ext@2.0.0"})
+			})
+		})
+
 		it("configures the phase with the expected network mode", func() {
 			h.AssertEq(t, configProvider.HostConfig().NetworkMode, container.NetworkMode(providedNetworkMode))
 		})
@@ -1397,6 +1611,38 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			h.AssertFunctionName(t, configProvider.ContainerOps()[1], "CopyDir")
 		})
 
+		when("a detect destination dir is provided", func() {
+			lifecycleOps = append(lifecycleOps, func(opts *build.LifecycleOptions) {
+				opts.DetectOnly = true
+				opts.DetectDestinationDir = "some-detect-dir"
+			})
+
+			it("copies out the group and plan", func() {
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 3)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "EnsureVolumeAccess")
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[1], "CopyOut")
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[2], "CopyOut")
+			})
+		})
+
+		when("an event handler is provided", func() {
+			var received []events.Event
+			lifecycleOps = append(lifecycleOps, func(opts *build.LifecycleOptions) {
+				opts.EventHandler = func(e events.Event) {
+					received = append(received, e)
+				}
+			})
+
+			it("emits events when the phase starts and finishes", func() {
+				h.AssertEq(t, len(received), 2)
+				h.AssertEq(t, received[0].Type, events.PhaseStarted)
+				h.AssertEq(t, received[0].Phase, "detector")
+				h.AssertEq(t, received[1].Type, events.PhaseFinished)
+				h.AssertEq(t, received[1].Phase, "detector")
+				h.AssertEq(t, received[1].Error, "")
+			})
+		})
+
 		when("extensions", func() {
 			platformAPI = api.MustParse("0.10")
 
@@ -1442,6 +1688,16 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("using an s3 cache", func() {
+				fakeBuildCache = newFakeS3Cache()
+
+				it("downloads the cache before the analyzer reads it", func() {
+					h.AssertSliceContains(t, configProvider.HostConfig().Binds, "some-cache:/cache")
+					h.AssertEq(t, len(configProvider.ContainerOps()), 1)
+					h.AssertFunctionName(t, configProvider.ContainerOps()[0], "DownloadCache")
+				})
+			})
+
 			when("clear cache is false", func() {
 				it("configures the phase with the expected arguments", func() {
 					h.AssertIncludeAllExpectedPatterns(t,
@@ -1883,6 +2139,33 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("using an s3 cache", func() {
+			fakeBuildCache = newFakeS3Cache()
+
+			it("configures the phase with the cache volume", func() {
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "some-cache:/cache")
+				h.AssertIncludeAllExpectedPatterns(t,
+					configProvider.ContainerConfig().Cmd,
+					[]string{"-cache-dir", "/cache"},
+				)
+			})
+
+			when("platform < 0.7", func() {
+				it("does not download the cache again", func() {
+					h.AssertEq(t, len(configProvider.ContainerOps()), 0)
+				})
+			})
+
+			when("platform >= 0.7", func() {
+				platformAPI = api.MustParse("0.7")
+
+				it("downloads the cache before restoring", func() {
+					h.AssertEq(t, len(configProvider.ContainerOps()), 1)
+					h.AssertFunctionName(t, configProvider.ContainerOps()[0], "DownloadCache")
+				})
+			})
+		})
+
 		when("using cache image", func() {
 			fakeBuildCache = newFakeImageCache()
 
@@ -2097,6 +2380,23 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 		it("configures the phase with binds", func() {
 			h.AssertSliceContains(t, configProvider.HostConfig().Binds, providedVolumes...)
 		})
+
+		it("doesn't copy out the layers", func() {
+			h.AssertEq(t, len(configProvider.PostContainerRunOps()), 0)
+		})
+
+		when("a build destination dir is provided", func() {
+			lifecycleOps = append(lifecycleOps, func(opts *build.LifecycleOptions) {
+				opts.BuildOnly = true
+				opts.BuildDestinationDir = "some-build-dir"
+			})
+
+			it("copies out the layers", func() {
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 2)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "EnsureVolumeAccess")
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[1], "CopyOut")
+			})
+		})
 	})
 
 	when("#ExtendBuild", func() {
@@ -2330,6 +2630,16 @@ func testLifecycleExecution(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("using an s3 cache", func() {
+			fakeBuildCache = newFakeS3Cache()
+
+			it("uploads the cache after exporting", func() {
+				h.AssertSliceContains(t, configProvider.HostConfig().Binds, "some-cache:/cache")
+				h.AssertEq(t, len(configProvider.PostContainerRunOps()), 1)
+				h.AssertFunctionName(t, configProvider.PostContainerRunOps()[0], "UploadCache")
+			})
+		})
+
 		when("using cache image", func() {
 			fakeBuildCache = newFakeImageCache()
 
@@ -2674,6 +2984,13 @@ func newFakeVolumeCache() *fakes.FakeCache {
 	return c
 }
 
+func newFakeS3Cache() *fakes.FakeSyncedCache {
+	c := fakes.NewFakeSyncedCache()
+	c.ReturnForType = cache.S3
+	c.ReturnForName = "some-cache"
+	return c
+}
+
 func newFakeImageCache() *fakes.FakeCache {
 	c := fakes.NewFakeCache()
 	c.ReturnForType = cache.Image
@@ -2699,10 +3016,16 @@ func (f *fakeImageFetcher) fetchRunImage(name string) error {
 }
 
 type fakeDockerClient struct {
-	nNetworks int
+	nNetworks      int
+	removedVolumes []string
 	build.DockerClient
 }
 
+func (f *fakeDockerClient) VolumeRemove(ctx context.Context, volumeID string, options client.VolumeRemoveOptions) (client.VolumeRemoveResult, error) {
+	f.removedVolumes = append(f.removedVolumes, volumeID)
+	return client.VolumeRemoveResult{}, nil
+}
+
 func (f *fakeDockerClient) NetworkList(ctx context.Context, opts client.NetworkListOptions) (client.NetworkListResult, error) {
 	ret := make([]network.Summary, f.nNetworks)
 	return client.NetworkListResult{Items: ret}, nil
@@ -2718,6 +3041,30 @@ func (f *fakeDockerClient) NetworkRemove(ctx context.Context, network string, op
 	return client.NetworkRemoveResult{}, nil
 }
 
+// groupDockerClient copies a group.toml out of containers
+type groupDockerClient struct {
+	build.DockerClient
+	group      string
+	copiedFrom string
+}
+
+func (f *groupDockerClient) CopyFromContainer(ctx context.Context, containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error) {
+	f.copiedFrom = options.SourcePath
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "group.toml", Mode: 0644, Size: int64(len(f.group))}); err != nil {
+		return client.CopyFromContainerResult{}, err
+	}
+	if _, err := tw.Write([]byte(f.group)); err != nil {
+		return client.CopyFromContainerResult{}, err
+	}
+	if err := tw.Close(); err != nil {
+		return client.CopyFromContainerResult{}, err
+	}
+	return client.CopyFromContainerResult{Content: io.NopCloser(&buf)}, nil
+}
+
 func newTestLifecycleExecErr(t *testing.T, logVerbose bool, tmpDir string, ops ...func(*build.LifecycleOptions)) (*build.LifecycleExecution, error) {
 	docker, err := client.New(client.FromEnv)
 	h.AssertNil(t, err)
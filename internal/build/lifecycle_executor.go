@@ -16,7 +16,9 @@ import (
 	"github.com/buildpacks/pack/internal/container"
 	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/metrics"
 )
 
 var (
@@ -61,6 +63,15 @@ type Cache interface {
 	Type() cache.Type
 }
 
+// SyncedCache is a Cache staged in a volume whose contents are kept in remote storage.
+// Download returns a nil reader when nothing has been stored yet, and ClearStaging empties the volume only.
+type SyncedCache interface {
+	Cache
+	Download(context.Context) (io.ReadCloser, error)
+	Upload(context.Context, io.Reader) error
+	ClearStaging(context.Context) error
+}
+
 type Termui interface {
 	logging.Logger
 
@@ -89,6 +100,7 @@ type LifecycleOptions struct {
 	Termui                          Termui
 	DockerHost                      string
 	Cache                           cache.CacheOpts
+	CacheUsage                      *cache.UsageStore // records the caches used, if set
 	ExecutionEnvironment            string
 	CacheImage                      string
 	HTTPProxy                       string
@@ -109,6 +121,19 @@ type LifecycleOptions struct {
 	CreationTime                    *time.Time
 	Keychain                        authn.Keychain
 	EnableUsernsHost                bool
+	EventHandler                    events.Handler
+	MetricsHandler                  func(metrics.Build)
+	// LayerHandler, when set, receives the events of layers being added or reused, without turning on the other events
+	LayerHandler events.Handler
+	// DetectOnly stops the build after the detector, which always logs at debug level
+	// so the output of each buildpack is reported
+	DetectOnly bool
+	// DetectDestinationDir is where group.toml and plan.toml are copied when detection passes
+	DetectDestinationDir string
+	// BuildOnly stops the build after the builder, without exporting an image
+	BuildOnly bool
+	// BuildDestinationDir is where the layers dir is copied when the build succeeds
+	BuildDestinationDir string
 }
 
 func NewLifecycleExecutor(logger logging.Logger, docker DockerClient) *LifecycleExecutor {
@@ -50,6 +50,14 @@ func (m mountPaths) reportPath() string {
 	return m.join(m.layersDir(), "report.toml")
 }
 
+func (m mountPaths) groupPath() string {
+	return m.join(m.layersDir(), "group.toml")
+}
+
+func (m mountPaths) planPath() string {
+	return m.join(m.layersDir(), "plan.toml")
+}
+
 func (m mountPaths) appDirName() string {
 	return m.workspace
 }
@@ -62,6 +70,11 @@ func (m mountPaths) cacheDir() string {
 	return m.join(m.volume, "cache")
 }
 
+// cacheParentDir is where archives of the cache dir, which are rooted at "cache", are extracted
+func (m mountPaths) cacheParentDir() string {
+	return m.volume + m.separator
+}
+
 func (m mountPaths) kanikoCacheDir() string {
 	return m.join(m.volume, "kaniko")
 }
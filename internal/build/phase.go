@@ -3,6 +3,7 @@ package build
 import (
 	"context"
 	"io"
+	"sync/atomic"
 
 	dcontainer "github.com/moby/moby/api/types/container"
 	"github.com/moby/moby/client"
@@ -25,9 +26,29 @@ type Phase struct {
 	containerOps        []ContainerOperation
 	postContainerRunOps []ContainerOperation
 	fileFilter          func(string) bool
+	exitCode            int
+	bytesCopiedIn       int64
+	bytesCopiedOut      int64
+}
+
+// PhaseReporter is implemented by phases which can report on their last run
+type PhaseReporter interface {
+	// Report returns the exit code of the phase container, or -1 if it did not exit,
+	// and the number of bytes copied into and out of it
+	Report() (exitCode int, bytesCopiedIn, bytesCopiedOut int64)
+}
+
+func (p *Phase) Report() (int, int64, int64) {
+	return p.exitCode, atomic.LoadInt64(&p.bytesCopiedIn), atomic.LoadInt64(&p.bytesCopiedOut)
 }
 
 func (p *Phase) Run(ctx context.Context) error {
+	// a phase that's run again only reports its last run
+	p.exitCode = -1
+	atomic.StoreInt64(&p.bytesCopiedIn, 0)
+	atomic.StoreInt64(&p.bytesCopiedOut, 0)
+	docker := &countingDockerClient{DockerClient: p.docker, in: &p.bytesCopiedIn, out: &p.bytesCopiedOut}
+
 	var err error
 	p.ctr, err = p.docker.ContainerCreate(ctx, client.ContainerCreateOptions{
 		Config:     p.ctrConf,
@@ -38,7 +59,7 @@ func (p *Phase) Run(ctx context.Context) error {
 	}
 
 	for _, containerOp := range p.containerOps {
-		if err := containerOp(p.docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
+		if err := containerOp(docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
 			return err
 		}
 	}
@@ -53,12 +74,13 @@ func (p *Phase) Run(ctx context.Context) error {
 		p.docker,
 		p.ctr.ID,
 		handler)
+	p.recordExitCode(ctx)
 	if err != nil {
 		return err
 	}
 
 	for _, containerOp := range p.postContainerRunOps {
-		if err := containerOp(p.docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
+		if err := containerOp(docker, ctx, p.ctr.ID, p.infoWriter, p.errorWriter); err != nil {
 			return err
 		}
 	}
@@ -66,7 +88,55 @@ func (p *Phase) Run(ctx context.Context) error {
 	return nil
 }
 
+func (p *Phase) recordExitCode(ctx context.Context) {
+	result, err := p.docker.ContainerInspect(ctx, p.ctr.ID, client.ContainerInspectOptions{})
+	if err != nil || result.Container.State == nil || result.Container.State.Running {
+		return
+	}
+	p.exitCode = result.Container.State.ExitCode
+}
+
 func (p *Phase) Cleanup() error {
 	_, err := p.docker.ContainerRemove(context.Background(), p.ctr.ID, client.ContainerRemoveOptions{Force: true})
 	return err
 }
+
+// countingDockerClient counts the bytes copied into and out of containers
+type countingDockerClient struct {
+	DockerClient
+	in, out *int64
+}
+
+func (c *countingDockerClient) CopyToContainer(ctx context.Context, container string, options client.CopyToContainerOptions) (client.CopyToContainerResult, error) {
+	options.Content = &countingReader{Reader: options.Content, n: c.in}
+	return c.DockerClient.CopyToContainer(ctx, container, options)
+}
+
+func (c *countingDockerClient) CopyFromContainer(ctx context.Context, containerID string, options client.CopyFromContainerOptions) (client.CopyFromContainerResult, error) {
+	result, err := c.DockerClient.CopyFromContainer(ctx, containerID, options)
+	if err != nil {
+		return result, err
+	}
+	result.Content = &countingReadCloser{countingReader: countingReader{Reader: result.Content, n: c.out}, closer: result.Content}
+	return result, nil
+}
+
+type countingReader struct {
+	io.Reader
+	n *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	atomic.AddInt64(r.n, int64(n))
+	return n, err
+}
+
+type countingReadCloser struct {
+	countingReader
+	closer io.Closer
+}
+
+func (r *countingReadCloser) Close() error {
+	return r.closer.Close()
+}
@@ -32,6 +32,7 @@ type PhaseConfigProvider struct {
 	infoWriter          io.Writer
 	errorWriter         io.Writer
 	handler             pcontainer.Handler
+	logPrefix           string
 }
 
 func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops ...PhaseConfigProviderOperation) *PhaseConfigProvider {
@@ -72,6 +73,10 @@ func NewPhaseConfigProvider(name string, lifecycleExec *LifecycleExecution, ops
 		op(provider)
 	}
 
+	if handler := lifecycleExec.outputEventHandler(); handler != nil {
+		provider.infoWriter = newEventWriter(provider.infoWriter, provider.PhaseName(), handler)
+	}
+
 	provider.ctrConf.Entrypoint = []string{""} // override entrypoint in case it is set
 	provider.ctrConf.Cmd = append([]string{"/cnb/lifecycle/" + name}, provider.ctrConf.Cmd...)
 
@@ -130,6 +135,15 @@ func (p *PhaseConfigProvider) Name() string {
 	return p.name
 }
 
+// PhaseName returns the name used to identify the phase in logs and events,
+// which distinguishes phases that share a lifecycle binary (e.g. the build and run extenders)
+func (p *PhaseConfigProvider) PhaseName() string {
+	if p.logPrefix != "" {
+		return p.logPrefix
+	}
+	return p.name
+}
+
 func (p *PhaseConfigProvider) ErrorWriter() io.Writer {
 	return p.errorWriter
 }
@@ -209,6 +223,7 @@ func WithImage(image string) PhaseConfigProviderOperation {
 func WithLogPrefix(prefix string) PhaseConfigProviderOperation {
 	return func(provider *PhaseConfigProvider) {
 		if prefix != "" {
+			provider.logPrefix = prefix
 			provider.infoWriter = logging.NewPrefixWriter(provider.infoWriter, prefix)
 			provider.errorWriter = logging.NewPrefixWriter(provider.errorWriter, prefix)
 		}
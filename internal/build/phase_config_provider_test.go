@@ -16,6 +16,7 @@ import (
 
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/build/fakes"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/logging"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -348,6 +349,127 @@ func testPhaseConfigProvider(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("an event handler is provided", func() {
+			it("emits events for recognised phase output", func() {
+				var received []events.Event
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.EventHandler = func(e events.Event) {
+						received = append(received, e)
+					}
+				})
+
+				phaseConfigProvider := build.NewPhaseConfigProvider(
+					"extender",
+					lifecycle,
+					build.WithLogPrefix("extender (build)"),
+				)
+				h.AssertEq(t, phaseConfigProvider.PhaseName(), "extender (build)")
+
+				w := phaseConfigProvider.InfoWriter()
+				_, err := io.WriteString(w, "======== Results ========\npass: some/bp@1.0.0\nfail: other/")
+				h.AssertNil(t, err)
+				_, err = io.WriteString(w, "bp@2.0.0\n1 of 2 buildpacks participating\nsome/bp 1.0.0\n")
+				h.AssertNil(t, err)
+				_, err = io.WriteString(w, "Restoring data for \"some/bp:layer\" from cache\nReusing layer 'some/bp:layer'\nAdding cache layer 'some/bp:cache'")
+				h.AssertNil(t, err)
+				h.AssertNil(t, w.(io.Closer).Close())
+
+				h.AssertEq(t, len(received), 5)
+				for _, e := range received {
+					h.AssertEq(t, e.Phase, "extender (build)")
+				}
+				h.AssertEq(t, received[0].Type, events.BuildpackDetected)
+				h.AssertEq(t, received[0].Buildpack, "some/bp@1.0.0")
+				h.AssertEq(t, received[0].Result, events.ResultPass)
+				h.AssertEq(t, received[0].Group, 1)
+				h.AssertEq(t, received[1].Buildpack, "other/bp@2.0.0")
+				h.AssertEq(t, received[1].Result, events.ResultFail)
+				h.AssertEq(t, received[2].Type, events.LayerRestored)
+				h.AssertEq(t, received[2].Layer, "some/bp:layer")
+				h.AssertEq(t, received[2].Source, events.SourceCache)
+				h.AssertEq(t, received[3].Type, events.LayerReused)
+				h.AssertEq(t, received[3].Cache, false)
+				h.AssertEq(t, received[4].Type, events.LayerAdded)
+				h.AssertEq(t, received[4].Layer, "some/bp:cache")
+				h.AssertEq(t, received[4].Cache, true)
+			})
+
+			it("attaches detect output to each buildpack result", func() {
+				var received []events.Event
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.EventHandler = func(e events.Event) {
+						received = append(received, e)
+					}
+				})
+
+				w := build.NewPhaseConfigProvider("detector", lifecycle).InfoWriter()
+				_, err := io.WriteString(w, "======== Output: some/bp@1.0.0 ========\nno package.json found\npass: not a result\n")
+				h.AssertNil(t, err)
+				_, err = io.WriteString(w, "======== Error: some/bp@1.0.0 ========\nexit status 1\n")
+				h.AssertNil(t, err)
+				_, err = io.WriteString(w, "======== Results ========\nerr:  some/bp@1.0.0 (1)\nfail: other/bp@2.0.0\n")
+				h.AssertNil(t, err)
+				_, err = io.WriteString(w, "======== Results ========\nfail: some/bp@1.0.0\n")
+				h.AssertNil(t, err)
+				h.AssertNil(t, w.(io.Closer).Close())
+
+				h.AssertEq(t, len(received), 3)
+				h.AssertEq(t, received[0].Buildpack, "some/bp@1.0.0")
+				h.AssertEq(t, received[0].Result, events.ResultError)
+				h.AssertEq(t, received[0].Output, "no package.json found\npass: not a result\nexit status 1")
+				h.AssertEq(t, received[1].Buildpack, "other/bp@2.0.0")
+				h.AssertEq(t, received[1].Output, "")
+				h.AssertEq(t, received[2].Group, 2)
+				h.AssertEq(t, received[2].Output, "")
+			})
+
+			it("passes the phase output through unchanged", func() {
+				var outBuf bytes.Buffer
+				logger := logging.NewLogWithWriters(&outBuf, &outBuf)
+
+				docker, err := client.New(client.FromEnv)
+				h.AssertNil(t, err)
+
+				defaultBuilder, err := fakes.NewFakeBuilder()
+				h.AssertNil(t, err)
+
+				lifecycleExec, err := build.NewLifecycleExecution(logger, docker, "some-temp-dir", build.LifecycleOptions{
+					AppPath:      "some-app-path",
+					Builder:      defaultBuilder,
+					EventHandler: func(events.Event) {},
+				})
+				h.AssertNil(t, err)
+
+				output := "======== Output: other/bp@2.0.0 ========\nsomething broke\nerr:  other/bp@2.0.0 (1)\nERROR: No buildpack groups passed detection."
+				w := build.NewPhaseConfigProvider("detector", lifecycleExec).InfoWriter()
+				_, err = io.WriteString(w, output)
+				h.AssertNil(t, err)
+				h.AssertNil(t, w.(io.Closer).Close())
+
+				h.AssertEq(t, outBuf.String(), output)
+			})
+		})
+
+		when("a layer handler is provided", func() {
+			it("only emits the events of layers added or reused", func() {
+				var received []events.Event
+				lifecycle := newTestLifecycleExec(t, false, "some-temp-dir", func(opts *build.LifecycleOptions) {
+					opts.LayerHandler = func(e events.Event) {
+						received = append(received, e)
+					}
+				})
+
+				w := build.NewPhaseConfigProvider("exporter", lifecycle).InfoWriter()
+				_, err := io.WriteString(w, "Layer cache not found\nReusing layer 'some/bp:layer'\nAdding cache layer 'some/bp:cache'\n")
+				h.AssertNil(t, err)
+				h.AssertNil(t, w.(io.Closer).Close())
+
+				h.AssertEq(t, len(received), 2)
+				h.AssertEq(t, received[0].Type, events.LayerReused)
+				h.AssertEq(t, received[1].Type, events.LayerAdded)
+			})
+		})
+
 		when("verbose", func() {
 			it("prints debug information about the phase", func() {
 				var outBuf bytes.Buffer
@@ -153,6 +153,34 @@ func testPhase(t *testing.T, when spec.G, it spec.S) {
 				h.AssertContains(t, actual, "running some-lifecycle-phase")
 			})
 
+			it("reports the bytes copied by its last run only", func() {
+				configProvider := build.NewPhaseConfigProvider(
+					phaseName,
+					lifecycleExec,
+					build.WithArgs("read", "/workspace/fake-app-file"),
+					build.WithContainerOperations(
+						build.CopyDir(
+							lifecycleExec.AppPath(),
+							"/workspace",
+							lifecycleExec.Builder().UID(),
+							lifecycleExec.Builder().GID(),
+							osType,
+							false,
+							nil,
+						),
+					),
+				)
+				readPhase := phaseFactory.New(configProvider)
+				assertRunSucceeds(t, readPhase, &outBuf, &errBuf)
+				exitCode, firstIn, _ := readPhase.(build.PhaseReporter).Report()
+				h.AssertEq(t, exitCode, 0)
+				h.AssertTrue(t, firstIn > 0)
+
+				assertRunSucceeds(t, readPhase, &outBuf, &errBuf)
+				_, secondIn, _ := readPhase.(build.PhaseReporter).Report()
+				h.AssertEq(t, secondIn, firstIn)
+			})
+
 			it("copies the app into the app volume", func() {
 				configProvider := build.NewPhaseConfigProvider(
 					phaseName,
@@ -52,7 +52,7 @@ const (
 
 	emptyTarDiffID = "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
 
-	metadataLabel = "io.buildpacks.builder.metadata"
+	MetadataLabel = "io.buildpacks.builder.metadata"
 	stackLabel    = "io.buildpacks.stack.id"
 
 	EnvUID = "CNB_USER_ID"
@@ -141,10 +141,10 @@ func New(baseImage imgutil.Image, name string, ops ...BuilderOption) (*Builder,
 
 func constructBuilder(img imgutil.Image, newName string, errOnMissingLabel bool, ops ...BuilderOption) (*Builder, error) {
 	var metadata Metadata
-	if ok, err := dist.GetLabel(img, metadataLabel, &metadata); err != nil {
-		return nil, errors.Wrapf(err, "getting label %s", metadataLabel)
+	if ok, err := dist.GetLabel(img, MetadataLabel, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "getting label %s", MetadataLabel)
 	} else if !ok && errOnMissingLabel {
-		return nil, fmt.Errorf("builder %s missing label %s -- try recreating builder", style.Symbol(img.Name()), style.Symbol(metadataLabel))
+		return nil, fmt.Errorf("builder %s missing label %s -- try recreating builder", style.Symbol(img.Name()), style.Symbol(MetadataLabel))
 	}
 
 	system := dist.System{}
@@ -640,7 +640,7 @@ func (b *Builder) Save(logger logging.Logger, creatorMetadata CreatorMetadata, a
 
 	b.metadata.CreatedBy = creatorMetadata
 
-	if err := dist.SetLabel(b.image, metadataLabel, b.metadata); err != nil {
+	if err := dist.SetLabel(b.image, MetadataLabel, b.metadata); err != nil {
 		return err
 	}
 
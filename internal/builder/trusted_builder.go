@@ -118,24 +118,39 @@ func IsKnownTrustedBuilder(builderName string) bool {
 }
 
 func IsTrustedBuilder(cfg config.Config, builderName string) (bool, error) {
+	trustedBuilder, err := findTrustedBuilder(cfg, builderName)
+	return trustedBuilder != nil, err
+}
+
+// TrustedBuilderKey returns the key the signature of a builder trusted in the config is verified with,
+// or an empty string when the builder isn't verified
+func TrustedBuilderKey(cfg config.Config, builderName string) (string, error) {
+	trustedBuilder, err := findTrustedBuilder(cfg, builderName)
+	if err != nil || trustedBuilder == nil {
+		return "", err
+	}
+	return trustedBuilder.Key, nil
+}
+
+func findTrustedBuilder(cfg config.Config, builderName string) (*config.TrustedBuilder, error) {
 	builderReference, err := name.ParseReference(builderName, name.WithDefaultTag(""))
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	for _, trustedBuilder := range cfg.TrustedBuilders {
+	for i, trustedBuilder := range cfg.TrustedBuilders {
 		trustedBuilderReference, err := name.ParseReference(trustedBuilder.Name, name.WithDefaultTag(""))
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		if trustedBuilderReference.Identifier() != "" {
 			if builderReference.Name() == trustedBuilderReference.Name() {
-				return true, nil
+				return &cfg.TrustedBuilders[i], nil
 			}
 		} else {
 			if builderReference.Context().RepositoryStr() == trustedBuilderReference.Context().RepositoryStr() {
-				return true, nil
+				return &cfg.TrustedBuilders[i], nil
 			}
 		}
 	}
-	return false, nil
+	return nil, nil
 }
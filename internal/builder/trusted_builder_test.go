@@ -99,4 +99,27 @@ func trustedBuilder(t *testing.T, when spec.G, it spec.S) {
 			}
 		})
 	})
+
+	when("TrustedBuilderKey", func() {
+		it("returns the key of the matching trusted builder", func() {
+			cfg := config.Config{
+				TrustedBuilders: []config.TrustedBuilder{
+					{Name: "my/trusted/builder-jammy"},
+					{Name: "my/signed/builder-jammy", Key: "some-key"},
+				},
+			}
+
+			key, err := bldr.TrustedBuilderKey(cfg, "my/signed/builder-jammy:1.2.3")
+			h.AssertNil(t, err)
+			h.AssertEq(t, key, "some-key")
+
+			key, err = bldr.TrustedBuilderKey(cfg, "my/trusted/builder-jammy")
+			h.AssertNil(t, err)
+			h.AssertEq(t, key, "")
+
+			key, err = bldr.TrustedBuilderKey(cfg, "my/private/builder")
+			h.AssertNil(t, err)
+			h.AssertEq(t, key, "")
+		})
+	})
 }
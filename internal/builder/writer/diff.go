@@ -0,0 +1,121 @@
+package writer
+
+import (
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/pkg/client"
+)
+
+type APIVersionsDiff struct {
+	SupportedAdded    []string `json:"supported_added,omitempty" yaml:"supported_added,omitempty" toml:"supported_added,omitempty"`
+	SupportedRemoved  []string `json:"supported_removed,omitempty" yaml:"supported_removed,omitempty" toml:"supported_removed,omitempty"`
+	DeprecatedAdded   []string `json:"deprecated_added,omitempty" yaml:"deprecated_added,omitempty" toml:"deprecated_added,omitempty"`
+	DeprecatedRemoved []string `json:"deprecated_removed,omitempty" yaml:"deprecated_removed,omitempty" toml:"deprecated_removed,omitempty"`
+}
+
+type LifecycleDiff struct {
+	VersionBefore string          `json:"version_before,omitempty" yaml:"version_before,omitempty" toml:"version_before,omitempty"`
+	VersionAfter  string          `json:"version_after,omitempty" yaml:"version_after,omitempty" toml:"version_after,omitempty"`
+	BuildpackAPIs APIVersionsDiff `json:"buildpack_apis" yaml:"buildpack_apis" toml:"buildpack_apis"`
+	PlatformAPIs  APIVersionsDiff `json:"platform_apis" yaml:"platform_apis" toml:"platform_apis"`
+}
+
+type ModuleDiff struct {
+	ID             string   `json:"id" yaml:"id" toml:"id"`
+	Change         string   `json:"change" yaml:"change" toml:"change"`
+	VersionsBefore []string `json:"versions_before,omitempty" yaml:"versions_before,omitempty" toml:"versions_before,omitempty"`
+	VersionsAfter  []string `json:"versions_after,omitempty" yaml:"versions_after,omitempty" toml:"versions_after,omitempty"`
+}
+
+type DetectionOrderDiff struct {
+	Before pubbldr.DetectionOrder `json:"before" yaml:"before" toml:"before"`
+	After  pubbldr.DetectionOrder `json:"after" yaml:"after" toml:"after"`
+}
+
+type RunImageDiff struct {
+	Image          string   `json:"image" yaml:"image" toml:"image"`
+	Change         string   `json:"change" yaml:"change" toml:"change"`
+	MirrorsAdded   []string `json:"mirrors_added,omitempty" yaml:"mirrors_added,omitempty" toml:"mirrors_added,omitempty"`
+	MirrorsRemoved []string `json:"mirrors_removed,omitempty" yaml:"mirrors_removed,omitempty" toml:"mirrors_removed,omitempty"`
+}
+
+type TargetDiff struct {
+	Before string `json:"before" yaml:"before" toml:"before"`
+	After  string `json:"after" yaml:"after" toml:"after"`
+}
+
+type EnvDiff struct {
+	Name   string `json:"name" yaml:"name" toml:"name"`
+	Change string `json:"change" yaml:"change" toml:"change"`
+	Before string `json:"before,omitempty" yaml:"before,omitempty" toml:"before,omitempty"`
+	After  string `json:"after,omitempty" yaml:"after,omitempty" toml:"after,omitempty"`
+}
+
+type DiffOutput struct {
+	OldBuilder      string              `json:"old_builder" yaml:"old_builder" toml:"old_builder"`
+	NewBuilder      string              `json:"new_builder" yaml:"new_builder" toml:"new_builder"`
+	Lifecycle       *LifecycleDiff      `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty" toml:"lifecycle,omitempty"`
+	Buildpacks      []ModuleDiff        `json:"buildpacks" yaml:"buildpacks" toml:"buildpacks"`
+	Extensions      []ModuleDiff        `json:"extensions" yaml:"extensions" toml:"extensions"`
+	DetectionOrder  *DetectionOrderDiff `json:"detection_order,omitempty" yaml:"detection_order,omitempty" toml:"detection_order,omitempty"`
+	OrderExtensions *DetectionOrderDiff `json:"order_extensions,omitempty" yaml:"order_extensions,omitempty" toml:"order_extensions,omitempty"`
+	RunImages       []RunImageDiff      `json:"run_images" yaml:"run_images" toml:"run_images"`
+	Target          *TargetDiff         `json:"target,omitempty" yaml:"target,omitempty" toml:"target,omitempty"`
+	Env             []EnvDiff           `json:"env" yaml:"env" toml:"env"`
+}
+
+// Empty returns true when the builders have no differences
+func (d *DiffOutput) Empty() bool {
+	return d.Lifecycle == nil &&
+		len(d.Buildpacks) == 0 &&
+		len(d.Extensions) == 0 &&
+		d.DetectionOrder == nil &&
+		d.OrderExtensions == nil &&
+		len(d.RunImages) == 0 &&
+		d.Target == nil &&
+		len(d.Env) == 0
+}
+
+func NewDiffOutput(oldBuilder, newBuilder string, diff *client.BuilderDiff) *DiffOutput {
+	result := &DiffOutput{
+		OldBuilder: oldBuilder,
+		NewBuilder: newBuilder,
+		Buildpacks: moduleDiffs(diff.Buildpacks),
+		Extensions: moduleDiffs(diff.Extensions),
+		RunImages:  []RunImageDiff{},
+		Env:        []EnvDiff{},
+	}
+
+	if diff.Lifecycle != nil {
+		result.Lifecycle = &LifecycleDiff{
+			VersionBefore: diff.Lifecycle.VersionBefore,
+			VersionAfter:  diff.Lifecycle.VersionAfter,
+			BuildpackAPIs: APIVersionsDiff(diff.Lifecycle.BuildpackAPIs),
+			PlatformAPIs:  APIVersionsDiff(diff.Lifecycle.PlatformAPIs),
+		}
+	}
+	if diff.Order != nil {
+		result.DetectionOrder = &DetectionOrderDiff{Before: diff.Order.Before, After: diff.Order.After}
+	}
+	if diff.OrderExtensions != nil {
+		result.OrderExtensions = &DetectionOrderDiff{Before: diff.OrderExtensions.Before, After: diff.OrderExtensions.After}
+	}
+	for _, runImage := range diff.RunImages {
+		result.RunImages = append(result.RunImages, RunImageDiff(runImage))
+	}
+	if diff.Target != nil {
+		result.Target = &TargetDiff{Before: diff.Target.Before.ValuesAsPlatform(), After: diff.Target.After.ValuesAsPlatform()}
+	}
+	for _, env := range diff.Env {
+		result.Env = append(result.Env, EnvDiff(env))
+	}
+
+	return result
+}
+
+func moduleDiffs(diffs []client.ModuleDiff) []ModuleDiff {
+	result := []ModuleDiff{}
+	for _, diff := range diffs {
+		result = append(result, ModuleDiff(diff))
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package writer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/builder/writer"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDiffWriters(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Builder Diff Writers", testDiffWriters, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDiffWriters(t *testing.T, when spec.G, it spec.S) {
+	var (
+		assert = h.NewAssertionManager(t)
+		outBuf bytes.Buffer
+		logger logging.Logger
+		diff   *writer.DiffOutput
+	)
+
+	it.Before(func() {
+		outBuf = bytes.Buffer{}
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+
+		orderEntry := func(id, version string, optional bool) pubbldr.DetectionOrderEntry {
+			return pubbldr.DetectionOrderEntry{
+				ModuleRef: dist.ModuleRef{ModuleInfo: dist.ModuleInfo{ID: id, Version: version}, Optional: optional},
+			}
+		}
+
+		diff = writer.NewDiffOutput("some/builder:v1", "some/builder:v2", &client.BuilderDiff{
+			Lifecycle: &client.LifecycleDiff{
+				VersionBefore: "0.17.0",
+				VersionAfter:  "0.20.0",
+				BuildpackAPIs: client.APIVersionsDiff{SupportedAdded: []string{"0.11"}},
+				PlatformAPIs:  client.APIVersionsDiff{SupportedRemoved: []string{"0.8"}, DeprecatedAdded: []string{"0.9"}},
+			},
+			Buildpacks: []client.ModuleDiff{
+				{ID: "new/bp", Change: client.DiffAdded, VersionsAfter: []string{"0.1.0"}},
+				{ID: "some/bp", Change: client.DiffChanged, VersionsBefore: []string{"1.0.0"}, VersionsAfter: []string{"1.1.0"}},
+				{ID: "old/bp", Change: client.DiffRemoved, VersionsBefore: []string{"2.0.0"}},
+			},
+			Order: &client.DetectionOrderDiff{
+				Before: pubbldr.DetectionOrder{{GroupDetectionOrder: pubbldr.DetectionOrder{
+					orderEntry("some/bp", "1.0.0", false),
+					orderEntry("old/bp", "2.0.0", true),
+				}}},
+				After: pubbldr.DetectionOrder{{GroupDetectionOrder: pubbldr.DetectionOrder{
+					orderEntry("some/bp", "1.1.0", false),
+					orderEntry("new/bp", "0.1.0", true),
+				}}},
+			},
+			RunImages: []client.RunImageConfigDiff{
+				{Image: "some/run:noble", Change: client.DiffAdded},
+				{Image: "some/run:jammy", Change: client.DiffRemoved, MirrorsRemoved: []string{"mirror.example.com/run:jammy"}},
+			},
+			Target: &client.TargetDiff{
+				Before: dist.Target{OS: "linux", Arch: "amd64", Distributions: []dist.Distribution{{Name: "ubuntu", Version: "22.04"}}},
+				After:  dist.Target{OS: "linux", Arch: "amd64", Distributions: []dist.Distribution{{Name: "ubuntu", Version: "24.04"}}},
+			},
+			Env: []client.EnvDiff{
+				{Name: "CNB_USER_ID", Change: client.DiffChanged, Before: "1000", After: "1001"},
+				{Name: "SOME_VAR", Change: client.DiffAdded, After: "some-value"},
+			},
+		})
+	})
+
+	when("Factory#DiffWriter", func() {
+		it("returns a writer for each output format", func() {
+			factory := writer.NewFactory()
+			for _, kind := range []string{"human-readable", "json", "yaml", "toml"} {
+				w, err := factory.DiffWriter(kind)
+				assert.Nil(err)
+				assert.NotNil(w)
+			}
+		})
+
+		it("errors for unsupported formats", func() {
+			_, err := writer.NewFactory().DiffWriter("xml")
+			assert.ErrorWithMessage(err, "output format 'xml' is not supported")
+		})
+	})
+
+	when("HumanReadable#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewHumanReadable().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), `Comparing 'some/builder:v1' to 'some/builder:v2'
+
+Lifecycle:
+  ~ version                     0.17.0 -> 0.20.0
+  + buildpack APIs              0.11
+  - platform APIs               0.8
+  + deprecated platform APIs    0.9
+
+Buildpacks:
+  + new/bp     0.1.0
+  ~ some/bp    1.0.0 -> 1.1.0
+  - old/bp     2.0.0
+
+Detection Order:
+    └ Group #1:
+  -    ├ some/bp@1.0.0
+  -    └ old/bp@2.0.0     (optional)
+  +    ├ some/bp@1.1.0
+  +    └ new/bp@0.1.0     (optional)
+
+Run Images:
+  + some/run:noble
+  - some/run:jammy
+      - mirror mirror.example.com/run:jammy
+
+Target:
+  - linux/amd64/ubuntu@22.04
+  + linux/amd64/ubuntu@24.04
+
+Environment:
+  ~ CNB_USER_ID    1000 -> 1001
+  + SOME_VAR       some-value
+`)
+		})
+
+		it("reports builders without differences", func() {
+			empty := writer.NewDiffOutput("some/builder:v1", "some/builder:v2", &client.BuilderDiff{})
+			assert.Nil(writer.NewHumanReadable().PrintDiff(logger, empty))
+
+			assert.Contains(outBuf.String(), "No differences found")
+		})
+	})
+
+	when("JSON#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewJSON().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), `"old_builder": "some/builder:v1"`)
+			assert.Contains(outBuf.String(), `"supported_added": [
+        "0.11"
+      ]`)
+			assert.Contains(outBuf.String(), `"before": "linux/amd64/ubuntu@22.04"`)
+		})
+	})
+
+	when("YAML#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewYAML().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), "new_builder: some/builder:v2")
+			assert.Contains(outBuf.String(), "version_after: 0.20.0")
+		})
+	})
+
+	when("TOML#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewTOML().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), "[[buildpacks]]")
+			assert.Contains(outBuf.String(), `name = "CNB_USER_ID"`)
+		})
+	})
+}
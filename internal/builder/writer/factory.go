@@ -22,6 +22,10 @@ type BuilderWriter interface {
 	) error
 }
 
+type BuilderDiffWriter interface {
+	PrintDiff(logger logging.Logger, diff *DiffOutput) error
+}
+
 type SharedBuilderInfo struct {
 	Name      string `json:"builder_name" yaml:"builder_name" toml:"builder_name"`
 	Trusted   bool   `json:"trusted" yaml:"trusted" toml:"trusted"`
@@ -32,6 +36,10 @@ type BuilderWriterFactory interface {
 	Writer(kind string) (BuilderWriter, error)
 }
 
+type BuilderDiffWriterFactory interface {
+	DiffWriter(kind string) (BuilderDiffWriter, error)
+}
+
 func NewFactory() *Factory {
 	return &Factory{}
 }
@@ -50,3 +58,18 @@ func (f *Factory) Writer(kind string) (BuilderWriter, error) {
 
 	return nil, fmt.Errorf("output format %s is not supported", style.Symbol(kind))
 }
+
+func (f *Factory) DiffWriter(kind string) (BuilderDiffWriter, error) {
+	switch kind {
+	case "human-readable":
+		return NewHumanReadable(), nil
+	case "json":
+		return NewJSON(), nil
+	case "yaml":
+		return NewYAML(), nil
+	case "toml":
+		return NewTOML(), nil
+	}
+
+	return nil, fmt.Errorf("output format %s is not supported", style.Symbol(kind))
+}
@@ -129,7 +129,7 @@ func writeBuilderInfo(
 	if err != nil {
 		return fmt.Errorf("compiling run images output: %w", err)
 	}
-	orderString, orderWarnings, err := detectionOrderOutput(info.Order, sharedInfo.Name)
+	orderString, orderWarnings, err := DetectionOrderOutput(info.Order, sharedInfo.Name)
 	if err != nil {
 		return fmt.Errorf("compiling detection order output: %w", err)
 	}
@@ -446,7 +446,8 @@ const (
 	trunkPrefix      = " │ "
 )
 
-func detectionOrderOutput(order pubbldr.DetectionOrder, builderName string) (string, []string, error) {
+// DetectionOrderOutput renders a detection order as the tree shown when inspecting a builder
+func DetectionOrderOutput(order pubbldr.DetectionOrder, builderName string) (string, []string, error) {
 	output := "Detection Order:\n"
 
 	if len(order) == 0 {
@@ -0,0 +1,204 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	strs "github.com/buildpacks/pack/internal/strings"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+var changeSymbols = map[string]string{
+	client.DiffAdded:   "+",
+	client.DiffRemoved: "-",
+	client.DiffChanged: "~",
+}
+
+func (h *HumanReadable) PrintDiff(logger logging.Logger, diff *DiffOutput) error {
+	logger.Infof("Comparing %s to %s\n", style.Symbol(diff.OldBuilder), style.Symbol(diff.NewBuilder))
+	if diff.Empty() {
+		logger.Info("\nNo differences found\n")
+		return nil
+	}
+
+	buf := bytes.Buffer{}
+	tw := tabwriter.NewWriter(&trailingSpaceStrippingWriter{output: &buf}, writerMinWidth, writerTabWidth, defaultTabWidth, writerPadChar, writerFlags)
+
+	if diff.Lifecycle != nil {
+		fmt.Fprintln(tw, "\nLifecycle:")
+		if diff.Lifecycle.VersionBefore != diff.Lifecycle.VersionAfter {
+			fmt.Fprintf(tw, "  ~ version\t%s -> %s\n", strs.ValueOrDefault(diff.Lifecycle.VersionBefore, none), strs.ValueOrDefault(diff.Lifecycle.VersionAfter, none))
+		}
+		writeAPIVersionsDiff(tw, "buildpack", diff.Lifecycle.BuildpackAPIs)
+		writeAPIVersionsDiff(tw, "platform", diff.Lifecycle.PlatformAPIs)
+	}
+
+	writeModuleDiffs(tw, "Buildpacks", diff.Buildpacks)
+	writeModuleDiffs(tw, "Extensions", diff.Extensions)
+
+	if err := writeDetectionOrderDiff(tw, "Detection Order", diff.DetectionOrder); err != nil {
+		return err
+	}
+	if err := writeDetectionOrderDiff(tw, "Detection Order (Extensions)", diff.OrderExtensions); err != nil {
+		return err
+	}
+
+	if len(diff.RunImages) > 0 {
+		fmt.Fprintln(tw, "\nRun Images:")
+		for _, runImage := range diff.RunImages {
+			fmt.Fprintf(tw, "  %s %s\n", changeSymbols[runImage.Change], runImage.Image)
+			for _, mirror := range runImage.MirrorsAdded {
+				fmt.Fprintf(tw, "      + mirror %s\n", mirror)
+			}
+			for _, mirror := range runImage.MirrorsRemoved {
+				fmt.Fprintf(tw, "      - mirror %s\n", mirror)
+			}
+		}
+	}
+
+	if diff.Target != nil {
+		fmt.Fprintln(tw, "\nTarget:")
+		fmt.Fprintf(tw, "  - %s\n", strs.ValueOrDefault(diff.Target.Before, none))
+		fmt.Fprintf(tw, "  + %s\n", strs.ValueOrDefault(diff.Target.After, none))
+	}
+
+	if len(diff.Env) > 0 {
+		fmt.Fprintln(tw, "\nEnvironment:")
+		for _, env := range diff.Env {
+			value := env.Before
+			switch env.Change {
+			case client.DiffAdded:
+				value = env.After
+			case client.DiffChanged:
+				value = fmt.Sprintf("%s -> %s", env.Before, env.After)
+			}
+			fmt.Fprintf(tw, "  %s %s\t%s\n", changeSymbols[env.Change], env.Name, value)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("flushing tab writer: %w", err)
+	}
+	logger.Info(buf.String())
+	return nil
+}
+
+func writeAPIVersionsDiff(w io.Writer, kind string, diff APIVersionsDiff) {
+	rows := []struct {
+		symbol, description string
+		versions            []string
+	}{
+		{"+", kind + " APIs", diff.SupportedAdded},
+		{"-", kind + " APIs", diff.SupportedRemoved},
+		{"+", "deprecated " + kind + " APIs", diff.DeprecatedAdded},
+		{"-", "deprecated " + kind + " APIs", diff.DeprecatedRemoved},
+	}
+	for _, row := range rows {
+		if len(row.versions) > 0 {
+			fmt.Fprintf(w, "  %s %s\t%s\n", row.symbol, row.description, strings.Join(row.versions, ", "))
+		}
+	}
+}
+
+func writeModuleDiffs(w io.Writer, title string, diffs []ModuleDiff) {
+	if len(diffs) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, diff := range diffs {
+		versions := strings.Join(diff.VersionsBefore, ", ")
+		switch diff.Change {
+		case client.DiffAdded:
+			versions = strings.Join(diff.VersionsAfter, ", ")
+		case client.DiffChanged:
+			versions = fmt.Sprintf("%s -> %s", versions, strings.Join(diff.VersionsAfter, ", "))
+		}
+		fmt.Fprintf(w, "  %s %s\t%s\n", changeSymbols[diff.Change], diff.ID, versions)
+	}
+}
+
+// writeDetectionOrderDiff renders the detection order of both builders as a tree, and shows which lines of it changed
+func writeDetectionOrderDiff(w io.Writer, title string, diff *DetectionOrderDiff) error {
+	if diff == nil {
+		return nil
+	}
+
+	render := func(order pubbldr.DetectionOrder) ([]string, error) {
+		if len(order) == 0 {
+			return []string{" " + none}, nil
+		}
+		buf := bytes.Buffer{}
+		if err := writeDetectionOrderGroup(&buf, order, ""); err != nil {
+			return nil, fmt.Errorf("writing detection order group: %w", err)
+		}
+		return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+	}
+	before, err := render(diff.Before)
+	if err != nil {
+		return err
+	}
+	after, err := render(diff.After)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, line := range diffLines(before, after) {
+		fmt.Fprintf(w, "  %s%s\n", line.symbol, line.text)
+	}
+	return nil
+}
+
+type diffLine struct {
+	symbol string
+	text   string
+}
+
+// diffLines returns the lines of both texts, marking those only in before with '-' and those only in after with '+'
+func diffLines(before, after []string) []diffLine {
+	// common[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			lines = append(lines, diffLine{" ", before[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, diffLine{"-", before[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{"+", after[j]})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		lines = append(lines, diffLine{"-", before[i]})
+	}
+	for ; j < len(after); j++ {
+		lines = append(lines, diffLine{"+", after[j]})
+	}
+	return lines
+}
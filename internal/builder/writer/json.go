@@ -9,7 +9,7 @@ type JSON struct {
 	StructuredFormat
 }
 
-func NewJSON() BuilderWriter {
+func NewJSON() *JSON {
 	return &JSON{
 		StructuredFormat: StructuredFormat{
 			MarshalFunc: func(i interface{}) ([]byte, error) {
@@ -139,6 +139,17 @@ func (w *StructuredFormat) Print(
 	return nil
 }
 
+func (w *StructuredFormat) PrintDiff(logger logging.Logger, diff *DiffOutput) error {
+	output, err := w.MarshalFunc(diff)
+	if err != nil {
+		return fmt.Errorf("untested, unexpected failure while marshaling: %w", err)
+	}
+
+	logger.Info(string(output))
+
+	return nil
+}
+
 func runImages(runImages []pubbldr.RunImageConfig, localRunImages []config.RunImage) []RunImage {
 	images := []RunImage{}
 
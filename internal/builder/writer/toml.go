@@ -10,7 +10,7 @@ type TOML struct {
 	StructuredFormat
 }
 
-func NewTOML() BuilderWriter {
+func NewTOML() *TOML {
 	return &TOML{
 		StructuredFormat: StructuredFormat{
 			MarshalFunc: func(v interface{}) ([]byte, error) {
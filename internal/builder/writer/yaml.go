@@ -10,7 +10,7 @@ type YAML struct {
 	StructuredFormat
 }
 
-func NewYAML() BuilderWriter {
+func NewYAML() *YAML {
 	return &YAML{
 		StructuredFormat: StructuredFormat{
 			MarshalFunc: func(v interface{}) ([]byte, error) {
@@ -34,7 +34,7 @@ func AddBuildpackRegistry(logger logging.Logger, cfg config.Config, cfgPath stri
 		}),
 	}
 	cmd.Flags().BoolVar(&setDefault, "default", false, "Set this buildpack registry as the default")
-	cmd.Flags().StringVar(&registryType, "type", "github", "Type of buildpack registry [git|github]")
+	cmd.Flags().StringVar(&registryType, "type", "github", "Type of buildpack registry [git|github|oci]")
 	AddHelpFlag(cmd, "add-registry")
 
 	return cmd
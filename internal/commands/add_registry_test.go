@@ -92,7 +92,7 @@ func testAddRegistryCommand(t *testing.T, when spec.G, it spec.S) {
 				assert.Error(command.Execute())
 
 				output := outBuf.String()
-				h.AssertContains(t, output, "'bogus' is not a valid type. Supported types are: 'git', 'github'.")
+				h.AssertContains(t, output, "'bogus' is not a valid type. Supported types are: 'git', 'github', 'oci'.")
 			})
 
 			it("should throw error when registry already exists", func() {
@@ -19,12 +19,19 @@ import (
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
 	"github.com/buildpacks/pack/pkg/project"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 )
 
+const (
+	outputFormatHumanReadable = "human-readable"
+	outputFormatJSONL         = "jsonl"
+	outputFormatJSON          = "json"
+)
+
 type BuildFlags struct {
 	Publish                bool
 	ClearCache             bool
@@ -34,6 +41,8 @@ type BuildFlags struct {
 	Interactive            bool
 	Sparse                 bool
 	EnableUsernsHost       bool
+	DryRun                 bool
+	Watch                  bool
 	DockerHost             string
 	CacheImage             string
 	Cache                  cache.CacheOpts
@@ -60,10 +69,14 @@ type BuildFlags struct {
 	PreviousImage          string
 	SBOMDestinationDir     string
 	ReportDestinationDir   string
+	ProvenanceDestDir      string
 	DateTime               string
 	PreBuildpacks          []string
 	PostBuildpacks         []string
 	InsecureRegistries     []string
+	OutputFormat           string
+	VerifyKey              string
+	PolicyPath             string
 }
 
 // Build an image from source code
@@ -82,158 +95,242 @@ func Build(logger logging.Logger, cfg config.Config, packClient PackClient) *cob
 			"on how to use `pack build`, see: https://buildpacks.io/docs/app-developer-guide/build-an-app/.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			inputImageName := client.ParseInputImageReference(args[0])
-			if err := validateBuildFlags(&flags, cfg, inputImageName, logger); err != nil {
-				return err
-			}
-
-			inputPreviousImage := client.ParseInputImageReference(flags.PreviousImage)
-
-			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
+			buildOpts, err := resolveBuildOptions(cmd, &flags, cfg, logger, packClient, inputImageName)
 			if err != nil {
 				return err
 			}
 
-			if actualDescriptorPath != "" {
-				logger.Debugf("Using project descriptor located at %s", style.Symbol(actualDescriptorPath))
+			var plan client.BuildPlan
+			if flags.DryRun {
+				buildOpts.PlanHandler = func(p client.BuildPlan) {
+					plan = p
+				}
 			}
 
-			builder := flags.Builder
-			// We only override the builder to the one in the project descriptor
-			// if it was not explicitly set by the user
-			if !cmd.Flags().Changed("builder") && descriptor.Build.Builder != "" {
-				builder = descriptor.Build.Builder
+			if flags.Watch {
+				return packClient.WatchBuild(cmd.Context(), client.WatchBuildOptions{
+					BuildOptions: buildOpts,
+					CycleHandler: func(cycle client.WatchCycle) {
+						logWatchCycle(logger, inputImageName.Name(), cycle)
+					},
+				})
 			}
 
-			if builder == "" {
-				suggestSettingBuilder(logger, packClient)
-				return client.NewSoftError()
+			if err := packClient.Build(cmd.Context(), buildOpts); err != nil {
+				return errors.Wrap(err, "failed to build")
+			}
+			if flags.DryRun {
+				return writeBuildPlan(logger.Writer(), plan, flags.OutputFormat)
 			}
+			logger.Infof("Successfully built image %s", style.Symbol(inputImageName.Name()))
+			return nil
+		}),
+	}
+	buildCommandFlags(cmd, &flags, cfg)
+	AddHelpFlag(cmd, "build")
+	return cmd
+}
 
-			buildpacks := flags.Buildpacks
-			extensions := flags.Extensions
+// resolveBuildOptions validates the build flags and resolves them, together with the project descriptor and config,
+// into the options of a build of inputImageName
+func resolveBuildOptions(cmd *cobra.Command, flags *BuildFlags, cfg config.Config, logger logging.Logger, packClient PackClient, inputImageName client.InputImageReference) (client.BuildOptions, error) {
+	if err := validateBuildFlags(flags, cfg, inputImageName, logger); err != nil {
+		return client.BuildOptions{}, err
+	}
 
-			env, err := parseEnv(flags.EnvFiles, flags.Env)
-			if err != nil {
-				return err
-			}
+	inputPreviousImage := client.ParseInputImageReference(flags.PreviousImage)
 
-			isTrusted, err := bldr.IsTrustedBuilder(cfg, builder)
-			if err != nil {
-				return err
-			}
-			trustBuilder := isTrusted || bldr.IsKnownTrustedBuilder(builder) || flags.TrustBuilder
-			if trustBuilder {
-				logger.Debugf("Builder %s is trusted", style.Symbol(builder))
-				if flags.LifecycleImage != "" {
-					logger.Warn("Ignoring the provided lifecycle image as the builder is trusted, running the creator in a single container using the provided builder")
-				}
-			} else {
-				logger.Debugf("Builder %s is untrusted", style.Symbol(builder))
-				logger.Debug("As a result, the phases of the lifecycle which require root access will be run in separate trusted ephemeral containers.")
-				logger.Debug("For more information, see https://medium.com/buildpacks/faster-more-secure-builds-with-pack-0-11-0-4d0c633ca619")
-			}
+	descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
+	if err != nil {
+		return client.BuildOptions{}, err
+	}
 
-			if !trustBuilder && len(flags.Volumes) > 0 {
-				logger.Warn("Using untrusted builder with volume mounts. If there is sensitive data in the volumes, this may present a security vulnerability.")
-			}
+	if actualDescriptorPath != "" {
+		logger.Debugf("Using project descriptor located at %s", style.Symbol(actualDescriptorPath))
+	}
 
-			stringPolicy := flags.Policy
-			if stringPolicy == "" {
-				stringPolicy = cfg.PullPolicy
-			}
-			pullPolicy, err := image.ParsePullPolicy(stringPolicy)
-			if err != nil {
-				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
-			}
+	builder := flags.Builder
+	// We only override the builder to the one in the project descriptor
+	// if it was not explicitly set by the user
+	if !cmd.Flags().Changed("builder") && descriptor.Build.Builder != "" {
+		builder = descriptor.Build.Builder
+	}
 
-			var lifecycleImage string
-			if flags.LifecycleImage != "" {
-				ref, err := name.ParseReference(flags.LifecycleImage)
-				if err != nil {
-					return errors.Wrapf(err, "parsing lifecycle image %s", flags.LifecycleImage)
-				}
-				lifecycleImage = ref.Name()
-			}
+	if builder == "" {
+		suggestSettingBuilder(logger, packClient)
+		return client.BuildOptions{}, client.NewSoftError()
+	}
 
-			err = isForbiddenTag(cfg, inputImageName.Name(), lifecycleImage, builder)
-			if err != nil {
-				return errors.Wrapf(err, "forbidden image name")
-			}
+	buildpacks := flags.Buildpacks
+	extensions := flags.Extensions
 
-			var gid = -1
-			if cmd.Flags().Changed("gid") {
-				gid = flags.GID
-			}
+	env, err := parseEnv(flags.EnvFiles, flags.Env)
+	if err != nil {
+		return client.BuildOptions{}, err
+	}
 
-			var uid = -1
-			if cmd.Flags().Changed("uid") {
-				uid = flags.UID
-			}
+	isTrusted, err := bldr.IsTrustedBuilder(cfg, builder)
+	if err != nil {
+		return client.BuildOptions{}, err
+	}
+	trustBuilder := isTrusted || bldr.IsKnownTrustedBuilder(builder) || flags.TrustBuilder
+	builderKey, err := bldr.TrustedBuilderKey(cfg, builder)
+	if err != nil {
+		return client.BuildOptions{}, err
+	}
 
-			dateTime, err := parseTime(flags.DateTime)
-			if err != nil {
-				return errors.Wrapf(err, "parsing creation time %s", flags.DateTime)
-			}
-			if err := packClient.Build(cmd.Context(), client.BuildOptions{
-				AppPath:           flags.AppPath,
-				Builder:           builder,
-				Registry:          flags.Registry,
-				AdditionalMirrors: getMirrors(cfg),
-				AdditionalTags:    flags.AdditionalTags,
-				RunImage:          flags.RunImage,
-				Env:               env,
-				Image:             inputImageName.Name(),
-				Publish:           flags.Publish,
-				DockerHost:        flags.DockerHost,
-				Platform:          flags.Platform,
-				PullPolicy:        pullPolicy,
-				ClearCache:        flags.ClearCache,
-				TrustBuilder: func(string) bool {
-					return trustBuilder
-				},
-				TrustExtraBuildpacks: flags.TrustExtraBuildpacks,
-				Buildpacks:           buildpacks,
-				Extensions:           extensions,
-				ContainerConfig: client.ContainerConfig{
-					Network: flags.Network,
-					Volumes: flags.Volumes,
-				},
-				DefaultProcessType:       flags.DefaultProcessType,
-				ProjectDescriptorBaseDir: filepath.Dir(actualDescriptorPath),
-				ProjectDescriptor:        descriptor,
-				Cache:                    flags.Cache,
-				CacheImage:               flags.CacheImage,
-				Workspace:                flags.Workspace,
-				LifecycleImage:           lifecycleImage,
-				GroupID:                  gid,
-				UserID:                   uid,
-				PreviousImage:            inputPreviousImage.Name(),
-				Interactive:              flags.Interactive,
-				SBOMDestinationDir:       flags.SBOMDestinationDir,
-				ReportDestinationDir:     flags.ReportDestinationDir,
-				CreationTime:             dateTime,
-				PreBuildpacks:            flags.PreBuildpacks,
-				PostBuildpacks:           flags.PostBuildpacks,
-				DisableSystemBuildpacks:  flags.DisableSystemBuilpacks,
-				EnableUsernsHost:         flags.EnableUsernsHost,
-				LayoutConfig: &client.LayoutConfig{
-					Sparse:             flags.Sparse,
-					InputImage:         inputImageName,
-					PreviousInputImage: inputPreviousImage,
-					LayoutRepoDir:      cfg.LayoutRepositoryDir,
-				},
-				CNBExecutionEnv:    flags.ExecutionEnv,
-				InsecureRegistries: flags.InsecureRegistries,
-			}); err != nil {
-				return errors.Wrap(err, "failed to build")
-			}
-			logger.Infof("Successfully built image %s", style.Symbol(inputImageName.Name()))
-			return nil
-		}),
+	var imageKey []byte
+	if flags.VerifyKey != "" {
+		if imageKey, err = os.ReadFile(filepath.Clean(flags.VerifyKey)); err != nil {
+			return client.BuildOptions{}, errors.Wrap(err, "reading verify key")
+		}
 	}
-	buildCommandFlags(cmd, &flags, cfg)
-	AddHelpFlag(cmd, "build")
-	return cmd
+	buildPolicy, err := readPolicy(flags.PolicyPath, cfg)
+	if err != nil {
+		return client.BuildOptions{}, err
+	}
+
+	if trustBuilder {
+		logger.Debugf("Builder %s is trusted", style.Symbol(builder))
+		if flags.LifecycleImage != "" {
+			logger.Warn("Ignoring the provided lifecycle image as the builder is trusted, running the creator in a single container using the provided builder")
+		}
+	} else {
+		logger.Debugf("Builder %s is untrusted", style.Symbol(builder))
+		logger.Debug("As a result, the phases of the lifecycle which require root access will be run in separate trusted ephemeral containers.")
+		logger.Debug("For more information, see https://medium.com/buildpacks/faster-more-secure-builds-with-pack-0-11-0-4d0c633ca619")
+	}
+
+	if !trustBuilder && len(flags.Volumes) > 0 {
+		logger.Warn("Using untrusted builder with volume mounts. If there is sensitive data in the volumes, this may present a security vulnerability.")
+	}
+
+	stringPolicy := flags.Policy
+	if stringPolicy == "" {
+		stringPolicy = cfg.PullPolicy
+	}
+	pullPolicy, err := image.ParsePullPolicy(stringPolicy)
+	if err != nil {
+		return client.BuildOptions{}, errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+	}
+
+	var lifecycleImage string
+	if flags.LifecycleImage != "" {
+		ref, err := name.ParseReference(flags.LifecycleImage)
+		if err != nil {
+			return client.BuildOptions{}, errors.Wrapf(err, "parsing lifecycle image %s", flags.LifecycleImage)
+		}
+		lifecycleImage = ref.Name()
+	}
+
+	err = isForbiddenTag(cfg, inputImageName.Name(), lifecycleImage, builder)
+	if err != nil {
+		return client.BuildOptions{}, errors.Wrapf(err, "forbidden image name")
+	}
+
+	var gid = -1
+	if cmd.Flags().Changed("gid") {
+		gid = flags.GID
+	}
+
+	var uid = -1
+	if cmd.Flags().Changed("uid") {
+		uid = flags.UID
+	}
+
+	dateTime, err := parseTime(flags.DateTime)
+	if err != nil {
+		return client.BuildOptions{}, errors.Wrapf(err, "parsing creation time %s", flags.DateTime)
+	}
+
+	var eventHandler events.Handler
+	if flags.OutputFormat == outputFormatJSONL && !flags.DryRun {
+		// keep the logs of the build out of the event stream
+		logging.UseStderr(logger)
+		eventHandler = events.NewJSONLHandler(logger.Writer())
+	}
+
+	return client.BuildOptions{
+		AppPath:           flags.AppPath,
+		Builder:           builder,
+		Registry:          flags.Registry,
+		AdditionalMirrors: getMirrors(cfg),
+		AdditionalTags:    flags.AdditionalTags,
+		RunImage:          flags.RunImage,
+		Env:               env,
+		Image:             inputImageName.Name(),
+		Publish:           flags.Publish,
+		DockerHost:        flags.DockerHost,
+		Platform:          flags.Platform,
+		PullPolicy:        pullPolicy,
+		ClearCache:        flags.ClearCache,
+		TrustBuilder: func(string) bool {
+			return trustBuilder
+		},
+		TrustExtraBuildpacks: flags.TrustExtraBuildpacks,
+		BuilderKey:           []byte(builderKey),
+		ImageKey:             imageKey,
+		Buildpacks:           buildpacks,
+		Extensions:           extensions,
+		ContainerConfig: client.ContainerConfig{
+			Network: flags.Network,
+			Volumes: flags.Volumes,
+		},
+		DefaultProcessType:       flags.DefaultProcessType,
+		ProjectDescriptorBaseDir: filepath.Dir(actualDescriptorPath),
+		ProjectDescriptor:        descriptor,
+		Cache:                    flags.Cache,
+		CacheImage:               flags.CacheImage,
+		Workspace:                flags.Workspace,
+		LifecycleImage:           lifecycleImage,
+		GroupID:                  gid,
+		UserID:                   uid,
+		PreviousImage:            inputPreviousImage.Name(),
+		Interactive:              flags.Interactive,
+		SBOMDestinationDir:       flags.SBOMDestinationDir,
+		ReportDestinationDir:     flags.ReportDestinationDir,
+		ProvenanceDestinationDir: flags.ProvenanceDestDir,
+		CreationTime:             dateTime,
+		PreBuildpacks:            flags.PreBuildpacks,
+		PostBuildpacks:           flags.PostBuildpacks,
+		DisableSystemBuildpacks:  flags.DisableSystemBuilpacks,
+		EnableUsernsHost:         flags.EnableUsernsHost,
+		LayoutConfig: &client.LayoutConfig{
+			Sparse:             flags.Sparse,
+			InputImage:         inputImageName,
+			PreviousInputImage: inputPreviousImage,
+			LayoutRepoDir:      cfg.LayoutRepositoryDir,
+		},
+		CNBExecutionEnv:    flags.ExecutionEnv,
+		InsecureRegistries: flags.InsecureRegistries,
+		EventHandler:       eventHandler,
+		DryRun:             flags.DryRun,
+		Policy:             buildPolicy,
+	}, nil
+}
+
+// logWatchCycle reports the outcome of a build run by 'pack build --watch'
+func logWatchCycle(logger logging.Logger, imageName string, cycle client.WatchCycle) {
+	duration := cycle.Duration.Round(100 * time.Millisecond)
+	if cycle.Err != nil {
+		logger.Errorf("Build #%d failed after %s: %s", cycle.Number, duration, cycle.Err)
+		return
+	}
+
+	logger.Infof("Successfully built image %s (build #%d, %s)", style.Symbol(imageName), cycle.Number, duration)
+	logger.Infof("  Layers rebuilt: %s", layerList(cycle.Rebuilt))
+	logger.Infof("  Layers reused: %s", layerList(cycle.Reused))
+}
+
+func layerList(layers []string) string {
+	if len(layers) == 0 {
+		return "none"
+	}
+	var symbols []string
+	for _, layer := range layers {
+		symbols = append(symbols, style.Symbol(layer))
+	}
+	return strings.Join(symbols, ", ")
 }
 
 func parseTime(providedTime string) (*time.Time, error) {
@@ -265,6 +362,8 @@ func buildCommandFlags(cmd *cobra.Command, buildFlags *BuildFlags, cfg config.Co
 - Cache as image (requires --publish): 'type=<build/launch>;format=image;name=<registry image name>'
 - Cache as volume: 'type=<build/launch>;format=volume;[name=<volume name>]'
     - If no name is provided, a random name will be generated.
+- Cache in an S3-compatible bucket: 'type=build;format=s3;bucket=<bucket>;[prefix=<key prefix>];[endpoint=<url>];[region=<region>]'
+    - Credentials, and the region and endpoint if not provided, are read from the standard AWS environment and config files.
 `)
 	cmd.Flags().StringVar(&buildFlags.CacheImage, "cache-image", "", `Cache build layers in remote registry. Requires --publish`)
 	cmd.Flags().BoolVar(&buildFlags.ClearCache, "clear-cache", false, "Clear image's associated cache before building")
@@ -293,16 +392,22 @@ This option may set DOCKER_HOST environment variable for the build container if
 	cmd.Flags().StringSliceVarP(&buildFlags.AdditionalTags, "tag", "t", nil, "Additional tags to push the output image to.\nTags should be in the format 'image:tag' or 'repository/image:tag'."+stringSliceHelp("tag"))
 	cmd.Flags().BoolVar(&buildFlags.TrustBuilder, "trust-builder", false, "Trust the provided builder.\nAll lifecycle phases will be run in a single container.\nFor more on trusted builders, and when to trust or untrust a builder, check out our docs here: https://buildpacks.io/docs/tools/pack/concepts/trusted_builders")
 	cmd.Flags().BoolVar(&buildFlags.TrustExtraBuildpacks, "trust-extra-buildpacks", false, "Trust buildpacks that are provided in addition to the buildpacks on the builder")
+	cmd.Flags().StringVar(&buildFlags.VerifyKey, "verify-key", "", "Path to a PEM encoded public key to verify the signatures of the run image and of buildpack package images with.\nThe build fails when one of them isn't signed with the key.")
+	cmd.Flags().StringVar(&buildFlags.PolicyPath, "policy", "", "Path to a policy file restricting the builder, run image, buildpacks and volumes of the build.\nDefaults to the policy set in the pack config, if any.")
 	cmd.Flags().StringArrayVar(&buildFlags.Volumes, "volume", nil, "Mount host volume into the build container, in the form '<host path>:<target path>[:<options>]'.\n- 'host path': Name of the volume or absolute directory path to mount.\n- 'target path': The path where the file or directory is available in the container.\n- 'options' (default \"ro\"): An optional comma separated list of mount options.\n    - \"ro\", volume contents are read-only.\n    - \"rw\", volume contents are readable and writeable.\n    - \"volume-opt=<key>=<value>\", can be specified more than once, takes a key-value pair consisting of the option name and its value."+stringArrayHelp("volume"))
 	cmd.Flags().StringVar(&buildFlags.Workspace, "workspace", "", "Location at which to mount the app dir in the build image")
 	cmd.Flags().IntVar(&buildFlags.GID, "gid", 0, `Override GID of user's group in the stack's build and run images. The provided value must be a positive number`)
 	cmd.Flags().IntVar(&buildFlags.UID, "uid", 0, `Override UID of user in the stack's build and run images. The provided value must be a positive number`)
 	cmd.Flags().StringVar(&buildFlags.PreviousImage, "previous-image", "", "Set previous image to a particular tag reference, digest reference, or (when performing a daemon build) image ID")
 	cmd.Flags().StringVar(&buildFlags.SBOMDestinationDir, "sbom-output-dir", "", "Path to export SBoM contents.\nOmitting the flag will yield no SBoM content.")
-	cmd.Flags().StringVar(&buildFlags.ReportDestinationDir, "report-output-dir", "", "Path to export build report.toml.\nOmitting the flag yield no report file.")
+	cmd.Flags().StringVar(&buildFlags.ReportDestinationDir, "report-output-dir", "", "Path to export build report.toml and build-metrics.json.\nOmitting the flag yield no report files.")
+	cmd.Flags().StringVar(&buildFlags.ProvenanceDestDir, "provenance-output-dir", "", "Path to export an in-toto statement with the SLSA provenance of the image.\nWhen publishing, the statement is also attached to the image as an OCI referrer; otherwise the image is identified by its image ID.\nOmitting the flag yields no provenance.")
 	cmd.Flags().BoolVar(&buildFlags.Interactive, "interactive", false, "Launch a terminal UI to depict the build process")
 	cmd.Flags().BoolVar(&buildFlags.Sparse, "sparse", false, "Use this flag to avoid saving on disk the run-image layers when the application image is exported to OCI layout format")
 	cmd.Flags().BoolVar(&buildFlags.EnableUsernsHost, "userns-host", false, "Enable user namespace isolation for the build containers")
+	cmd.Flags().StringVar(&buildFlags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format for build progress (human-readable, jsonl).\n'jsonl' writes one JSON build event per line to stdout, and the build logs to stderr.\nWith --dry-run, the plan is printed as text, or as JSON when set to 'json' or 'jsonl'.")
+	cmd.Flags().BoolVar(&buildFlags.Watch, "watch", false, "Rebuild the image whenever the files in the app dir change, honoring the include and exclude lists of the project descriptor")
+	cmd.Flags().BoolVar(&buildFlags.DryRun, "dry-run", false, "Resolve the builder, run image, lifecycle, buildpacks, env vars, volumes and caches and print the build plan without building.\nImages are read from the daemon or the registry rather than pulled; buildpack archives are still downloaded.")
 	if !cfg.Experimental {
 		cmd.Flags().MarkHidden("interactive")
 		cmd.Flags().MarkHidden("sparse")
@@ -351,6 +456,32 @@ func validateBuildFlags(flags *BuildFlags, cfg config.Config, inputImageRef clie
 			inputImageRef.Name(), inputImageRef.Name())
 	}
 
+	if flags.DryRun && flags.Interactive {
+		return errors.New("dry-run flag cannot be used with the 'interactive' flag")
+	}
+
+	if flags.Watch && flags.DryRun {
+		return errors.New("watch flag cannot be used with the 'dry-run' flag")
+	}
+
+	if flags.Watch && flags.Interactive {
+		return errors.New("watch flag cannot be used with the 'interactive' flag")
+	}
+
+	switch flags.OutputFormat {
+	case outputFormatHumanReadable:
+	case outputFormatJSONL:
+		if flags.Interactive {
+			return errors.New("output format 'jsonl' cannot be used with the 'interactive' flag")
+		}
+	case outputFormatJSON:
+		if !flags.DryRun {
+			return errors.New("output format 'json' requires the 'dry-run' flag")
+		}
+	default:
+		return errors.Errorf("unsupported output format %s; must be one of: %s, %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSONL, outputFormatJSON)
+	}
+
 	if flags.ExecutionEnv != "" && flags.ExecutionEnv != "production" && flags.ExecutionEnv != "test" {
 		// RFC: the / character is reserved in case we need to introduce namespacing in the future.
 		var executionEnvRegex = regexp.MustCompile(`^[a-zA-Z0-9.-]+$`)
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+const buildPlanTemplate = `Image: {{ .Image }}
+{{- if .AdditionalTags }}
+Additional Tags:
+{{- range .AdditionalTags }}
+  {{ . }}
+{{- end }}
+{{- end }}
+Publish: {{ .Publish }}
+
+Builder: {{ .Builder }}{{ if .EphemeralBuilder }} (extended for this build){{ end }}
+Trusted: {{ yesNo .TrustedBuilder }}
+Lifecycle:
+  Version: {{ .LifecycleVersion }}
+  Platform API: {{ .PlatformAPI }}
+  Flow: {{ if .UseCreator }}creator (single container){{ else }}untrusted (separate containers per phase){{ end }}
+{{- if .LifecycleImage }}
+  Image: {{ .LifecycleImage }}
+{{- end }}
+Platform: {{ .Platform }}
+
+Run Image: {{ .RunImage }}
+
+Buildpacks Added:
+{{- if .Buildpacks }}
+{{- range .Buildpacks }}
+  {{ .FullName }}
+{{- end }}
+{{- else }}
+  (none)
+{{- end }}
+
+Extensions Added:
+{{- if .Extensions }}
+{{- range .Extensions }}
+  {{ .FullName }}
+{{- end }}
+{{- else }}
+  (none)
+{{- end }}
+
+Detection Order:
+{{- if .Order }}
+{{ order .Order }}
+{{- else }}
+  (none)
+{{- end }}
+{{- if .OrderExtensions }}
+
+Extensions Order:
+{{ order .OrderExtensions }}
+{{- end }}
+
+Env Vars:
+{{- if .Env }}
+{{- range .Env }}
+  {{ . }}
+{{- end }}
+{{- else }}
+  (none)
+{{- end }}
+
+Volumes:
+{{- if .Volumes }}
+{{- range .Volumes }}
+  {{ . }}
+{{- end }}
+{{- else }}
+  (none)
+{{- end }}
+
+Network: {{ if .Network }}{{ .Network }}{{ else }}(ephemeral){{ end }}
+{{- if .Workspace }}
+Workspace: {{ .Workspace }}
+{{- end }}
+
+Caches:
+  Build: {{ or .Caches.Build.Name "(named by the first build)" }} ({{ .Caches.Build.Format }})
+  Launch: {{ or .Caches.Launch.Name "(named by the first build)" }} ({{ .Caches.Launch.Format }})
+  Kaniko: {{ or .Caches.Kaniko.Name "(named by the first build)" }} ({{ .Caches.Kaniko.Format }})
+`
+
+func writeBuildPlan(w io.Writer, plan client.BuildPlan, format string) error {
+	switch format {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	case outputFormatJSONL:
+		return json.NewEncoder(w).Encode(plan)
+	}
+
+	tpl := template.Must(template.New("build-plan").Funcs(template.FuncMap{
+		"yesNo": func(b bool) string {
+			if b {
+				return "Yes"
+			}
+			return "No"
+		},
+		"order": buildPlanOrder,
+	}).Parse(buildPlanTemplate))
+	return tpl.Execute(w, plan)
+}
+
+func buildPlanOrder(order dist.Order) string {
+	var lines []string
+	for i, entry := range order {
+		lines = append(lines, fmt.Sprintf("  Group #%d:", i+1))
+		for _, ref := range entry.Group {
+			line := "    " + ref.FullName()
+			if ref.Optional {
+				line += " (optional)"
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,8 @@ package commands_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,8 +24,11 @@ import (
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/policy"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -113,6 +118,77 @@ func testBuildCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("the builder is trusted with a key", func() {
+				it("sets the builder key option", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithBuilderKey("some-key")).
+						Return(nil)
+
+					cfg := config.Config{TrustedBuilders: []config.TrustedBuilder{{Name: "my-builder", Key: "some-key"}}}
+					command = commands.Build(logger, cfg, mockClient)
+					command.SetArgs([]string{"image", "--builder", "my-builder"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("--verify-key", func() {
+				it("sets the image key option to the contents of the key", func() {
+					keyPath := filepath.Join(t.TempDir(), "cosign.pub")
+					h.AssertNil(t, os.WriteFile(keyPath, []byte("some-key"), 0600))
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithImageKey("some-key")).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--verify-key", keyPath})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("errors when the key can't be read", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--verify-key", "some-missing-key"})
+					h.AssertError(t, command.Execute(), "reading verify key")
+				})
+			})
+
+			when("--policy", func() {
+				it("sets the policy option to the policy read from the file", func() {
+					policyPath := filepath.Join(t.TempDir(), "policy.toml")
+					h.AssertNil(t, os.WriteFile(policyPath, []byte("[builders]\nallowed = [\"registry.example.com\"]\n"), 0600))
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPolicy(&policy.Policy{Builders: policy.ImageRule{Allowed: []string{"registry.example.com"}}})).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--policy", policyPath})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("defaults to the policy of the config", func() {
+					policyPath := filepath.Join(t.TempDir(), "policy.toml")
+					h.AssertNil(t, os.WriteFile(policyPath, []byte("audit-only = true\n"), 0600))
+					cfg.Policy = policyPath
+					command = commands.Build(logger, cfg, mockClient)
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPolicy(&policy.Policy{AuditOnly: true})).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("doesn't set a policy when there is none", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithPolicy(nil)).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder"})
+					h.AssertNil(t, command.Execute())
+				})
+
+				it("errors when the policy can't be read", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--policy", "some-missing-policy.toml"})
+					h.AssertError(t, command.Execute(), "reading policy 'some-missing-policy.toml'")
+				})
+			})
+
 			when("the builder is known to be trusted and suggested", func() {
 				it("sets the trust builder option", func() {
 					mockClient.EXPECT().
@@ -899,6 +975,17 @@ builder = "my-builder"
 			})
 		})
 
+		when("provenance destination directory is provided", func() {
+			it("forwards it onto the client", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithProvenanceOutputDir("some-output-dir")).
+					Return(nil)
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--provenance-output-dir", "some-output-dir"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
 		when("--creation-time", func() {
 			when("provided as 'now'", func() {
 				it("passes it to the builder", func() {
@@ -1087,6 +1174,172 @@ builder = "my-builder"
 				h.AssertNil(t, command.Execute())
 			})
 		})
+
+		when("--output-format", func() {
+			when("is not provided", func() {
+				it("does not set an event handler", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEventHandler(false)).
+						Return(nil)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder"})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
+			when("is jsonl", func() {
+				it("writes build events as json lines, and the logs to stderr", func() {
+					var errBuf bytes.Buffer
+					logger = logging.NewLogWithWriters(&outBuf, &errBuf)
+					command = commands.Build(logger, cfg, mockClient)
+
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithEventHandler(true)).
+						DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+							logger.Info("===> DETECTING")
+							opts.EventHandler(events.Event{Type: events.PhaseStarted, Phase: "detector"})
+							return nil
+						})
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output-format", "jsonl"})
+					h.AssertNil(t, command.Execute())
+					h.AssertContains(t, outBuf.String(), `{"type":"phase-started",`)
+					h.AssertContains(t, outBuf.String(), `"phase":"detector"}`)
+					h.AssertNotContains(t, outBuf.String(), "===> DETECTING")
+					h.AssertContains(t, errBuf.String(), "===> DETECTING")
+				})
+
+				when("--interactive is provided", func() {
+					it("errors", func() {
+						cfg.Experimental = true
+						command = commands.Build(logger, cfg, mockClient)
+
+						command.SetArgs([]string{"image", "--builder", "my-builder", "--output-format", "jsonl", "--interactive"})
+						h.AssertError(t, command.Execute(), "output format 'jsonl' cannot be used with the 'interactive' flag")
+					})
+				})
+			})
+
+			when("is unsupported", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output-format", "xml"})
+					h.AssertError(t, command.Execute(), "unsupported output format 'xml'")
+				})
+			})
+		})
+
+		when("--dry-run", func() {
+			var plan client.BuildPlan
+
+			it.Before(func() {
+				plan = client.BuildPlan{
+					Image:          "index.docker.io/library/image:latest",
+					Builder:        "my-builder",
+					RunImage:       "some/run-image",
+					TrustedBuilder: true,
+					UseCreator:     true,
+					Buildpacks:     []dist.ModuleInfo{{ID: "some/bp", Version: "1.2.3"}},
+					Order: dist.Order{{Group: []dist.ModuleRef{
+						{ModuleInfo: dist.ModuleInfo{ID: "some/bp", Version: "1.2.3"}},
+						{ModuleInfo: dist.ModuleInfo{ID: "other/bp", Version: "4.5.6"}, Optional: true},
+					}}},
+					Env: []string{"KEY"},
+					Caches: client.BuildPlanCaches{
+						Build:  client.BuildPlanCache{Format: "volume", Name: "some-build-volume"},
+						Launch: client.BuildPlanCache{Format: "volume", Name: "some-launch-volume"},
+						Kaniko: client.BuildPlanCache{Format: "volume", Name: "some-kaniko-volume"},
+					},
+				}
+			})
+
+			it("prints the resolved plan", func() {
+				mockClient.EXPECT().
+					Build(gomock.Any(), EqBuildOptionsWithDryRun(true)).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						opts.PlanHandler(plan)
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--dry-run"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Builder: my-builder")
+				h.AssertContains(t, outBuf.String(), "Trusted: Yes")
+				h.AssertContains(t, outBuf.String(), "Run Image: some/run-image")
+				h.AssertContains(t, outBuf.String(), "  Group #1:\n    some/bp@1.2.3\n    other/bp@4.5.6 (optional)")
+				h.AssertContains(t, outBuf.String(), "Build: some-build-volume (volume)")
+				h.AssertNotContains(t, outBuf.String(), "Successfully built image")
+			})
+
+			when("--output-format is json", func() {
+				it("prints the resolved plan as json", func() {
+					mockClient.EXPECT().
+						Build(gomock.Any(), EqBuildOptionsWithDryRun(true)).
+						DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+							opts.PlanHandler(plan)
+							return nil
+						})
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--dry-run", "--output-format", "json"})
+					h.AssertNil(t, command.Execute())
+
+					var output client.BuildPlan
+					h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &output))
+					h.AssertEq(t, output, plan)
+				})
+			})
+
+			when("--interactive is provided", func() {
+				it("errors", func() {
+					cfg.Experimental = true
+					command = commands.Build(logger, cfg, mockClient)
+
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--dry-run", "--interactive"})
+					h.AssertError(t, command.Execute(), "dry-run flag cannot be used with the 'interactive' flag")
+				})
+			})
+
+			when("--output-format is json without --dry-run", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--output-format", "json"})
+					h.AssertError(t, command.Execute(), "output format 'json' requires the 'dry-run' flag")
+				})
+			})
+		})
+
+		when("--watch", func() {
+			it("watches the app with the same build options and reports each build", func() {
+				mockClient.EXPECT().
+					WatchBuild(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.WatchBuildOptions) error {
+						h.AssertEq(t, opts.Builder, "my-builder")
+						h.AssertEq(t, opts.Image, "image")
+						h.AssertEq(t, opts.TrustBuilder("my-builder"), false)
+
+						opts.CycleHandler(client.WatchCycle{
+							Number:   2,
+							Rebuilt:  []string{"some/bp:app"},
+							Reused:   []string{"some/bp:deps", "other/bp:tools"},
+							Duration: 1500 * time.Millisecond,
+						})
+						opts.CycleHandler(client.WatchCycle{Number: 3, Err: errors.New("some-build-error")})
+						return nil
+					})
+
+				command.SetArgs([]string{"image", "--builder", "my-builder", "--watch"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Successfully built image 'image' (build #2, 1.5s)")
+				h.AssertContains(t, outBuf.String(), "Layers rebuilt: 'some/bp:app'")
+				h.AssertContains(t, outBuf.String(), "Layers reused: 'some/bp:deps', 'other/bp:tools'")
+				h.AssertContains(t, outBuf.String(), "Build #3 failed after 0s: some-build-error")
+			})
+
+			when("--dry-run is provided", func() {
+				it("errors", func() {
+					command.SetArgs([]string{"image", "--builder", "my-builder", "--watch", "--dry-run"})
+					h.AssertError(t, command.Execute(), "watch flag cannot be used with the 'dry-run' flag")
+				})
+			})
+		})
 	})
 
 	when("export to OCI layout is expected", func() {
@@ -1238,6 +1491,33 @@ func EqBuildOptionsWithTrustedBuilder(trustBuilder bool) gomock.Matcher {
 	}
 }
 
+func EqBuildOptionsWithBuilderKey(key string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("BuilderKey=%s", key),
+		equals: func(o client.BuildOptions) bool {
+			return string(o.BuilderKey) == key
+		},
+	}
+}
+
+func EqBuildOptionsWithImageKey(key string) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("ImageKey=%s", key),
+		equals: func(o client.BuildOptions) bool {
+			return string(o.ImageKey) == key
+		},
+	}
+}
+
+func EqBuildOptionsWithPolicy(p *policy.Policy) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("Policy=%+v", p),
+		equals: func(o client.BuildOptions) bool {
+			return reflect.DeepEqual(o.Policy, p)
+		},
+	}
+}
+
 func EqBuildOptionsWithVolumes(volumes []string) gomock.Matcher {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("Volumes=%s", volumes),
@@ -1311,6 +1591,15 @@ func EqBuildOptionsWithSBOMOutputDir(s string) interface{} {
 	}
 }
 
+func EqBuildOptionsWithProvenanceOutputDir(s string) interface{} {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("provenance-destination-dir=%s", s),
+		equals: func(o client.BuildOptions) bool {
+			return o.ProvenanceDestinationDir == s
+		},
+	}
+}
+
 func EqBuildOptionsWithDateTime(t *time.Time) interface{} {
 	return buildOptionsMatcher{
 		description: fmt.Sprintf("CreationTime=%s", t),
@@ -1369,6 +1658,24 @@ func EqBuildOptionsWithInsecureRegistries(insecureRegistries []string) gomock.Ma
 	}
 }
 
+func EqBuildOptionsWithEventHandler(set bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("EventHandler set=%t", set),
+		equals: func(o client.BuildOptions) bool {
+			return (o.EventHandler != nil) == set
+		},
+	}
+}
+
+func EqBuildOptionsWithDryRun(dryRun bool) gomock.Matcher {
+	return buildOptionsMatcher{
+		description: fmt.Sprintf("DryRun=%t", dryRun),
+		equals: func(o client.BuildOptions) bool {
+			return o.DryRun == dryRun && (o.PlanHandler != nil) == dryRun
+		},
+	}
+}
+
 type buildOptionsMatcher struct {
 	equals      func(client.BuildOptions) bool
 	description string
@@ -28,6 +28,9 @@ type BuilderCreateFlags struct {
 	Targets               []string
 	Label                 map[string]string
 	AdditionalTags        []string
+	Lockfile              string
+	SignKey               string
+	PolicyPath            string
 }
 
 // CreateBuilder creates a builder image, based on a builder config
@@ -112,6 +115,21 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 				}()
 			}
 
+			lockfile, err := readLockfile(flags.Lockfile)
+			if err != nil {
+				return err
+			}
+
+			signKey, err := readSignKey(flags.SignKey)
+			if err != nil {
+				return err
+			}
+
+			builderPolicy, err := readPolicy(flags.PolicyPath, cfg)
+			if err != nil {
+				return err
+			}
+
 			imageName := args[0]
 			if err := pack.CreateBuilder(cmd.Context(), client.CreateBuilderOptions{
 				RelativeBaseDir:       relativeBaseDir,
@@ -127,6 +145,9 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 				Targets:               multiArchCfg.Targets(),
 				TempDirectory:         tempDir,
 				AdditionalTags:        flags.AdditionalTags,
+				Lock:                  lockfile,
+				SignKey:               signKey,
+				Policy:                builderPolicy,
 			}); err != nil {
 				return err
 			}
@@ -141,6 +162,9 @@ Creating a custom builder allows you to control what buildpacks are used and wha
 		cmd.Flags().MarkHidden("buildpack-registry")
 	}
 	cmd.Flags().StringVarP(&flags.BuilderTomlPath, "config", "c", "", "Path to builder TOML file (required)")
+	cmd.Flags().StringVar(&flags.Lockfile, "lockfile", "", "Path to a lockfile generated by 'pack buildpack lock', pinning the buildpacks, extensions and lifecycle of the config")
+	cmd.Flags().StringVar(&flags.SignKey, "sign-key", "", "Path to a PEM encoded private key to sign the builder with, or env://<VAR> to read it from an environment variable.\nRequires --publish\n"+signKeyFormats)
+	cmd.Flags().StringVar(&flags.PolicyPath, "policy", "", "Path to a policy file restricting the run images and buildpacks of the builder.\nDefaults to the policy set in the pack config, if any.")
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, "Publish the builder directly to the container registry specified in <image-name>, instead of the daemon.")
 	cmd.Flags().BoolVar(&flags.AppendImageNameSuffix, "append-image-name-suffix", false, "Append an [os]-[arch] suffix to intermediate image tags when creating a multi-arch image; useful when publishing to a registry that doesn't allow overwriting existing tags")
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
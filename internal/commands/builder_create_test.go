@@ -283,6 +283,35 @@ func testCreateCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("--sign-key", func() {
+			it.Before(func() {
+				h.AssertNil(t, os.WriteFile(builderConfigPath, []byte(validConfig), 0666))
+			})
+
+			it("passes the key to sign the builder with", func() {
+				keyPath := filepath.Join(tmpDir, "cosign.key")
+				h.AssertNil(t, os.WriteFile(keyPath, []byte("some-key"), 0600))
+				mockClient.EXPECT().CreateBuilder(gomock.Any(), EqCreateBuilderOptionsSignKey("some-key")).Return(nil)
+
+				command.SetArgs([]string{
+					"some/builder",
+					"--config", builderConfigPath,
+					"--publish",
+					"--sign-key", keyPath,
+				})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when the key can't be read", func() {
+				command.SetArgs([]string{
+					"some/builder",
+					"--config", builderConfigPath,
+					"--sign-key", filepath.Join(tmpDir, "missing.key"),
+				})
+				h.AssertError(t, command.Execute(), "reading sign key")
+			})
+		})
+
 		when("uses --builder-config", func() {
 			it.Before(func() {
 				h.AssertNil(t, os.WriteFile(builderConfigPath, []byte(validConfig), 0666))
@@ -573,6 +602,15 @@ func EqCreateBuilderOptionsTargets(targets []dist.Target) gomock.Matcher {
 	}
 }
 
+func EqCreateBuilderOptionsSignKey(key string) gomock.Matcher {
+	return createbuilderOptionsMatcher{
+		description: fmt.Sprintf("SignKey=%s", key),
+		equals: func(o client.CreateBuilderOptions) bool {
+			return string(o.SignKey) == key
+		},
+	}
+}
+
 type createbuilderOptionsMatcher struct {
 	equals      func(options client.CreateBuilderOptions) bool
 	description string
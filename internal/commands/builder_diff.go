@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/builder/writer"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type BuilderDiffFlags struct {
+	Remote       bool
+	OutputFormat string
+}
+
+// BuilderDiff compares two builders, typically before upgrading to a new version of one
+func BuilderDiff(
+	logger logging.Logger,
+	pack PackClient,
+	writerFactory writer.BuilderDiffWriterFactory,
+) *cobra.Command {
+	var flags BuilderDiffFlags
+	cmd := &cobra.Command{
+		Use:     "diff <old-builder> <new-builder>",
+		Args:    cobra.ExactArgs(2),
+		Short:   "Show what changed between two builders",
+		Example: "pack builder diff cnbs/sample-builder:jammy cnbs/sample-builder:noble",
+		Long: "Show what changed between two builders: the lifecycle version and the APIs it supports, the buildpacks " +
+			"and extensions that were added, removed or bumped, the detection order, the run images and their mirrors, " +
+			"the target and the environment of the builder image.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			w, err := writerFactory.DiffWriter(flags.OutputFormat)
+			if err != nil {
+				return err
+			}
+
+			diff, err := pack.DiffBuilders(cmd.Context(), args[0], args[1], client.DiffBuildersOptions{Daemon: !flags.Remote})
+			if err != nil {
+				return err
+			}
+
+			return w.PrintDiff(logger, writer.NewDiffOutput(args[0], args[1], diff))
+		}),
+	}
+	AddHelpFlag(cmd, "diff")
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Compare builders in a remote registry (without pulling them)")
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format to display the differences (json, yaml, toml, human-readable).\nOmission of this flag will display as human-readable.")
+	return cmd
+}
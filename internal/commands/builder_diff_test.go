@@ -0,0 +1,111 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/fakes"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuilderDiffCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuilderDiffCommand", testBuilderDiffCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuilderDiffCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		assert         = h.NewAssertionManager(t)
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		builderWriter  *fakes.FakeBuilderWriter
+		writerFactory  *fakes.FakeBuilderWriterFactory
+		builderDiff    *client.BuilderDiff
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+
+		builderWriter = &fakes.FakeBuilderWriter{PrintForDiff: "Sample output for diff"}
+		writerFactory = &fakes.FakeBuilderWriterFactory{ReturnForDiffWriter: builderWriter}
+		builderDiff = &client.BuilderDiff{
+			Buildpacks: []client.ModuleDiff{{ID: "some/bp", Change: client.DiffChanged, VersionsBefore: []string{"1.0.0"}, VersionsAfter: []string{"1.1.0"}}},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuilderDiff", func() {
+		it("passes the differences of the daemon builders to the writer", func() {
+			mockClient.EXPECT().DiffBuilders(gomock.Any(), "some/builder:v1", "some/builder:v2", client.DiffBuildersOptions{Daemon: true}).Return(builderDiff, nil)
+
+			command := commands.BuilderDiff(logger, mockClient, writerFactory)
+			command.SetArgs([]string{"some/builder:v1", "some/builder:v2"})
+			assert.Nil(command.Execute())
+
+			assert.Equal(writerFactory.ReceivedForDiffKind, "human-readable")
+			assert.Equal(builderWriter.ReceivedDiff.OldBuilder, "some/builder:v1")
+			assert.Equal(builderWriter.ReceivedDiff.NewBuilder, "some/builder:v2")
+			assert.Equal(len(builderWriter.ReceivedDiff.Buildpacks), 1)
+			assert.Contains(outBuf.String(), "DIFF:\nSample output for diff")
+		})
+
+		it("compares remote builders in the requested format", func() {
+			mockClient.EXPECT().DiffBuilders(gomock.Any(), "some/builder:v1", "some/builder:v2", client.DiffBuildersOptions{Daemon: false}).Return(builderDiff, nil)
+
+			command := commands.BuilderDiff(logger, mockClient, writerFactory)
+			command.SetArgs([]string{"some/builder:v1", "some/builder:v2", "--remote", "--output", "yaml"})
+			assert.Nil(command.Execute())
+
+			assert.Equal(writerFactory.ReceivedForDiffKind, "yaml")
+		})
+
+		it("is registered as a builder subcommand", func() {
+			mockClient.EXPECT().DiffBuilders(gomock.Any(), "some/builder:v1", "some/builder:v2", gomock.Any()).Return(builderDiff, nil)
+
+			command := commands.NewBuilderCommand(logger, config.Config{}, mockClient)
+			command.SetArgs([]string{"diff", "some/builder:v1", "some/builder:v2"})
+			assert.Nil(command.Execute())
+
+			assert.Contains(outBuf.String(), "Comparing 'some/builder:v1' to 'some/builder:v2'")
+		})
+
+		when("the output format is unsupported", func() {
+			it("errors", func() {
+				writerFactory.ErrorForDiffWriter = errors.New("output format 'xml' is not supported")
+
+				command := commands.BuilderDiff(logger, mockClient, writerFactory)
+				command.SetArgs([]string{"some/builder:v1", "some/builder:v2", "--output", "xml"})
+				assert.ErrorWithMessage(command.Execute(), "output format 'xml' is not supported")
+			})
+		})
+
+		when("the builders can't be compared", func() {
+			it("errors", func() {
+				mockClient.EXPECT().DiffBuilders(gomock.Any(), "some/builder:v1", "some/builder:v2", gomock.Any()).Return(nil, errors.New("builder 'some/builder:v1' cannot be found"))
+
+				command := commands.BuilderDiff(logger, mockClient, writerFactory)
+				command.SetArgs([]string{"some/builder:v1", "some/builder:v2"})
+				assert.ErrorWithMessage(command.Execute(), "builder 'some/builder:v1' cannot be found")
+			})
+		})
+	})
+}
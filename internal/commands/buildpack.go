@@ -17,9 +17,13 @@ func NewBuildpackCommand(logger logging.Logger, cfg config.Config, client PackCl
 
 	cmd.AddCommand(BuildpackInspect(logger, cfg, client))
 	cmd.AddCommand(BuildpackPackage(logger, cfg, client, packageConfigReader))
+	cmd.AddCommand(BuildpackLock(logger, cfg, client, packageConfigReader))
 	cmd.AddCommand(BuildpackNew(logger, client))
 	cmd.AddCommand(BuildpackPull(logger, cfg, client))
 	cmd.AddCommand(BuildpackRegister(logger, cfg, client))
+	cmd.AddCommand(BuildpackSearch(logger, cfg, client))
+	cmd.AddCommand(BuildpackTest(logger, cfg, client))
+	cmd.AddCommand(BuildpackVersions(logger, cfg, client))
 	cmd.AddCommand(BuildpackYank(logger, cfg, client))
 
 	AddHelpFlag(cmd, "buildpack")
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/lock"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// BuildpackLockFlags define flags provided to the BuildpackLock command
+type BuildpackLockFlags struct {
+	Config            string
+	Output            string
+	BuildpackRegistry string
+}
+
+// BuildpackLock generates a lockfile pinning the buildpacks, extensions and lifecycle of a builder or package config
+func BuildpackLock(logger logging.Logger, cfg config.Config, pack PackClient, packageConfigReader PackageConfigReader) *cobra.Command {
+	var flags BuildpackLockFlags
+
+	cmd := &cobra.Command{
+		Use:     "lock --config <config-path>",
+		Args:    cobra.NoArgs,
+		Short:   "Generate a lockfile pinning the buildpacks and lifecycle of a builder or package config",
+		Example: "pack buildpack lock --config ./builder.toml\npack buildpack lock --config ./package.toml --output ./package.lock.toml",
+		Long: "Generate a lockfile pinning the buildpacks, extensions and lifecycle of a builder or package config to " +
+			"the sha256 checksum of the contents downloaded from their URI, or to the digest of their image.\n\n" +
+			"Configs named 'package.toml' are read as package configs, any other as builder configs. " +
+			"Pass the lockfile to 'pack builder create' or 'pack buildpack package' with '--lockfile' for " +
+			"them to fail when what a URI resolves to changes. Buildpacks in local directories aren't locked.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.Config == "" {
+				return errors.New("Please provide a builder or package config path, using --config.")
+			}
+
+			relativeBaseDir, err := filepath.Abs(filepath.Dir(flags.Config))
+			if err != nil {
+				return errors.Wrap(err, "getting absolute path for config")
+			}
+
+			registry, err := config.GetRegistry(cfg, flags.BuildpackRegistry)
+			if err != nil {
+				return err
+			}
+
+			opts := client.LockBuildpacksOptions{
+				RelativeBaseDir: relativeBaseDir,
+				Registry:        registry.Name,
+			}
+			if filepath.Base(flags.Config) == "package.toml" {
+				err = lockPackageConfig(flags.Config, packageConfigReader, &opts)
+			} else {
+				err = lockBuilderConfig(logger, flags.Config, &opts)
+			}
+			if err != nil {
+				return err
+			}
+
+			lockfile, err := pack.LockBuildpacks(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			output := flags.Output
+			if output == "" {
+				output = strings.TrimSuffix(flags.Config, ".toml") + ".lock.toml"
+			}
+			if err := lockfile.Write(output); err != nil {
+				return errors.Wrapf(err, "writing lockfile %s", style.Symbol(output))
+			}
+
+			logger.Infof("Successfully wrote lockfile %s", style.Symbol(output))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Config, "config", "c", "", "Path to the builder or package config (required)")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Path to write the lockfile to, defaults to the config path ending with '.lock.toml'")
+	cmd.Flags().StringVarP(&flags.BuildpackRegistry, "buildpack-registry", "r", "", "Buildpack Registry name")
+	AddHelpFlag(cmd, "lock")
+	return cmd
+}
+
+func lockBuilderConfig(logger logging.Logger, path string, opts *client.LockBuildpacksOptions) error {
+	builderConfig, warnings, err := pubbldr.ReadConfig(path)
+	if err != nil {
+		return errors.Wrap(err, "invalid builder config")
+	}
+	for _, w := range warnings {
+		logger.Warnf("builder configuration: %s", w)
+	}
+
+	for _, module := range append(builderConfig.Buildpacks, builderConfig.Extensions...) {
+		if err := addLockURI(opts, module.BuildpackURI); err != nil {
+			return err
+		}
+	}
+
+	lifecycle := builderConfig.Lifecycle
+	if lifecycle.URI != "" {
+		uri, err := blob.WithSHA256(lifecycle.URI, lifecycle.SHA256)
+		if err != nil {
+			return err
+		}
+		lifecycle.URI = uri
+	}
+	opts.Lifecycle = &lifecycle
+	opts.Targets = builderConfig.Targets
+	return nil
+}
+
+func lockPackageConfig(path string, packageConfigReader PackageConfigReader, opts *client.LockBuildpacksOptions) error {
+	packageConfig, err := packageConfigReader.Read(path)
+	if err != nil {
+		return errors.Wrap(err, "reading config")
+	}
+
+	for _, module := range []dist.BuildpackURI{packageConfig.Buildpack, packageConfig.Extension} {
+		if err := addLockURI(opts, module); err != nil {
+			return err
+		}
+	}
+	for _, dep := range packageConfig.Dependencies {
+		if err := addLockURI(opts, dep.BuildpackURI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addLockURI(opts *client.LockBuildpacksOptions, module dist.BuildpackURI) error {
+	if module.URI == "" {
+		return nil
+	}
+	uri, err := blob.WithSHA256(module.URI, module.SHA256)
+	if err != nil {
+		return err
+	}
+	opts.URIs = append(opts.URIs, uri)
+	return nil
+}
+
+// readLockfile reads the lockfile at path, returning an empty lockfile when no path is given
+func readLockfile(path string) (lock.File, error) {
+	if path == "" {
+		return lock.File{}, nil
+	}
+	return lock.Read(path)
+}
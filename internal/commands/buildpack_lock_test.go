@@ -0,0 +1,122 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	pubbldpkg "github.com/buildpacks/pack/buildpackage"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/fakes"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/lock"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackLockCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BuildpackLockCommand", testBuildpackLockCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackLockCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		tmpDir         string
+		lockfile       lock.File
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		tmpDir = t.TempDir()
+		lockfile = lock.File{Entries: []lock.Entry{{URI: "https://example.com/bp.tgz", SHA256: strings.Repeat("ab", 32)}}}
+
+		packageConfig := pubbldpkg.Config{
+			Buildpack: dist.BuildpackURI{URI: "."},
+			Dependencies: []dist.ImageOrURI{
+				{BuildpackURI: dist.BuildpackURI{URI: "https://example.com/bp.tgz"}},
+				{ImageRef: dist.ImageRef{ImageName: "example/image"}},
+			},
+		}
+		command = commands.BuildpackLock(logger, config.Config{}, mockClient, fakes.NewFakePackageConfigReader(whereReadReturns(packageConfig, nil)))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildpackLock", func() {
+		it("locks the buildpacks and lifecycle of a builder config", func() {
+			configPath := filepath.Join(tmpDir, "builder.toml")
+			h.AssertNil(t, os.WriteFile(configPath, []byte(`
+[[buildpacks]]
+uri = "https://example.com/bp.tgz"
+sha256 = "`+strings.Repeat("ab", 32)+`"
+
+[lifecycle]
+version = "0.20.0"
+
+[[targets]]
+os = "linux"
+arch = "arm64"
+`), 0600))
+
+			mockClient.EXPECT().LockBuildpacks(gomock.Any(), client.LockBuildpacksOptions{
+				URIs:            []string{"https://example.com/bp.tgz#sha256=" + strings.Repeat("ab", 32)},
+				Lifecycle:       &pubbldr.LifecycleConfig{Version: "0.20.0"},
+				Targets:         []dist.Target{{OS: "linux", Arch: "arm64"}},
+				RelativeBaseDir: tmpDir,
+				Registry:        "official",
+			}).Return(lockfile, nil)
+
+			command.SetArgs([]string{"--config", configPath})
+			h.AssertNil(t, command.Execute())
+
+			written, err := lock.Read(filepath.Join(tmpDir, "builder.lock.toml"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, written, lockfile)
+		})
+
+		it("locks the buildpack and dependencies of a package config", func() {
+			configPath := filepath.Join(tmpDir, "package.toml")
+			outputPath := filepath.Join(tmpDir, "out.lock.toml")
+
+			mockClient.EXPECT().LockBuildpacks(gomock.Any(), client.LockBuildpacksOptions{
+				URIs:            []string{".", "https://example.com/bp.tgz"},
+				RelativeBaseDir: tmpDir,
+				Registry:        "official",
+			}).Return(lockfile, nil)
+
+			command.SetArgs([]string{"--config", configPath, "--output", outputPath})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Successfully wrote lockfile")
+
+			_, err := os.Stat(outputPath)
+			h.AssertNil(t, err)
+		})
+
+		it("errors without a config", func() {
+			h.AssertError(t, command.Execute(), "Please provide a builder or package config path")
+		})
+	})
+}
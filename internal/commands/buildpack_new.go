@@ -22,9 +22,10 @@ type BuildpackNewFlags struct {
 	API  string
 	Path string
 	// Deprecated: Stacks are deprecated
-	Stacks  []string
-	Targets []string
-	Version string
+	Stacks   []string
+	Targets  []string
+	Template string
+	Version  string
 }
 
 // BuildpackCreator creates buildpacks
@@ -40,7 +41,8 @@ func BuildpackNew(logger logging.Logger, creator BuildpackCreator) *cobra.Comman
 		Short:   "Creates basic scaffolding of a buildpack.",
 		Args:    cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 		Example: "pack buildpack new sample/my-buildpack",
-		Long:    "buildpack new generates the basic scaffolding of a buildpack repository. It creates a new directory `name` in the current directory (or at `path`, if passed as a flag), and initializes a buildpack.toml, and two executable bash scripts, `bin/detect` and `bin/build`. ",
+		Long: "buildpack new generates the basic scaffolding of a buildpack repository. It creates a new directory `name` in the current directory (or at `path`, if passed as a flag), and initializes a buildpack.toml, and two executable bash scripts, `bin/detect` and `bin/build`. " +
+			"Other layouts can be generated with `--template`.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
 			id := args[0]
 			idParts := strings.Split(id, "/")
@@ -72,10 +74,7 @@ func BuildpackNew(logger logging.Logger, creator BuildpackCreator) *cobra.Comman
 
 			var targets []dist.Target
 			if len(flags.Targets) == 0 && len(flags.Stacks) == 0 {
-				targets = []dist.Target{{
-					OS:   runtime.GOOS,
-					Arch: runtime.GOARCH,
-				}}
+				targets = defaultBuildpackTargets(flags.Template)
 			} else {
 				if targets, err = target.ParseTargets(flags.Targets, logger); err != nil {
 					return err
@@ -83,12 +82,13 @@ func BuildpackNew(logger logging.Logger, creator BuildpackCreator) *cobra.Comman
 			}
 
 			if err := creator.NewBuildpack(cmd.Context(), client.NewBuildpackOptions{
-				API:     flags.API,
-				ID:      id,
-				Path:    path,
-				Stacks:  stacks,
-				Targets: targets,
-				Version: flags.Version,
+				API:      flags.API,
+				ID:       id,
+				Path:     path,
+				Stacks:   stacks,
+				Targets:  targets,
+				Template: flags.Template,
+				Version:  flags.Version,
 			}); err != nil {
 				return err
 			}
@@ -110,6 +110,23 @@ func BuildpackNew(logger logging.Logger, creator BuildpackCreator) *cobra.Comman
 	- case for different architecture with distributed versions : '--targets "linux/arm/v6:ubuntu@14.04"  --targets "linux/arm/v6:ubuntu@16.04"'
 	`)
 
+	cmd.Flags().StringVar(&flags.Template, "template", "",
+		fmt.Sprintf(`Template to generate the buildpack from, defaults to 'bash'. Either one of %s, a local template directory, or the URL of a git repository holding one.
+Files of a template directory ending with '.tmpl' are rendered with Go templates, where {{ .ID }}, {{ .Name }}, {{ .Version }}, {{ .API }} and {{ .Targets }} are available, and are created without the extension.`,
+			strings.Join(client.BuildpackTemplates(), ", ")))
+
 	AddHelpFlag(cmd, "new")
 	return cmd
 }
+
+// defaultBuildpackTargets are the targets of a buildpack when none are given: the current platform,
+// or the most common platforms for templates meant to be packaged for several targets
+func defaultBuildpackTargets(template string) []dist.Target {
+	if template == client.PackageTemplate {
+		return []dist.Target{{OS: "linux", Arch: "amd64"}, {OS: "linux", Arch: "arm64"}}
+	}
+	return []dist.Target{{
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+	}}
+}
@@ -85,6 +85,36 @@ func testBuildpackNewCommand(t *testing.T, when spec.G, it spec.S) {
 			h.AssertContains(t, outBuf.String(), "ERROR: directory")
 		})
 
+		when("template flag is specified", func() {
+			it("generates the buildpack from the template", func() {
+				mockClient.EXPECT().NewBuildpack(gomock.Any(), client.NewBuildpackOptions{
+					API:      "0.8",
+					ID:       "example/some-cnb",
+					Path:     filepath.Join(tmpDir, "some-cnb"),
+					Version:  "1.0.0",
+					Targets:  targets,
+					Template: "go",
+				}).Return(nil)
+
+				command.SetArgs([]string{"--path", filepath.Join(tmpDir, "some-cnb"), "example/some-cnb", "--template", "go"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("targets several platforms by default for the package template", func() {
+				mockClient.EXPECT().NewBuildpack(gomock.Any(), client.NewBuildpackOptions{
+					API:      "0.8",
+					ID:       "example/some-cnb",
+					Path:     filepath.Join(tmpDir, "some-cnb"),
+					Version:  "1.0.0",
+					Targets:  []dist.Target{{OS: "linux", Arch: "amd64"}, {OS: "linux", Arch: "arm64"}},
+					Template: "package",
+				}).Return(nil)
+
+				command.SetArgs([]string{"--path", filepath.Join(tmpDir, "some-cnb"), "example/some-cnb", "--template", "package"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
 		when("target flag is specified, ", func() {
 			it("it uses target to generate artifacts", func() {
 				mockClient.EXPECT().NewBuildpack(gomock.Any(), client.NewBuildpackOptions{
@@ -32,6 +32,8 @@ type BuildpackPackageFlags struct {
 	Flatten               bool
 	AppendImageNameSuffix bool
 	AdditionalTags        []string
+	Lockfile              string
+	SignKey               string
 }
 
 // BuildpackPackager packages buildpacks
@@ -136,6 +138,16 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 				logger.Warnf("--append-image-name-suffix will be ignored, use combined with --publish")
 			}
 
+			lockfile, err := readLockfile(flags.Lockfile)
+			if err != nil {
+				return err
+			}
+
+			signKey, err := readSignKey(flags.SignKey)
+			if err != nil {
+				return err
+			}
+
 			if err := packager.PackageBuildpack(cmd.Context(), client.PackageBuildpackOptions{
 				RelativeBaseDir:       relativeBaseDir,
 				Name:                  name,
@@ -150,6 +162,8 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 				Labels:                flags.Label,
 				Targets:               multiArchCfg.Targets(),
 				AdditionalTags:        flags.AdditionalTags,
+				Lock:                  lockfile,
+				SignKey:               signKey,
 			}); err != nil {
 				return err
 			}
@@ -169,6 +183,8 @@ func BuildpackPackage(logger logging.Logger, cfg config.Config, packager Buildpa
 	}
 
 	cmd.Flags().StringVarP(&flags.PackageTomlPath, "config", "c", "", "Path to package TOML config")
+	cmd.Flags().StringVar(&flags.Lockfile, "lockfile", "", "Path to a lockfile generated by 'pack buildpack lock', pinning the buildpack and dependencies of the config")
+	cmd.Flags().StringVar(&flags.SignKey, "sign-key", "", "Path to a PEM encoded private key to sign the package with, or env://<VAR> to read it from an environment variable.\nImages are signed in their registry and require --publish; files get a detached signature written to <file>.sig\n"+signKeyFormats)
 	cmd.Flags().StringVarP(&flags.Format, "format", "f", "", `Format to save package as ("image" or "file")`)
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, `Publish the buildpack directly to the container registry specified in <name>, instead of the daemon (applies to "--format=image" only).`)
 	cmd.Flags().BoolVar(&flags.AppendImageNameSuffix, "append-image-name-suffix", false, "When publishing to a registry that doesn't allow overwrite existing tags use this flag to append a [os]-[arch] suffix to package <name>")
@@ -3,6 +3,7 @@ package commands_test
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -204,6 +205,39 @@ func testPackageCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("--sign-key", func() {
+				it("passes the key from a file", func() {
+					keyPath := filepath.Join(t.TempDir(), "cosign.key")
+					h.AssertNil(t, os.WriteFile(keyPath, []byte("some-key"), 0600))
+
+					cmd := packageCommand(withBuildpackPackager(fakeBuildpackPackager))
+					cmd.SetArgs([]string{"some-image-name", "--config", "/path/to/some/file", "--sign-key", keyPath})
+					h.AssertNil(t, cmd.Execute())
+
+					receivedOptions := fakeBuildpackPackager.CreateCalledWithOptions
+					h.AssertEq(t, string(receivedOptions.SignKey), "some-key")
+				})
+
+				it("passes the key from an environment variable", func() {
+					envName := "PACK_TEST_SIGN_KEY_" + h.RandString(8)
+					h.AssertNil(t, os.Setenv(envName, "some-key"))
+					defer os.Unsetenv(envName)
+
+					cmd := packageCommand(withBuildpackPackager(fakeBuildpackPackager))
+					cmd.SetArgs([]string{"some-image-name", "--config", "/path/to/some/file", "--sign-key", "env://" + envName})
+					h.AssertNil(t, cmd.Execute())
+
+					receivedOptions := fakeBuildpackPackager.CreateCalledWithOptions
+					h.AssertEq(t, string(receivedOptions.SignKey), "some-key")
+				})
+
+				it("errors when the environment variable is empty", func() {
+					cmd := packageCommand(withBuildpackPackager(fakeBuildpackPackager))
+					cmd.SetArgs([]string{"some-image-name", "--config", "/path/to/some/file", "--sign-key", "env://PACK_TEST_MISSING_SIGN_KEY"})
+					h.AssertError(t, cmd.Execute(), "holding the sign key is empty")
+				})
+			})
+
 			when("no --pull-policy", func() {
 				var pullPolicyArgs = []string{
 					"some-image-name",
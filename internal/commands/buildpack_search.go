@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// BuildpackSearchFlags define flags provided to the BuildpackSearch command
+type BuildpackSearchFlags struct {
+	Registry      string
+	OutputFormat  string
+	IncludeYanked bool
+}
+
+// BuildpackSearch searches a buildpack registry for buildpacks
+func BuildpackSearch(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
+	var flags BuildpackSearchFlags
+
+	cmd := &cobra.Command{
+		Use:     "search [<query>]",
+		Args:    cobra.MaximumNArgs(1),
+		Short:   "Search a buildpack registry for buildpacks",
+		Example: "pack buildpack search java\npack buildpack search java --output-format json",
+		Long: "Search the buildpacks of a buildpack registry whose id contains the query, listing the latest version of " +
+			"each. Without a query, every buildpack of the registry is listed. Yanked versions are skipped unless " +
+			"'--include-yanked' is set.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatJSON {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSON)
+			}
+
+			registry, err := config.GetRegistry(cfg, flags.Registry)
+			if err != nil {
+				return err
+			}
+
+			query := ""
+			if len(args) > 0 {
+				query = args[0]
+			}
+			results, err := pack.SearchBuildpacks(client.SearchBuildpacksOptions{
+				Query:         query,
+				Registry:      registry.Name,
+				IncludeYanked: flags.IncludeYanked,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == outputFormatJSON {
+				return writeRegistryBuildpacksJSON(logger.Writer(), results)
+			}
+			if len(results) == 0 {
+				logger.Infof("No buildpacks matching %s found in registry %s", style.Symbol(query), style.Symbol(registry.Name))
+				return nil
+			}
+			return writeRegistryBuildpacks(logger.Writer(), results)
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Registry, "registry", "r", "", "Name of the buildpack registry to search, defaults to the default registry")
+	cmd.Flags().BoolVar(&flags.IncludeYanked, "include-yanked", false, "Consider yanked versions")
+	cmd.Flags().StringVar(&flags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format (human-readable, json)")
+	AddHelpFlag(cmd, "search")
+	return cmd
+}
+
+func writeRegistryBuildpacksJSON(w io.Writer, buildpacks []client.RegistryBuildpack) error {
+	if buildpacks == nil {
+		buildpacks = []client.RegistryBuildpack{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildpacks)
+}
+
+func writeRegistryBuildpacks(w io.Writer, buildpacks []client.RegistryBuildpack) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "ID\tVERSION\tADDRESS")
+	for _, bp := range buildpacks {
+		version := bp.Version
+		if bp.Yanked {
+			version += " (yanked)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", bp.ID, version, bp.Address)
+	}
+	return tw.Flush()
+}
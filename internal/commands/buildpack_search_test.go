@@ -0,0 +1,104 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackSearchCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BuildpackSearchCommand", testBuildpackSearchCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackSearchCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		results        []client.RegistryBuildpack
+	)
+
+	it.Before(func() {
+		logger := logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		cfg := config.Config{Registries: []config.Registry{{Name: "some-registry", Type: "github", URL: "https://github.com/some/registry"}}}
+		command = commands.BuildpackSearch(logger, cfg, mockClient)
+
+		results = []client.RegistryBuildpack{
+			{ID: "example/java", Version: "1.2.0", Address: "example.com/java@sha256:abc"},
+			{ID: "example/node", Version: "2.0.0", Yanked: true, Address: "example.com/node@sha256:def"},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildpackSearch", func() {
+		it("lists the matching buildpacks of the default registry", func() {
+			mockClient.EXPECT().SearchBuildpacks(client.SearchBuildpacksOptions{
+				Query:    "example",
+				Registry: "official",
+			}).Return(results, nil)
+
+			command.SetArgs([]string{"example"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "example/java")
+			h.AssertContains(t, outBuf.String(), "2.0.0 (yanked)")
+		})
+
+		it("searches the given registry, including yanked versions", func() {
+			mockClient.EXPECT().SearchBuildpacks(client.SearchBuildpacksOptions{
+				Registry:      "some-registry",
+				IncludeYanked: true,
+			}).Return(results, nil)
+
+			command.SetArgs([]string{"--registry", "some-registry", "--include-yanked"})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("outputs json", func() {
+			mockClient.EXPECT().SearchBuildpacks(gomock.Any()).Return(results, nil)
+
+			command.SetArgs([]string{"example", "--output-format", "json"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `"id": "example/node"`)
+			h.AssertContains(t, outBuf.String(), `"yanked": true`)
+		})
+
+		it("reports when no buildpack matches", func() {
+			mockClient.EXPECT().SearchBuildpacks(gomock.Any()).Return([]client.RegistryBuildpack{}, nil)
+
+			command.SetArgs([]string{"python"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "No buildpacks matching 'python' found in registry 'official'")
+		})
+
+		it("errors for unknown registries", func() {
+			command.SetArgs([]string{"example", "--registry", "other-registry"})
+			h.AssertError(t, command.Execute(), "registry 'other-registry' is not defined in your config file")
+		})
+
+		it("errors for unsupported output formats", func() {
+			command.SetArgs([]string{"example", "--output-format", "yaml"})
+			h.AssertError(t, command.Execute(), "unsupported output format 'yaml'")
+		})
+	})
+}
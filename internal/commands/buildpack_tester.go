@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	bldr "github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// BuildpackTestFlags define flags provided to the BuildpackTest command
+type BuildpackTestFlags struct {
+	AppPath    string
+	Builder    string
+	ConfigPath string
+	ReportPath string
+	Network    string
+	Policy     string
+	Env        []string
+	EnvFiles   []string
+}
+
+// BuildpackTest runs the detect and build phases of a buildpack against a fixture app and checks the outcome
+func BuildpackTest(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags BuildpackTestFlags
+
+	cmd := &cobra.Command{
+		Use:     "test <buildpack-path>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "Test a buildpack against a fixture app",
+		Example: "pack buildpack test ./my-buildpack --app ./fixtures/node-app --builder cnbs/sample-builder:noble",
+		Long: "buildpack test runs the detect and build phases of the buildpack at <buildpack-path> against a fixture app, " +
+			"inside the build image of the builder, and checks the outcome against the expectations declared in a test.toml file.\n\n" +
+			"The test.toml file is read from the fixture app unless `--config` is provided. It may declare:\n" +
+			"  detect = false                 if the buildpack is expected to fail detection\n" +
+			"  layers = [\"<name>\", ...]       layers the buildpack is expected to contribute\n" +
+			"  [env]                          values expected in the env directories of the layers\n" +
+			"  [[processes]]                  launch processes expected, with a type and optionally a command\n\n" +
+			"Use `--report` to write the results as JUnit XML.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.AppPath == "" {
+				return errors.New("a fixture app must be provided with --app")
+			}
+			if flags.Builder == "" {
+				suggestSettingBuilder(logger, packClient)
+				return client.NewSoftError()
+			}
+
+			env, err := parseEnv(flags.EnvFiles, flags.Env)
+			if err != nil {
+				return err
+			}
+
+			// the test always runs in separate containers, trust only decides whether a lifecycle image is required
+			isTrusted, err := bldr.IsTrustedBuilder(cfg, flags.Builder)
+			if err != nil {
+				return err
+			}
+			trustBuilder := isTrusted || bldr.IsKnownTrustedBuilder(flags.Builder)
+
+			stringPolicy := flags.Policy
+			if stringPolicy == "" {
+				stringPolicy = cfg.PullPolicy
+			}
+			pullPolicy, err := image.ParsePullPolicy(stringPolicy)
+			if err != nil {
+				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+			}
+
+			result, err := packClient.TestBuildpack(cmd.Context(), client.TestBuildpackOptions{
+				BuildpackPath: args[0],
+				ConfigPath:    flags.ConfigPath,
+				BuildOptions: client.BuildOptions{
+					AppPath:           flags.AppPath,
+					Builder:           flags.Builder,
+					AdditionalMirrors: getMirrors(cfg),
+					Env:               env,
+					PullPolicy:        pullPolicy,
+					TrustBuilder: func(string) bool {
+						return trustBuilder
+					},
+					ContainerConfig: client.ContainerConfig{
+						Network: flags.Network,
+					},
+					LifecycleImage: cfg.LifecycleImage,
+					GroupID:        -1,
+					UserID:         -1,
+				},
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to test buildpack")
+			}
+
+			if err := writeBuildpackTestResult(logger.Writer(), *result); err != nil {
+				return err
+			}
+			if flags.ReportPath != "" {
+				if err := writeJUnitReport(flags.ReportPath, *result); err != nil {
+					return err
+				}
+				logger.Infof("Wrote JUnit report to %s", style.Symbol(flags.ReportPath))
+			}
+
+			if failures := result.Failures(); failures > 0 {
+				return errors.Errorf("%d of %d expectations failed", failures, len(result.Cases))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.AppPath, "app", "a", "", "Path to the fixture app dir")
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image whose build image and lifecycle run the test")
+	cmd.Flags().StringVarP(&flags.ConfigPath, "config", "c", "", "Path to the test.toml declaring the expectations (defaults to test.toml in the fixture app)")
+	cmd.Flags().StringVar(&flags.ReportPath, "report", "", "Path to write the results to as JUnit XML")
+	cmd.Flags().StringArrayVarP(&flags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file."+stringArrayHelp("env"))
+	cmd.Flags().StringArrayVar(&flags.EnvFiles, "env-file", []string{}, "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed")
+	cmd.Flags().StringVar(&flags.Network, "network", "", "Connect detect and build containers to network")
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	AddHelpFlag(cmd, "test")
+	return cmd
+}
+
+func writeBuildpackTestResult(w io.Writer, result client.BuildpackTestResult) error {
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "Results for %s with app %s:\n", style.Symbol(result.Buildpack), style.Symbol(result.App))
+	for _, testCase := range result.Cases {
+		switch {
+		case testCase.Failure != "":
+			fmt.Fprintf(&buf, "  fail: %s\n    %s\n", testCase.Name, testCase.Failure)
+		case testCase.Skipped != "":
+			fmt.Fprintf(&buf, "  skip: %s (%s)\n", testCase.Name, testCase.Skipped)
+		default:
+			fmt.Fprintf(&buf, "  pass: %s\n", testCase.Name)
+		}
+	}
+	fmt.Fprintf(&buf, "\n%d expectations, %d failed, %d skipped\n", len(result.Cases), result.Failures(), result.Skipped())
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+func writeJUnitReport(path string, result client.BuildpackTestResult) error {
+	suite := junitTestSuite{
+		Name:     fmt.Sprintf("%s (%s)", result.Buildpack, filepath.Base(result.App)),
+		Tests:    len(result.Cases),
+		Failures: result.Failures(),
+		Skipped:  result.Skipped(),
+		Time:     fmt.Sprintf("%.3f", result.Duration.Seconds()),
+	}
+	for _, testCase := range result.Cases {
+		junitCase := junitTestCase{Name: testCase.Name, ClassName: result.Buildpack}
+		if testCase.Failure != "" {
+			junitCase.Failure = &junitMessage{Message: testCase.Failure}
+		}
+		if testCase.Skipped != "" {
+			junitCase.Skipped = &junitMessage{Message: testCase.Skipped}
+		}
+		suite.Cases = append(suite.Cases, junitCase)
+	}
+
+	contents, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding JUnit report")
+	}
+	if err := os.WriteFile(path, append([]byte(xml.Header), append(contents, '\n')...), 0600); err != nil {
+		return errors.Wrapf(err, "writing JUnit report to %s", style.Symbol(path))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackTestCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "BuildpackTestCommand", testBuildpackTestCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackTestCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		result         *client.BuildpackTestResult
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.BuildpackTest(logger, config.Config{DefaultBuilder: "some/builder"}, mockClient)
+
+		result = &client.BuildpackTestResult{
+			Buildpack: "some/bp@1.0.0",
+			App:       "fixtures/node-app",
+			Duration:  1500 * time.Millisecond,
+			Cases: []client.BuildpackTestCase{
+				{Name: "detect"},
+				{Name: "build"},
+				{Name: "layer node"},
+			},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildpackTest", func() {
+		it("tests the buildpack against the fixture app", func() {
+			mockClient.EXPECT().
+				TestBuildpack(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ interface{}, opts client.TestBuildpackOptions) (*client.BuildpackTestResult, error) {
+					h.AssertEq(t, opts.BuildpackPath, "some-bp")
+					h.AssertEq(t, opts.ConfigPath, "some-test.toml")
+					h.AssertEq(t, opts.BuildOptions.AppPath, "fixtures/node-app")
+					h.AssertEq(t, opts.BuildOptions.Builder, "some/builder")
+					h.AssertEq(t, opts.BuildOptions.Env, map[string]string{"SOME_VAR": "some-value"})
+					return result, nil
+				})
+
+			command.SetArgs([]string{"some-bp", "--app", "fixtures/node-app", "--config", "some-test.toml", "--env", "SOME_VAR=some-value"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `Results for 'some/bp@1.0.0' with app 'fixtures/node-app':
+  pass: detect
+  pass: build
+  pass: layer node
+
+3 expectations, 0 failed, 0 skipped
+`)
+		})
+
+		it("fails when expectations aren't met", func() {
+			result.Cases[2].Failure = "expected layer 'node' to be contributed"
+			mockClient.EXPECT().TestBuildpack(gomock.Any(), gomock.Any()).Return(result, nil)
+
+			command.SetArgs([]string{"some-bp", "--app", "fixtures/node-app"})
+			h.AssertError(t, command.Execute(), "1 of 3 expectations failed")
+			h.AssertContains(t, outBuf.String(), "  fail: layer node\n    expected layer 'node' to be contributed\n")
+		})
+
+		it("errors when the test can't run", func() {
+			mockClient.EXPECT().TestBuildpack(gomock.Any(), gomock.Any()).Return(nil, errors.New("builder not found"))
+
+			command.SetArgs([]string{"some-bp", "--app", "fixtures/node-app"})
+			h.AssertError(t, command.Execute(), "failed to test buildpack: builder not found")
+		})
+
+		it("requires a fixture app", func() {
+			command.SetArgs([]string{"some-bp"})
+			h.AssertError(t, command.Execute(), "a fixture app must be provided with --app")
+		})
+
+		when("--report is provided", func() {
+			it("writes the results as JUnit XML", func() {
+				result.Cases[1].Failure = "expected the build to pass, but it failed: exit status 1"
+				result.Cases[2].Skipped = "the build failed"
+				mockClient.EXPECT().TestBuildpack(gomock.Any(), gomock.Any()).Return(result, nil)
+
+				reportPath := filepath.Join(t.TempDir(), "junit.xml")
+				command.SetArgs([]string{"some-bp", "--app", "fixtures/node-app", "--report", reportPath})
+				h.AssertNotNil(t, command.Execute())
+
+				contents, err := os.ReadFile(reportPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, string(contents), `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="some/bp@1.0.0 (node-app)" tests="3" failures="1" skipped="1" time="1.500">
+    <testcase name="detect" classname="some/bp@1.0.0"></testcase>
+    <testcase name="build" classname="some/bp@1.0.0">
+      <failure message="expected the build to pass, but it failed: exit status 1"></failure>
+    </testcase>
+    <testcase name="layer node" classname="some/bp@1.0.0">
+      <skipped message="the build failed"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`)
+			})
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// BuildpackVersionsFlags define flags provided to the BuildpackVersions command
+type BuildpackVersionsFlags struct {
+	Registry      string
+	OutputFormat  string
+	IncludeYanked bool
+}
+
+// BuildpackVersions lists the versions of a buildpack in a buildpack registry
+func BuildpackVersions(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
+	var flags BuildpackVersionsFlags
+
+	cmd := &cobra.Command{
+		Use:     "versions <namespace>/<name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "List the versions of a buildpack in a buildpack registry",
+		Example: "pack buildpack versions example/java",
+		Long: "List the versions of a buildpack in a buildpack registry, from the newest to the oldest. " +
+			"Yanked versions are skipped unless '--include-yanked' is set.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatJSON {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSON)
+			}
+
+			registry, err := config.GetRegistry(cfg, flags.Registry)
+			if err != nil {
+				return err
+			}
+
+			versions, err := pack.ListBuildpackVersions(client.ListBuildpackVersionsOptions{
+				ID:            args[0],
+				Registry:      registry.Name,
+				IncludeYanked: flags.IncludeYanked,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == outputFormatJSON {
+				return writeRegistryBuildpacksJSON(logger.Writer(), versions)
+			}
+			return writeRegistryBuildpacks(logger.Writer(), versions)
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Registry, "registry", "r", "", "Name of the buildpack registry the buildpack is in, defaults to the default registry")
+	cmd.Flags().BoolVar(&flags.IncludeYanked, "include-yanked", false, "List yanked versions")
+	cmd.Flags().StringVar(&flags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format (human-readable, json)")
+	AddHelpFlag(cmd, "versions")
+	return cmd
+}
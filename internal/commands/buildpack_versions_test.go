@@ -0,0 +1,76 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestBuildpackVersionsCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "BuildpackVersionsCommand", testBuildpackVersionsCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testBuildpackVersionsCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger := logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.BuildpackVersions(logger, config.Config{}, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#BuildpackVersions", func() {
+		it("lists the versions of the buildpack", func() {
+			mockClient.EXPECT().ListBuildpackVersions(client.ListBuildpackVersionsOptions{
+				ID:            "example/java",
+				Registry:      "official",
+				IncludeYanked: true,
+			}).Return([]client.RegistryBuildpack{
+				{ID: "example/java", Version: "1.10.0"},
+				{ID: "example/java", Version: "1.2.0", Yanked: true},
+			}, nil)
+
+			command.SetArgs([]string{"example/java", "--include-yanked"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "1.10.0")
+			h.AssertContains(t, outBuf.String(), "1.2.0 (yanked)")
+		})
+
+		it("returns the error of the listing", func() {
+			mockClient.EXPECT().ListBuildpackVersions(gomock.Any()).Return(nil, errors.New("no versions of buildpack 'example/java' found"))
+
+			command.SetArgs([]string{"example/java"})
+			h.AssertError(t, command.Execute(), "no versions of buildpack 'example/java' found")
+		})
+
+		it("requires a buildpack id", func() {
+			h.AssertError(t, command.Execute(), "accepts 1 arg")
+		})
+	})
+}
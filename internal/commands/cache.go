@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewCacheCommand(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Interact with build caches",
+		Long: `'pack cache' commands list, remove, export and import the volume and bind caches pack creates for the images it builds.
+
+Generated cache volumes are attributed to images using the keys stored in '$PACK_HOME/volume-keys.toml'.`,
+		RunE: nil,
+	}
+
+	cmd.AddCommand(CacheList(logger, client))
+	cmd.AddCommand(CachePrune(logger, client))
+	cmd.AddCommand(CacheExport(logger, cfg, client))
+	cmd.AddCommand(CacheImport(logger, cfg, client))
+
+	AddHelpFlag(cmd, "cache")
+	return cmd
+}
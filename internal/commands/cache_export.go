@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type CacheExportFlags struct {
+	Cache      cache.CacheOpts
+	OutputPath string
+}
+
+// CacheExport writes the build cache of an image to an OCI layout archive
+func CacheExport(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
+	var flags CacheExportFlags
+
+	cmd := &cobra.Command{
+		Use:     "export <image-name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "Export the build cache of an image to an archive",
+		Example: "pack cache export my-app -o cache.tar",
+		Long: "Export the volume or bind build cache of an image to a tarball containing an OCI layout, " +
+			"so it can be shipped as an artifact without a registry and restored with 'pack cache import'.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputPath == "" {
+				return errors.New("an output path must be provided using --output")
+			}
+
+			if err := pack.ExportCache(cmd.Context(), client.ExportCacheOptions{
+				Image:       args[0],
+				Cache:       flags.Cache,
+				OutputPath:  flags.OutputPath,
+				HelperImage: cfg.LifecycleImage,
+			}); err != nil {
+				return err
+			}
+
+			logger.Infof("Exported build cache of %s to %s", style.Symbol(args[0]), style.Symbol(flags.OutputPath))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.OutputPath, "output", "o", "", "Path to write the archive to")
+	cmd.Flags().Var(&flags.Cache, "cache", "Build cache to export, using the same options as 'pack build --cache'; defaults to the generated cache volume of the image")
+	AddHelpFlag(cmd, "export")
+	return cmd
+}
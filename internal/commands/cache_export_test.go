@@ -0,0 +1,107 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheExportCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testCacheExportCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testCacheExportCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cfg            config.Config
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		cfg = config.Config{}
+		command = commands.CacheExport(logger, cfg, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheExport", func() {
+		it("exports the build cache of the image", func() {
+			mockClient.EXPECT().ExportCache(gomock.Any(), client.ExportCacheOptions{
+				Image:      "my-app",
+				OutputPath: "cache.tar",
+			}).Return(nil)
+
+			command.SetArgs([]string{"my-app", "-o", "cache.tar"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Exported build cache of 'my-app' to 'cache.tar'")
+		})
+
+		when("--cache is provided", func() {
+			it("exports that cache", func() {
+				mockClient.EXPECT().ExportCache(gomock.Any(), client.ExportCacheOptions{
+					Image:      "my-app",
+					OutputPath: "cache.tar",
+					Cache:      cache.CacheOpts{Build: cache.CacheInfo{Format: cache.CacheVolume, Source: "my-volume"}},
+				}).Return(nil)
+
+				command.SetArgs([]string{"my-app", "-o", "cache.tar", "--cache", "type=build;format=volume;name=my-volume"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("a custom lifecycle image is configured", func() {
+			it("is used as the helper image", func() {
+				command = commands.CacheExport(logger, config.Config{LifecycleImage: "my/lifecycle"}, mockClient)
+				mockClient.EXPECT().ExportCache(gomock.Any(), client.ExportCacheOptions{
+					Image:       "my-app",
+					OutputPath:  "cache.tar",
+					HelperImage: "my/lifecycle",
+				}).Return(nil)
+
+				command.SetArgs([]string{"my-app", "-o", "cache.tar"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--output is not provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"my-app"})
+				h.AssertError(t, command.Execute(), "an output path must be provided using --output")
+			})
+		})
+
+		when("exporting fails", func() {
+			it("errors", func() {
+				mockClient.EXPECT().ExportCache(gomock.Any(), gomock.Any()).Return(errors.New("no build cache found"))
+
+				command.SetArgs([]string{"my-app", "-o", "cache.tar"})
+				h.AssertError(t, command.Execute(), "no build cache found")
+			})
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type CacheImportFlags struct {
+	Cache     cache.CacheOpts
+	InputPath string
+}
+
+// CacheImport restores the build cache of an image from an archive written by 'pack cache export'
+func CacheImport(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
+	var flags CacheImportFlags
+
+	cmd := &cobra.Command{
+		Use:     "import <image-name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "Import the build cache of an image from an archive",
+		Example: "pack cache import my-app -i cache.tar",
+		Long: "Restore the build cache of an image from an archive written by 'pack cache export'. " +
+			"Any existing build cache of the image is replaced.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.InputPath == "" {
+				return errors.New("an input path must be provided using --input")
+			}
+
+			if err := pack.ImportCache(cmd.Context(), client.ImportCacheOptions{
+				Image:       args[0],
+				Cache:       flags.Cache,
+				InputPath:   flags.InputPath,
+				HelperImage: cfg.LifecycleImage,
+			}); err != nil {
+				return err
+			}
+
+			logger.Infof("Imported build cache of %s from %s", style.Symbol(args[0]), style.Symbol(flags.InputPath))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.InputPath, "input", "i", "", "Path of the archive to import")
+	cmd.Flags().Var(&flags.Cache, "cache", "Build cache to import into, using the same options as 'pack build --cache'; defaults to the generated cache volume of the image")
+	AddHelpFlag(cmd, "import")
+	return cmd
+}
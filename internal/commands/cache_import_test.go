@@ -0,0 +1,91 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheImportCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testCacheImportCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testCacheImportCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.CacheImport(logger, config.Config{}, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheImport", func() {
+		it("imports the build cache of the image", func() {
+			mockClient.EXPECT().ImportCache(gomock.Any(), client.ImportCacheOptions{
+				Image:     "my-app",
+				InputPath: "cache.tar",
+			}).Return(nil)
+
+			command.SetArgs([]string{"my-app", "-i", "cache.tar"})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Imported build cache of 'my-app' from 'cache.tar'")
+		})
+
+		when("--cache is provided", func() {
+			it("imports into that cache", func() {
+				mockClient.EXPECT().ImportCache(gomock.Any(), client.ImportCacheOptions{
+					Image:     "my-app",
+					InputPath: "cache.tar",
+					Cache:     cache.CacheOpts{Build: cache.CacheInfo{Format: cache.CacheVolume, Source: "my-volume"}},
+				}).Return(nil)
+
+				command.SetArgs([]string{"my-app", "-i", "cache.tar", "--cache", "type=build;format=volume;name=my-volume"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--input is not provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"my-app"})
+				h.AssertError(t, command.Execute(), "an input path must be provided using --input")
+			})
+		})
+
+		when("importing fails", func() {
+			it("errors", func() {
+				mockClient.EXPECT().ImportCache(gomock.Any(), gomock.Any()).Return(errors.New("not a build cache exported by pack"))
+
+				command.SetArgs([]string{"my-app", "-i", "cache.tar"})
+				h.AssertError(t, command.Execute(), "not a build cache exported by pack")
+			})
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type CacheListFlags struct {
+	Image        string
+	OutputFormat string
+}
+
+// CacheList lists the volume and bind caches created by pack
+func CacheList(logger logging.Logger, pack PackClient) *cobra.Command {
+	var flags CacheListFlags
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Args:    cobra.NoArgs,
+		Short:   "List build caches",
+		Example: "pack cache list --image my-app",
+		Long:    "List the volume and bind caches created by pack, along with their size, when they were last used and the image they belong to.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatJSON {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSON)
+			}
+
+			entries, err := pack.ListCaches(cmd.Context(), client.ListCachesOptions{Image: flags.Image})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == outputFormatJSON {
+				if entries == nil {
+					entries = []client.CacheEntry{}
+				}
+				encoder := json.NewEncoder(logger.Writer())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(entries)
+			}
+
+			if len(entries) == 0 {
+				logger.Info("No caches found")
+				return nil
+			}
+			return writeCacheEntries(logger.Writer(), entries)
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.Image, "image", "", "Only list the caches of this image")
+	cmd.Flags().StringVar(&flags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format (human-readable, json)")
+	AddHelpFlag(cmd, "list")
+	return cmd
+}
+
+func writeCacheEntries(w io.Writer, entries []client.CacheEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tFORMAT\tIMAGE\tSIZE\tLAST USED")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Name,
+			orUnknown(entry.Type),
+			entry.Format,
+			orUnknown(entry.Image),
+			cacheSize(entry.Size),
+			cacheLastUsed(entry),
+		)
+	}
+	return tw.Flush()
+}
+
+func cacheSize(size int64) string {
+	if size < 0 {
+		return "-"
+	}
+	return humanize.Bytes(uint64(size))
+}
+
+func cacheLastUsed(entry client.CacheEntry) string {
+	if entry.LastUsed.IsZero() {
+		return "-"
+	}
+	return humanize.Time(entry.LastUsed)
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "(unknown)"
+	}
+	return value
+}
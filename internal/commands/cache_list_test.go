@@ -0,0 +1,102 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheListCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testCacheListCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testCacheListCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		entries        []client.CacheEntry
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.CacheList(logger, mockClient)
+
+		entries = []client.CacheEntry{
+			{Name: "pack-cache-unknown.build", Format: "volume", Type: "build", Size: -1},
+			{Name: "pack-cache-my-app.launch", Format: "volume", Type: "launch", Image: "index.docker.io/library/my-app:latest", Size: 2 * 1000 * 1000, LastUsed: time.Now().Add(-3 * 24 * time.Hour)},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CacheList", func() {
+		it("prints the caches", func() {
+			mockClient.EXPECT().ListCaches(gomock.Any(), client.ListCachesOptions{}).Return(entries, nil)
+
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+
+			output := outBuf.String()
+			h.AssertContainsMatch(t, output, `NAME\s+TYPE\s+FORMAT\s+IMAGE\s+SIZE\s+LAST USED`)
+			h.AssertContainsMatch(t, output, `pack-cache-unknown.build\s+build\s+volume\s+\(unknown\)\s+-\s+-`)
+			h.AssertContainsMatch(t, output, `pack-cache-my-app.launch\s+launch\s+volume\s+index.docker.io/library/my-app:latest\s+2.0 MB\s+3 days ago`)
+		})
+
+		when("--image is provided", func() {
+			it("passes the image to the client", func() {
+				mockClient.EXPECT().ListCaches(gomock.Any(), client.ListCachesOptions{Image: "my-app"}).Return(nil, nil)
+
+				command.SetArgs([]string{"--image", "my-app"})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "No caches found")
+			})
+		})
+
+		when("--output-format is json", func() {
+			it("prints the caches as json", func() {
+				mockClient.EXPECT().ListCaches(gomock.Any(), gomock.Any()).Return(entries, nil)
+
+				command.SetArgs([]string{"--output-format", "json"})
+				h.AssertNil(t, command.Execute())
+
+				var output []client.CacheEntry
+				h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &output))
+				h.AssertEq(t, len(output), 2)
+				h.AssertEq(t, output[1].Image, "index.docker.io/library/my-app:latest")
+			})
+		})
+
+		when("listing fails", func() {
+			it("returns the error", func() {
+				mockClient.EXPECT().ListCaches(gomock.Any(), gomock.Any()).Return(nil, errors.New("listing volumes: no docker"))
+
+				command.SetArgs([]string{})
+				h.AssertError(t, command.Execute(), "listing volumes: no docker")
+			})
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type CachePruneFlags struct {
+	OlderThan    string
+	Image        string
+	Unattributed bool
+}
+
+// CachePrune removes stale caches created by pack
+func CachePrune(logger logging.Logger, pack PackClient) *cobra.Command {
+	var flags CachePruneFlags
+
+	cmd := &cobra.Command{
+		Use:     "prune",
+		Args:    cobra.NoArgs,
+		Short:   "Remove stale build caches",
+		Example: "pack cache prune --older-than 30d",
+		Long: "Remove the volume caches created by pack that match all of the provided flags.\n\n" +
+			"Caches that can't be attributed to an image are only removed with --unattributed. " +
+			"Bind caches are reported but never removed, as their directories belong to the user. " +
+			"Entries in volume-keys.toml for images that no longer have any caches are removed as well.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			var olderThan time.Duration
+			if flags.OlderThan != "" {
+				var err error
+				if olderThan, err = parseAge(flags.OlderThan); err != nil {
+					return err
+				}
+			}
+
+			result, err := pack.PruneCaches(cmd.Context(), client.PruneCachesOptions{
+				OlderThan:    olderThan,
+				Image:        flags.Image,
+				Unattributed: flags.Unattributed,
+			})
+			if err != nil {
+				return err
+			}
+
+			var reclaimed int64
+			for _, entry := range result.Removed {
+				logger.Infof("Removed %s cache %s", entry.Format, style.Symbol(entry.Name))
+				if entry.Size > 0 {
+					reclaimed += entry.Size
+				}
+			}
+			for _, image := range result.RemovedKeys {
+				logger.Infof("Removed volume key for %s", style.Symbol(image))
+			}
+			for _, entry := range result.BindCaches {
+				logger.Infof("Not removing bind cache %s; remove its directory yourself if it's no longer needed", style.Symbol(entry.Name))
+			}
+			if result.Unattributed > 0 {
+				logger.Infof("Kept %d cache(s) that can't be attributed to an image; use %s to remove them", result.Unattributed, style.Symbol("--unattributed"))
+			}
+
+			if len(result.Removed) == 0 && len(result.RemovedKeys) == 0 {
+				logger.Info("No caches to prune")
+				return nil
+			}
+			logger.Infof("Reclaimed %s", humanize.Bytes(uint64(reclaimed)))
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.OlderThan, "older-than", "", "Remove caches last used longer ago than this duration, e.g. 72h or 30d")
+	cmd.Flags().StringVar(&flags.Image, "image", "", "Remove the caches of this image")
+	cmd.Flags().BoolVar(&flags.Unattributed, "unattributed", false, "Also remove caches that can't be attributed to an image, such as volumes named with PACK_VOLUME_KEY or created before pack recorded cache use")
+	AddHelpFlag(cmd, "prune")
+	return cmd
+}
+
+// parseAge parses a duration, additionally accepting a whole number of days such as 30d
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, errors.Errorf("invalid duration %s; must be a duration such as 72h or a number of days such as 30d", style.Symbol(value))
+	}
+	return age, nil
+}
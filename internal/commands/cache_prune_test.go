@@ -0,0 +1,129 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCachePruneCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testCachePruneCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testCachePruneCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.CachePrune(logger, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#CachePrune", func() {
+		it("prints the removed caches and volume keys", func() {
+			mockClient.EXPECT().PruneCaches(gomock.Any(), client.PruneCachesOptions{}).Return(client.PruneCachesResult{
+				Removed: []client.CacheEntry{
+					{Name: "pack-cache-my-app.build", Format: "volume", Size: 1000},
+					{Name: "pack-cache-my-app.launch", Format: "volume", Size: 500},
+				},
+				RemovedKeys: []string{"index.docker.io/library/my-app:latest"},
+			}, nil)
+
+			command.SetArgs([]string{})
+			h.AssertNil(t, command.Execute())
+
+			output := outBuf.String()
+			h.AssertContains(t, output, "Removed volume cache 'pack-cache-my-app.build'")
+			h.AssertContains(t, output, "Removed volume cache 'pack-cache-my-app.launch'")
+			h.AssertContains(t, output, "Removed volume key for 'index.docker.io/library/my-app:latest'")
+			h.AssertContains(t, output, "Reclaimed 1.5 kB")
+		})
+
+		when("nothing is removed", func() {
+			it("says so", func() {
+				mockClient.EXPECT().PruneCaches(gomock.Any(), gomock.Any()).Return(client.PruneCachesResult{}, nil)
+
+				command.SetArgs([]string{})
+				h.AssertNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "No caches to prune")
+			})
+		})
+
+		when("bind caches or unattributed caches are kept", func() {
+			it("reports them", func() {
+				mockClient.EXPECT().PruneCaches(gomock.Any(), gomock.Any()).Return(client.PruneCachesResult{
+					BindCaches:   []client.CacheEntry{{Name: "/some/dir/build-cache", Format: "bind"}},
+					Unattributed: 2,
+				}, nil)
+
+				command.SetArgs([]string{})
+				h.AssertNil(t, command.Execute())
+
+				output := outBuf.String()
+				h.AssertContains(t, output, "Not removing bind cache '/some/dir/build-cache'")
+				h.AssertContains(t, output, "Kept 2 cache(s) that can't be attributed to an image; use '--unattributed' to remove them")
+			})
+		})
+
+		when("--unattributed is provided", func() {
+			it("passes it to the client", func() {
+				mockClient.EXPECT().PruneCaches(gomock.Any(), client.PruneCachesOptions{Unattributed: true}).Return(client.PruneCachesResult{}, nil)
+
+				command.SetArgs([]string{"--unattributed"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--older-than and --image are provided", func() {
+			it("passes them to the client", func() {
+				mockClient.EXPECT().PruneCaches(gomock.Any(), client.PruneCachesOptions{
+					OlderThan: 30 * 24 * time.Hour,
+					Image:     "my-app",
+				}).Return(client.PruneCachesResult{}, nil)
+
+				command.SetArgs([]string{"--older-than", "30d", "--image", "my-app"})
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("accepts go durations", func() {
+				mockClient.EXPECT().PruneCaches(gomock.Any(), client.PruneCachesOptions{OlderThan: 90 * time.Minute}).Return(client.PruneCachesResult{}, nil)
+
+				command.SetArgs([]string{"--older-than", "1h30m"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--older-than is invalid", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"--older-than", "a week"})
+				h.AssertError(t, command.Execute(), "invalid duration 'a week'")
+			})
+		})
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/google/go-containerregistry/pkg/v1/types"
@@ -17,25 +19,45 @@ import (
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/lock"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/policy"
 )
 
 //go:generate mockgen -package testmocks -destination testmocks/mock_pack_client.go github.com/buildpacks/pack/internal/commands PackClient
 type PackClient interface {
 	InspectBuilder(string, bool, ...client.BuilderInspectionModifier) (*client.BuilderInfo, error)
 	InspectImage(string, bool) (*client.ImageInfo, error)
+	DiffImages(ctx context.Context, nameA, nameB string, options client.DiffImagesOptions) (*client.ImageDiff, error)
+	DiffBuilders(ctx context.Context, oldName, newName string, options client.DiffBuildersOptions) (*client.BuilderDiff, error)
 	Rebase(context.Context, client.RebaseOptions) error
 	CreateBuilder(context.Context, client.CreateBuilderOptions) error
 	NewBuildpack(context.Context, client.NewBuildpackOptions) error
+	NewExtension(context.Context, client.NewExtensionOptions) error
 	PackageBuildpack(ctx context.Context, opts client.PackageBuildpackOptions) error
 	PackageExtension(ctx context.Context, opts client.PackageBuildpackOptions) error
 	Build(context.Context, client.BuildOptions) error
+	WatchBuild(context.Context, client.WatchBuildOptions) error
+	Run(context.Context, client.RunOptions) error
+	Detect(context.Context, client.BuildOptions) (*client.DetectResult, error)
+	TestBuildpack(context.Context, client.TestBuildpackOptions) (*client.BuildpackTestResult, error)
+	ListCaches(context.Context, client.ListCachesOptions) ([]client.CacheEntry, error)
+	PruneCaches(context.Context, client.PruneCachesOptions) (client.PruneCachesResult, error)
+	ExportCache(context.Context, client.ExportCacheOptions) error
+	ImportCache(context.Context, client.ImportCacheOptions) error
 	RegisterBuildpack(context.Context, client.RegisterBuildpackOptions) error
 	YankBuildpack(client.YankBuildpackOptions) error
 	InspectBuildpack(client.InspectBuildpackOptions) (*client.BuildpackInfo, error)
 	InspectExtension(client.InspectExtensionOptions) (*client.ExtensionInfo, error)
 	PullBuildpack(context.Context, client.PullBuildpackOptions) error
+	SearchBuildpacks(client.SearchBuildpacksOptions) ([]client.RegistryBuildpack, error)
+	ListBuildpackVersions(client.ListBuildpackVersionsOptions) ([]client.RegistryBuildpack, error)
 	DownloadSBOM(name string, options client.DownloadSBOMOptions) error
+	ReadSBOM(ctx context.Context, name string, options client.ReadSBOMOptions) ([]client.SBOMPackage, error)
+	MergeSBOM(ctx context.Context, name string, options client.MergeSBOMOptions) error
+	ScanSBOM(ctx context.Context, name string, options client.ScanSBOMOptions) ([]client.SBOMVulnerability, error)
+	SyncMirror(ctx context.Context, opts client.SyncMirrorOptions) error
+	LockBuildpacks(ctx context.Context, opts client.LockBuildpacksOptions) (lock.File, error)
 	CreateManifest(ctx context.Context, opts client.CreateManifestOptions) error
 	AnnotateManifest(ctx context.Context, opts client.ManifestAnnotateOptions) error
 	AddManifest(ctx context.Context, opts client.ManifestAddOptions) error
@@ -105,6 +127,46 @@ func getMirrors(config config.Config) map[string][]string {
 	return mirrors
 }
 
+// signKeyFormats describes the private keys --sign-key accepts
+const signKeyFormats = "The key must be unencrypted: PKCS #8 (PRIVATE KEY), EC (EC PRIVATE KEY) or PKCS #1 (RSA PRIVATE KEY).\n" +
+	"Encrypted keys, such as cosign's ENCRYPTED SIGSTORE PRIVATE KEY, must be exported unencrypted first"
+
+// readSignKey reads the PEM encoded private key to sign with from a file, or from an environment variable when
+// the path is env://<name>
+func readSignKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if envName, ok := strings.CutPrefix(path, "env://"); ok {
+		key := os.Getenv(envName)
+		if key == "" {
+			return nil, errors.Errorf("environment variable %s holding the sign key is empty", style.Symbol(envName))
+		}
+		return []byte(key), nil
+	}
+	key, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading sign key")
+	}
+	return key, nil
+}
+
+// readPolicy reads the policy file at path, or at the policy path of the config when path is empty. It returns nil
+// when neither is set.
+func readPolicy(path string, cfg config.Config) (*policy.Policy, error) {
+	if path == "" {
+		path = cfg.Policy
+	}
+	if path == "" {
+		return nil, nil
+	}
+	p, err := policy.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 func deprecationWarning(logger logging.Logger, oldCmd, replacementCmd string) {
 	logger.Warnf("Command %s has been deprecated, please use %s instead", style.Symbol("pack "+oldCmd), style.Symbol("pack "+replacementCmd))
 }
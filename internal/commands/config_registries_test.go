@@ -221,7 +221,7 @@ func testConfigRegistries(t *testing.T, when spec.G, it spec.S) {
 				assert.Error(cmd.Execute())
 
 				output := outBuf.String()
-				assert.Contains(output, "'bogus' is not a valid type. Supported types are: 'git', 'github'.")
+				assert.Contains(output, "'bogus' is not a valid type. Supported types are: 'git', 'github', 'oci'.")
 			})
 
 			it("should throw error when registry already exists", func() {
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"os"
+	"path/filepath"
 	"sort"
 
 	"github.com/pkg/errors"
@@ -10,6 +12,7 @@ import (
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/signature"
 )
 
 func ConfigTrustedBuilder(logger logging.Logger, cfg config.Config, cfgPath string) *cobra.Command {
@@ -33,9 +36,14 @@ func ConfigTrustedBuilder(logger logging.Logger, cfg config.Config, cfgPath stri
 	listCmd.Example = "pack config trusted-builders list"
 	cmd.AddCommand(listCmd)
 
-	addCmd := generateAdd("trusted-builders", logger, cfg, cfgPath, addTrustedBuilder)
-	addCmd.Long = "Trust builder.\n\nWhen building with this builder, all lifecycle phases will be run in a single container using the builder image."
-	addCmd.Example = "pack config trusted-builders add cnbs/sample-stack-run:bionic"
+	var keyPath string
+	addCmd := generateAdd("trusted-builders", logger, cfg, cfgPath, func(args []string, logger logging.Logger, cfg config.Config, cfgPath string) error {
+		return addTrustedBuilder(args, logger, cfg, cfgPath, keyPath)
+	})
+	addCmd.Long = "Trust builder.\n\nWhen building with this builder, all lifecycle phases will be run in a single container using the builder image.\n\n" +
+		"When a key is provided, the builder is only trusted when its signature in the registry verifies with the key."
+	addCmd.Example = "pack config trusted-builders add cnbs/sample-stack-run:bionic --key cosign.pub"
+	addCmd.Flags().StringVar(&keyPath, "key", "", "Path to the PEM encoded public key the signature of the builder is verified with, like the cosign.pub of `cosign generate-key-pair`")
 	cmd.AddCommand(addCmd)
 
 	rmCmd := generateRemove("trusted-builders", logger, cfg, cfgPath, removeTrustedBuilder)
@@ -47,23 +55,45 @@ func ConfigTrustedBuilder(logger logging.Logger, cfg config.Config, cfgPath stri
 	return cmd
 }
 
-func addTrustedBuilder(args []string, logger logging.Logger, cfg config.Config, cfgPath string) error {
+func addTrustedBuilder(args []string, logger logging.Logger, cfg config.Config, cfgPath string, keyPath string) error {
 	imageName := args[0]
 	builderToTrust := config.TrustedBuilder{Name: imageName}
 
+	if keyPath != "" {
+		key, err := os.ReadFile(filepath.Clean(keyPath))
+		if err != nil {
+			return errors.Wrap(err, "reading key")
+		}
+		if _, err := signature.LoadPublicKey(key); err != nil {
+			return errors.Wrapf(err, "invalid key %s", style.Symbol(keyPath))
+		}
+		builderToTrust.Key = string(key)
+	}
+
 	isTrusted, err := bldr.IsTrustedBuilder(cfg, imageName)
 	if err != nil {
 		return err
 	}
-	if isTrusted || bldr.IsKnownTrustedBuilder(imageName) {
+	// a key can be added to builders that are already trusted, so that they are verified
+	if (isTrusted || bldr.IsKnownTrustedBuilder(imageName)) && builderToTrust.Key == "" {
 		logger.Infof("Builder %s is already trusted", style.Symbol(imageName))
 		return nil
 	}
 
-	cfg.TrustedBuilders = append(cfg.TrustedBuilders, builderToTrust)
+	trustedBuilders := []config.TrustedBuilder{}
+	for _, trustedBuilder := range cfg.TrustedBuilders {
+		if trustedBuilder.Name != imageName {
+			trustedBuilders = append(trustedBuilders, trustedBuilder)
+		}
+	}
+	cfg.TrustedBuilders = append(trustedBuilders, builderToTrust)
 	if err := config.Write(cfg, cfgPath); err != nil {
 		return errors.Wrap(err, "writing config")
 	}
+	if builderToTrust.Key != "" {
+		logger.Infof("Builder %s is now trusted, when its signature verifies with key %s", style.Symbol(imageName), style.Symbol(keyPath))
+		return nil
+	}
 	logger.Infof("Builder %s is now trusted", style.Symbol(imageName))
 
 	return nil
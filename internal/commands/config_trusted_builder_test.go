@@ -2,6 +2,9 @@ package commands_test
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -175,6 +178,31 @@ func testTrustedBuilderCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("a key is provided", func() {
+				it("stores the key with the builder", func() {
+					privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+					h.AssertNil(t, err)
+					keyPath := h.WritePublicKey(t, tempPackHome, privateKey)
+
+					command.SetArgs(append(args, "some-builder", "--key", keyPath))
+					h.AssertNil(t, command.Execute())
+
+					b, err := os.ReadFile(configPath)
+					h.AssertNil(t, err)
+					h.AssertContains(t, string(b), `name = "some-builder"`)
+					h.AssertContains(t, string(b), "-----BEGIN PUBLIC KEY-----")
+					h.AssertContains(t, outBuf.String(), "when its signature verifies with key")
+				})
+
+				it("errors when the key is invalid", func() {
+					keyPath := filepath.Join(tempPackHome, "cosign.pub")
+					h.AssertNil(t, os.WriteFile(keyPath, []byte("some-key"), 0600))
+
+					command.SetArgs(append(args, "some-builder", "--key", keyPath))
+					h.AssertError(t, command.Execute(), "public key isn't PEM encoded")
+				})
+			})
+
 			when("builder is a suggested builder", func() {
 				it("does nothing", func() {
 					h.AssertNil(t, os.WriteFile(configPath, []byte(""), os.ModePerm))
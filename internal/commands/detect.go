@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	bldr "github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/builder/writer"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type DetectFlags struct {
+	AppPath        string
+	Builder        string
+	Registry       string
+	RunImage       string
+	Platform       string
+	Policy         string
+	Network        string
+	DescriptorPath string
+	LifecycleImage string
+	OutputFormat   string
+	Env            []string
+	EnvFiles       []string
+	Buildpacks     []string
+	Extensions     []string
+	Volumes        []string
+	PreBuildpacks  []string
+	PostBuildpacks []string
+}
+
+// Detect runs the analyze and detect phases of a build without building an image
+func Detect(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags DetectFlags
+
+	cmd := &cobra.Command{
+		Use:     "detect",
+		Args:    cobra.NoArgs,
+		Short:   "Run only the detect phase of a build",
+		Example: "pack detect --path apps/test-app --builder cnbs/sample-builder:bionic",
+		Long: "Pack Detect runs the analyze and detect phases of the lifecycle against your source code, without building an image.\n\n" +
+			"It reports the result and output of each buildpack in every group that was tried, the selected group, " +
+			"and the group.toml and plan.toml written by the detector. Use it to find out why no buildpack groups passed detection.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.Registry != "" && !cfg.Experimental {
+				return client.NewExperimentError("Support for buildpack registries is currently experimental.")
+			}
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatJSON {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSON)
+			}
+
+			if flags.OutputFormat == outputFormatJSON {
+				// keep the logs of the phases out of the JSON document
+				logging.UseStderr(logger)
+			}
+
+			descriptor, actualDescriptorPath, err := parseProjectToml(flags.AppPath, flags.DescriptorPath, logger)
+			if err != nil {
+				return err
+			}
+
+			builder := flags.Builder
+			if !cmd.Flags().Changed("builder") && descriptor.Build.Builder != "" {
+				builder = descriptor.Build.Builder
+			}
+			if builder == "" {
+				suggestSettingBuilder(logger, packClient)
+				return client.NewSoftError()
+			}
+
+			env, err := parseEnv(flags.EnvFiles, flags.Env)
+			if err != nil {
+				return err
+			}
+
+			// detection always runs in separate containers, trust only decides whether a lifecycle image is required
+			isTrusted, err := bldr.IsTrustedBuilder(cfg, builder)
+			if err != nil {
+				return err
+			}
+			trustBuilder := isTrusted || bldr.IsKnownTrustedBuilder(builder)
+
+			stringPolicy := flags.Policy
+			if stringPolicy == "" {
+				stringPolicy = cfg.PullPolicy
+			}
+			pullPolicy, err := image.ParsePullPolicy(stringPolicy)
+			if err != nil {
+				return errors.Wrapf(err, "parsing pull policy %s", flags.Policy)
+			}
+
+			var lifecycleImage string
+			if flags.LifecycleImage != "" {
+				ref, err := name.ParseReference(flags.LifecycleImage)
+				if err != nil {
+					return errors.Wrapf(err, "parsing lifecycle image %s", flags.LifecycleImage)
+				}
+				lifecycleImage = ref.Name()
+			}
+
+			result, detectErr := packClient.Detect(cmd.Context(), client.BuildOptions{
+				AppPath:           flags.AppPath,
+				Builder:           builder,
+				Registry:          flags.Registry,
+				AdditionalMirrors: getMirrors(cfg),
+				RunImage:          flags.RunImage,
+				Env:               env,
+				Platform:          flags.Platform,
+				PullPolicy:        pullPolicy,
+				TrustBuilder: func(string) bool {
+					return trustBuilder
+				},
+				Buildpacks: flags.Buildpacks,
+				Extensions: flags.Extensions,
+				ContainerConfig: client.ContainerConfig{
+					Network: flags.Network,
+					Volumes: flags.Volumes,
+				},
+				ProjectDescriptorBaseDir: filepath.Dir(actualDescriptorPath),
+				ProjectDescriptor:        descriptor,
+				LifecycleImage:           lifecycleImage,
+				GroupID:                  -1,
+				UserID:                   -1,
+				PreBuildpacks:            flags.PreBuildpacks,
+				PostBuildpacks:           flags.PostBuildpacks,
+			})
+			if result != nil {
+				if err := writeDetectResult(logger.Writer(), *result, flags.OutputFormat); err != nil {
+					return err
+				}
+			}
+			if detectErr != nil {
+				return errors.Wrap(detectErr, "failed to detect")
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.AppPath, "path", "p", "", "Path to app dir or zip-formatted file (defaults to current working directory)")
+	cmd.Flags().StringSliceVarP(&flags.Buildpacks, "buildpack", "b", nil, "Buildpack to use. One of:\n  a buildpack by id and version in the form of '<buildpack>@<version>',\n  path to a buildpack directory (not supported on Windows),\n  path/URL to a buildpack .tar or .tgz file, or\n  a packaged buildpack image name in the form of '<hostname>/<repo>[:<tag>]'"+stringSliceHelp("buildpack"))
+	cmd.Flags().StringSliceVarP(&flags.Extensions, "extension", "", nil, "Extension to use. One of:\n  an extension by id and version in the form of '<extension>@<version>',\n  path to an extension directory (not supported on Windows),\n  path/URL to an extension .tar or .tgz file, or\n  a packaged extension image name in the form of '<hostname>/<repo>[:<tag>]'"+stringSliceHelp("extension"))
+	cmd.Flags().StringVarP(&flags.Builder, "builder", "B", cfg.DefaultBuilder, "Builder image")
+	cmd.Flags().StringVarP(&flags.DescriptorPath, "descriptor", "d", "", "Path to the project descriptor file")
+	cmd.Flags().StringArrayVarP(&flags.Env, "env", "e", []string{}, "Build-time environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed.\nThis flag may be specified multiple times and will override\n  individual values defined by --env-file."+stringArrayHelp("env"))
+	cmd.Flags().StringArrayVar(&flags.EnvFiles, "env-file", []string{}, "Build-time environment variables file\nOne variable per line, of the form 'VAR=VALUE' or 'VAR'\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed")
+	cmd.Flags().StringVar(&flags.Network, "network", "", "Connect analyze and detect containers to network")
+	cmd.Flags().StringArrayVar(&flags.PreBuildpacks, "pre-buildpack", []string{}, "Buildpacks to prepend to the groups in the builder's order")
+	cmd.Flags().StringArrayVar(&flags.PostBuildpacks, "post-buildpack", []string{}, "Buildpacks to append to the groups in the builder's order")
+	cmd.Flags().StringVar(&flags.LifecycleImage, "lifecycle-image", cfg.LifecycleImage, `Custom lifecycle image to use for analysis`)
+	cmd.Flags().StringVar(&flags.Platform, "platform", "", `Platform to detect on (e.g., "linux/amd64").`)
+	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", `Pull policy to use. Accepted values are always, never, and if-not-present. (default "always")`)
+	cmd.Flags().StringVarP(&flags.Registry, "buildpack-registry", "r", cfg.DefaultRegistryName, "Buildpack Registry by name")
+	cmd.Flags().StringVar(&flags.RunImage, "run-image", "", "Run image (defaults to default stack's run image)")
+	cmd.Flags().StringArrayVar(&flags.Volumes, "volume", nil, "Mount host volume into the detect container, in the form '<host path>:<target path>[:<options>]'."+stringArrayHelp("volume"))
+	cmd.Flags().StringVar(&flags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format for the detect result (human-readable, json).\nWith json, only the result is written to stdout; logs are written to stderr.")
+	AddHelpFlag(cmd, "detect")
+	return cmd
+}
+
+func writeDetectResult(w io.Writer, result client.DetectResult, format string) error {
+	if format == outputFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	orderOutput, _, err := writer.DetectionOrderOutput(result.Order, "")
+	if err != nil {
+		return err
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString(orderOutput)
+
+	buf.WriteString("\nDetection Results:\n")
+	if len(result.Groups) == 0 {
+		buf.WriteString("  (none)\n")
+	}
+	for i, group := range result.Groups {
+		status := "failed"
+		if group.Passed {
+			status = "passed"
+		}
+		fmt.Fprintf(&buf, "  Group #%d: %s\n", i+1, status)
+		for _, bp := range group.Buildpacks {
+			fmt.Fprintf(&buf, "    %s: %s\n", bp.Result, bp.Buildpack)
+			if bp.Output != "" && !group.Passed {
+				for _, line := range strings.Split(bp.Output, "\n") {
+					fmt.Fprintf(&buf, "      %s\n", line)
+				}
+			}
+		}
+	}
+
+	if len(result.Group) > 0 {
+		buf.WriteString("\nSelected Group:\n")
+		for _, bp := range result.Group {
+			fmt.Fprintf(&buf, "  %s\n", bp)
+		}
+	}
+	if result.GroupTOML != "" {
+		fmt.Fprintf(&buf, "\ngroup.toml:\n%s\n", strings.TrimSuffix(result.GroupTOML, "\n"))
+	}
+	if result.PlanTOML != "" {
+		fmt.Fprintf(&buf, "\nplan.toml:\n%s\n", strings.TrimSuffix(result.PlanTOML, "\n"))
+	}
+
+	_, err = io.WriteString(w, buf.String())
+	return err
+}
@@ -0,0 +1,156 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDetectCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testDetectCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testDetectCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cfg            config.Config
+		result         *client.DetectResult
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		cfg = config.Config{}
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		result = &client.DetectResult{
+			Order: pubbldr.DetectionOrder{{
+				GroupDetectionOrder: pubbldr.DetectionOrder{
+					{ModuleRef: dist.ModuleRef{ModuleInfo: dist.ModuleInfo{ID: "some/bp", Version: "1.0.0"}}},
+				},
+			}, {
+				GroupDetectionOrder: pubbldr.DetectionOrder{
+					{ModuleRef: dist.ModuleRef{ModuleInfo: dist.ModuleInfo{ID: "other/bp", Version: "2.0.0"}}},
+				},
+			}},
+			Groups: []client.DetectGroupResult{
+				{Buildpacks: []client.DetectBuildpackResult{{Buildpack: "some/bp@1.0.0", Result: "fail", Output: "no go.mod found\nskipping"}}},
+			},
+		}
+
+		command = commands.Detect(logger, cfg, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Detect", func() {
+		when("no builder is provided", func() {
+			it("suggests a builder", func() {
+				mockClient.EXPECT().InspectBuilder(gomock.Any(), false).Return(&client.BuilderInfo{}, nil).AnyTimes()
+
+				command.SetArgs([]string{})
+				h.AssertNotNil(t, command.Execute())
+				h.AssertContains(t, outBuf.String(), "Please select a default builder with:")
+			})
+		})
+
+		when("detection passes", func() {
+			it("prints the results, selected group, group.toml and plan.toml", func() {
+				result.Groups = append(result.Groups, client.DetectGroupResult{
+					Passed:     true,
+					Buildpacks: []client.DetectBuildpackResult{{Buildpack: "other/bp@2.0.0", Result: "pass", Output: "found main.go"}},
+				})
+				result.Group = []string{"other/bp@2.0.0"}
+				result.GroupTOML = "[[group]]\n  id = \"other/bp\"\n"
+				result.PlanTOML = "[[entries]]\n"
+
+				mockClient.EXPECT().
+					Detect(gomock.Any(), EqBuildOptionsWithImage("my-builder", "")).
+					Return(result, nil)
+
+				command.SetArgs([]string{"--builder", "my-builder"})
+				h.AssertNil(t, command.Execute())
+
+				output := outBuf.String()
+				h.AssertContains(t, output, "Detection Order:\n ├ Group #1:\n │  └ some/bp@1.0.0\n └ Group #2:\n    └ other/bp@2.0.0\n")
+				h.AssertContains(t, output, "  Group #1: failed\n    fail: some/bp@1.0.0\n      no go.mod found\n      skipping\n")
+				h.AssertContains(t, output, "  Group #2: passed\n    pass: other/bp@2.0.0\n")
+				h.AssertNotContains(t, output, "found main.go")
+				h.AssertContains(t, output, "Selected Group:\n  other/bp@2.0.0\n")
+				h.AssertContains(t, output, "group.toml:\n[[group]]\n  id = \"other/bp\"\n")
+				h.AssertContains(t, output, "plan.toml:\n[[entries]]\n")
+			})
+		})
+
+		when("detection fails", func() {
+			it("prints the groups that were tried and errors", func() {
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					Return(result, errors.New("failed with status code: 20"))
+
+				command.SetArgs([]string{"--builder", "my-builder"})
+				h.AssertError(t, command.Execute(), "failed to detect: failed with status code: 20")
+				h.AssertContains(t, outBuf.String(), "  Group #1: failed\n    fail: some/bp@1.0.0\n      no go.mod found\n")
+				h.AssertNotContains(t, outBuf.String(), "Selected Group:")
+			})
+		})
+
+		when("--output-format is json", func() {
+			it("prints the result as json, and the logs to stderr", func() {
+				var errBuf bytes.Buffer
+				logger = logging.NewLogWithWriters(&outBuf, &errBuf)
+				command = commands.Detect(logger, cfg, mockClient)
+
+				mockClient.EXPECT().
+					Detect(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(context.Context, client.BuildOptions) (*client.DetectResult, error) {
+						logger.Info("===> DETECTING")
+						logger.Warn("some warning")
+						return result, nil
+					})
+
+				command.SetArgs([]string{"--builder", "my-builder", "--output-format", "json"})
+				h.AssertNil(t, command.Execute())
+
+				var output client.DetectResult
+				h.AssertNil(t, json.Unmarshal(outBuf.Bytes(), &output))
+				h.AssertEq(t, output.Groups, result.Groups)
+				h.AssertContains(t, errBuf.String(), "===> DETECTING")
+				h.AssertContains(t, errBuf.String(), "some warning")
+			})
+		})
+
+		when("--output-format is unsupported", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"--builder", "my-builder", "--output-format", "jsonl"})
+				h.AssertError(t, command.Execute(), "unsupported output format 'jsonl'")
+			})
+		})
+	})
+}
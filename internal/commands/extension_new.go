@@ -1,36 +1,113 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/internal/target"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
 // ExtensionNewFlags define flags provided to the ExtensionNew command
 type ExtensionNewFlags struct {
-	API     string
-	Path    string
-	Stacks  []string
-	Version string
+	API      string
+	Path     string
+	Targets  []string
+	Template string
+	Version  string
 }
 
-// extensioncreator type to be added here and argument also to be added in the function
+// ExtensionCreator creates extensions
+type ExtensionCreator interface {
+	NewExtension(ctx context.Context, options client.NewExtensionOptions) error
+}
 
 // ExtensionNew generates the scaffolding of an extension
-func ExtensionNew(logger logging.Logger) *cobra.Command {
+func ExtensionNew(logger logging.Logger, creator ExtensionCreator) *cobra.Command {
+	var flags ExtensionNewFlags
 	cmd := &cobra.Command{
 		Use:     "new <id>",
 		Short:   "Creates basic scaffolding of an extension",
 		Args:    cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 		Example: "pack extension new <example-extension>",
+		Long: "extension new generates the basic scaffolding of an extension repository. It creates a new directory `name` in the current directory (or at `path`, if passed as a flag), and initializes an extension.toml, " +
+			"an executable bash script `bin/detect`, and an executable bash script `bin/generate` writing a build.Dockerfile and a run.Dockerfile. " +
+			"Use `--template dockerfiles` to provide static Dockerfiles in the `generate` directory instead.",
 		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
-			// logic will go here
+			id := args[0]
+			idParts := strings.Split(id, "/")
+			dirName := idParts[len(idParts)-1]
+
+			var path string
+			if len(flags.Path) == 0 {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				path = filepath.Join(cwd, dirName)
+			} else {
+				path = flags.Path
+			}
+
+			_, err := os.Stat(path)
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("directory %s exists", style.Symbol(path))
+			}
+
+			targets := defaultExtensionTargets()
+			if len(flags.Targets) > 0 {
+				if targets, err = target.ParseTargets(flags.Targets, logger); err != nil {
+					return err
+				}
+			}
+
+			if err := creator.NewExtension(cmd.Context(), client.NewExtensionOptions{
+				API:      flags.API,
+				ID:       id,
+				Path:     path,
+				Targets:  targets,
+				Template: flags.Template,
+				Version:  flags.Version,
+			}); err != nil {
+				return err
+			}
+
+			logger.Infof("Successfully created %s", style.Symbol(id))
 			return nil
 		}),
 	}
 
-	// flags will go here
+	cmd.Flags().StringVarP(&flags.API, "api", "a", "0.10", "Buildpack API compatibility of the generated extension")
+	cmd.Flags().StringVarP(&flags.Path, "path", "p", "", "Path to generate the extension")
+	cmd.Flags().StringVarP(&flags.Version, "version", "V", "1.0.0", "Version of the generated extension")
+	cmd.Flags().StringSliceVarP(&flags.Targets, "targets", "t", nil,
+		`Targets are of the form 'os/arch/variant', for example 'linux/amd64' or 'linux/arm64/v9'. The full format for targets follows the form [os][/arch][/variant]:[distroname@osversion@anotherversion];[distroname@osversion]
+	- Base case for two different architectures :  '--targets "linux/amd64" --targets "linux/arm64"'
+	- case for distribution version: '--targets "linux/amd64:ubuntu@22.04"'
+	`)
+	cmd.Flags().StringVar(&flags.Template, "template", "",
+		fmt.Sprintf(`Template to generate the extension from, defaults to 'bash'. Either one of %s, a local template directory, or the URL of a git repository holding one.
+Files of a template directory ending with '.tmpl' are rendered with Go templates, where {{ .ID }}, {{ .Name }}, {{ .Version }}, {{ .API }} and {{ .Targets }} are available, and are created without the extension.`,
+			strings.Join(client.ExtensionTemplates(), ", ")))
 
 	AddHelpFlag(cmd, "new")
 	return cmd
 }
+
+// defaultExtensionTargets are the targets of an extension when none are given. Dockerfiles only
+// extend linux images, so it is the current architecture on linux.
+func defaultExtensionTargets() []dist.Target {
+	return []dist.Target{{
+		OS:   "linux",
+		Arch: runtime.GOARCH,
+	}}
+}
@@ -26,6 +26,8 @@ type ExtensionPackageFlags struct {
 	Policy          string
 	Path            string
 	AdditionalTags  []string
+	Lockfile        string
+	SignKey         string
 }
 
 // ExtensionPackager packages extensions
@@ -114,6 +116,16 @@ func ExtensionPackage(logger logging.Logger, cfg config.Config, packager Extensi
 				defer clean(filesToClean)
 			}
 
+			lockfile, err := readLockfile(flags.Lockfile)
+			if err != nil {
+				return err
+			}
+
+			signKey, err := readSignKey(flags.SignKey)
+			if err != nil {
+				return err
+			}
+
 			if err := packager.PackageExtension(cmd.Context(), client.PackageBuildpackOptions{
 				RelativeBaseDir: relativeBaseDir,
 				Name:            name,
@@ -123,6 +135,8 @@ func ExtensionPackage(logger logging.Logger, cfg config.Config, packager Extensi
 				PullPolicy:      pullPolicy,
 				Targets:         multiArchCfg.Targets(),
 				AdditionalTags:  flags.AdditionalTags,
+				Lock:            lockfile,
+				SignKey:         signKey,
 			}); err != nil {
 				return err
 			}
@@ -143,6 +157,8 @@ func ExtensionPackage(logger logging.Logger, cfg config.Config, packager Extensi
 
 	// flags will be added here
 	cmd.Flags().StringVarP(&flags.PackageTomlPath, "config", "c", "", "Path to package TOML config")
+	cmd.Flags().StringVar(&flags.Lockfile, "lockfile", "", "Path to a lockfile generated by 'pack buildpack lock', pinning the extension of the config")
+	cmd.Flags().StringVar(&flags.SignKey, "sign-key", "", "Path to a PEM encoded private key to sign the package with, or env://<VAR> to read it from an environment variable.\nImages are signed in their registry and require --publish; files get a detached signature written to <file>.sig\n"+signKeyFormats)
 	cmd.Flags().StringVarP(&flags.Format, "format", "f", "", `Format to save package as ("image" or "file")`)
 	cmd.Flags().BoolVar(&flags.Publish, "publish", false, `Publish the extension directly to the container registry specified in <name>, instead of the daemon (applies to "--format=image" only).`)
 	cmd.Flags().StringVar(&flags.Policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
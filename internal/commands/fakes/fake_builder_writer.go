@@ -18,6 +18,9 @@ type FakeBuilderWriter struct {
 	ReceivedErrorForRemote error
 	ReceivedBuilderInfo    writer.SharedBuilderInfo
 	ReceivedLocalRunImages []config.RunImage
+
+	PrintForDiff string
+	ReceivedDiff *writer.DiffOutput
 }
 
 func (w *FakeBuilderWriter) Print(
@@ -39,3 +42,11 @@ func (w *FakeBuilderWriter) Print(
 
 	return w.ErrorForPrint
 }
+
+func (w *FakeBuilderWriter) PrintDiff(logger logging.Logger, diff *writer.DiffOutput) error {
+	w.ReceivedDiff = diff
+
+	logger.Infof("\nDIFF:\n%s\n", w.PrintForDiff)
+
+	return w.ErrorForPrint
+}
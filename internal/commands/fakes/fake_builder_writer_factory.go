@@ -9,6 +9,11 @@ type FakeBuilderWriterFactory struct {
 	ErrorForWriter  error
 
 	ReceivedForKind string
+
+	ReturnForDiffWriter writer.BuilderDiffWriter
+	ErrorForDiffWriter  error
+
+	ReceivedForDiffKind string
 }
 
 func (f *FakeBuilderWriterFactory) Writer(kind string) (writer.BuilderWriter, error) {
@@ -16,3 +21,9 @@ func (f *FakeBuilderWriterFactory) Writer(kind string) (writer.BuilderWriter, er
 
 	return f.ReturnForWriter, f.ErrorForWriter
 }
+
+func (f *FakeBuilderWriterFactory) DiffWriter(kind string) (writer.BuilderDiffWriter, error) {
+	f.ReceivedForDiffKind = kind
+
+	return f.ReturnForDiffWriter, f.ErrorForDiffWriter
+}
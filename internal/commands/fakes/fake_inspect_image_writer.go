@@ -16,6 +16,9 @@ type FakeInspectImageWriter struct {
 	RecievedGeneralInfo    inspectimage.GeneralInfo
 	ReceivedErrorForLocal  error
 	ReceivedErrorForRemote error
+
+	PrintForDiff string
+	ReceivedDiff *inspectimage.DiffDisplay
 }
 
 func (w *FakeInspectImageWriter) Print(
@@ -35,3 +38,11 @@ func (w *FakeInspectImageWriter) Print(
 
 	return w.ErrorForPrint
 }
+
+func (w *FakeInspectImageWriter) PrintDiff(logger logging.Logger, diff *inspectimage.DiffDisplay) error {
+	w.ReceivedDiff = diff
+
+	logger.Infof("\nDIFF:\n%s\n", w.PrintForDiff)
+
+	return w.ErrorForPrint
+}
@@ -10,6 +10,10 @@ type FakeInspectImageWriterFactory struct {
 
 	ReceivedForKind string
 	ReceivedForBOM  bool
+
+	ReturnForDiffWriter writer.InspectImageDiffWriter
+	ErrorForDiffWriter  error
+	ReceivedForDiffKind string
 }
 
 func (f *FakeInspectImageWriterFactory) Writer(kind string, bom bool) (writer.InspectImageWriter, error) {
@@ -18,3 +22,9 @@ func (f *FakeInspectImageWriterFactory) Writer(kind string, bom bool) (writer.In
 
 	return f.ReturnForWriter, f.ErrorForWriter
 }
+
+func (f *FakeInspectImageWriterFactory) DiffWriter(kind string) (writer.InspectImageDiffWriter, error) {
+	f.ReceivedForDiffKind = kind
+
+	return f.ReturnForDiffWriter, f.ErrorForDiffWriter
+}
@@ -14,6 +14,7 @@ import (
 //go:generate mockgen -package testmocks -destination testmocks/mock_inspect_image_writer_factory.go github.com/buildpacks/pack/internal/commands InspectImageWriterFactory
 type InspectImageWriterFactory interface {
 	Writer(kind string, BOM bool) (writer.InspectImageWriter, error)
+	DiffWriter(kind string) (writer.InspectImageDiffWriter, error)
 }
 
 type InspectImageFlags struct {
@@ -60,6 +61,7 @@ func InspectImage(
 			return nil
 		}),
 	}
+	cmd.AddCommand(InspectImageDiff(logger, writerFactory, client))
 	AddHelpFlag(cmd, "inspect")
 	cmd.Flags().BoolVar(&flags.BOM, "bom", false, "print bill of materials")
 	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format to display builder detail (json, yaml, toml, human-readable).\nOmission of this flag will display as human-readable.")
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/inspectimage"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type InspectImageDiffFlags struct {
+	Remote       bool
+	OutputFormat string
+}
+
+// InspectImageDiff compares two app images at the buildpack and layer level
+func InspectImageDiff(
+	logger logging.Logger,
+	writerFactory InspectImageWriterFactory,
+	pack PackClient,
+) *cobra.Command {
+	var flags InspectImageDiffFlags
+	cmd := &cobra.Command{
+		Use:     "diff <image-a> <image-b>",
+		Args:    cobra.ExactArgs(2),
+		Short:   "Show what changed between two app images",
+		Example: "pack inspect diff my-app:v1 my-app:v2",
+		Long: "Show what changed between two app images: the run image, the version and layers of each buildpack " +
+			"along with their size, the processes, and the packages listed in the SBoM.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			w, err := writerFactory.DiffWriter(flags.OutputFormat)
+			if err != nil {
+				return err
+			}
+
+			diff, err := pack.DiffImages(cmd.Context(), args[0], args[1], client.DiffImagesOptions{Daemon: !flags.Remote})
+			if err != nil {
+				return err
+			}
+
+			return w.PrintDiff(logger, inspectimage.NewDiffDisplay(args[0], args[1], diff))
+		}),
+	}
+	AddHelpFlag(cmd, "diff")
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Compare images in a remote registry (without pulling them)")
+	cmd.Flags().StringVarP(&flags.OutputFormat, "output", "o", "human-readable", "Output format to display the differences (json, yaml, toml, human-readable).\nOmission of this flag will display as human-readable.")
+	return cmd
+}
@@ -0,0 +1,101 @@
+package commands_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/fakes"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestInspectImageDiffCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "InspectImageDiffCommand", testInspectImageDiffCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testInspectImageDiffCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		assert             = h.NewAssertionManager(t)
+		logger             logging.Logger
+		outBuf             bytes.Buffer
+		mockController     *gomock.Controller
+		mockClient         *testmocks.MockPackClient
+		inspectImageWriter *fakes.FakeInspectImageWriter
+		writerFactory      *fakes.FakeInspectImageWriterFactory
+		imageDiff          *client.ImageDiff
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+
+		inspectImageWriter = &fakes.FakeInspectImageWriter{PrintForDiff: "Sample output for diff"}
+		writerFactory = &fakes.FakeInspectImageWriterFactory{ReturnForDiffWriter: inspectImageWriter}
+		imageDiff = &client.ImageDiff{
+			Buildpacks: []client.BuildpackDiff{{ID: "some/bp", Change: client.DiffAdded, VersionAfter: "1.0.0"}},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#InspectImageDiff", func() {
+		it("passes the differences of the daemon images to the writer", func() {
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:v1", "some/app:v2", client.DiffImagesOptions{Daemon: true}).Return(imageDiff, nil)
+
+			command := commands.InspectImage(logger, writerFactory, config.Config{}, mockClient)
+			command.SetArgs([]string{"diff", "some/app:v1", "some/app:v2"})
+			assert.Nil(command.Execute())
+
+			assert.Equal(writerFactory.ReceivedForDiffKind, "human-readable")
+			assert.Equal(inspectImageWriter.ReceivedDiff.ImageA, "some/app:v1")
+			assert.Equal(inspectImageWriter.ReceivedDiff.ImageB, "some/app:v2")
+			assert.Equal(len(inspectImageWriter.ReceivedDiff.Buildpacks), 1)
+			assert.Contains(outBuf.String(), "DIFF:\nSample output for diff")
+		})
+
+		it("compares remote images in the requested format", func() {
+			mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:v1", "some/app:v2", client.DiffImagesOptions{Daemon: false}).Return(imageDiff, nil)
+
+			command := commands.InspectImageDiff(logger, writerFactory, mockClient)
+			command.SetArgs([]string{"some/app:v1", "some/app:v2", "--remote", "--output", "json"})
+			assert.Nil(command.Execute())
+
+			assert.Equal(writerFactory.ReceivedForDiffKind, "json")
+		})
+
+		when("the output format is unsupported", func() {
+			it("errors", func() {
+				writerFactory.ErrorForDiffWriter = errors.New("output format 'xml' is not supported")
+
+				command := commands.InspectImageDiff(logger, writerFactory, mockClient)
+				command.SetArgs([]string{"some/app:v1", "some/app:v2", "--output", "xml"})
+				assert.ErrorWithMessage(command.Execute(), "output format 'xml' is not supported")
+			})
+		})
+
+		when("the images can't be compared", func() {
+			it("errors", func() {
+				mockClient.EXPECT().DiffImages(gomock.Any(), "some/app:v1", "some/app:v2", gomock.Any()).Return(nil, errors.New("image 'some/app:v1' cannot be found"))
+
+				command := commands.InspectImageDiff(logger, writerFactory, mockClient)
+				command.SetArgs([]string{"some/app:v1", "some/app:v2"})
+				assert.ErrorWithMessage(command.Execute(), "image 'some/app:v1' cannot be found")
+			})
+		})
+	})
+}
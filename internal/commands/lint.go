@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/lint"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+const outputFormatSARIF = "sarif"
+
+// LintFlags define flags provided to the Lint command
+type LintFlags struct {
+	Kind         string
+	OutputFormat string
+	OutputPath   string
+}
+
+// Lint checks buildpack.toml, extension.toml, package.toml and builder.toml files for mistakes
+func Lint(logger logging.Logger, version string) *cobra.Command {
+	var flags LintFlags
+
+	cmd := &cobra.Command{
+		Use:     "lint [<path>...]",
+		Args:    cobra.ArbitraryArgs,
+		Short:   "Check buildpack, extension, package and builder configuration files for mistakes",
+		Example: "pack lint ./my-buildpack\npack lint ./builder-noble.toml --kind builder --format sarif --output lint.sarif",
+		Long: "lint checks buildpack.toml, extension.toml, package.toml and builder.toml files for mistakes that would otherwise " +
+			"only surface while packaging a buildpack or creating a builder: unknown keys, unsupported Buildpack APIs, " +
+			"missing or non-executable `bin/` executables, targets conflicting with deprecated stacks, " +
+			"and order groups referencing modules that aren't provided or pinning versions that don't match.\n\n" +
+			"Each path is either one of these files, or a directory whose files of these names are checked. " +
+			"It defaults to the current directory. The kind of a file is given by its name, unless `--kind` is provided.\n\n" +
+			"Use `--format sarif` to write the findings as SARIF, for IDEs and code review tools to annotate the files.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatSARIF {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatSARIF)
+			}
+
+			paths := args
+			if len(paths) == 0 {
+				paths = []string{"."}
+			}
+			var findings []lint.Finding
+			for _, path := range paths {
+				pathFindings, err := lint.Lint(path, lint.Kind(flags.Kind))
+				if err != nil {
+					return err
+				}
+				findings = append(findings, pathFindings...)
+			}
+
+			out := logger.Writer()
+			if flags.OutputPath != "" {
+				file, err := os.Create(filepath.Clean(flags.OutputPath))
+				if err != nil {
+					return errors.Wrapf(err, "creating %s", style.Symbol(flags.OutputPath))
+				}
+				defer file.Close()
+				out = file
+			}
+
+			if flags.OutputFormat == outputFormatSARIF {
+				if err := lint.WriteSARIF(out, findings, version); err != nil {
+					return err
+				}
+			} else if err := writeLintFindings(out, findings); err != nil {
+				return err
+			}
+
+			errorCount := lint.Errors(findings)
+			if flags.OutputFormat == outputFormatHumanReadable || flags.OutputPath != "" {
+				logger.Info(lintSummary(len(findings)-errorCount, errorCount))
+			}
+			if errorCount > 0 {
+				return client.NewSoftError()
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Kind, "kind", "k", "", fmt.Sprintf("Kind of the files to check, one of %s", strings.Join(lintKinds(), ", ")))
+	cmd.Flags().StringVarP(&flags.OutputFormat, "format", "f", outputFormatHumanReadable, fmt.Sprintf("Output format to write the findings in, one of %s, %s", outputFormatHumanReadable, outputFormatSARIF))
+	cmd.Flags().StringVarP(&flags.OutputPath, "output", "o", "", "Path to write the findings to, instead of the standard output")
+	AddHelpFlag(cmd, "lint")
+	return cmd
+}
+
+func writeLintFindings(w io.Writer, findings []lint.Finding) error {
+	buf := strings.Builder{}
+	for _, finding := range findings {
+		location := finding.File
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+		}
+		fmt.Fprintf(&buf, "%s: %s: %s [%s]\n", location, finding.Level, finding.Message, finding.Rule)
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func lintSummary(warnings, errorCount int) string {
+	if warnings == 0 && errorCount == 0 {
+		return "No problems found"
+	}
+	return fmt.Sprintf("Found %d %s and %d %s", errorCount, pluralize(errorCount, "error", "errors"), warnings, pluralize(warnings, "warning", "warnings"))
+}
+
+func lintKinds() []string {
+	var kinds []string
+	for _, kind := range lint.Kinds {
+		kinds = append(kinds, string(kind))
+	}
+	return kinds
+}
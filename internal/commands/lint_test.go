@@ -0,0 +1,87 @@
+package commands_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLintCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "LintCommand", testLintCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLintCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command *cobra.Command
+		logger  *logging.LogWithWriters
+		outBuf  bytes.Buffer
+		tmpDir  string
+	)
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.Lint(logger, "1.2.3")
+
+		h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "package.toml"), []byte(`[buildpack]
+uri = "./missing"
+`), 0644))
+	})
+
+	when("#Lint", func() {
+		it("writes the findings and fails when there are errors", func() {
+			command.SetArgs([]string{tmpDir})
+			h.AssertNotNil(t, command.Execute())
+
+			h.AssertContains(t, outBuf.String(), filepath.Join(tmpDir, "package.toml")+":2: error: './missing' doesn't exist [locator]")
+			h.AssertContains(t, outBuf.String(), "Found 1 error and 0 warnings")
+		})
+
+		it("succeeds when there are no problems", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "package.toml"), []byte(`[buildpack]
+uri = "docker://example/buildpack:1.0.0"
+`), 0644))
+
+			command.SetArgs([]string{filepath.Join(tmpDir, "package.toml")})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "No problems found")
+		})
+
+		it("writes SARIF to the output file", func() {
+			outputPath := filepath.Join(tmpDir, "lint.sarif")
+			command.SetArgs([]string{tmpDir, "--format", "sarif", "--output", outputPath})
+			h.AssertNotNil(t, command.Execute())
+
+			contents, err := os.ReadFile(outputPath)
+			h.AssertNil(t, err)
+			var log map[string]interface{}
+			h.AssertNil(t, json.Unmarshal(contents, &log))
+			h.AssertEq(t, log["version"], "2.1.0")
+			h.AssertContains(t, string(contents), `"ruleId": "locator"`)
+			h.AssertContains(t, outBuf.String(), "Found 1 error and 0 warnings")
+		})
+
+		it("errors for an unsupported format", func() {
+			command.SetArgs([]string{tmpDir, "--format", "xml"})
+			h.AssertError(t, command.Execute(), "unsupported output format 'xml'; must be one of: human-readable, sarif")
+		})
+
+		it("errors for an unknown kind", func() {
+			command.SetArgs([]string{filepath.Join(tmpDir, "package.toml"), "--kind", "stack"})
+			h.AssertError(t, command.Execute(), "unknown kind stack: must be one of buildpack, extension, package, builder")
+		})
+	})
+}
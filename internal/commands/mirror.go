@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+func NewMirrorCommand(logger logging.Logger, cfg config.Config, client PackClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Interact with the local mirror used in offline mode",
+		Long: `'pack mirror' commands manage the local mirror of builders, run images, lifecycles, buildpacks and registry indexes in '$PACK_HOME/mirror'.
+
+With '--offline', pack resolves images, downloads and registry lookups from the mirror instead of the network.`,
+		RunE: nil,
+	}
+
+	cmd.AddCommand(MirrorSync(logger, cfg, client))
+
+	AddHelpFlag(cmd, "mirror")
+	return cmd
+}
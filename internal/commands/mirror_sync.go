@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
+)
+
+type MirrorSyncFlags struct {
+	Config string
+}
+
+// MirrorSync fetches the artifacts listed in a mirror config into the local mirror
+func MirrorSync(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
+	var flags MirrorSyncFlags
+
+	cmd := &cobra.Command{
+		Use:     "sync",
+		Args:    cobra.NoArgs,
+		Short:   "Fetch builders, images, lifecycles, buildpacks and registry indexes into the local mirror",
+		Example: "pack mirror sync --config mirror.toml",
+		Long: "Fetch the artifacts listed in a mirror config into the local mirror, for builds to run with '--offline' " +
+			"on machines without network access.\n\n" +
+			"The config lists 'builders', mirrored along with their run images and lifecycle image, 'images', " +
+			"lifecycle versions as 'lifecycles', buildpack URIs, images and registry references as 'buildpacks', " +
+			"and the names of the buildpack 'registries' whose index is mirrored. " +
+			"Images are mirrored for each of the 'platforms', which default to linux on the current architecture.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			mirrorConfig, err := mirror.ReadConfig(flags.Config)
+			if err != nil {
+				return err
+			}
+
+			return pack.SyncMirror(cmd.Context(), client.SyncMirrorOptions{
+				Config:   mirrorConfig,
+				Registry: cfg.DefaultRegistryName,
+			})
+		}),
+	}
+
+	cmd.Flags().StringVarP(&flags.Config, "config", "c", "mirror.toml", "Path to the mirror config")
+	AddHelpFlag(cmd, "sync")
+	return cmd
+}
@@ -0,0 +1,85 @@
+package commands_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMirrorSyncCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testMirrorSyncCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testMirrorSyncCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		configPath     string
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		command = commands.MirrorSync(logger, config.Config{DefaultRegistryName: "some-registry"}, mockClient)
+
+		configPath = filepath.Join(t.TempDir(), "mirror.toml")
+		h.AssertNil(t, os.WriteFile(configPath, []byte(`
+builders = ["example/builder:1.0"]
+lifecycles = ["0.20.0"]
+`), 0600))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#MirrorSync", func() {
+		it("syncs the artifacts of the mirror config", func() {
+			mockClient.EXPECT().SyncMirror(gomock.Any(), client.SyncMirrorOptions{
+				Config: mirror.Config{
+					Builders:   []string{"example/builder:1.0"},
+					Lifecycles: []string{"0.20.0"},
+				},
+				Registry: "some-registry",
+			}).Return(nil)
+
+			command.SetArgs([]string{"--config", configPath})
+			h.AssertNil(t, command.Execute())
+		})
+
+		it("errors when the mirror config can't be read", func() {
+			command.SetArgs([]string{"--config", filepath.Join(filepath.Dir(configPath), "missing.toml")})
+			h.AssertError(t, command.Execute(), "reading mirror config")
+		})
+
+		it("returns the error of the sync", func() {
+			mockClient.EXPECT().SyncMirror(gomock.Any(), gomock.Any()).Return(errors.New("fetching image 'example/builder:1.0'"))
+
+			command.SetArgs([]string{"--config", configPath})
+			h.AssertError(t, command.Execute(), "fetching image 'example/builder:1.0'")
+		})
+	})
+}
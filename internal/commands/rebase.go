@@ -16,6 +16,7 @@ import (
 func Rebase(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Command {
 	var opts client.RebaseOptions
 	var policy string
+	var policyPath string
 
 	cmd := &cobra.Command{
 		Use:     "rebase <image-name>",
@@ -38,6 +39,11 @@ func Rebase(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Co
 				return errors.Wrapf(err, "parsing pull policy %s", stringPolicy)
 			}
 
+			opts.Policy, err = readPolicy(policyPath, cfg)
+			if err != nil {
+				return err
+			}
+
 			if err := pack.Rebase(cmd.Context(), opts); err != nil {
 				return err
 			}
@@ -49,6 +55,7 @@ func Rebase(logger logging.Logger, cfg config.Config, pack PackClient) *cobra.Co
 	cmd.Flags().BoolVar(&opts.Publish, "publish", false, "Publish the rebased application image directly to the container registry specified in <image-name>, instead of the daemon. The previous application image must also reside in the registry.")
 	cmd.Flags().StringVar(&opts.RunImage, "run-image", "", "Run image to use for rebasing")
 	cmd.Flags().StringVar(&policy, "pull-policy", "", "Pull policy to use. Accepted values are always, never, and if-not-present. The default is always")
+	cmd.Flags().StringVar(&policyPath, "policy", "", "Path to a policy file restricting the run image to rebase onto.\nDefaults to the policy set in the pack config, if any.")
 	cmd.Flags().StringVar(&opts.PreviousImage, "previous-image", "", "Image to rebase. Set to a particular tag reference, digest reference, or (when performing a daemon build) image ID. Use this flag in combination with <image-name> to avoid replacing the original image.")
 	cmd.Flags().StringVar(&opts.ReportDestinationDir, "report-output-dir", "", "Path to export build report.toml.\nOmitting the flag yield no report file.")
 	cmd.Flags().BoolVar(&opts.Force, "force", false, "Perform rebase operation without target validation (only available for API >= 0.12)")
@@ -2,6 +2,8 @@ package commands_test
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/heroku/color"
@@ -18,6 +20,7 @@ import (
 	"github.com/buildpacks/pack/internal/commands/testmocks"
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/policy"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -162,6 +165,20 @@ func testRebaseCommand(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("--policy", func() {
+				it("passes the policy read from the file", func() {
+					policyPath := filepath.Join(t.TempDir(), "policy.toml")
+					h.AssertNil(t, os.WriteFile(policyPath, []byte("[run-images]\nrequire-digest = true\n"), 0600))
+					opts.Policy = &policy.Policy{RunImages: policy.ImageRule{RequireDigest: true}}
+					mockClient.EXPECT().
+						Rebase(gomock.Any(), opts).
+						Return(nil)
+
+					command.SetArgs([]string{repoName, "--policy", policyPath})
+					h.AssertNil(t, command.Execute())
+				})
+			})
+
 			when("image name and previous image are provided", func() {
 				var expectedOpts client.RebaseOptions
 
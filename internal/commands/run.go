@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type RunFlags struct {
+	BuildFlags
+	NoBuild bool
+	Process string
+	RunEnv  []string
+	Ports   []string
+}
+
+// Run builds an app image from source code and starts a container from it
+func Run(logger logging.Logger, cfg config.Config, packClient PackClient) *cobra.Command {
+	var flags RunFlags
+
+	cmd := &cobra.Command{
+		Use:     "run <image-name> [args...]",
+		Args:    cobra.MinimumNArgs(1),
+		Short:   "Build an app image and run it",
+		Example: "pack run test_img --path apps/test-app --process web --port 8080:8080 --run-env PORT=8080",
+		Long: "Pack Run builds an app image the same way as `pack build`, then starts a container from it and streams " +
+			"the app's output until the app exits or the command is interrupted. The container is removed afterwards.\n\n" +
+			"Use `--process` to start one of the process types defined by the buildpacks instead of the default process, " +
+			"and pass any arguments for the process after the image name. Use `--no-build` to run an existing image.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.DryRun {
+				return errors.New("dry-run flag cannot be used with the run command")
+			}
+			if flags.Watch {
+				return errors.New("watch flag cannot be used with the run command")
+			}
+
+			inputImageName := client.ParseInputImageReference(args[0])
+			if inputImageName.Layout() {
+				return errors.New("images exported to OCI layout cannot be run")
+			}
+
+			runEnv, err := parseEnv(nil, flags.RunEnv)
+			if err != nil {
+				return err
+			}
+
+			pullPolicy := image.PullIfNotPresent
+			if !flags.NoBuild {
+				buildOpts, err := resolveBuildOptions(cmd, &flags.BuildFlags, cfg, logger, packClient, inputImageName)
+				if err != nil {
+					return err
+				}
+				if err := packClient.Build(cmd.Context(), buildOpts); err != nil {
+					return errors.Wrap(err, "failed to build")
+				}
+				logger.Infof("Successfully built image %s", style.Symbol(inputImageName.Name()))
+
+				if flags.Publish {
+					// the image only exists in the registry
+					pullPolicy = image.PullAlways
+				}
+			}
+
+			logger.Infof("Running %s", style.Symbol(inputImageName.Name()))
+			if err := packClient.Run(cmd.Context(), client.RunOptions{
+				Image:      inputImageName.Name(),
+				Process:    flags.Process,
+				Args:       args[1:],
+				Env:        runEnv,
+				Ports:      flags.Ports,
+				PullPolicy: pullPolicy,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to run %s", style.Symbol(inputImageName.Name()))
+			}
+			return nil
+		}),
+	}
+	buildCommandFlags(cmd, &flags.BuildFlags, cfg)
+	cmd.Flags().BoolVar(&flags.NoBuild, "no-build", false, "Run the existing image without building it first")
+	cmd.Flags().StringVar(&flags.Process, "process", "", "Process type to start, as defined by the buildpacks (defaults to the default process of the image)")
+	cmd.Flags().StringArrayVar(&flags.RunEnv, "run-env", []string{}, "Runtime environment variable, in the form 'VAR=VALUE' or 'VAR'.\nWhen using latter value-less form, value will be taken from current\n  environment at the time this command is executed."+stringArrayHelp("run-env"))
+	cmd.Flags().StringArrayVar(&flags.Ports, "port", []string{}, "Publish a container port on the host, in the form '[[host-ip:]host-port:]container-port[/protocol]'."+stringArrayHelp("port"))
+	cmd.Flags().MarkHidden("dry-run")
+	cmd.Flags().MarkHidden("watch")
+	AddHelpFlag(cmd, "run")
+	return cmd
+}
@@ -0,0 +1,162 @@
+package commands_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRunCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "Commands", testRunCommand, spec.Random(), spec.Report(report.Terminal{}))
+}
+
+func testRunCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		cfg            config.Config
+	)
+
+	it.Before(func() {
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		cfg = config.Config{
+			TrustedBuilders: []config.TrustedBuilder{{Name: "my-builder"}},
+		}
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+
+		command = commands.Run(logger, cfg, mockClient)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#Run", func() {
+		it("builds the image and runs it", func() {
+			gomock.InOrder(
+				mockClient.EXPECT().
+					Build(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.BuildOptions) error {
+						h.AssertEq(t, opts.Image, "my-app")
+						h.AssertEq(t, opts.Builder, "my-builder")
+						h.AssertEq(t, opts.Env, map[string]string{"BP_DEBUG": "true"})
+						return nil
+					}),
+				mockClient.EXPECT().
+					Run(gomock.Any(), client.RunOptions{
+						Image:      "my-app",
+						Process:    "worker",
+						Args:       []string{"--queue", "emails"},
+						Env:        map[string]string{"PORT": "8080"},
+						Ports:      []string{"8080:8080"},
+						PullPolicy: image.PullIfNotPresent,
+					}).
+					Return(nil),
+			)
+
+			command.SetArgs([]string{
+				"my-app", "--builder", "my-builder", "--env", "BP_DEBUG=true",
+				"--process", "worker", "--run-env", "PORT=8080", "--port", "8080:8080",
+				"--", "--queue", "emails",
+			})
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "Successfully built image 'my-app'")
+			h.AssertContains(t, outBuf.String(), "Running 'my-app'")
+		})
+
+		when("--no-build", func() {
+			it("runs the existing image", func() {
+				mockClient.EXPECT().
+					Run(gomock.Any(), client.RunOptions{
+						Image:      "my-app",
+						Args:       []string{},
+						Env:        map[string]string{},
+						Ports:      []string{},
+						PullPolicy: image.PullIfNotPresent,
+					}).
+					Return(nil)
+
+				command.SetArgs([]string{"my-app", "--no-build"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("--publish", func() {
+			it("pulls the published image", func() {
+				mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(nil)
+				mockClient.EXPECT().
+					Run(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, opts client.RunOptions) error {
+						h.AssertEq(t, opts.PullPolicy, image.PullAlways)
+						return nil
+					})
+
+				command.SetArgs([]string{"my-app", "--builder", "my-builder", "--publish"})
+				h.AssertNil(t, command.Execute())
+			})
+		})
+
+		when("the build fails", func() {
+			it("doesn't run the image", func() {
+				mockClient.EXPECT().Build(gomock.Any(), gomock.Any()).Return(errors.New("some-build-error"))
+
+				command.SetArgs([]string{"my-app", "--builder", "my-builder"})
+				err := command.Execute()
+				h.AssertError(t, err, "failed to build: some-build-error")
+			})
+		})
+
+		when("the app fails", func() {
+			it("errors", func() {
+				mockClient.EXPECT().Run(gomock.Any(), gomock.Any()).Return(errors.New("failed with status code: 1"))
+
+				command.SetArgs([]string{"my-app", "--no-build"})
+				err := command.Execute()
+				h.AssertError(t, err, "failed to run 'my-app': failed with status code: 1")
+			})
+		})
+
+		when("--dry-run is provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"my-app", "--builder", "my-builder", "--dry-run"})
+				h.AssertError(t, command.Execute(), "dry-run flag cannot be used with the run command")
+			})
+		})
+
+		when("--watch is provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"my-app", "--builder", "my-builder", "--watch"})
+				h.AssertError(t, command.Execute(), "watch flag cannot be used with the run command")
+			})
+		})
+
+		when("no image name is provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{})
+				h.AssertError(t, command.Execute(), "requires at least 1 arg(s), only received 0")
+			})
+		})
+	})
+}
@@ -15,6 +15,8 @@ func NewSBOMCommand(logger logging.Logger, cfg config.Config, client PackClient)
 	}
 
 	cmd.AddCommand(DownloadSBOM(logger, client))
+	cmd.AddCommand(ShowSBOM(logger, client))
+	cmd.AddCommand(ScanSBOM(logger, client))
 	AddHelpFlag(cmd, "sbom")
 	return cmd
 }
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type ScanSBOMFlags struct {
+	Remote       bool
+	DatabaseDir  string
+	FailOn       string
+	OutputFormat string
+}
+
+// ScanSBOM reports the known vulnerabilities of the packages in the SBOM of an image
+func ScanSBOM(logger logging.Logger, pack PackClient) *cobra.Command {
+	var flags ScanSBOMFlags
+
+	cmd := &cobra.Command{
+		Use:     "scan <image-name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "Scan the SBoM of an image for known vulnerabilities",
+		Example: "pack sbom scan buildpacksio/pack --db ./osv --fail-on high",
+		Long: "Match the purls of the packages in the SBoM of an image against an offline vulnerability database, " +
+			"and report the vulnerabilities found for each buildpack layer.\n\n" +
+			"The database is a directory of OSV records, such as an extracted export of https://osv.dev. " +
+			"Use `--fail-on` to exit with an error when vulnerabilities of the given severity or higher are found.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.DatabaseDir == "" {
+				return errors.New("a vulnerability database must be provided using --db")
+			}
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatJSON {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSON)
+			}
+			failOn := sbom.SeverityUnknown
+			if flags.FailOn != "" {
+				var err error
+				if failOn, err = sbom.ParseSeverity(flags.FailOn); err != nil {
+					return errors.Errorf("invalid fail-on severity %s; must be one of: low, medium, high, critical", style.Symbol(flags.FailOn))
+				}
+			}
+
+			vulnerabilities, err := pack.ScanSBOM(cmd.Context(), args[0], client.ScanSBOMOptions{
+				ReadSBOMOptions: client.ReadSBOMOptions{Daemon: !flags.Remote},
+				DatabaseDir:     flags.DatabaseDir,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == outputFormatJSON {
+				if vulnerabilities == nil {
+					vulnerabilities = []client.SBOMVulnerability{}
+				}
+				encoder := json.NewEncoder(logger.Writer())
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(vulnerabilities); err != nil {
+					return err
+				}
+			} else if len(vulnerabilities) == 0 {
+				logger.Infof("No known vulnerabilities found in the SBoM of %s", style.Symbol(args[0]))
+			} else {
+				if err := writeSBOMVulnerabilities(logger.Writer(), vulnerabilities); err != nil {
+					return err
+				}
+				logger.Infof("Found %s", vulnerabilitiesSummary(vulnerabilities))
+			}
+
+			if failOn == sbom.SeverityUnknown {
+				return nil
+			}
+			failing := 0
+			for _, vulnerability := range vulnerabilities {
+				if severity, _ := sbom.ParseSeverity(vulnerability.Severity); severity >= failOn {
+					failing++
+				}
+			}
+			if failing > 0 {
+				return errors.Errorf("found %d %s with %s severity or higher", failing, pluralize(failing, "vulnerability", "vulnerabilities"), failOn)
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&flags.DatabaseDir, "db", "", "Directory of OSV vulnerability records to match the packages against")
+	cmd.Flags().StringVar(&flags.FailOn, "fail-on", "", "Fail when vulnerabilities of this severity or higher are found (low, medium, high, critical)")
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Scan the SBoM of the image in a remote registry (without pulling the image)")
+	cmd.Flags().StringVar(&flags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format of the vulnerabilities (human-readable, json)")
+	AddHelpFlag(cmd, "scan")
+	return cmd
+}
+
+// writeSBOMVulnerabilities writes a table of vulnerabilities for each buildpack layer
+func writeSBOMVulnerabilities(w io.Writer, vulnerabilities []client.SBOMVulnerability) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	group := ""
+	for _, vulnerability := range vulnerabilities {
+		pkg := vulnerability.Package
+		source := pkg.Buildpack
+		if pkg.Layer != "" {
+			source += " layer " + pkg.Layer
+		}
+		source = fmt.Sprintf("%s (%s)", source, pkg.Scope)
+
+		if source != group {
+			if group != "" {
+				fmt.Fprintln(tw)
+			}
+			group = source
+			fmt.Fprintf(tw, "%s:\n", source)
+			fmt.Fprintln(tw, "  ID\tSEVERITY\tPACKAGE\tVERSION\tFIXED IN\tSUMMARY")
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+			vulnerability.ID,
+			vulnerability.Severity,
+			pkg.Name,
+			orDash(pkg.Version),
+			orDash(vulnerability.FixedVersion),
+			orDash(vulnerability.Summary),
+		)
+	}
+	return tw.Flush()
+}
+
+// vulnerabilitiesSummary counts vulnerabilities by severity, e.g. '3 vulnerabilities (1 critical, 2 high)'
+func vulnerabilitiesSummary(vulnerabilities []client.SBOMVulnerability) string {
+	counts := map[sbom.Severity]int{}
+	for _, vulnerability := range vulnerabilities {
+		severity, _ := sbom.ParseSeverity(vulnerability.Severity)
+		counts[severity]++
+	}
+
+	var parts []string
+	for severity := sbom.SeverityCritical; severity >= sbom.SeverityUnknown; severity-- {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	return fmt.Sprintf("%d %s (%s)", len(vulnerabilities), pluralize(len(vulnerabilities), "vulnerability", "vulnerabilities"), strings.Join(parts, ", "))
+}
+
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,129 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestScanSBOMCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ScanSBOMCommand", testScanSBOMCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testScanSBOMCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command         *cobra.Command
+		logger          logging.Logger
+		outBuf          bytes.Buffer
+		mockController  *gomock.Controller
+		mockClient      *testmocks.MockPackClient
+		vulnerabilities []cpkg.SBOMVulnerability
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.ScanSBOM(logger, mockClient)
+
+		vulnerabilities = []cpkg.SBOMVulnerability{
+			{
+				ID:           "GHSA-npm",
+				Summary:      "npm is vulnerable",
+				Severity:     "low",
+				FixedVersion: "9.8.0",
+				Package:      cpkg.SBOMPackage{Name: "npm", Version: "9.6.4", Buildpack: "paketo-buildpacks/node-engine", Layer: "node", Scope: "launch"},
+			},
+			{
+				ID:       "GHSA-express",
+				Severity: "high",
+				Package:  cpkg.SBOMPackage{Name: "express", Version: "4.18.2", Buildpack: "paketo-buildpacks/npm-install", Scope: "launch"},
+			},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	expectScan := func(daemon bool, result []cpkg.SBOMVulnerability) {
+		mockClient.EXPECT().ScanSBOM(gomock.Any(), "some/image", cpkg.ScanSBOMOptions{
+			ReadSBOMOptions: cpkg.ReadSBOMOptions{Daemon: daemon},
+			DatabaseDir:     "some/db",
+		}).Return(result, nil)
+	}
+
+	when("#ScanSBOM", func() {
+		it("prints the vulnerabilities of each buildpack layer", func() {
+			expectScan(true, vulnerabilities)
+			command.SetArgs([]string{"some/image", "--db", "some/db"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "paketo-buildpacks/node-engine layer node (launch):")
+			h.AssertContains(t, outBuf.String(), "  GHSA-npm   low        npm       9.6.4     9.8.0      npm is vulnerable")
+			h.AssertContains(t, outBuf.String(), "paketo-buildpacks/npm-install (launch):")
+			h.AssertContains(t, outBuf.String(), "GHSA-express   high       express   4.18.2    -          -")
+			h.AssertContains(t, outBuf.String(), "Found 2 vulnerabilities (1 high, 1 low)")
+		})
+
+		it("prints the vulnerabilities as json", func() {
+			expectScan(false, vulnerabilities)
+			command.SetArgs([]string{"some/image", "--db", "some/db", "--remote", "--output-format", "json"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `"id": "GHSA-npm"`)
+			h.AssertContains(t, outBuf.String(), `"fixedVersion": "9.8.0"`)
+		})
+
+		it("reports images without vulnerabilities", func() {
+			expectScan(true, nil)
+			command.SetArgs([]string{"some/image", "--db", "some/db", "--fail-on", "low"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "No known vulnerabilities found in the SBoM of 'some/image'")
+		})
+
+		when("--fail-on", func() {
+			it("errors when vulnerabilities of the severity or higher are found", func() {
+				expectScan(true, vulnerabilities)
+				command.SetArgs([]string{"some/image", "--db", "some/db", "--fail-on", "moderate"})
+
+				h.AssertError(t, command.Execute(), "found 1 vulnerability with medium severity or higher")
+			})
+
+			it("succeeds when all vulnerabilities are less severe", func() {
+				expectScan(true, vulnerabilities)
+				command.SetArgs([]string{"some/image", "--db", "some/db", "--fail-on", "critical"})
+
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors on unknown severities", func() {
+				command.SetArgs([]string{"some/image", "--db", "some/db", "--fail-on", "severe"})
+
+				h.AssertError(t, command.Execute(), "invalid fail-on severity 'severe'; must be one of: low, medium, high, critical")
+			})
+		})
+
+		when("no database is provided", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"some/image"})
+
+				h.AssertError(t, command.Execute(), "a vulnerability database must be provided using --db")
+			})
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+type ShowSBOMFlags struct {
+	Remote       bool
+	Merge        string
+	OutputFormat string
+}
+
+// ShowSBOM lists the packages in the SBOM of an image, or merges its SBOM documents
+func ShowSBOM(logger logging.Logger, pack PackClient) *cobra.Command {
+	var flags ShowSBOMFlags
+
+	cmd := &cobra.Command{
+		Use:     "show <image-name>",
+		Args:    cobra.ExactArgs(1),
+		Short:   "List the packages in the SBoM of an image",
+		Example: "pack sbom show buildpacksio/pack --merge cyclonedx > sbom.cdx.json",
+		Long: "List the packages in the CycloneDX, SPDX and Syft SBoM documents written by each buildpack to an image, " +
+			"along with the buildpack and layer that contributed them.\n\n" +
+			"Use `--merge` to print a single CycloneDX or SPDX document describing all the packages of the image instead.",
+		RunE: logError(logger, func(cmd *cobra.Command, args []string) error {
+			if flags.OutputFormat != outputFormatHumanReadable && flags.OutputFormat != outputFormatJSON {
+				return errors.Errorf("unsupported output format %s; must be one of: %s, %s", style.Symbol(flags.OutputFormat), outputFormatHumanReadable, outputFormatJSON)
+			}
+			if flags.Merge != "" && cmd.Flags().Changed("output-format") {
+				return errors.New("output-format flag cannot be used with the 'merge' flag")
+			}
+
+			readOpts := client.ReadSBOMOptions{Daemon: !flags.Remote}
+			if flags.Merge != "" {
+				return pack.MergeSBOM(cmd.Context(), args[0], client.MergeSBOMOptions{
+					ReadSBOMOptions: readOpts,
+					Format:          flags.Merge,
+					Output:          logger.Writer(),
+				})
+			}
+
+			packages, err := pack.ReadSBOM(cmd.Context(), args[0], readOpts)
+			if err != nil {
+				return err
+			}
+
+			if flags.OutputFormat == outputFormatJSON {
+				if packages == nil {
+					packages = []client.SBOMPackage{}
+				}
+				encoder := json.NewEncoder(logger.Writer())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(packages)
+			}
+
+			if len(packages) == 0 {
+				logger.Infof("No packages found in the SBoM of %s", style.Symbol(args[0]))
+				return nil
+			}
+			return writeSBOMPackages(logger.Writer(), packages)
+		}),
+	}
+
+	cmd.Flags().BoolVar(&flags.Remote, "remote", false, "Read the SBoM of the image in a remote registry (without pulling the image)")
+	cmd.Flags().StringVar(&flags.Merge, "merge", "", "Print one merged SBoM document in the given format (cyclonedx, spdx)")
+	cmd.Flags().StringVar(&flags.OutputFormat, "output-format", outputFormatHumanReadable, "Output format of the package list (human-readable, json)")
+	AddHelpFlag(cmd, "show")
+	return cmd
+}
+
+func writeSBOMPackages(w io.Writer, packages []client.SBOMPackage) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVERSION\tBUILDPACK\tLAYER\tSCOPE\tPURL")
+	for _, pkg := range packages {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			pkg.Name,
+			orDash(pkg.Version),
+			pkg.Buildpack,
+			orDash(pkg.Layer),
+			pkg.Scope,
+			orDash(pkg.PURL),
+		)
+	}
+	return tw.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/spf13/cobra"
+
+	"github.com/buildpacks/pack/internal/commands"
+	"github.com/buildpacks/pack/internal/commands/testmocks"
+	cpkg "github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestShowSBOMCommand(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "ShowSBOMCommand", testShowSBOMCommand, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testShowSBOMCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		command        *cobra.Command
+		logger         logging.Logger
+		outBuf         bytes.Buffer
+		mockController *gomock.Controller
+		mockClient     *testmocks.MockPackClient
+		packages       []cpkg.SBOMPackage
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockClient = testmocks.NewMockPackClient(mockController)
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		command = commands.ShowSBOM(logger, mockClient)
+
+		packages = []cpkg.SBOMPackage{
+			{Name: "express", Version: "4.18.2", PURL: "pkg:npm/express@4.18.2", Buildpack: "paketo-buildpacks/npm-install", Scope: "launch"},
+			{Name: "node", Version: "20.1.0", Buildpack: "paketo-buildpacks/node-engine", Layer: "node", Scope: "launch"},
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#ShowSBOM", func() {
+		it("prints the packages as a table", func() {
+			mockClient.EXPECT().ReadSBOM(gomock.Any(), "some/image", cpkg.ReadSBOMOptions{Daemon: true}).Return(packages, nil)
+			command.SetArgs([]string{"some/image"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "NAME      VERSION   BUILDPACK                       LAYER   SCOPE    PURL")
+			h.AssertContains(t, outBuf.String(), "express   4.18.2    paketo-buildpacks/npm-install   -       launch   pkg:npm/express@4.18.2")
+			h.AssertContains(t, outBuf.String(), "node      20.1.0    paketo-buildpacks/node-engine   node    launch   -")
+		})
+
+		it("prints the packages as json", func() {
+			mockClient.EXPECT().ReadSBOM(gomock.Any(), "some/image", cpkg.ReadSBOMOptions{Daemon: true}).Return(packages, nil)
+			command.SetArgs([]string{"some/image", "--output-format", "json"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), `"purl": "pkg:npm/express@4.18.2"`)
+			h.AssertContains(t, outBuf.String(), `"layer": "node"`)
+		})
+
+		it("reads the SBOM of remote images", func() {
+			mockClient.EXPECT().ReadSBOM(gomock.Any(), "some/image", cpkg.ReadSBOMOptions{Daemon: false}).Return(nil, nil)
+			command.SetArgs([]string{"some/image", "--remote"})
+
+			h.AssertNil(t, command.Execute())
+			h.AssertContains(t, outBuf.String(), "No packages found in the SBoM of 'some/image'")
+		})
+
+		when("--merge", func() {
+			it("merges the SBOM documents", func() {
+				mockClient.EXPECT().MergeSBOM(gomock.Any(), "some/image", gomock.Any()).
+					DoAndReturn(func(_ interface{}, _ string, opts cpkg.MergeSBOMOptions) error {
+						h.AssertEq(t, opts.ReadSBOMOptions, cpkg.ReadSBOMOptions{Daemon: true})
+						h.AssertEq(t, opts.Format, "cyclonedx")
+						h.AssertNotNil(t, opts.Output)
+						return nil
+					})
+				command.SetArgs([]string{"some/image", "--merge", "cyclonedx"})
+
+				h.AssertNil(t, command.Execute())
+			})
+
+			it("errors when used with --output-format", func() {
+				command.SetArgs([]string{"some/image", "--merge", "spdx", "--output-format", "json"})
+
+				h.AssertError(t, command.Execute(), "output-format flag cannot be used with the 'merge' flag")
+			})
+		})
+
+		when("the output format is unsupported", func() {
+			it("errors", func() {
+				command.SetArgs([]string{"some/image", "--output-format", "yaml"})
+
+				h.AssertError(t, command.Execute(), "unsupported output format 'yaml'")
+			})
+		})
+	})
+}
@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 
 	client "github.com/buildpacks/pack/pkg/client"
+	lock "github.com/buildpacks/pack/pkg/lock"
 )
 
 // MockPackClient is a mock of PackClient interface.
@@ -120,6 +121,51 @@ func (mr *MockPackClientMockRecorder) DeleteManifest(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteManifest", reflect.TypeOf((*MockPackClient)(nil).DeleteManifest), arg0)
 }
 
+// Detect mocks base method.
+func (m *MockPackClient) Detect(arg0 context.Context, arg1 client.BuildOptions) (*client.DetectResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detect", arg0, arg1)
+	ret0, _ := ret[0].(*client.DetectResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detect indicates an expected call of Detect.
+func (mr *MockPackClientMockRecorder) Detect(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detect", reflect.TypeOf((*MockPackClient)(nil).Detect), arg0, arg1)
+}
+
+// DiffBuilders mocks base method.
+func (m *MockPackClient) DiffBuilders(arg0 context.Context, arg1, arg2 string, arg3 client.DiffBuildersOptions) (*client.BuilderDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffBuilders", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*client.BuilderDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffBuilders indicates an expected call of DiffBuilders.
+func (mr *MockPackClientMockRecorder) DiffBuilders(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffBuilders", reflect.TypeOf((*MockPackClient)(nil).DiffBuilders), arg0, arg1, arg2, arg3)
+}
+
+// DiffImages mocks base method.
+func (m *MockPackClient) DiffImages(arg0 context.Context, arg1, arg2 string, arg3 client.DiffImagesOptions) (*client.ImageDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffImages", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*client.ImageDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffImages indicates an expected call of DiffImages.
+func (mr *MockPackClientMockRecorder) DiffImages(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffImages", reflect.TypeOf((*MockPackClient)(nil).DiffImages), arg0, arg1, arg2, arg3)
+}
+
 // DownloadSBOM mocks base method.
 func (m *MockPackClient) DownloadSBOM(arg0 string, arg1 client.DownloadSBOMOptions) error {
 	m.ctrl.T.Helper()
@@ -134,6 +180,34 @@ func (mr *MockPackClientMockRecorder) DownloadSBOM(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadSBOM", reflect.TypeOf((*MockPackClient)(nil).DownloadSBOM), arg0, arg1)
 }
 
+// ExportCache mocks base method.
+func (m *MockPackClient) ExportCache(arg0 context.Context, arg1 client.ExportCacheOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCache", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCache indicates an expected call of ExportCache.
+func (mr *MockPackClientMockRecorder) ExportCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCache", reflect.TypeOf((*MockPackClient)(nil).ExportCache), arg0, arg1)
+}
+
+// ImportCache mocks base method.
+func (m *MockPackClient) ImportCache(arg0 context.Context, arg1 client.ImportCacheOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCache", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportCache indicates an expected call of ImportCache.
+func (mr *MockPackClientMockRecorder) ImportCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCache", reflect.TypeOf((*MockPackClient)(nil).ImportCache), arg0, arg1)
+}
+
 // InspectBuilder mocks base method.
 func (m *MockPackClient) InspectBuilder(arg0 string, arg1 bool, arg2 ...client.BuilderInspectionModifier) (*client.BuilderInfo, error) {
 	m.ctrl.T.Helper()
@@ -213,6 +287,65 @@ func (mr *MockPackClientMockRecorder) InspectManifest(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectManifest", reflect.TypeOf((*MockPackClient)(nil).InspectManifest), arg0)
 }
 
+// ListBuildpackVersions mocks base method.
+func (m *MockPackClient) ListBuildpackVersions(arg0 client.ListBuildpackVersionsOptions) ([]client.RegistryBuildpack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBuildpackVersions", arg0)
+	ret0, _ := ret[0].([]client.RegistryBuildpack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBuildpackVersions indicates an expected call of ListBuildpackVersions.
+func (mr *MockPackClientMockRecorder) ListBuildpackVersions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBuildpackVersions", reflect.TypeOf((*MockPackClient)(nil).ListBuildpackVersions), arg0)
+}
+
+// ListCaches mocks base method.
+func (m *MockPackClient) ListCaches(arg0 context.Context, arg1 client.ListCachesOptions) ([]client.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCaches", arg0, arg1)
+	ret0, _ := ret[0].([]client.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCaches indicates an expected call of ListCaches.
+func (mr *MockPackClientMockRecorder) ListCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCaches", reflect.TypeOf((*MockPackClient)(nil).ListCaches), arg0, arg1)
+}
+
+// LockBuildpacks mocks base method.
+func (m *MockPackClient) LockBuildpacks(arg0 context.Context, arg1 client.LockBuildpacksOptions) (lock.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBuildpacks", arg0, arg1)
+	ret0, _ := ret[0].(lock.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockBuildpacks indicates an expected call of LockBuildpacks.
+func (mr *MockPackClientMockRecorder) LockBuildpacks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBuildpacks", reflect.TypeOf((*MockPackClient)(nil).LockBuildpacks), arg0, arg1)
+}
+
+// MergeSBOM mocks base method.
+func (m *MockPackClient) MergeSBOM(arg0 context.Context, arg1 string, arg2 client.MergeSBOMOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeSBOM", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeSBOM indicates an expected call of MergeSBOM.
+func (mr *MockPackClientMockRecorder) MergeSBOM(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeSBOM", reflect.TypeOf((*MockPackClient)(nil).MergeSBOM), arg0, arg1, arg2)
+}
+
 // NewBuildpack mocks base method.
 func (m *MockPackClient) NewBuildpack(arg0 context.Context, arg1 client.NewBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -227,6 +360,20 @@ func (mr *MockPackClientMockRecorder) NewBuildpack(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBuildpack", reflect.TypeOf((*MockPackClient)(nil).NewBuildpack), arg0, arg1)
 }
 
+// NewExtension mocks base method.
+func (m *MockPackClient) NewExtension(arg0 context.Context, arg1 client.NewExtensionOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewExtension", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NewExtension indicates an expected call of NewExtension.
+func (mr *MockPackClientMockRecorder) NewExtension(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewExtension", reflect.TypeOf((*MockPackClient)(nil).NewExtension), arg0, arg1)
+}
+
 // PackageBuildpack mocks base method.
 func (m *MockPackClient) PackageBuildpack(arg0 context.Context, arg1 client.PackageBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -255,6 +402,21 @@ func (mr *MockPackClientMockRecorder) PackageExtension(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackageExtension", reflect.TypeOf((*MockPackClient)(nil).PackageExtension), arg0, arg1)
 }
 
+// PruneCaches mocks base method.
+func (m *MockPackClient) PruneCaches(arg0 context.Context, arg1 client.PruneCachesOptions) (client.PruneCachesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneCaches", arg0, arg1)
+	ret0, _ := ret[0].(client.PruneCachesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneCaches indicates an expected call of PruneCaches.
+func (mr *MockPackClientMockRecorder) PruneCaches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneCaches", reflect.TypeOf((*MockPackClient)(nil).PruneCaches), arg0, arg1)
+}
+
 // PullBuildpack mocks base method.
 func (m *MockPackClient) PullBuildpack(arg0 context.Context, arg1 client.PullBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -283,6 +445,21 @@ func (mr *MockPackClientMockRecorder) PushManifest(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushManifest", reflect.TypeOf((*MockPackClient)(nil).PushManifest), arg0)
 }
 
+// ReadSBOM mocks base method.
+func (m *MockPackClient) ReadSBOM(arg0 context.Context, arg1 string, arg2 client.ReadSBOMOptions) ([]client.SBOMPackage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadSBOM", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]client.SBOMPackage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadSBOM indicates an expected call of ReadSBOM.
+func (mr *MockPackClientMockRecorder) ReadSBOM(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadSBOM", reflect.TypeOf((*MockPackClient)(nil).ReadSBOM), arg0, arg1, arg2)
+}
+
 // Rebase mocks base method.
 func (m *MockPackClient) Rebase(arg0 context.Context, arg1 client.RebaseOptions) error {
 	m.ctrl.T.Helper()
@@ -325,6 +502,93 @@ func (mr *MockPackClientMockRecorder) RemoveManifest(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveManifest", reflect.TypeOf((*MockPackClient)(nil).RemoveManifest), arg0, arg1)
 }
 
+// Run mocks base method.
+func (m *MockPackClient) Run(arg0 context.Context, arg1 client.RunOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockPackClientMockRecorder) Run(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockPackClient)(nil).Run), arg0, arg1)
+}
+
+// ScanSBOM mocks base method.
+func (m *MockPackClient) ScanSBOM(arg0 context.Context, arg1 string, arg2 client.ScanSBOMOptions) ([]client.SBOMVulnerability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanSBOM", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]client.SBOMVulnerability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanSBOM indicates an expected call of ScanSBOM.
+func (mr *MockPackClientMockRecorder) ScanSBOM(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanSBOM", reflect.TypeOf((*MockPackClient)(nil).ScanSBOM), arg0, arg1, arg2)
+}
+
+// SearchBuildpacks mocks base method.
+func (m *MockPackClient) SearchBuildpacks(arg0 client.SearchBuildpacksOptions) ([]client.RegistryBuildpack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBuildpacks", arg0)
+	ret0, _ := ret[0].([]client.RegistryBuildpack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBuildpacks indicates an expected call of SearchBuildpacks.
+func (mr *MockPackClientMockRecorder) SearchBuildpacks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBuildpacks", reflect.TypeOf((*MockPackClient)(nil).SearchBuildpacks), arg0)
+}
+
+// SyncMirror mocks base method.
+func (m *MockPackClient) SyncMirror(arg0 context.Context, arg1 client.SyncMirrorOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncMirror", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncMirror indicates an expected call of SyncMirror.
+func (mr *MockPackClientMockRecorder) SyncMirror(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncMirror", reflect.TypeOf((*MockPackClient)(nil).SyncMirror), arg0, arg1)
+}
+
+// TestBuildpack mocks base method.
+func (m *MockPackClient) TestBuildpack(arg0 context.Context, arg1 client.TestBuildpackOptions) (*client.BuildpackTestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestBuildpack", arg0, arg1)
+	ret0, _ := ret[0].(*client.BuildpackTestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestBuildpack indicates an expected call of TestBuildpack.
+func (mr *MockPackClientMockRecorder) TestBuildpack(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestBuildpack", reflect.TypeOf((*MockPackClient)(nil).TestBuildpack), arg0, arg1)
+}
+
+// WatchBuild mocks base method.
+func (m *MockPackClient) WatchBuild(arg0 context.Context, arg1 client.WatchBuildOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchBuild", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchBuild indicates an expected call of WatchBuild.
+func (mr *MockPackClientMockRecorder) WatchBuild(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchBuild", reflect.TypeOf((*MockPackClient)(nil).WatchBuild), arg0, arg1)
+}
+
 // YankBuildpack mocks base method.
 func (m *MockPackClient) YankBuildpack(arg0 client.YankBuildpackOptions) error {
 	m.ctrl.T.Helper()
@@ -35,7 +35,7 @@ func YankBuildpack(logger logging.Logger, cfg config.Config, pack PackClient) *c
 			opts := client.YankBuildpackOptions{
 				ID:      id,
 				Version: version,
-				Type:    "github",
+				Type:    registry.Type,
 				URL:     registry.URL,
 				Yank:    !flags.Undo,
 			}
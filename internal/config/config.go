@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
@@ -23,10 +24,22 @@ type Config struct {
 	LifecycleImage      string            `toml:"lifecycle-image,omitempty"`
 	RegistryMirrors     map[string]string `toml:"registry-mirrors,omitempty"`
 	LayoutRepositoryDir string            `toml:"layout-repo-dir,omitempty"`
+	// Policy is the path to the policy file builds, builder creations and rebases are evaluated against
+	Policy string `toml:"policy,omitempty"`
 }
 
 type VolumeConfig struct {
 	VolumeKeys map[string]string `toml:"volume-keys,omitempty"`
+	// Caches records the volume and bind caches pack has used, keyed by volume name or directory
+	Caches map[string]CacheUsage `toml:"caches,omitempty"`
+}
+
+type CacheUsage struct {
+	Image string `toml:"image"`
+	// Type is one of build, launch or kaniko
+	Type     string    `toml:"type"`
+	Format   string    `toml:"format"`
+	LastUsed time.Time `toml:"last-used"`
 }
 
 type Registry struct {
@@ -42,6 +55,8 @@ type RunImage struct {
 
 type TrustedBuilder struct {
 	Name string `toml:"name"`
+	// Key is the PEM encoded public key the signature of the builder is verified with before trusting it
+	Key string `toml:"key,omitempty"`
 }
 
 const OfficialRegistryName = "official"
@@ -100,6 +115,71 @@ func ReadVolumeKeys(path string) (VolumeConfig, error) {
 	return cfg, nil
 }
 
+// UpdateVolumeKeys applies update to the volume keys file at path while holding a lock on it, so that concurrent
+// pack processes don't overwrite each other's keys and cache records. The file is replaced atomically.
+func UpdateVolumeKeys(path string, update func(cfg *VolumeConfig) error) error {
+	if err := MkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg, err := ReadVolumeKeys(path)
+	if err != nil {
+		return err
+	}
+	if err := update(&cfg); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := toml.NewEncoder(tmp).Encode(cfg); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+const (
+	lockRetryInterval = 25 * time.Millisecond
+	lockTimeout       = 10 * time.Second
+	// a lock older than this was left behind by a process that died while holding it
+	lockStaleAfter = time.Minute
+)
+
+// lockFile takes an exclusive lock by creating the lock file, and returns a function releasing it
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Wrapf(err, "locking %s", path)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("timed out waiting for lock %s", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
 func Write(cfg interface{}, path string) error {
 	if err := MkdirAll(filepath.Dir(path)); err != nil {
 		return err
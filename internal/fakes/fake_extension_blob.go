@@ -35,6 +35,7 @@ func (b *fakeExtensionBlob) Open() (reader io.ReadCloser, err error) {
 	tarBuilder.AddDir("bin", b.chmod, time.Now())
 	tarBuilder.AddFile("bin/build", b.chmod, time.Now(), []byte("build-contents"))
 	tarBuilder.AddFile("bin/detect", b.chmod, time.Now(), []byte("detect-contents"))
+	tarBuilder.AddFile("bin/generate", b.chmod, time.Now(), []byte("generate-contents"))
 
 	return tarBuilder.Reader(archive.DefaultTarWriterFactory()), err
 }
@@ -8,9 +8,15 @@ import (
 
 type FakeLifecycle struct {
 	Opts build.LifecycleOptions
+
+	// ExecuteFunc, when set, is called with the options to simulate running the lifecycle
+	ExecuteFunc func(opts build.LifecycleOptions) error
 }
 
 func (f *FakeLifecycle) Execute(ctx context.Context, opts build.LifecycleOptions) error {
 	f.Opts = opts
+	if f.ExecuteFunc != nil {
+		return f.ExecuteFunc(opts)
+	}
 	return nil
 }
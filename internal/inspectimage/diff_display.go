@@ -0,0 +1,149 @@
+package inspectimage
+
+import (
+	"github.com/buildpacks/lifecycle/platform/files"
+
+	"github.com/buildpacks/pack/pkg/client"
+)
+
+type RunImageDisplay struct {
+	Image     string `json:"image" yaml:"image" toml:"image"`
+	Reference string `json:"reference" yaml:"reference" toml:"reference"`
+	TopLayer  string `json:"top_layer" yaml:"top_layer" toml:"top_layer"`
+}
+
+type RunImageDiffDisplay struct {
+	Before RunImageDisplay `json:"before" yaml:"before" toml:"before"`
+	After  RunImageDisplay `json:"after" yaml:"after" toml:"after"`
+}
+
+type LayerDiffDisplay struct {
+	Name         string `json:"name" yaml:"name" toml:"name"`
+	Change       string `json:"change" yaml:"change" toml:"change"`
+	DiffIDBefore string `json:"diff_id_before,omitempty" yaml:"diff_id_before,omitempty" toml:"diff_id_before,omitempty"`
+	DiffIDAfter  string `json:"diff_id_after,omitempty" yaml:"diff_id_after,omitempty" toml:"diff_id_after,omitempty"`
+	SizeBefore   *int64 `json:"size_before,omitempty" yaml:"size_before,omitempty" toml:"size_before,omitempty"`
+	SizeAfter    *int64 `json:"size_after,omitempty" yaml:"size_after,omitempty" toml:"size_after,omitempty"`
+	SizeDelta    *int64 `json:"size_delta,omitempty" yaml:"size_delta,omitempty" toml:"size_delta,omitempty"`
+}
+
+type BuildpackDiffDisplay struct {
+	ID            string             `json:"id" yaml:"id" toml:"id"`
+	Change        string             `json:"change" yaml:"change" toml:"change"`
+	VersionBefore string             `json:"version_before,omitempty" yaml:"version_before,omitempty" toml:"version_before,omitempty"`
+	VersionAfter  string             `json:"version_after,omitempty" yaml:"version_after,omitempty" toml:"version_after,omitempty"`
+	Layers        []LayerDiffDisplay `json:"layers" yaml:"layers" toml:"layers"`
+}
+
+type ProcessDiffDisplay struct {
+	Type   string          `json:"type" yaml:"type" toml:"type"`
+	Change string          `json:"change" yaml:"change" toml:"change"`
+	Before *ProcessDisplay `json:"before,omitempty" yaml:"before,omitempty" toml:"before,omitempty"`
+	After  *ProcessDisplay `json:"after,omitempty" yaml:"after,omitempty" toml:"after,omitempty"`
+}
+
+type SBOMPackageDiffDisplay struct {
+	Name           string   `json:"name" yaml:"name" toml:"name"`
+	Change         string   `json:"change" yaml:"change" toml:"change"`
+	VersionsBefore []string `json:"versions_before,omitempty" yaml:"versions_before,omitempty" toml:"versions_before,omitempty"`
+	VersionsAfter  []string `json:"versions_after,omitempty" yaml:"versions_after,omitempty" toml:"versions_after,omitempty"`
+}
+
+type DiffDisplay struct {
+	ImageA       string                   `json:"image_a" yaml:"image_a" toml:"image_a"`
+	ImageB       string                   `json:"image_b" yaml:"image_b" toml:"image_b"`
+	RunImage     *RunImageDiffDisplay     `json:"run_image,omitempty" yaml:"run_image,omitempty" toml:"run_image,omitempty"`
+	Buildpacks   []BuildpackDiffDisplay   `json:"buildpacks" yaml:"buildpacks" toml:"buildpacks"`
+	Processes    []ProcessDiffDisplay     `json:"processes" yaml:"processes" toml:"processes"`
+	SBOMCompared bool                     `json:"sbom_compared" yaml:"sbom_compared" toml:"sbom_compared"`
+	SBOM         []SBOMPackageDiffDisplay `json:"sbom" yaml:"sbom" toml:"sbom"`
+}
+
+// Empty returns true when the images have no differences
+func (d *DiffDisplay) Empty() bool {
+	return d.RunImage == nil && len(d.Buildpacks) == 0 && len(d.Processes) == 0 && len(d.SBOM) == 0
+}
+
+func NewDiffDisplay(imageA, imageB string, diff *client.ImageDiff) *DiffDisplay {
+	result := &DiffDisplay{
+		ImageA:       imageA,
+		ImageB:       imageB,
+		Buildpacks:   []BuildpackDiffDisplay{},
+		Processes:    []ProcessDiffDisplay{},
+		SBOMCompared: diff.SBOMCompared,
+		SBOM:         []SBOMPackageDiffDisplay{},
+	}
+
+	if diff.RunImage != nil {
+		result.RunImage = &RunImageDiffDisplay{
+			Before: displayRunImage(diff.RunImage.Before),
+			After:  displayRunImage(diff.RunImage.After),
+		}
+	}
+
+	for _, bp := range diff.Buildpacks {
+		bpDisplay := BuildpackDiffDisplay{
+			ID:            bp.ID,
+			Change:        bp.Change,
+			VersionBefore: bp.VersionBefore,
+			VersionAfter:  bp.VersionAfter,
+			Layers:        []LayerDiffDisplay{},
+		}
+		for _, layer := range bp.Layers {
+			bpDisplay.Layers = append(bpDisplay.Layers, displayLayerDiff(layer))
+		}
+		result.Buildpacks = append(result.Buildpacks, bpDisplay)
+	}
+
+	for _, proc := range diff.Processes {
+		procDisplay := ProcessDiffDisplay{Type: proc.Type, Change: proc.Change}
+		if proc.Before != nil {
+			before := convertToDisplay(*proc.Before, proc.DefaultBefore)
+			procDisplay.Before = &before
+		}
+		if proc.After != nil {
+			after := convertToDisplay(*proc.After, proc.DefaultAfter)
+			procDisplay.After = &after
+		}
+		result.Processes = append(result.Processes, procDisplay)
+	}
+
+	for _, pkg := range diff.SBOM {
+		result.SBOM = append(result.SBOM, SBOMPackageDiffDisplay{
+			Name:           pkg.Name,
+			Change:         pkg.Change,
+			VersionsBefore: pkg.VersionsBefore,
+			VersionsAfter:  pkg.VersionsAfter,
+		})
+	}
+
+	return result
+}
+
+func displayRunImage(runImage files.RunImageForRebase) RunImageDisplay {
+	return RunImageDisplay{
+		Image:     runImage.Image,
+		Reference: runImage.Reference,
+		TopLayer:  runImage.TopLayer,
+	}
+}
+
+func displayLayerDiff(layer client.LayerDiff) LayerDiffDisplay {
+	result := LayerDiffDisplay{
+		Name:         layer.Name,
+		Change:       layer.Change,
+		DiffIDBefore: layer.DiffIDBefore,
+		DiffIDAfter:  layer.DiffIDAfter,
+	}
+	// sizes are only shown for the images the layer is in, when they're known
+	if layer.DiffIDBefore != "" && layer.SizeBefore >= 0 {
+		result.SizeBefore = &layer.SizeBefore
+	}
+	if layer.DiffIDAfter != "" && layer.SizeAfter >= 0 {
+		result.SizeAfter = &layer.SizeAfter
+	}
+	if delta, ok := layer.SizeDelta(); ok {
+		result.SizeDelta = &delta
+	}
+	return result
+}
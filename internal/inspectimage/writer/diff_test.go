@@ -0,0 +1,156 @@
+package writer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/buildpacks/lifecycle/launch"
+	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/inspectimage"
+	"github.com/buildpacks/pack/internal/inspectimage/writer"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDiffWriters(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Diff Writers", testDiffWriters, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDiffWriters(t *testing.T, when spec.G, it spec.S) {
+	var (
+		assert = h.NewAssertionManager(t)
+		outBuf bytes.Buffer
+		logger logging.Logger
+		diff   *inspectimage.DiffDisplay
+	)
+
+	it.Before(func() {
+		outBuf = bytes.Buffer{}
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+
+		diff = inspectimage.NewDiffDisplay("some/app:v1", "some/app:v2", &client.ImageDiff{
+			RunImage: &client.RunImageDiff{
+				Before: files.RunImageForRebase{TopLayer: "sha256:top-a", Reference: "some/run@sha256:aaa"},
+				After:  files.RunImageForRebase{TopLayer: "sha256:top-b", Reference: "some/run@sha256:bbb"},
+			},
+			Buildpacks: []client.BuildpackDiff{
+				{
+					ID:            "some/bp",
+					Change:        client.DiffChanged,
+					VersionBefore: "1.0.0",
+					VersionAfter:  "1.1.0",
+					Layers: []client.LayerDiff{
+						{Name: "added", Change: client.DiffAdded, DiffIDAfter: "sha256:added", SizeAfter: 2000},
+						{Name: "deps", Change: client.DiffChanged, DiffIDBefore: "sha256:deps-1", DiffIDAfter: "sha256:deps-2", SizeBefore: 3000000, SizeAfter: 2000000},
+						{Name: "unknown", Change: client.DiffChanged, DiffIDBefore: "sha256:unknown-1", DiffIDAfter: "sha256:unknown-2", SizeBefore: -1, SizeAfter: -1},
+					},
+				},
+				{ID: "old/bp", Change: client.DiffRemoved, VersionBefore: "2.0.0"},
+			},
+			Processes: []client.ProcessDiff{
+				{
+					Type:          "web",
+					Change:        client.DiffChanged,
+					Before:        &launch.Process{Type: "web", Command: launch.NewRawCommand([]string{"node"}), Args: []string{"server.js"}, Direct: true},
+					After:         &launch.Process{Type: "web", Command: launch.NewRawCommand([]string{"node"}), Args: []string{"index.js"}, Direct: true},
+					DefaultBefore: true,
+					DefaultAfter:  true,
+				},
+			},
+			SBOMCompared: true,
+			SBOM: []client.SBOMPackageDiff{
+				{Name: "express", Change: client.DiffChanged, VersionsBefore: []string{"4.18.2"}, VersionsAfter: []string{"4.19.2"}},
+				{Name: "lodash", Change: client.DiffAdded, VersionsAfter: []string{"4.17.21"}},
+			},
+		})
+	})
+
+	when("Factory#DiffWriter", func() {
+		it("returns a writer for each output format", func() {
+			factory := writer.NewFactory()
+			for _, kind := range []string{"human-readable", "json", "yaml", "toml"} {
+				w, err := factory.DiffWriter(kind)
+				assert.Nil(err)
+				assert.NotNil(w)
+			}
+		})
+
+		it("errors for unsupported formats", func() {
+			_, err := writer.NewFactory().DiffWriter("xml")
+			assert.ErrorWithMessage(err, "output format 'xml' is not supported")
+		})
+	})
+
+	when("HumanReadable#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewHumanReadable().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), `Comparing 'some/app:v1' to 'some/app:v2'
+
+Run Image:
+  - some/run@sha256:aaa    top layer sha256:top-a
+  + some/run@sha256:bbb    top layer sha256:top-b
+
+Buildpacks:
+  ~ some/bp        1.0.0 -> 1.1.0
+      + added      2.0 kB
+      ~ deps       3.0 MB -> 2.0 MB (-1.0 MB)
+      ~ unknown    (size unknown)
+  - old/bp         2.0.0
+
+Processes:
+  ~ web    node server.js (default) -> node index.js (default)
+
+SBOM Packages:
+  ~ express    4.18.2 -> 4.19.2
+  + lodash     4.17.21
+`)
+		})
+
+		it("reports images without differences", func() {
+			empty := inspectimage.NewDiffDisplay("some/app:v1", "some/app:v2", &client.ImageDiff{})
+			assert.Nil(writer.NewHumanReadable().PrintDiff(logger, empty))
+
+			assert.Contains(outBuf.String(), "No differences found")
+			assert.Contains(outBuf.String(), "(not compared, the SBoM of an image could not be read)")
+		})
+	})
+
+	when("JSON#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewJSON().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), `"image_a": "some/app:v1"`)
+			assert.Contains(outBuf.String(), `"size_delta": -1000000`)
+			assert.Contains(outBuf.String(), `"versions_after": [
+        "4.17.21"
+      ]`)
+		})
+	})
+
+	when("YAML#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewYAML().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), "image_b: some/app:v2")
+			assert.Contains(outBuf.String(), "version_before: 2.0.0")
+		})
+	})
+
+	when("TOML#PrintDiff", func() {
+		it("prints the changes", func() {
+			assert.Nil(writer.NewTOML().PrintDiff(logger, diff))
+
+			assert.Contains(outBuf.String(), "[[buildpacks.layers]]")
+			assert.Contains(outBuf.String(), "size_after = 2000")
+			assert.Contains(outBuf.String(), "sbom_compared = true")
+		})
+	})
+}
@@ -22,6 +22,10 @@ type InspectImageWriter interface {
 	) error
 }
 
+type InspectImageDiffWriter interface {
+	PrintDiff(logger logging.Logger, diff *inspectimage.DiffDisplay) error
+}
+
 func NewFactory() *Factory {
 	return &Factory{}
 }
@@ -49,3 +53,18 @@ func (f *Factory) Writer(kind string, bom bool) (InspectImageWriter, error) {
 
 	return nil, fmt.Errorf("output format %s is not supported", style.Symbol(kind))
 }
+
+func (f *Factory) DiffWriter(kind string) (InspectImageDiffWriter, error) {
+	switch kind {
+	case "human-readable":
+		return NewHumanReadable(), nil
+	case "json":
+		return NewJSON(), nil
+	case "yaml":
+		return NewYAML(), nil
+	case "toml":
+		return NewTOML(), nil
+	}
+
+	return nil, fmt.Errorf("output format %s is not supported", style.Symbol(kind))
+}
@@ -0,0 +1,126 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/buildpacks/pack/internal/inspectimage"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+var changeSymbols = map[string]string{
+	client.DiffAdded:   "+",
+	client.DiffRemoved: "-",
+	client.DiffChanged: "~",
+}
+
+func (h *HumanReadable) PrintDiff(logger logging.Logger, diff *inspectimage.DiffDisplay) error {
+	logger.Infof("Comparing %s to %s\n", style.Symbol(diff.ImageA), style.Symbol(diff.ImageB))
+	if diff.Empty() {
+		logger.Info("\nNo differences found\n")
+	}
+
+	buf := bytes.NewBuffer(nil)
+	tw := tabwriter.NewWriter(buf, 0, 0, 4, ' ', 0)
+
+	if diff.RunImage != nil {
+		fmt.Fprintln(tw, "\nRun Image:")
+		fmt.Fprintf(tw, "  - %s\ttop layer %s\n", runImageName(diff.RunImage.Before), diff.RunImage.Before.TopLayer)
+		fmt.Fprintf(tw, "  + %s\ttop layer %s\n", runImageName(diff.RunImage.After), diff.RunImage.After.TopLayer)
+	}
+
+	if len(diff.Buildpacks) > 0 {
+		fmt.Fprintln(tw, "\nBuildpacks:")
+		for _, bp := range diff.Buildpacks {
+			fmt.Fprintf(tw, "  %s %s\t%s\n", changeSymbols[bp.Change], bp.ID, versionChange(bp.Change, bp.VersionBefore, bp.VersionAfter))
+			for _, layer := range bp.Layers {
+				fmt.Fprintf(tw, "      %s %s\t%s\n", changeSymbols[layer.Change], layer.Name, layerSizeChange(layer))
+			}
+		}
+	}
+
+	if len(diff.Processes) > 0 {
+		fmt.Fprintln(tw, "\nProcesses:")
+		for _, proc := range diff.Processes {
+			fmt.Fprintf(tw, "  %s %s\t%s\n", changeSymbols[proc.Change], proc.Type, processChange(proc))
+		}
+	}
+
+	if len(diff.SBOM) > 0 {
+		fmt.Fprintln(tw, "\nSBOM Packages:")
+		for _, pkg := range diff.SBOM {
+			versions := versionChange(pkg.Change, strings.Join(pkg.VersionsBefore, ", "), strings.Join(pkg.VersionsAfter, ", "))
+			fmt.Fprintf(tw, "  %s %s\t%s\n", changeSymbols[pkg.Change], pkg.Name, versions)
+		}
+	}
+	if !diff.SBOMCompared {
+		fmt.Fprintln(tw, "\nSBOM Packages:\n  (not compared, the SBoM of an image could not be read)")
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	logger.Info(buf.String())
+	return nil
+}
+
+func runImageName(runImage inspectimage.RunImageDisplay) string {
+	if runImage.Reference != "" {
+		return runImage.Reference
+	}
+	if runImage.Image != "" {
+		return runImage.Image
+	}
+	return "(unknown)"
+}
+
+func versionChange(change, before, after string) string {
+	switch {
+	case change == client.DiffAdded:
+		return after
+	case change == client.DiffRemoved, before == after:
+		return before
+	default:
+		return fmt.Sprintf("%s -> %s", before, after)
+	}
+}
+
+func layerSizeChange(layer inspectimage.LayerDiffDisplay) string {
+	switch {
+	case layer.Change == client.DiffAdded && layer.SizeAfter != nil:
+		return humanize.Bytes(uint64(*layer.SizeAfter))
+	case layer.Change == client.DiffRemoved && layer.SizeBefore != nil:
+		return humanize.Bytes(uint64(*layer.SizeBefore))
+	case layer.SizeDelta != nil && *layer.SizeDelta < 0:
+		return fmt.Sprintf("%s -> %s (-%s)", humanize.Bytes(uint64(*layer.SizeBefore)), humanize.Bytes(uint64(*layer.SizeAfter)), humanize.Bytes(uint64(-*layer.SizeDelta)))
+	case layer.SizeDelta != nil:
+		return fmt.Sprintf("%s -> %s (+%s)", humanize.Bytes(uint64(*layer.SizeBefore)), humanize.Bytes(uint64(*layer.SizeAfter)), humanize.Bytes(uint64(*layer.SizeDelta)))
+	default:
+		return "(size unknown)"
+	}
+}
+
+func processChange(proc inspectimage.ProcessDiffDisplay) string {
+	command := func(p *inspectimage.ProcessDisplay) string {
+		description := strings.Join(append([]string{p.Command}, p.Args...), " ")
+		if p.Default {
+			description += " (default)"
+		}
+		return description
+	}
+
+	switch {
+	case proc.Before == nil:
+		return command(proc.After)
+	case proc.After == nil:
+		return command(proc.Before)
+	default:
+		return fmt.Sprintf("%s -> %s", command(proc.Before), command(proc.After))
+	}
+}
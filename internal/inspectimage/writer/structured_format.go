@@ -47,3 +47,13 @@ func (w *StructuredFormat) Print(
 	_, err = logger.Writer().Write(out)
 	return err
 }
+
+func (w *StructuredFormat) PrintDiff(logger logging.Logger, diff *inspectimage.DiffDisplay) error {
+	out, err := w.MarshalFunc(diff)
+	if err != nil {
+		return fmt.Errorf("preparing diff of %s and %s: %w", style.Symbol(diff.ImageA), style.Symbol(diff.ImageB), err)
+	}
+
+	_, err = logger.Writer().Write(out)
+	return err
+}
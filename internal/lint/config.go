@@ -0,0 +1,294 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/buildpackage"
+	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+func (f *file) lintPackage() {
+	config := buildpackage.Config{}
+	md, err := toml.DecodeFile(f.path, &config)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	f.unknownKeys(keyNames(md.Undecoded()), LevelError, "isn't allowed")
+
+	switch {
+	case config.Buildpack.URI == "" && config.Extension.URI == "":
+		f.add(RuleRequiredField, LevelError, 0, "'buildpack.uri' or 'extension.uri' is required")
+	case config.Buildpack.URI != "" && config.Extension.URI != "":
+		f.add(RuleLocator, LevelWarning, f.lineOf("extension.uri", ""),
+			"both 'buildpack.uri' and 'extension.uri' are declared: package the buildpack and the extension with separate files")
+	}
+
+	if config.Platform.OS != "" {
+		if config.Platform.OS != "linux" && config.Platform.OS != "windows" {
+			f.add(RuleTargets, LevelError, f.lineOf("platform.os", ""),
+				"'platform.os' must be linux or windows, found %s", config.Platform.OS)
+		}
+		if len(config.Targets) > 0 {
+			f.add(RuleTargets, LevelWarning, f.lineOf("platform", ""),
+				"'platform' is deprecated and ignored as 'targets' are declared: remove it")
+		}
+	}
+	f.lintTargets(config.Targets)
+
+	dependencies := newModules()
+	for i, dependency := range config.Dependencies {
+		if dependency.URI != "" && dependency.ImageName != "" {
+			f.add(RuleLocator, LevelError, f.lineOf("dependencies", ""),
+				"dependency #%d declares both 'uri' and 'image': declare one of them", i+1)
+			continue
+		}
+		if dependency.URI == "" && dependency.ImageName == "" {
+			f.add(RuleRequiredField, LevelError, f.lineOf("dependencies", ""), "dependency #%d has no 'uri' or 'image'", i+1)
+			continue
+		}
+		if dependency.URI != "" && !f.lintURI(dependency.URI, "dependencies.uri") {
+			continue
+		}
+		dependencies.add(f, builder.ModuleConfig{ImageOrURI: dependency}, buildpack.KindBuildpack, "dependencies.uri")
+	}
+
+	for _, uri := range []struct{ kind, value string }{{buildpack.KindBuildpack, config.Buildpack.URI}, {buildpack.KindExtension, config.Extension.URI}} {
+		key := uri.kind + ".uri"
+		if uri.value == "" || !f.lintURI(uri.value, key) {
+			continue
+		}
+		if uri.kind != buildpack.KindBuildpack {
+			continue
+		}
+
+		// the order of a composite buildpack references its dependencies
+		dir, ok := f.localDir(uri.value)
+		if !ok {
+			continue
+		}
+		descriptor := dist.BuildpackDescriptor{}
+		if _, err := buildpack.ReadDescriptor(buildpack.KindBuildpack, &descriptor, blob.NewBlob(dir)); err != nil {
+			f.add(RuleLocator, LevelError, f.lineOf(key, ""), "reading the buildpack at '%s': %s", uri.value, err)
+			continue
+		}
+		descriptorFile, err := openFile(filepath.Join(dir, KindBuildpack.FileName()), f.findings)
+		if err != nil {
+			continue
+		}
+		descriptorFile.lintReferences(descriptor.WithOrder, "order", dependencies, "[[dependencies]] of "+f.path)
+	}
+}
+
+func (f *file) lintBuilder() {
+	raw := builder.Config{}
+	md, err := toml.DecodeFile(f.path, &raw)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	if undecoded := keyNames(md.Undecoded()); len(undecoded) > 0 {
+		f.unknownKeys(undecoded, LevelError, "isn't allowed")
+		return
+	}
+
+	// read the config the way pack does, merging the deprecated stack into the build and run images
+	config, _, err := builder.ReadConfig(f.path)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	if err := builder.ValidateConfig(config); err != nil {
+		line := f.lineOf("build", "")
+		if strings.HasPrefix(err.Error(), "run.") {
+			line = f.lineOf("run.images", "")
+		}
+		f.add(RuleImages, LevelError, line, "%s", err)
+	}
+	if raw.Stack.ID != "" || raw.Stack.BuildImage != "" || raw.Stack.RunImage != "" || len(raw.Stack.RunImageMirrors) > 0 {
+		f.add(RuleImages, LevelWarning, f.lineOf("stack", ""),
+			"'stack' is deprecated: declare the build image as 'build.image' and the run images as 'run.images'")
+	}
+	f.lintTargets(config.Targets)
+
+	if config.Lifecycle.URI != "" && config.Lifecycle.Version != "" {
+		f.add(RuleLocator, LevelError, f.lineOf("lifecycle", ""), "'lifecycle' declares both 'uri' and 'version': declare one of them")
+	}
+
+	if len(config.Order) == 0 {
+		f.add(RuleOrder, LevelWarning, 0, "'order' is empty: no buildpack of the builder would detect")
+	}
+	f.lintOrder(config.Order, "order", false)
+	f.lintOrder(config.OrderExtensions, "order-extensions", false)
+
+	for _, collection := range []struct {
+		kind    string
+		key     string
+		modules builder.ModuleCollection
+		order   dist.Order
+		orderOf string
+	}{
+		{buildpack.KindBuildpack, "buildpacks", config.Buildpacks, config.Order, "order"},
+		{buildpack.KindExtension, "extensions", config.Extensions, config.OrderExtensions, "order-extensions"},
+	} {
+		provided := newModules()
+		for i, module := range collection.modules {
+			if module.URI != "" && module.ImageName != "" {
+				f.add(RuleLocator, LevelError, f.lineOf(collection.key, ""),
+					"%s #%d declares both 'uri' and 'image': declare one of them", collection.kind, i+1)
+				continue
+			}
+			if module.URI == "" && module.ImageName == "" {
+				f.add(RuleRequiredField, LevelError, f.lineOf(collection.key, ""), "%s #%d has no 'uri' or 'image'", collection.kind, i+1)
+				continue
+			}
+			if module.URI != "" && !f.lintURI(module.URI, collection.key+".uri") {
+				continue
+			}
+			provided.add(f, module, collection.kind, collection.key+".uri")
+		}
+		f.lintReferences(collection.order, collection.orderOf, provided, "[["+collection.key+"]]")
+	}
+}
+
+// lintURI checks that a module URI can be located, relative to the directory of the file
+func (f *file) lintURI(uri, key string) bool {
+	line := f.lineOf(key, uri)
+	if dir, ok := f.localPath(uri); ok {
+		if _, err := os.Stat(dir); err != nil {
+			f.add(RuleLocator, LevelError, line, "'%s' doesn't exist", uri)
+			return false
+		}
+		return true
+	}
+
+	locatorType, err := buildpack.GetLocatorType(uri, f.dir(), nil)
+	if err != nil {
+		f.add(RuleLocator, LevelError, line, "'%s' is invalid: %s", uri, err)
+		return false
+	}
+	if locatorType == buildpack.InvalidLocator {
+		f.add(RuleLocator, LevelError, line, "'%s' isn't a path, a URL, an image or a registry reference", uri)
+		return false
+	}
+	return true
+}
+
+// localPath returns the path a URI designates when it is a path or a file URI, relative to the directory of the file
+func (f *file) localPath(uri string) (string, bool) {
+	switch {
+	case strings.HasPrefix(uri, "file://"):
+		path, err := paths.URIToFilePath(uri)
+		return path, err == nil
+	case paths.IsURI(uri):
+		return "", false
+	case filepath.IsAbs(uri):
+		return uri, true
+	case uri == "." || strings.HasPrefix(uri, "./") || strings.HasPrefix(uri, "../"):
+		return filepath.Join(f.dir(), uri), true
+	}
+	path := filepath.Join(f.dir(), uri)
+	if _, err := os.Stat(path); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// localDir returns the directory a URI designates, if it is a local directory
+func (f *file) localDir(uri string) (string, bool) {
+	path, ok := f.localPath(uri)
+	if !ok {
+		return "", false
+	}
+	if isDir, err := paths.IsDir(path); err != nil || !isDir {
+		return "", false
+	}
+	return path, true
+}
+
+// lintReferences checks that the modules an order references are provided, in the versions they pin
+func (f *file) lintReferences(order dist.Order, key string, provided *modules, providedBy string) {
+	for i, entry := range order {
+		for _, ref := range entry.Group {
+			if ref.ID == "" {
+				continue
+			}
+			line := f.lineOf(key+".group.id", ref.ID)
+			versions, found := provided.versions[ref.ID]
+			switch {
+			case !found && provided.complete:
+				f.add(RuleOrderReference, LevelError, line,
+					"group #%d of '%s' references '%s', which isn't provided by the %s", i+1, key, ref.ID, providedBy)
+			case found && ref.Version != "" && !slices.Contains(versions, "") && !slices.Contains(versions, ref.Version):
+				f.add(RuleVersionPin, LevelError, line,
+					"group #%d of '%s' pins '%s' to version %s, but the %s provide version %s", i+1, key, ref.ID, ref.Version, providedBy, strings.Join(versions, ", "))
+			}
+		}
+	}
+}
+
+// modules are the buildpacks or extensions provided to an order
+type modules struct {
+	// versions are the versions provided by ID, an empty version standing for any version
+	versions map[string][]string
+	// complete is whether the ID of every module is known
+	complete bool
+}
+
+func newModules() *modules {
+	return &modules{
+		versions: map[string][]string{},
+		complete: true,
+	}
+}
+
+// add adds a module to the modules provided, reading its ID and version from its descriptor when it is a local
+// directory. A version pinned by the reference that doesn't match the descriptor is reported.
+func (m *modules) add(f *file, module builder.ModuleConfig, kind, key string) {
+	info := module.ModuleInfo
+	if dir, ok := f.localDir(module.URI); module.URI != "" && ok {
+		var descriptor interface{ Info() dist.ModuleInfo }
+		if kind == buildpack.KindExtension {
+			descriptor = &dist.ExtensionDescriptor{}
+		} else {
+			descriptor = &dist.BuildpackDescriptor{}
+		}
+		if _, err := buildpack.ReadDescriptor(kind, descriptor, blob.NewBlob(dir)); err == nil {
+			line := f.lineOf(key, module.URI)
+			actual := descriptor.Info()
+			if info.ID != "" && info.ID != actual.ID {
+				f.add(RuleVersionPin, LevelError, line, "the %s at '%s' is '%s', not '%s'", kind, module.URI, actual.ID, info.ID)
+			}
+			if info.Version != "" && info.Version != actual.Version {
+				f.add(RuleVersionPin, LevelError, line, "'%s' is pinned to version %s, but the %s at '%s' is version %s", actual.ID, info.Version, kind, module.URI, actual.Version)
+			}
+			info = actual
+		}
+	}
+
+	if info.ID == "" {
+		// the ID of packaged and remote modules is only known once they are fetched
+		m.complete = false
+		return
+	}
+	if !slices.Contains(m.versions[info.ID], info.Version) {
+		m.versions[info.ID] = append(m.versions[info.ID], info.Version)
+	}
+}
+
+func keyNames(keys []toml.Key) []string {
+	var names []string
+	for _, key := range keys {
+		names = append(names, key.String())
+	}
+	return names
+}
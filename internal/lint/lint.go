@@ -0,0 +1,284 @@
+// Package lint finds mistakes in buildpack.toml, extension.toml, package.toml and builder.toml files before
+// they surface while packaging a buildpack or creating a builder.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+)
+
+// Kind is the kind of a file that can be linted
+type Kind string
+
+const (
+	KindBuildpack Kind = "buildpack"
+	KindExtension Kind = "extension"
+	KindPackage   Kind = "package"
+	KindBuilder   Kind = "builder"
+)
+
+// Kinds are the kinds of files that can be linted, in the order files of a directory are linted
+var Kinds = []Kind{KindBuildpack, KindExtension, KindPackage, KindBuilder}
+
+// FileName returns the conventional name of files of the kind
+func (k Kind) FileName() string {
+	return string(k) + ".toml"
+}
+
+// Level is the severity of a finding
+type Level string
+
+const (
+	// LevelError is the level of mistakes pack refuses, or that fail a build
+	LevelError Level = "error"
+	// LevelWarning is the level of mistakes pack tolerates, but that are likely unintended
+	LevelWarning Level = "warning"
+)
+
+const (
+	RuleInvalidFile    = "invalid-file"
+	RuleUnknownKey     = "unknown-key"
+	RuleRequiredField  = "required-field"
+	RuleAPIVersion     = "api-version"
+	RuleExecutable     = "executable"
+	RuleTargets        = "targets"
+	RuleImages         = "images"
+	RuleLocator        = "locator"
+	RuleOrder          = "order"
+	RuleOrderReference = "order-reference"
+	RuleVersionPin     = "version-pin"
+)
+
+// Rule is a check performed on linted files
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules are the checks performed on linted files, in the order they are documented
+var Rules = []Rule{
+	{ID: RuleInvalidFile, Description: "The file can't be read or isn't valid TOML."},
+	{ID: RuleUnknownKey, Description: "The file contains a key that isn't defined for its kind. pack ignores unknown keys of descriptors and refuses unknown keys of package.toml and builder.toml."},
+	{ID: RuleRequiredField, Description: "A required field is missing."},
+	{ID: RuleAPIVersion, Description: "The Buildpack API of a buildpack or extension isn't supported by the lifecycle."},
+	{ID: RuleExecutable, Description: "An executable of the bin directory is missing or doesn't have its executable bits set."},
+	{ID: RuleTargets, Description: "Targets are invalid, or conflict with deprecated stacks or platform declarations."},
+	{ID: RuleImages, Description: "The build and run images of a builder are missing or conflict with the deprecated stack declaration."},
+	{ID: RuleLocator, Description: "A buildpack or extension is referenced with an invalid or missing URI or image."},
+	{ID: RuleOrder, Description: "An order is empty, or has groups which are empty or list the same module twice."},
+	{ID: RuleOrderReference, Description: "An order references a buildpack or extension that isn't provided."},
+	{ID: RuleVersionPin, Description: "A version pinned in an order or module reference doesn't match the version provided."},
+}
+
+// Finding is a mistake found in a file
+type Finding struct {
+	Rule  string
+	Level Level
+	// File is the path of the file the finding is about
+	File string
+	// Line is the line of File the finding is about, starting at 1. It is 0 when the finding is about the whole file.
+	Line    int
+	Message string
+}
+
+// Errors returns the number of findings of error level
+func Errors(findings []Finding) int {
+	var count int
+	for _, finding := range findings {
+		if finding.Level == LevelError {
+			count++
+		}
+	}
+	return count
+}
+
+// Lint lints the file at path, or every buildpack.toml, extension.toml, package.toml and builder.toml in the
+// directory at path. The kind of a file is given by its name, unless kind is set. Mistakes are returned as findings
+// ordered by file and line, an error is only returned if path can't be linted at all.
+func Lint(path string, kind Kind) ([]Finding, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	var files []string
+	var kinds []Kind
+	if info.IsDir() {
+		for _, k := range Kinds {
+			if kind != "" && k != kind {
+				continue
+			}
+			file := filepath.Join(path, k.FileName())
+			if _, err := os.Stat(file); err == nil {
+				files = append(files, file)
+				kinds = append(kinds, k)
+			}
+		}
+		if len(files) == 0 {
+			return nil, errors.Errorf("no buildpack.toml, extension.toml, package.toml or builder.toml found in %s", path)
+		}
+	} else {
+		fileKind, err := kindOf(path, kind)
+		if err != nil {
+			return nil, err
+		}
+		files = []string{path}
+		kinds = []Kind{fileKind}
+	}
+
+	var findings []Finding
+	for i, file := range files {
+		f, err := openFile(file, &findings)
+		if err != nil {
+			return nil, err
+		}
+		switch kinds[i] {
+		case KindBuildpack:
+			f.lintBuildpack()
+		case KindExtension:
+			f.lintExtension()
+		case KindPackage:
+			f.lintPackage()
+		case KindBuilder:
+			f.lintBuilder()
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+func kindOf(path string, kind Kind) (Kind, error) {
+	name := filepath.Base(path)
+	switch kind {
+	case "":
+		for _, k := range Kinds {
+			if name == k.FileName() {
+				return k, nil
+			}
+		}
+		return "", errors.Errorf("can't tell the kind of %s from its name: set the kind to one of %s", path, kindNames())
+	case KindBuildpack, KindExtension:
+		// descriptors are read the way pack reads them, from the root of their module
+		if name != kind.FileName() {
+			return "", errors.Errorf("a %s descriptor must be named %s, found %s", kind, kind.FileName(), name)
+		}
+		return kind, nil
+	case KindPackage, KindBuilder:
+		return kind, nil
+	default:
+		return "", errors.Errorf("unknown kind %s: must be one of %s", kind, kindNames())
+	}
+}
+
+func kindNames() string {
+	var names []string
+	for _, k := range Kinds {
+		names = append(names, string(k))
+	}
+	return strings.Join(names, ", ")
+}
+
+// file is a file being linted, its findings are added to a result shared by every linted file
+type file struct {
+	path     string
+	lines    []string
+	findings *[]Finding
+}
+
+func openFile(path string, findings *[]Finding) (*file, error) {
+	contents, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	return &file{
+		path:     path,
+		lines:    strings.Split(string(contents), "\n"),
+		findings: findings,
+	}, nil
+}
+
+func (f *file) dir() string {
+	return filepath.Dir(f.path)
+}
+
+func (f *file) add(rule string, level Level, line int, format string, args ...interface{}) {
+	*f.findings = append(*f.findings, Finding{
+		Rule:    rule,
+		Level:   level,
+		File:    f.path,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// invalid adds the finding of a file that can't be decoded
+func (f *file) invalid(err error) {
+	var line int
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		line = parseErr.Position.Line
+	}
+	f.add(RuleInvalidFile, LevelError, line, "%s", err)
+}
+
+// unknownKeys adds a finding for each key the kind of the file doesn't define
+func (f *file) unknownKeys(keys []string, level Level, consequence string) {
+	for _, key := range keys {
+		f.add(RuleUnknownKey, level, f.lineOf(key, ""), "unknown key '%s' %s", key, consequence)
+	}
+}
+
+// lineOf returns the line of the first definition of key, a dotted path ignoring array indices, whose value
+// contains the string value if it is set. It returns 0 if there is none. Tables are found by their header.
+func (f *file) lineOf(key, value string) int {
+	table := ""
+	for i, line := range f.lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			if end := strings.LastIndex(trimmed, "]"); end > 0 {
+				table = normalizeKey(strings.Trim(trimmed[:end], "[]"))
+			}
+			if value == "" && table == key {
+				return i + 1
+			}
+			continue
+		}
+
+		name, rest, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		fullName := normalizeKey(name)
+		if table != "" {
+			fullName = table + "." + fullName
+		}
+		if fullName == key && (value == "" || strings.Contains(rest, `"`+value+`"`) || strings.Contains(rest, `'`+value+`'`)) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func normalizeKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
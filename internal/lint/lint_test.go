@@ -0,0 +1,345 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/lint"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLint(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Lint", testLint, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLint(t *testing.T, when spec.G, it spec.S) {
+	var tmpDir string
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+	})
+
+	writeFile := func(path, contents string, mode os.FileMode) string {
+		path = filepath.Join(tmpDir, path)
+		h.AssertNil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		h.AssertNil(t, os.WriteFile(path, []byte(contents), mode))
+		return path
+	}
+
+	writeBuildpack := func(dir, id, version string) {
+		writeFile(filepath.Join(dir, "buildpack.toml"), `api = "0.10"
+
+[buildpack]
+id = "`+id+`"
+version = "`+version+`"
+
+[[targets]]
+os = "linux"
+`, 0644)
+		writeFile(filepath.Join(dir, "bin", "detect"), "#!/usr/bin/env bash", 0755)
+		writeFile(filepath.Join(dir, "bin", "build"), "#!/usr/bin/env bash", 0755)
+	}
+
+	assertFinding := func(findings []lint.Finding, expected lint.Finding) {
+		t.Helper()
+		for _, finding := range findings {
+			if finding == expected {
+				return
+			}
+		}
+		t.Fatalf("expected finding %+v in %+v", expected, findings)
+	}
+
+	when("#Lint", func() {
+		when("a buildpack is linted", func() {
+			it("has no findings when it is valid", func() {
+				writeBuildpack("bp", "example/bp", "1.0.0")
+
+				findings, err := lint.Lint(filepath.Join(tmpDir, "bp"), "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(findings), 0)
+			})
+
+			it("reports unknown keys, unsupported APIs and targets without os", func() {
+				path := writeFile(filepath.Join("bp", "buildpack.toml"), `api = "0.6"
+
+[buildpack]
+id = "example/bp"
+version = "1.0.0"
+homepag = "https://example.com"
+
+[[targets]]
+arch = "amd64"
+`, 0644)
+				writeFile(filepath.Join("bp", "bin", "detect"), "", 0755)
+				writeFile(filepath.Join("bp", "bin", "build"), "", 0755)
+
+				findings, err := lint.Lint(path, "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(findings), 3)
+				assertFinding(findings, lint.Finding{
+					Rule: lint.RuleAPIVersion, Level: lint.LevelError, File: path, Line: 1,
+					Message: `Buildpack API 0.6 isn't supported by the lifecycle, which supports ["0.7", "0.8", "0.9", "0.10", "0.11", "0.12"]`,
+				})
+				assertFinding(findings, lint.Finding{
+					Rule: lint.RuleUnknownKey, Level: lint.LevelWarning, File: path, Line: 6,
+					Message: "unknown key 'buildpack.homepag' is ignored",
+				})
+				assertFinding(findings, lint.Finding{
+					Rule: lint.RuleTargets, Level: lint.LevelError, File: path, Line: 8,
+					Message: "target #1 has no 'os'",
+				})
+			})
+
+			it("reports missing and non-executable executables", func() {
+				if runtime.GOOS == "windows" {
+					t.Skip("executable bits aren't checked on windows")
+				}
+				writeBuildpack("bp", "example/bp", "1.0.0")
+				h.AssertNil(t, os.Remove(filepath.Join(tmpDir, "bp", "bin", "build")))
+				h.AssertNil(t, os.Chmod(filepath.Join(tmpDir, "bp", "bin", "detect"), 0644))
+
+				findings, err := lint.Lint(filepath.Join(tmpDir, "bp"), "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(findings), 2)
+				path := filepath.Join(tmpDir, "bp", "buildpack.toml")
+				assertFinding(findings, lint.Finding{
+					Rule: lint.RuleExecutable, Level: lint.LevelError, File: path,
+					Message: "'bin/detect' isn't executable: run chmod +x bin/detect",
+				})
+				assertFinding(findings, lint.Finding{
+					Rule: lint.RuleExecutable, Level: lint.LevelError, File: path,
+					Message: "'bin/build' is missing",
+				})
+			})
+
+			it("reports deprecated stacks declared along targets", func() {
+				writeBuildpack("bp", "example/bp", "1.0.0")
+				path := writeFile(filepath.Join("bp", "buildpack.toml"), `api = "0.10"
+
+[buildpack]
+id = "example/bp"
+version = "1.0.0"
+
+[[stacks]]
+id = "io.buildpacks.stacks.jammy"
+
+[[targets]]
+os = "linux"
+`, 0644)
+
+				findings, err := lint.Lint(path, "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, findings, []lint.Finding{{
+					Rule: lint.RuleTargets, Level: lint.LevelWarning, File: path, Line: 7,
+					Message: "'stacks' are deprecated since Buildpack API 0.10 and 'targets' are declared: remove the stacks",
+				}})
+			})
+
+			it("reports invalid TOML with its line", func() {
+				path := writeFile(filepath.Join("bp", "buildpack.toml"), "api = \"0.10\"\napi = \"0.11\"\n", 0644)
+
+				findings, err := lint.Lint(path, "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(findings), 1)
+				h.AssertEq(t, findings[0].Rule, lint.RuleInvalidFile)
+				h.AssertEq(t, findings[0].Line, 2)
+			})
+		})
+
+		when("an extension is linted", func() {
+			it("reports an old API and missing Dockerfiles", func() {
+				path := writeFile(filepath.Join("ext", "extension.toml"), `api = "0.8"
+
+[extension]
+id = "example/ext"
+version = "1.0.0"
+`, 0644)
+
+				findings, err := lint.Lint(filepath.Join(tmpDir, "ext"), "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(findings), 2)
+				assertFinding(findings, lint.Finding{
+					Rule: lint.RuleAPIVersion, Level: lint.LevelError, File: path, Line: 1,
+					Message: "extensions require Buildpack API 0.9 or later, found 0.8",
+				})
+				h.AssertEq(t, findings[0].Rule, lint.RuleExecutable)
+				h.AssertContains(t, findings[0].Message, "the extension provides no Dockerfiles")
+			})
+		})
+
+		when("a package is linted", func() {
+			it("reports order references and version pins of a composite buildpack", func() {
+				writeBuildpack("dep", "example/dep", "2.0.0")
+				descriptorPath := writeFile(filepath.Join("composite", "buildpack.toml"), `api = "0.10"
+
+[buildpack]
+id = "example/composite"
+version = "1.0.0"
+
+[[order]]
+[[order.group]]
+id = "example/dep"
+version = "1.0.0"
+
+[[order.group]]
+id = "example/missing"
+version = "1.0.0"
+`, 0644)
+				packagePath := writeFile(filepath.Join("composite", "package.toml"), `[buildpack]
+uri = "."
+
+[[dependencies]]
+uri = "../dep"
+`, 0644)
+
+				findings, err := lint.Lint(packagePath, "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, findings, []lint.Finding{
+					{
+						Rule: lint.RuleVersionPin, Level: lint.LevelError, File: descriptorPath, Line: 9,
+						Message: "group #1 of 'order' pins 'example/dep' to version 1.0.0, but the [[dependencies]] of " + packagePath + " provide version 2.0.0",
+					},
+					{
+						Rule: lint.RuleOrderReference, Level: lint.LevelError, File: descriptorPath, Line: 13,
+						Message: "group #1 of 'order' references 'example/missing', which isn't provided by the [[dependencies]] of " + packagePath,
+					},
+				})
+			})
+
+			it("reports unknown keys and missing URIs", func() {
+				path := writeFile("package.toml", `[buildpack]
+uri = "./missing"
+
+[platform]
+os = "linux"
+arch = "amd64"
+`, 0644)
+
+				findings, err := lint.Lint(path, "")
+				h.AssertNil(t, err)
+				h.AssertEq(t, findings, []lint.Finding{
+					{Rule: lint.RuleLocator, Level: lint.LevelError, File: path, Line: 2, Message: "'./missing' doesn't exist"},
+					{Rule: lint.RuleUnknownKey, Level: lint.LevelError, File: path, Line: 6, Message: "unknown key 'platform.arch' isn't allowed"},
+				})
+			})
+		})
+
+		when("a builder is linted", func() {
+			it("reports order references, version pins and missing images", func() {
+				writeBuildpack("dep", "example/dep", "2.0.0")
+				path := writeFile("my-builder.toml", `[[buildpacks]]
+uri = "./dep"
+version = "3.0.0"
+
+[[buildpacks]]
+id = "example/remote"
+uri = "docker://example/remote:1.0.0"
+
+[[order]]
+[[order.group]]
+id = "example/remote"
+
+[[order.group]]
+id = "example/nope"
+
+[stack]
+id = "io.buildpacks.stacks.jammy"
+build-image = "example/build"
+`, 0644)
+
+				findings, err := lint.Lint(path, lint.KindBuilder)
+				h.AssertNil(t, err)
+				h.AssertEq(t, findings, []lint.Finding{
+					{Rule: lint.RuleImages, Level: lint.LevelError, File: path, Message: "run.images are required"},
+					{Rule: lint.RuleVersionPin, Level: lint.LevelError, File: path, Line: 2, Message: "'example/dep' is pinned to version 3.0.0, but the buildpack at './dep' is version 2.0.0"},
+					{Rule: lint.RuleOrderReference, Level: lint.LevelError, File: path, Line: 14, Message: "group #1 of 'order' references 'example/nope', which isn't provided by the [[buildpacks]]"},
+					{Rule: lint.RuleImages, Level: lint.LevelWarning, File: path, Line: 16, Message: "'stack' is deprecated: declare the build image as 'build.image' and the run images as 'run.images'"},
+				})
+			})
+		})
+
+		it("errors when the kind of a file can't be told", func() {
+			path := writeFile("my-builder.toml", "", 0644)
+
+			_, err := lint.Lint(path, "")
+			h.AssertError(t, err, "can't tell the kind of "+path+" from its name: set the kind to one of buildpack, extension, package, builder")
+		})
+
+		it("errors when a directory has no file to lint", func() {
+			_, err := lint.Lint(tmpDir, "")
+			h.AssertError(t, err, "no buildpack.toml, extension.toml, package.toml or builder.toml found in "+tmpDir)
+		})
+	})
+
+	when("#WriteSARIF", func() {
+		it("writes a result for each finding", func() {
+			var buf bytes.Buffer
+			h.AssertNil(t, lint.WriteSARIF(&buf, []lint.Finding{
+				{Rule: lint.RuleOrderReference, Level: lint.LevelError, File: "builder.toml", Line: 7, Message: "some message"},
+				{Rule: lint.RuleExecutable, Level: lint.LevelWarning, File: "buildpack.toml", Message: "other message"},
+			}, "1.2.3"))
+
+			var log struct {
+				Version string `json:"version"`
+				Runs    []struct {
+					Tool struct {
+						Driver struct {
+							Name    string `json:"name"`
+							Version string `json:"version"`
+							Rules   []struct {
+								ID string `json:"id"`
+							} `json:"rules"`
+						} `json:"driver"`
+					} `json:"tool"`
+					Results []struct {
+						RuleID    string `json:"ruleId"`
+						RuleIndex int    `json:"ruleIndex"`
+						Level     string `json:"level"`
+						Message   struct {
+							Text string `json:"text"`
+						} `json:"message"`
+						Locations []struct {
+							PhysicalLocation struct {
+								ArtifactLocation struct {
+									URI string `json:"uri"`
+								} `json:"artifactLocation"`
+								Region *struct {
+									StartLine int `json:"startLine"`
+								} `json:"region"`
+							} `json:"physicalLocation"`
+						} `json:"locations"`
+					} `json:"results"`
+				} `json:"runs"`
+			}
+			h.AssertNil(t, json.Unmarshal(buf.Bytes(), &log))
+
+			h.AssertEq(t, log.Version, "2.1.0")
+			h.AssertEq(t, len(log.Runs), 1)
+			run := log.Runs[0]
+			h.AssertEq(t, run.Tool.Driver.Name, "pack")
+			h.AssertEq(t, run.Tool.Driver.Version, "1.2.3")
+			h.AssertEq(t, len(run.Tool.Driver.Rules), len(lint.Rules))
+
+			h.AssertEq(t, len(run.Results), 2)
+			first := run.Results[0]
+			h.AssertEq(t, first.RuleID, lint.RuleOrderReference)
+			h.AssertEq(t, run.Tool.Driver.Rules[first.RuleIndex].ID, lint.RuleOrderReference)
+			h.AssertEq(t, first.Level, "error")
+			h.AssertEq(t, first.Message.Text, "some message")
+			h.AssertEq(t, first.Locations[0].PhysicalLocation.ArtifactLocation.URI, "builder.toml")
+			h.AssertEq(t, first.Locations[0].PhysicalLocation.Region.StartLine, 7)
+			h.AssertNil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+		})
+	})
+}
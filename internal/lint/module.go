@@ -0,0 +1,200 @@
+package lint
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// stacksDeprecatedAPI is the first Buildpack API deprecating stacks in favor of targets
+var stacksDeprecatedAPI = api.MustParse("0.10")
+
+func (f *file) lintBuildpack() {
+	descriptor := dist.BuildpackDescriptor{}
+	moduleBlob := blob.NewBlob(f.dir())
+	undecodedKeys, err := buildpack.ReadDescriptor(buildpack.KindBuildpack, &descriptor, moduleBlob)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	f.unknownKeys(undecodedKeys, LevelWarning, "is ignored")
+	f.lintModuleInfo(buildpack.KindBuildpack, descriptor.WithInfo)
+	f.lintAPI(buildpack.KindBuildpack, descriptor.WithAPI)
+
+	if len(descriptor.WithOrder) > 0 {
+		if len(descriptor.WithStacks) > 0 || len(descriptor.WithTargets) > 0 {
+			f.add(RuleTargets, LevelError, f.lineOf("order", ""),
+				"a composite buildpack can't declare 'targets' or 'stacks': remove them, the targets of a composite buildpack are the ones of its buildpacks")
+		}
+		f.lintOrder(descriptor.WithOrder, "order", true)
+		return
+	}
+
+	if len(descriptor.WithStacks) > 0 && descriptor.WithAPI != nil && descriptor.WithAPI.AtLeast(stacksDeprecatedAPI.String()) {
+		if len(descriptor.WithTargets) > 0 {
+			f.add(RuleTargets, LevelWarning, f.lineOf("stacks", ""),
+				"'stacks' are deprecated since Buildpack API %s and 'targets' are declared: remove the stacks", stacksDeprecatedAPI)
+		} else {
+			f.add(RuleTargets, LevelWarning, f.lineOf("stacks", ""),
+				"'stacks' are deprecated since Buildpack API %s: declare 'targets' instead", stacksDeprecatedAPI)
+		}
+	}
+	f.lintTargets(descriptor.WithTargets)
+
+	entries, err := tarEntries(moduleBlob)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	f.lintExecutables(entries, []string{"detect", "build"}, nil)
+}
+
+func (f *file) lintExtension() {
+	descriptor := dist.ExtensionDescriptor{}
+	moduleBlob := blob.NewBlob(f.dir())
+	undecodedKeys, err := buildpack.ReadDescriptor(buildpack.KindExtension, &descriptor, moduleBlob)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	f.unknownKeys(undecodedKeys, LevelWarning, "is ignored")
+	f.lintModuleInfo(buildpack.KindExtension, descriptor.WithInfo)
+	f.lintAPI(buildpack.KindExtension, descriptor.WithAPI)
+	if descriptor.WithAPI != nil && descriptor.WithAPI.Compare(buildpack.MinExtensionAPI) < 0 {
+		f.add(RuleAPIVersion, LevelError, f.lineOf("api", ""),
+			"extensions require Buildpack API %s or later, found %s", buildpack.MinExtensionAPI, descriptor.WithAPI)
+	}
+	f.lintTargets(descriptor.WithTargets)
+
+	entries, err := tarEntries(moduleBlob)
+	if err != nil {
+		f.invalid(err)
+		return
+	}
+	f.lintExecutables(entries, nil, []string{"detect", "generate"})
+
+	hasDockerfiles := slices.ContainsFunc(buildpack.ExtensionDockerfileSources, func(file string) bool {
+		_, found := entries[file]
+		return found
+	})
+	if !hasDockerfiles {
+		f.add(RuleExecutable, LevelError, 0, "the extension provides no Dockerfiles: %s", buildpack.NoDockerfilesHint)
+	}
+}
+
+func (f *file) lintModuleInfo(kind string, info dist.ModuleInfo) {
+	if info.ID == "" {
+		f.add(RuleRequiredField, LevelError, f.lineOf(kind, ""), "'%s.id' is required", kind)
+	}
+	if info.Version == "" {
+		f.add(RuleRequiredField, LevelError, f.lineOf(kind, ""), "'%s.version' is required", kind)
+	}
+}
+
+func (f *file) lintAPI(kind string, version *api.Version) {
+	if version == nil {
+		f.add(RuleRequiredField, LevelError, 0,
+			"'api' is required: declare the Buildpack API the %s implements, one of %s", kind, api.Buildpack.Supported)
+		return
+	}
+	line := f.lineOf("api", "")
+	switch {
+	case !api.Buildpack.IsSupported(version):
+		f.add(RuleAPIVersion, LevelError, line,
+			"Buildpack API %s isn't supported by the lifecycle, which supports %s", version, api.Buildpack.Supported)
+	case api.Buildpack.IsDeprecated(version):
+		f.add(RuleAPIVersion, LevelWarning, line,
+			"Buildpack API %s is deprecated: migrate to %s", version, api.Buildpack.Latest())
+	}
+}
+
+func (f *file) lintTargets(targets []dist.Target) {
+	for i, target := range targets {
+		if target.OS == "" {
+			f.add(RuleTargets, LevelError, f.lineOf("targets", ""), "target #%d has no 'os'", i+1)
+		}
+		for _, distro := range target.Distributions {
+			if distro.Name == "" {
+				f.add(RuleTargets, LevelError, f.lineOf("targets.distros", ""), "a distribution of target #%d has no 'name'", i+1)
+			}
+		}
+	}
+}
+
+// lintOrder checks the shape of an order, under the key given. If versionRequired is set, every reference must
+// pin a version, as buildpack.toml requires.
+func (f *file) lintOrder(order dist.Order, key string, versionRequired bool) {
+	for i, entry := range order {
+		if len(entry.Group) == 0 {
+			f.add(RuleOrder, LevelError, f.lineOf(key, ""), "group #%d of '%s' is empty", i+1, key)
+		}
+		seen := map[string]bool{}
+		for _, ref := range entry.Group {
+			if ref.ID == "" {
+				f.add(RuleRequiredField, LevelError, f.lineOf(key+".group", ""), "a module of group #%d of '%s' has no 'id'", i+1, key)
+				continue
+			}
+			line := f.lineOf(key+".group.id", ref.ID)
+			if seen[ref.ID] {
+				f.add(RuleOrder, LevelError, line, "group #%d of '%s' lists '%s' more than once", i+1, key, ref.ID)
+			}
+			seen[ref.ID] = true
+			if versionRequired && ref.Version == "" {
+				f.add(RuleVersionPin, LevelError, line, "group #%d of '%s' references '%s' without a 'version'", i+1, key, ref.ID)
+			}
+		}
+	}
+}
+
+// lintExecutables checks that the required executables of the bin directory exist, and that every
+// executable, required or optional, has its executable bits set
+func (f *file) lintExecutables(entries map[string]int64, required, optional []string) {
+	for _, name := range append(append([]string{}, required...), optional...) {
+		binPath := path.Join("bin", name)
+		mode, found := entries[binPath]
+		if !found {
+			_, foundBat := entries[binPath+".bat"]
+			_, foundExe := entries[binPath+".exe"]
+			if !foundBat && !foundExe && slices.Contains(required, name) {
+				f.add(RuleExecutable, LevelError, 0, "'%s' is missing", binPath)
+			}
+			continue
+		}
+		// the executable bits aren't meaningful on windows, where pack sets them when packaging
+		if runtime.GOOS != "windows" && mode&0111 == 0 {
+			f.add(RuleExecutable, LevelError, 0, "'%s' isn't executable: run chmod +x %s", binPath, binPath)
+		}
+	}
+}
+
+// tarEntries returns the mode of every entry of blob, by its clean path
+func tarEntries(b buildpack.Blob) (map[string]int64, error) {
+	rc, err := b.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	entries := map[string]int64{}
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading module contents")
+		}
+		entries[path.Clean(strings.TrimPrefix(header.Name, "/"))] = header.Mode
+	}
+}
@@ -0,0 +1,113 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Level           `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF writes findings as a SARIF log, for IDEs and code review tools to annotate the files linted.
+// version is the version of pack reported as the version of the tool.
+func WriteSARIF(w io.Writer, findings []Finding, version string) error {
+	driver := sarifDriver{
+		Name:           "pack",
+		Version:        version,
+		InformationURI: "https://github.com/buildpacks/pack",
+	}
+	ruleIndex := map[string]int{}
+	for i, rule := range Rules {
+		driver.Rules = append(driver.Rules, sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}})
+		ruleIndex[rule.ID] = i
+	}
+
+	results := []sarifResult{}
+	for _, finding := range findings {
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(finding.File)},
+		}
+		if finding.Line > 0 {
+			location.Region = &sarifRegion{StartLine: finding.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    finding.Rule,
+			RuleIndex: ruleIndex[finding.Rule],
+			Level:     finding.Level,
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}); err != nil {
+		return errors.Wrap(err, "writing SARIF")
+	}
+	return nil
+}
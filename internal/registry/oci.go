@@ -0,0 +1,349 @@
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// IndexConfigMediaType is the media type of the config of the OCI artifacts holding the index of registries
+// of type oci. Their single layer is a tar of the index files, laid out as in the git index.
+const IndexConfigMediaType types.MediaType = "application/vnd.buildpacks.registry.index.config.v1+json"
+
+// NewOCIRegistryCache creates a registry cache of the index stored as an OCI artifact at indexRef, which is
+// pulled and pushed with the credentials of keychain
+func NewOCIRegistryCache(logger logging.Logger, home, indexRef string, keychain authn.Keychain) (Cache, error) {
+	if _, err := os.Stat(home); err != nil {
+		return Cache{}, errors.Wrapf(err, "finding home %s", home)
+	}
+
+	ref, err := name.ParseReference(indexRef, name.WeakValidation)
+	if err != nil {
+		return Cache{}, errors.Wrapf(err, "parsing registry index reference %s", indexRef)
+	}
+
+	key := sha256.New()
+	key.Write([]byte(ref.Name()))
+	cacheDir := fmt.Sprintf("%s-%s", defaultRegistryDir, hex.EncodeToString(key.Sum(nil)))
+
+	return Cache{
+		logger:   logger,
+		Root:     filepath.Join(home, cacheDir),
+		index:    ref,
+		keychain: keychain,
+	}, nil
+}
+
+// NewOfflineOCIRegistryCache creates a registry cache of the index of a registry of type oci pulled into home,
+// such as one in a mirror, which is never pulled again
+func NewOfflineOCIRegistryCache(logger logging.Logger, home, indexRef string) (Cache, error) {
+	cache, err := NewOCIRegistryCache(logger, home, indexRef, authn.DefaultKeychain)
+	if err != nil {
+		return Cache{}, err
+	}
+	cache.offline = true
+	return cache, nil
+}
+
+// maxIndexUpdates is how many times an update of the index of a registry of type oci is attempted, when others
+// push the index concurrently
+const maxIndexUpdates = 5
+
+// errIndexChanged is returned when the index was pushed by someone else since it was pulled
+var errIndexChanged = errors.New("registry index changed since it was pulled")
+
+// AddBuildpack adds a version of a buildpack to the index of a registry of type oci, and pushes the index
+func (r *Cache) AddBuildpack(b Buildpack) error {
+	return r.updateEntry(b.Namespace, b.Name, func(entry *Entry) error {
+		for _, existing := range entry.Buildpacks {
+			if existing.Version == b.Version {
+				return errors.Errorf("version %s of buildpack %s is already registered", style.Symbol(b.Version), style.Symbol(b.Namespace+"/"+b.Name))
+			}
+		}
+		entry.Buildpacks = append(entry.Buildpacks, b)
+		return nil
+	})
+}
+
+// YankBuildpack marks a version of a buildpack in the index of a registry of type oci as yanked, or as not
+// yanked, according to b.Yanked, and pushes the index
+func (r *Cache) YankBuildpack(b Buildpack) error {
+	return r.updateEntry(b.Namespace, b.Name, func(entry *Entry) error {
+		for i := range entry.Buildpacks {
+			if entry.Buildpacks[i].Version == b.Version {
+				entry.Buildpacks[i].Yanked = b.Yanked
+				return nil
+			}
+		}
+		return errors.Errorf("could not find version %s of buildpack %s", style.Symbol(b.Version), style.Symbol(b.Namespace+"/"+b.Name))
+	})
+}
+
+// updateEntry pulls the index, updates the entry of the buildpack, which is empty when it isn't in the index, and
+// pushes the index. The index is only pushed when it's still the index that was pulled, so that entries pushed
+// concurrently by others aren't lost; otherwise the update is made again on the index they pushed.
+func (r *Cache) updateEntry(ns, name string, update func(entry *Entry) error) error {
+	if r.index == nil {
+		return errors.New("only registries of type oci can be written to directly")
+	}
+	if r.offline {
+		return errors.New("registries can't be written to in offline mode")
+	}
+
+	for attempt := 1; ; attempt++ {
+		pulled, err := r.pullIndexDigest()
+		if err != nil {
+			return err
+		}
+
+		entry, err := r.cachedEntry(ns, name)
+		if err != nil {
+			return err
+		}
+		if err := update(&entry); err != nil {
+			return err
+		}
+
+		err = r.pushEntry(ns, name, entry, pulled)
+		if !errors.Is(err, errIndexChanged) {
+			return err
+		}
+		if attempt == maxIndexUpdates {
+			return errors.Wrapf(err, "pushing registry index %s after %d attempts", style.Symbol(r.index.String()), attempt)
+		}
+		r.logger.Debugf("Registry index %s changed since it was pulled, updating it again", style.Symbol(r.index.String()))
+	}
+}
+
+// cachedEntry returns the entry of the buildpack in the cache, which is empty when it isn't in the index
+func (r *Cache) cachedEntry(ns, name string) (Entry, error) {
+	index, err := IndexPath(r.Root, ns, name)
+	if err != nil {
+		return Entry{}, err
+	}
+	if _, err := os.Stat(index); os.IsNotExist(err) {
+		return Entry{}, nil
+	}
+	return r.readEntry(ns, name)
+}
+
+// pushEntry replaces the entry of the buildpack in the index, and pushes the index if it's still the index with
+// the digest pulled
+func (r *Cache) pushEntry(ns, name string, entry Entry, pulled v1.Hash) error {
+	index, err := IndexPath(r.Root, ns, name)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, bp := range entry.Buildpacks {
+		line, err := json.Marshal(bp)
+		if err != nil {
+			return errors.Wrapf(err, "converting buildpack file to json: %s/%s", ns, name)
+		}
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(index), 0750); err != nil {
+		return errors.Wrapf(err, "creating directory structure for: %s/%s", ns, name)
+	}
+	if err := os.WriteFile(index, buf.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "writing buildpack to file: %s/%s", ns, name)
+	}
+
+	return r.pushIndex(pulled)
+}
+
+// pullIndex replaces the contents of the cache with the index pulled from the registry. An index that
+// doesn't exist yet is empty.
+func (r *Cache) pullIndex() error {
+	_, err := r.pullIndexDigest()
+	return err
+}
+
+// pullIndexDigest pulls the index as pullIndex does, and returns the digest of its manifest, which is zero when
+// the index doesn't exist yet
+func (r *Cache) pullIndexDigest() (v1.Hash, error) {
+	r.logger.Debugf("Pulling registry index %s", style.Symbol(r.index.String()))
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(r.Root), "registry")
+	if err != nil {
+		return v1.Hash{}, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var digest v1.Hash
+	img, err := remote.Image(r.index, remote.WithAuthFromKeychain(r.keychain))
+	switch {
+	case isNotFound(err):
+		r.logger.Debugf("Registry index %s doesn't exist yet", style.Symbol(r.index.String()))
+	case err != nil:
+		return v1.Hash{}, errors.Wrapf(err, "pulling registry index %s", style.Symbol(r.index.String()))
+	default:
+		if digest, err = img.Digest(); err != nil {
+			return v1.Hash{}, errors.Wrapf(err, "pulling registry index %s", style.Symbol(r.index.String()))
+		}
+		if err := extractIndex(img, tmpDir); err != nil {
+			return v1.Hash{}, errors.Wrapf(err, "extracting registry index %s", style.Symbol(r.index.String()))
+		}
+	}
+
+	if err := os.RemoveAll(r.Root); err != nil {
+		return v1.Hash{}, errors.Wrap(err, "resetting registry cache")
+	}
+	return digest, os.Rename(tmpDir, r.Root)
+}
+
+// pushIndex pushes the contents of the cache to the registry, unless the index in the registry no longer has the
+// digest pulled. Registries can't make the push conditional, so a push in between the check and the push is
+// still lost, but that window is much narrower than the one since the pull.
+func (r *Cache) pushIndex(pulled v1.Hash) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	err := filepath.WalkDir(r.Root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(r.Root, filePath)
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(filepath.Clean(filePath))
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     filepath.ToSlash(rel),
+			Mode:     0644,
+			Size:     int64(len(contents)),
+		}); err != nil {
+			return err
+		}
+		_, err = tw.Write(contents)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "archiving registry index")
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "archiving registry index")
+	}
+
+	img, err := mutate.AppendLayers(empty.Image, static.NewLayer(buf.Bytes(), types.OCIUncompressedLayer))
+	if err != nil {
+		return err
+	}
+	img = mutate.ConfigMediaType(mutate.MediaType(img, types.OCIManifestSchema1), IndexConfigMediaType)
+
+	var current v1.Hash
+	desc, err := remote.Head(r.index, remote.WithAuthFromKeychain(r.keychain))
+	switch {
+	case isNotFound(err):
+	case err != nil:
+		return errors.Wrapf(err, "checking registry index %s", style.Symbol(r.index.String()))
+	default:
+		current = desc.Digest
+	}
+	if current != pulled {
+		return errIndexChanged
+	}
+
+	r.logger.Debugf("Pushing registry index %s", style.Symbol(r.index.String()))
+	if err := remote.Write(r.index, img, remote.WithAuthFromKeychain(r.keychain)); err != nil {
+		return errors.Wrapf(err, "pushing registry index %s", style.Symbol(r.index.String()))
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
+
+// extractIndex extracts the index files in the layer of img into dir
+func extractIndex(img v1.Image, dir string) error {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return err
+	}
+	if manifest.Config.MediaType != IndexConfigMediaType {
+		return errors.Errorf("it isn't a registry index: its config has media type %s instead of %s", style.Symbol(string(manifest.Config.MediaType)), style.Symbol(string(IndexConfigMediaType)))
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	if len(layers) != 1 {
+		return errors.Errorf("it has %d layers instead of 1", len(layers))
+	}
+
+	rc, err := layers[0].Uncompressed()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.Errorf("invalid path %s", style.Symbol(header.Name))
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(filepath.Clean(target), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, tr); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package registry
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestOCIRegistryCache(t *testing.T) {
+	color.Disable(true)
+	spec.Run(t, "OCIRegistryCache", testOCIRegistryCache, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testOCIRegistryCache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server        *httptest.Server
+		indexRef      string
+		registryCache Cache
+		logger        logging.Logger
+		outBuf        bytes.Buffer
+		fooV1         = Buildpack{Namespace: "example", Name: "foo", Version: "1.0.0", Address: "example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566"}
+		fooV2         = Buildpack{Namespace: "example", Name: "foo", Version: "2.0.0", Address: "example.com/some/package@sha256:74eb48882e835d8767f62940d453eb96ed2737de3a16573881dcea7dea769df7"}
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(ggcrregistry.New(ggcrregistry.Logger(log.New(io.Discard, "", 0))))
+		indexRef = strings.TrimPrefix(server.URL, "http://") + "/buildpacks/index:latest"
+		logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+
+		var err error
+		registryCache, err = NewOCIRegistryCache(logger, t.TempDir(), indexRef, authn.DefaultKeychain)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#NewOCIRegistryCache", func() {
+		it("fails for invalid references", func() {
+			_, err := NewOCIRegistryCache(logger, t.TempDir(), "Invalid Reference", authn.DefaultKeychain)
+			h.AssertError(t, err, "parsing registry index reference")
+		})
+	})
+
+	when("#AddBuildpack", func() {
+		it("pushes the buildpack to the index, for other caches to locate it", func() {
+			h.AssertNil(t, registryCache.AddBuildpack(fooV1))
+			h.AssertNil(t, registryCache.AddBuildpack(fooV2))
+
+			other, err := NewOCIRegistryCache(logger, t.TempDir(), indexRef, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			bp, err := other.LocateBuildpack("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp, fooV2)
+
+			bp, err = other.LocateBuildpack("example/foo@1.0.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp, fooV1)
+		})
+
+		it("errors when the version is already registered", func() {
+			h.AssertNil(t, registryCache.AddBuildpack(fooV1))
+
+			err := registryCache.AddBuildpack(fooV1)
+			h.AssertError(t, err, "version '1.0.0' of buildpack 'example/foo' is already registered")
+		})
+
+		it("keeps the entries another writer pushes in between the pull and the push", func() {
+			registryHandler := ggcrregistry.New(ggcrregistry.Logger(log.New(io.Discard, "", 0)))
+			var intercepted atomic.Bool
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				// the other writer pushes its entry right before the index is checked to be pushed
+				if req.Method == http.MethodHead && strings.Contains(req.URL.Path, "/manifests/") && intercepted.CompareAndSwap(false, true) {
+					other, err := NewOCIRegistryCache(logger, t.TempDir(), indexRef, authn.DefaultKeychain)
+					h.AssertNil(t, err)
+					h.AssertNil(t, other.AddBuildpack(fooV2))
+				}
+				registryHandler.ServeHTTP(w, req)
+			})
+
+			h.AssertNil(t, registryCache.AddBuildpack(fooV1))
+
+			other, err := NewOCIRegistryCache(logger, t.TempDir(), indexRef, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			bp, err := other.LocateBuildpack("example/foo@1.0.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp, fooV1)
+			bp, err = other.LocateBuildpack("example/foo@2.0.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp, fooV2)
+		})
+
+		it("errors for git registries", func() {
+			gitCache, err := NewRegistryCache(logger, t.TempDir(), "https://github.com/buildpacks/registry-index")
+			h.AssertNil(t, err)
+
+			err = gitCache.AddBuildpack(fooV1)
+			h.AssertError(t, err, "only registries of type oci can be written to directly")
+		})
+	})
+
+	when("#YankBuildpack", func() {
+		it.Before(func() {
+			h.AssertNil(t, registryCache.AddBuildpack(fooV1))
+			h.AssertNil(t, registryCache.AddBuildpack(fooV2))
+		})
+
+		it("marks the version as yanked, and back", func() {
+			yanked := fooV2
+			yanked.Yanked = true
+			h.AssertNil(t, registryCache.YankBuildpack(yanked))
+
+			versions, err := registryCache.Versions("example/foo", false)
+			h.AssertNil(t, err)
+			h.AssertEq(t, versions, []Buildpack{fooV1})
+
+			h.AssertNil(t, registryCache.YankBuildpack(fooV2))
+			versions, err = registryCache.Versions("example/foo", false)
+			h.AssertNil(t, err)
+			h.AssertEq(t, versions, []Buildpack{fooV2, fooV1})
+		})
+
+		it("errors for versions that aren't registered", func() {
+			err := registryCache.YankBuildpack(Buildpack{Namespace: "example", Name: "foo", Version: "3.0.0", Yanked: true})
+			h.AssertError(t, err, "could not find version '3.0.0' of buildpack 'example/foo'")
+		})
+	})
+
+	when("#Refresh", func() {
+		it("pulls an empty index when it doesn't exist yet", func() {
+			h.AssertNil(t, registryCache.Refresh())
+
+			results, err := registryCache.Search("", false)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(results), 0)
+		})
+
+		it("errors when the artifact isn't a registry index", func() {
+			ref, err := name.ParseReference(indexRef)
+			h.AssertNil(t, err)
+			img, err := random.Image(10, 1)
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.Write(ref, img))
+
+			h.AssertError(t, registryCache.Refresh(), "it isn't a registry index")
+		})
+	})
+
+	when("offline", func() {
+		it("locates buildpacks in the index pulled before", func() {
+			home := t.TempDir()
+			online, err := NewOCIRegistryCache(logger, home, indexRef, authn.DefaultKeychain)
+			h.AssertNil(t, err)
+			h.AssertNil(t, online.AddBuildpack(fooV1))
+			server.Close()
+
+			offline, err := NewOfflineOCIRegistryCache(logger, home, indexRef)
+			h.AssertNil(t, err)
+			bp, err := offline.LocateBuildpack("example/foo")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp, fooV1)
+
+			err = offline.AddBuildpack(fooV2)
+			h.AssertError(t, err, "registries can't be written to in offline mode")
+		})
+
+		it("errors when the index wasn't pulled", func() {
+			offline, err := NewOfflineOCIRegistryCache(logger, t.TempDir(), indexRef)
+			h.AssertNil(t, err)
+
+			_, err = offline.LocateBuildpack("example/foo")
+			h.AssertError(t, err, "isn't in the mirror")
+		})
+	})
+}
@@ -15,6 +15,8 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 
@@ -33,6 +35,11 @@ type Cache struct {
 	url         *url.URL
 	Root        string
 	RegistryDir string
+	offline     bool
+
+	// index and keychain are set for registries of type oci, whose index is an OCI artifact instead of a git repository
+	index    name.Reference
+	keychain authn.Keychain
 }
 
 const GithubIssueTitleTemplate = "{{ if .Yanked }}YANK{{ else }}ADD{{ end }} {{.Namespace}}/{{.Name}}@{{.Version}}"
@@ -75,11 +82,21 @@ func NewRegistryCache(logger logging.Logger, home, registryURL string) (Cache, e
 	}, nil
 }
 
+// NewOfflineRegistryCache creates a registry cache of a clone of the registry in home, such as one in a mirror,
+// which is never refreshed
+func NewOfflineRegistryCache(logger logging.Logger, home, registryURL string) (Cache, error) {
+	cache, err := NewRegistryCache(logger, home, registryURL)
+	if err != nil {
+		return Cache{}, err
+	}
+	cache.offline = true
+	return cache, nil
+}
+
 // LocateBuildpack stored in registry
 func (r *Cache) LocateBuildpack(bp string) (Buildpack, error) {
-	err := r.Refresh()
-	if err != nil {
-		return Buildpack{}, errors.Wrap(err, "refreshing cache")
+	if err := r.sync(); err != nil {
+		return Buildpack{}, err
 	}
 
 	ns, name, version, err := buildpack.ParseRegistryID(bp)
@@ -116,8 +133,31 @@ func (r *Cache) LocateBuildpack(bp string) (Buildpack, error) {
 	return Buildpack{}, fmt.Errorf("no entries for buildpack: %s", bp)
 }
 
+// sync refreshes the cache, unless it's offline, in which case it only checks it exists
+func (r *Cache) sync() error {
+	if r.offline {
+		if _, err := os.Stat(r.Root); err != nil {
+			return errors.Errorf("registry %s isn't in the mirror at %s: add it to the mirror config and run 'pack mirror sync'", style.Symbol(r.location()), style.Symbol(filepath.Dir(r.Root)))
+		}
+		return nil
+	}
+	return errors.Wrap(r.Refresh(), "refreshing cache")
+}
+
+// location returns the URL of the git repository, or the reference of the OCI artifact, holding the index
+func (r *Cache) location() string {
+	if r.index != nil {
+		return r.index.String()
+	}
+	return r.url.String()
+}
+
 // Refresh local Registry Cache
 func (r *Cache) Refresh() error {
+	if r.index != nil {
+		return r.pullIndex()
+	}
+
 	r.logger.Debugf("Refreshing registry cache for %s/%s", r.url.Host, r.url.Path)
 
 	if err := r.Initialize(); err != nil {
@@ -89,6 +89,36 @@ func testRegistryCache(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("#NewOfflineRegistryCache", func() {
+		var mirrorDir string
+
+		it.Before(func() {
+			mirrorDir = filepath.Join(tmpDir, "mirror")
+			h.AssertNil(t, os.MkdirAll(mirrorDir, 0750))
+		})
+
+		it("locates a buildpack in a clone of the registry without refreshing it", func() {
+			clone, err := NewRegistryCache(logger, mirrorDir, registryFixture)
+			h.AssertNil(t, err)
+			h.AssertNil(t, clone.Refresh())
+			h.AssertNil(t, os.RemoveAll(registryFixture))
+
+			registryCache, err := NewOfflineRegistryCache(logger, mirrorDir, registryFixture)
+			h.AssertNil(t, err)
+			bp, err := registryCache.LocateBuildpack("example/foo@1.1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, bp.Version, "1.1.0")
+		})
+
+		it("errors when the registry isn't cloned", func() {
+			registryCache, err := NewOfflineRegistryCache(logger, mirrorDir, registryFixture)
+			h.AssertNil(t, err)
+			_, err = registryCache.LocateBuildpack("example/foo")
+			h.AssertError(t, err, "isn't in the mirror")
+			h.AssertError(t, err, "run 'pack mirror sync'")
+		})
+	})
+
 	when("#LocateBuildpack", func() {
 		var (
 			registryCache Cache
@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Search returns the latest version of each buildpack in the registry whose `ns/name` id contains query,
+// sorted by id. Yanked versions are skipped unless includeYanked is set.
+func (r *Cache) Search(query string, includeYanked bool) ([]Buildpack, error) {
+	if err := r.sync(); err != nil {
+		return nil, err
+	}
+
+	ids, err := r.indexIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+	var results []Buildpack
+	for _, id := range ids {
+		if !strings.Contains(id, query) {
+			continue
+		}
+
+		versions, err := r.versions(id, includeYanked)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) > 0 {
+			results = append(results, versions[0])
+		}
+	}
+	return results, nil
+}
+
+// Versions returns the versions of the buildpack with the `ns/name` id, sorted from the newest to the oldest
+// by semver. Yanked versions are skipped unless includeYanked is set.
+func (r *Cache) Versions(id string, includeYanked bool) ([]Buildpack, error) {
+	if err := r.sync(); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := ParseNamespaceName(id); err != nil {
+		return nil, err
+	}
+
+	versions, err := r.versions(id, includeYanked)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, errors.Errorf("no versions of buildpack %s found", style.Symbol(id))
+	}
+	return versions, nil
+}
+
+func (r *Cache) versions(id string, includeYanked bool) ([]Buildpack, error) {
+	ns, name, err := ParseNamespaceName(id)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := r.readEntry(ns, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading entry")
+	}
+
+	var versions []Buildpack
+	for _, bp := range entry.Buildpacks {
+		if bp.Yanked && !includeYanked {
+			continue
+		}
+		versions = append(versions, bp)
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return semver.Compare(fmt.Sprintf("v%s", versions[i].Version), fmt.Sprintf("v%s", versions[j].Version)) > 0
+	})
+	return versions, nil
+}
+
+// indexIDs returns the `ns/name` ids of the buildpacks in the index, sorted
+func (r *Cache) indexIDs() ([]string, error) {
+	var ids []string
+	err := filepath.WalkDir(r.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && path != r.Root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// index files are named `<ns>_<name>`, neither of which may contain an underscore
+		ns, name, ok := strings.Cut(d.Name(), "_")
+		if !ok || validateField("namespace", ns) != nil || validateField("name", name) != nil {
+			return nil
+		}
+		if index, err := IndexPath(r.Root, ns, name); err != nil || index != path {
+			return nil
+		}
+		ids = append(ids, ns+"/"+name)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading registry index %s", style.Symbol(r.Root))
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSearch(t *testing.T) {
+	color.Disable(true)
+	spec.Run(t, "Search", testSearch, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSearch(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir        string
+		registryCache Cache
+		outBuf        bytes.Buffer
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "registry")
+		h.AssertNil(t, err)
+
+		logger := logging.NewLogWithWriters(&outBuf, &outBuf)
+		registryFixture := h.CreateRegistryFixture(t, tmpDir, filepath.Join("..", "..", "testdata", "registry"))
+		clone, err := NewRegistryCache(logger, tmpDir, registryFixture)
+		h.AssertNil(t, err)
+		h.AssertNil(t, clone.Initialize())
+		h.AssertNil(t, clone.Commit(Buildpack{Namespace: "example", Name: "foo", Version: "1.10.0", Yanked: true}, "user", "YANK example/foo@1.10.0"))
+
+		// the clone isn't refreshed offline, which would discard the yanked version committed to it
+		registryCache, err = NewOfflineRegistryCache(logger, tmpDir, registryFixture)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	when("#Search", func() {
+		it("returns the latest version of the matching buildpacks, sorted by id", func() {
+			results, err := registryCache.Search("EXAMPLE", false)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(results), 2)
+			h.AssertEq(t, results[0].Name, "foo")
+			h.AssertEq(t, results[0].Version, "1.2.0")
+			h.AssertEq(t, results[1].Name, "java")
+		})
+
+		it("includes yanked versions when asked to", func() {
+			results, err := registryCache.Search("foo", true)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(results), 1)
+			h.AssertEq(t, results[0].Version, "1.10.0")
+			h.AssertEq(t, results[0].Yanked, true)
+		})
+
+		it("returns nothing when no buildpack matches", func() {
+			results, err := registryCache.Search("python", false)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(results), 0)
+		})
+	})
+
+	when("#Versions", func() {
+		it("returns the versions sorted by semver, newest first", func() {
+			versions, err := registryCache.Versions("example/foo", true)
+			h.AssertNil(t, err)
+
+			var actual []string
+			for _, bp := range versions {
+				actual = append(actual, bp.Version)
+			}
+			h.AssertEq(t, actual, []string{"1.10.0", "1.2.0", "1.1.0", "1.0.0"})
+		})
+
+		it("skips yanked versions", func() {
+			versions, err := registryCache.Versions("example/foo", false)
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(versions), 3)
+			h.AssertEq(t, versions[0].Version, "1.2.0")
+		})
+
+		it("errors for invalid ids", func() {
+			_, err := registryCache.Versions("quack", false)
+			h.AssertError(t, err, "does not contain a namespace")
+		})
+
+		it("errors for buildpacks that aren't in the registry", func() {
+			_, err := registryCache.Versions("example/qu", false)
+			h.AssertError(t, err, "reading entry")
+		})
+	})
+}
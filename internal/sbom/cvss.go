@@ -0,0 +1,96 @@
+package sbom
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// cvss3Weights are the metric weights of the CVSS v3 specification
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3BaseScore computes the base score of a CVSS v3 vector, such as 'CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H'
+func cvss3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, errors.Errorf("not a CVSS v3 vector: %q", vector)
+	}
+
+	metrics := map[string]string{}
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return 0, errors.Errorf("invalid metric %q in CVSS vector %q", part, vector)
+		}
+		metrics[key] = value
+	}
+
+	values := map[string]float64{}
+	for metric, weights := range cvss3Weights {
+		weight, ok := weights[metrics[metric]]
+		if !ok {
+			return 0, errors.Errorf("missing or invalid metric %s in CVSS vector %q", metric, vector)
+		}
+		values[metric] = weight
+	}
+
+	changed := false
+	switch metrics["S"] {
+	case "U":
+	case "C":
+		changed = true
+	default:
+		return 0, errors.Errorf("missing or invalid metric S in CVSS vector %q", vector)
+	}
+
+	var privileges float64
+	switch metrics["PR"] {
+	case "N":
+		privileges = 0.85
+	case "L":
+		privileges = 0.62
+		if changed {
+			privileges = 0.68
+		}
+	case "H":
+		privileges = 0.27
+		if changed {
+			privileges = 0.5
+		}
+	default:
+		return 0, errors.Errorf("missing or invalid metric PR in CVSS vector %q", vector)
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * values["AV"] * values["AC"] * privileges * values["UI"]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp returns the smallest number with one decimal that is equal to or higher than value,
+// as defined by the CVSS v3.1 specification
+func roundUp(value float64) float64 {
+	i := int(math.Round(value * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
@@ -0,0 +1,44 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCVSS(t *testing.T) {
+	spec.Run(t, "CVSS", testCVSS, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testCVSS(t *testing.T, when spec.G, it spec.S) {
+	when("#cvss3BaseScore", func() {
+		for _, tc := range []struct {
+			vector   string
+			expected float64
+		}{
+			{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+			{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", 9.9},
+			{"CVSS:3.0/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.8},
+			{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+			{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0},
+		} {
+			vector, expected := tc.vector, tc.expected
+			it("scores "+vector, func() {
+				score, err := cvss3BaseScore(vector)
+				h.AssertNil(t, err)
+				h.AssertEq(t, score, expected)
+			})
+		}
+
+		it("errors on other vectors", func() {
+			_, err := cvss3BaseScore("AV:N/AC:L/Au:N/C:P/I:P/A:P")
+			h.AssertError(t, err, "not a CVSS v3 vector")
+
+			_, err = cvss3BaseScore("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H")
+			h.AssertError(t, err, "missing or invalid metric A")
+		})
+	})
+}
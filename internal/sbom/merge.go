@@ -0,0 +1,181 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Document is a merged SBOM describing all the packages of an image
+type Document struct {
+	// Name is the name of the image
+	Name        string
+	Created     time.Time
+	ToolName    string
+	ToolVersion string
+	Packages    []AttributedPackage
+}
+
+// AttributedPackage is a package along with where it was found
+type AttributedPackage struct {
+	Package
+	Sources []Source
+}
+
+// Source is an SBOM that listed a package
+type Source struct {
+	Buildpack string
+	// Layer is empty for SBOMs describing a whole buildpack
+	Layer string
+	// Scope is either launch, build or cache
+	Scope string
+}
+
+// Encode writes doc as a single SBOM document in the given format
+func Encode(w io.Writer, format Format, doc Document) error {
+	var out interface{}
+	switch format {
+	case FormatCycloneDX:
+		out = cycloneDXDocument(doc)
+	case FormatSPDX:
+		out = spdxDocument(doc)
+	default:
+		return errors.Errorf("merged SBOMs can only be written as %s or %s, got %s", FormatCycloneDX, FormatSPDX, format)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func cycloneDXDocument(doc Document) interface{} {
+	type tool struct {
+		Type    string `json:"type"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	type metadata struct {
+		Timestamp string `json:"timestamp"`
+		Tools     struct {
+			Components []tool `json:"components"`
+		} `json:"tools"`
+		Component cdxComponent `json:"component"`
+	}
+
+	meta := metadata{
+		Timestamp: doc.Created.UTC().Format(time.RFC3339),
+		Component: cdxComponent{Type: "container", Name: doc.Name},
+	}
+	meta.Tools.Components = []tool{{Type: "application", Name: doc.ToolName, Version: doc.ToolVersion}}
+
+	components := []cdxComponent{}
+	for i, pkg := range doc.Packages {
+		component := cdxComponent{
+			Type:    "library",
+			BOMRef:  fmt.Sprintf("pkg-%d", i+1),
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			PURL:    pkg.PURL,
+		}
+		for _, source := range pkg.Sources {
+			component.Properties = append(component.Properties, cdxProperty{Name: "io.buildpacks.buildpack", Value: source.Buildpack})
+			if source.Layer != "" {
+				component.Properties = append(component.Properties, cdxProperty{Name: "io.buildpacks.layer", Value: source.Layer})
+			}
+			component.Properties = append(component.Properties, cdxProperty{Name: "io.buildpacks.scope", Value: source.Scope})
+		}
+		components = append(components, component)
+	}
+
+	return struct {
+		BOMFormat   string         `json:"bomFormat"`
+		SpecVersion string         `json:"specVersion"`
+		Version     int            `json:"version"`
+		Metadata    metadata       `json:"metadata"`
+		Components  []cdxComponent `json:"components"`
+	}{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata:    meta,
+		Components:  components,
+	}
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func spdxDocument(doc Document) interface{} {
+	packages := []spdxPackage{}
+	relationships := []spdxRelationship{}
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%s\x00%s\x00", doc.Name, doc.Created.UTC().Format(time.RFC3339Nano))
+	for i, pkg := range doc.Packages {
+		p := spdxPackage{
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			Name:             pkg.Name,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: "NOASSERTION",
+		}
+		if pkg.PURL != "" {
+			p.ExternalRefs = []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: pkg.PURL}}
+		}
+		for _, source := range pkg.Sources {
+			if p.Comment != "" {
+				p.Comment += "; "
+			}
+			p.Comment += fmt.Sprintf("contributed by %s", sourceDescription(source))
+		}
+		packages = append(packages, p)
+		relationships = append(relationships, spdxRelationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: p.SPDXID,
+		})
+		fmt.Fprintf(hasher, "%s\x00%s\x00%s\x00", pkg.Name, pkg.Version, pkg.PURL)
+	}
+
+	return struct {
+		SPDXVersion       string             `json:"spdxVersion"`
+		DataLicense       string             `json:"dataLicense"`
+		SPDXID            string             `json:"SPDXID"`
+		Name              string             `json:"name"`
+		DocumentNamespace string             `json:"documentNamespace"`
+		CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+		Packages          []spdxPackage      `json:"packages"`
+		Relationships     []spdxRelationship `json:"relationships"`
+	}{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              doc.Name,
+		DocumentNamespace: fmt.Sprintf("https://buildpacks.io/spdxdocs/%s-%s", doc.Name, hex.EncodeToString(hasher.Sum(nil))[:16]),
+		CreationInfo: spdxCreationInfo{
+			Created:  doc.Created.UTC().Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: %s-%s", doc.ToolName, doc.ToolVersion)},
+		},
+		Packages:      packages,
+		Relationships: relationships,
+	}
+}
+
+func sourceDescription(source Source) string {
+	description := source.Buildpack
+	if source.Layer != "" {
+		description += " layer " + source.Layer
+	}
+	return fmt.Sprintf("%s (%s)", description, source.Scope)
+}
@@ -0,0 +1,450 @@
+package sbom
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+)
+
+// Severity of a vulnerability, ordered from least to most severe
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityUnknown:  "unknown",
+	SeverityLow:      "low",
+	SeverityMedium:   "medium",
+	SeverityHigh:     "high",
+	SeverityCritical: "critical",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity parses a severity name. 'moderate', as used by GitHub advisories, is the same as 'medium'.
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(name) {
+	case "low":
+		return SeverityLow, nil
+	case "medium", "moderate":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityUnknown, errors.Errorf("unknown severity %q", name)
+	}
+}
+
+func severityOfScore(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// Vulnerability is a vulnerability affecting a package
+type Vulnerability struct {
+	ID       string
+	Aliases  []string
+	Summary  string
+	Severity Severity
+	// FixedVersion is the first version of the package that isn't affected, if known
+	FixedVersion string
+}
+
+// osvRecord is a vulnerability in the OSV format, see https://ossf.github.io/osv-schema/
+type osvRecord struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Withdrawn string   `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+			PURL      string `json:"purl"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced   string `json:"introduced"`
+				Fixed        string `json:"fixed"`
+				LastAffected string `json:"last_affected"`
+			} `json:"events"`
+		} `json:"ranges"`
+		Versions          []string `json:"versions"`
+		EcosystemSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// Database is an offline vulnerability database
+type Database struct {
+	// records are indexed by package, see packageKeys
+	records map[string][]*osvRecord
+}
+
+// LoadDatabase loads the OSV records stored as JSON files in dir, such as an extracted
+// 'all.zip' export of https://osv.dev. Withdrawn records are ignored.
+func LoadDatabase(dir string) (*Database, error) {
+	db := &Database{records: map[string][]*osvRecord{}}
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		record := &osvRecord{}
+		if err := json.Unmarshal(data, record); err != nil {
+			return errors.Wrapf(err, "decoding OSV record %s", path)
+		}
+		if record.ID == "" || record.Withdrawn != "" {
+			return nil
+		}
+
+		for _, affected := range record.Affected {
+			keys := map[string]bool{}
+			if affected.Package.Ecosystem != "" && affected.Package.Name != "" {
+				keys[ecosystemKey(affected.Package.Ecosystem, affected.Package.Name)] = true
+			}
+			if purl, ok := parsePURL(affected.Package.PURL); ok {
+				keys[purl.base()] = true
+			}
+			for key := range keys {
+				if n := len(db.records[key]); n == 0 || db.records[key][n-1] != record {
+					db.records[key] = append(db.records[key], record)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading vulnerability database from %s", dir)
+	}
+	return db, nil
+}
+
+// Size returns the number of packages with known vulnerabilities
+func (db *Database) Size() int {
+	return len(db.records)
+}
+
+// Match returns the vulnerabilities affecting pkg, based on its purl
+func (db *Database) Match(pkg Package) []Vulnerability {
+	purl, ok := parsePURL(pkg.PURL)
+	if !ok || purl.version == "" {
+		return nil
+	}
+
+	seen := map[*osvRecord]bool{}
+	var vulnerabilities []Vulnerability
+	for _, key := range purl.keys() {
+		for _, record := range db.records[key] {
+			if seen[record] {
+				continue
+			}
+			seen[record] = true
+
+			if vulnerability, ok := record.match(purl); ok {
+				vulnerabilities = append(vulnerabilities, vulnerability)
+			}
+		}
+	}
+	sort.Slice(vulnerabilities, func(i, j int) bool {
+		return vulnerabilities[i].ID < vulnerabilities[j].ID
+	})
+	return vulnerabilities
+}
+
+func (r *osvRecord) match(purl packageURL) (Vulnerability, bool) {
+	keys := map[string]bool{}
+	for _, key := range purl.keys() {
+		keys[key] = true
+	}
+
+	for _, affected := range r.Affected {
+		affectedPURL, _ := parsePURL(affected.Package.PURL)
+		if !keys[ecosystemKey(affected.Package.Ecosystem, affected.Package.Name)] && !keys[affectedPURL.base()] {
+			continue
+		}
+
+		isAffected := false
+		for _, version := range affected.Versions {
+			if version == purl.version {
+				isAffected = true
+				break
+			}
+		}
+
+		fixed := ""
+		for _, rng := range affected.Ranges {
+			if rng.Type != "SEMVER" && rng.Type != "ECOSYSTEM" {
+				continue
+			}
+
+			inRange := false
+			for _, event := range rng.Events {
+				switch {
+				case event.Introduced != "":
+					if event.Introduced == "0" || compareVersions(purl.version, event.Introduced) >= 0 {
+						inRange = true
+					}
+				case event.Fixed != "":
+					if compareVersions(purl.version, event.Fixed) >= 0 {
+						inRange = false
+					} else if inRange && fixed == "" {
+						fixed = event.Fixed
+					}
+				case event.LastAffected != "":
+					if compareVersions(purl.version, event.LastAffected) > 0 {
+						inRange = false
+					}
+				}
+			}
+			isAffected = isAffected || inRange
+		}
+		if !isAffected {
+			continue
+		}
+
+		severity := r.severity()
+		if severity == SeverityUnknown {
+			severity, _ = ParseSeverity(affected.EcosystemSpecific.Severity)
+		}
+		return Vulnerability{
+			ID:           r.ID,
+			Aliases:      r.Aliases,
+			Summary:      r.Summary,
+			Severity:     severity,
+			FixedVersion: fixed,
+		}, true
+	}
+	return Vulnerability{}, false
+}
+
+// severity is the severity of the CVSS v3 vector of the record, or else the severity given by the database
+func (r *osvRecord) severity() Severity {
+	for _, severity := range r.Severity {
+		if severity.Type != "CVSS_V3" {
+			continue
+		}
+		if score, err := cvss3BaseScore(severity.Score); err == nil {
+			return severityOfScore(score)
+		}
+	}
+	severity, _ := ParseSeverity(r.DatabaseSpecific.Severity)
+	return severity
+}
+
+// compareVersions compares two versions, as semantic versions when possible, or else
+// segment by segment, comparing numeric segments as numbers
+func compareVersions(a, b string) int {
+	if va, err := semver.NewVersion(a); err == nil {
+		if vb, err := semver.NewVersion(b); err == nil {
+			return va.Compare(vb)
+		}
+	}
+
+	sa, sb := versionSegments(a), versionSegments(b)
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		if c := compareSegments(sa[i], sb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(sa) < len(sb):
+		return -1
+	case len(sa) > len(sb):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func versionSegments(version string) []string {
+	version = strings.TrimPrefix(version, "v")
+	var segments []string
+	current := ""
+	for _, r := range version {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if current != "" {
+				segments = append(segments, current)
+			}
+			current = ""
+			continue
+		}
+		if current != "" && unicode.IsDigit(r) != unicode.IsDigit(rune(current[len(current)-1])) {
+			segments = append(segments, current)
+			current = ""
+		}
+		current += string(r)
+	}
+	if current != "" {
+		segments = append(segments, current)
+	}
+	return segments
+}
+
+func compareSegments(a, b string) int {
+	aNumeric, bNumeric := unicode.IsDigit(rune(a[0])), unicode.IsDigit(rune(b[0]))
+	switch {
+	case aNumeric && bNumeric:
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return 1
+	case bNumeric:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// purlEcosystems maps purl types to OSV ecosystems
+var purlEcosystems = map[string]string{
+	"apk":      "Alpine",
+	"cargo":    "crates.io",
+	"composer": "Packagist",
+	"deb":      "Debian",
+	"gem":      "RubyGems",
+	"golang":   "Go",
+	"hex":      "Hex",
+	"maven":    "Maven",
+	"npm":      "npm",
+	"nuget":    "NuGet",
+	"pub":      "Pub",
+	"pypi":     "PyPI",
+}
+
+// packageURL is a parsed purl, see https://github.com/package-url/purl-spec
+type packageURL struct {
+	typ       string
+	namespace string
+	name      string
+	version   string
+}
+
+func parsePURL(purl string) (packageURL, bool) {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return packageURL{}, false
+	}
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	var p packageURL
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		p.version, _ = url.PathUnescape(rest[i+1:])
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 {
+		return packageURL{}, false
+	}
+	p.typ = strings.ToLower(parts[0])
+	for i, part := range parts[1:] {
+		part, _ = url.PathUnescape(part)
+		if i == len(parts)-2 {
+			p.name = part
+		} else if p.namespace == "" {
+			p.namespace = part
+		} else {
+			p.namespace += "/" + part
+		}
+	}
+	return p, p.name != ""
+}
+
+// base is the purl without version, qualifiers nor subpath
+func (p packageURL) base() string {
+	if p.name == "" {
+		return ""
+	}
+	if p.namespace == "" {
+		return "pkg:" + p.typ + "/" + p.name
+	}
+	return "pkg:" + p.typ + "/" + p.namespace + "/" + p.name
+}
+
+// keys are the keys under which records affecting the package are indexed
+func (p packageURL) keys() []string {
+	keys := []string{p.base()}
+	ecosystem, ok := purlEcosystems[p.typ]
+	if !ok {
+		return keys
+	}
+
+	name := p.name
+	switch p.typ {
+	case "maven":
+		name = p.namespace + ":" + p.name
+	case "pypi":
+		name = strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(p.name))
+	case "deb", "apk":
+		// the namespace is the distribution, which OSV has in the ecosystem instead
+	default:
+		if p.namespace != "" {
+			name = p.namespace + "/" + p.name
+		}
+	}
+	return append(keys, ecosystemKey(ecosystem, name))
+}
+
+// ecosystemKey identifies a package of an OSV ecosystem. Ecosystem versions, such as the
+// release in 'Debian:12', are ignored.
+func ecosystemKey(ecosystem, name string) string {
+	if i := strings.Index(ecosystem, ":"); i >= 0 {
+		ecosystem = ecosystem[:i]
+	}
+	if ecosystem == "PyPI" {
+		name = strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+	}
+	return ecosystem + "\x00" + name
+}
@@ -0,0 +1,137 @@
+package sbom_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestOSV(t *testing.T) {
+	spec.Run(t, "OSV", testOSV, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testOSV(t *testing.T, when spec.G, it spec.S) {
+	var dbDir string
+
+	writeRecord := func(name, contents string) {
+		path := filepath.Join(dbDir, name)
+		h.AssertNil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		h.AssertNil(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+
+	it.Before(func() {
+		dbDir = t.TempDir()
+		writeRecord("npm/GHSA-rv95-896h-c2vc.json", `{
+  "id": "GHSA-rv95-896h-c2vc",
+  "aliases": ["CVE-2024-29041"],
+  "summary": "Express.js Open Redirect",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:L/I:L/A:N"}],
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "express", "purl": "pkg:npm/express"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.19.2"}]}]
+  }]
+}`)
+		writeRecord("npm/GHSA-critical.json", `{
+  "id": "GHSA-critical",
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "@scope/lib"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "1.0.0"}, {"last_affected": "1.2.0"}]}]
+  }],
+  "database_specific": {"severity": "CRITICAL"}
+}`)
+		writeRecord("Debian/DSA-1.json", `{
+  "id": "DSA-1",
+  "affected": [{
+    "package": {"ecosystem": "Debian:12", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.11-1~deb12u2"}]}]
+  }]
+}`)
+		writeRecord("PyPI/PYSEC-1.json", `{
+  "id": "PYSEC-1",
+  "affected": [{"package": {"ecosystem": "PyPI", "name": "Flask_Cors"}, "versions": ["3.0.9"]}],
+  "database_specific": {"severity": "moderate"}
+}`)
+		writeRecord("npm/withdrawn.json", `{
+  "id": "GHSA-withdrawn",
+  "withdrawn": "2024-01-01T00:00:00Z",
+  "affected": [{"package": {"ecosystem": "npm", "name": "express"}, "versions": ["4.18.2"]}]
+}`)
+		writeRecord("README.md", "not a record")
+	})
+
+	when("#Match", func() {
+		var db *sbom.Database
+
+		it.Before(func() {
+			var err error
+			db, err = sbom.LoadDatabase(dbDir)
+			h.AssertNil(t, err)
+		})
+
+		it("matches affected ranges by purl", func() {
+			vulnerabilities := db.Match(sbom.Package{Name: "express", PURL: "pkg:npm/express@4.18.2"})
+			h.AssertEq(t, vulnerabilities, []sbom.Vulnerability{{
+				ID:           "GHSA-rv95-896h-c2vc",
+				Aliases:      []string{"CVE-2024-29041"},
+				Summary:      "Express.js Open Redirect",
+				Severity:     sbom.SeverityMedium,
+				FixedVersion: "4.19.2",
+			}})
+		})
+
+		it("doesn't match fixed versions", func() {
+			h.AssertEq(t, len(db.Match(sbom.Package{PURL: "pkg:npm/express@4.19.2"})), 0)
+		})
+
+		it("matches namespaced packages up to the last affected version", func() {
+			vulnerabilities := db.Match(sbom.Package{PURL: "pkg:npm/%40scope/lib@1.2.0"})
+			h.AssertEq(t, len(vulnerabilities), 1)
+			h.AssertEq(t, vulnerabilities[0].Severity, sbom.SeverityCritical)
+
+			h.AssertEq(t, len(db.Match(sbom.Package{PURL: "pkg:npm/%40scope/lib@1.2.1"})), 0)
+			h.AssertEq(t, len(db.Match(sbom.Package{PURL: "pkg:npm/%40scope/lib@0.9.0"})), 0)
+		})
+
+		it("matches distribution packages with ecosystem versions", func() {
+			h.AssertEq(t, len(db.Match(sbom.Package{PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u1?arch=amd64&distro=debian-12"})), 1)
+			h.AssertEq(t, len(db.Match(sbom.Package{PURL: "pkg:deb/debian/openssl@3.0.11-1~deb12u2?arch=amd64"})), 0)
+		})
+
+		it("matches explicit versions with normalized python names", func() {
+			vulnerabilities := db.Match(sbom.Package{PURL: "pkg:pypi/flask-cors@3.0.9"})
+			h.AssertEq(t, len(vulnerabilities), 1)
+			h.AssertEq(t, vulnerabilities[0].Severity, sbom.SeverityMedium)
+		})
+
+		it("ignores packages without purl version", func() {
+			h.AssertEq(t, len(db.Match(sbom.Package{PURL: "pkg:npm/express"})), 0)
+			h.AssertEq(t, len(db.Match(sbom.Package{Name: "express", Version: "4.18.2"})), 0)
+		})
+	})
+
+	when("#LoadDatabase", func() {
+		it("errors on invalid records", func() {
+			writeRecord("npm/invalid.json", "{")
+
+			_, err := sbom.LoadDatabase(dbDir)
+			h.AssertError(t, err, "decoding OSV record")
+		})
+	})
+
+	when("#ParseSeverity", func() {
+		it("accepts moderate as medium", func() {
+			severity, err := sbom.ParseSeverity("MODERATE")
+			h.AssertNil(t, err)
+			h.AssertEq(t, severity, sbom.SeverityMedium)
+
+			_, err = sbom.ParseSeverity("severe")
+			h.AssertError(t, err, `unknown severity "severe"`)
+		})
+	})
+}
@@ -0,0 +1,153 @@
+// Package sbom reads the packages listed in the SBOM documents written by buildpacks,
+// and writes merged SBOM documents.
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type Format string
+
+const (
+	FormatCycloneDX Format = "cyclonedx"
+	FormatSPDX      Format = "spdx"
+	FormatSyft      Format = "syft"
+)
+
+// fileFormats maps the SBOM file names defined by the buildpack spec to their format
+var fileFormats = map[string]Format{
+	"sbom.cdx.json":  FormatCycloneDX,
+	"sbom.spdx.json": FormatSPDX,
+	"sbom.syft.json": FormatSyft,
+}
+
+// FormatOf returns the format of the SBOM file at path, or false if it isn't an SBOM file
+func FormatOf(path string) (Format, bool) {
+	format, ok := fileFormats[filepath.Base(path)]
+	return format, ok
+}
+
+// Package is a package listed in an SBOM
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+// Decode returns the packages listed in an SBOM document
+func Decode(format Format, r io.Reader) ([]Package, error) {
+	switch format {
+	case FormatCycloneDX:
+		return decodeCycloneDX(r)
+	case FormatSPDX:
+		return decodeSPDX(r)
+	case FormatSyft:
+		return decodeSyft(r)
+	default:
+		return nil, errors.Errorf("unsupported SBOM format %s", format)
+	}
+}
+
+type cdxComponent struct {
+	Type       string         `json:"type,omitempty"`
+	BOMRef     string         `json:"bom-ref,omitempty"`
+	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
+	PURL       string         `json:"purl,omitempty"`
+	Properties []cdxProperty  `json:"properties,omitempty"`
+	Components []cdxComponent `json:"components,omitempty"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func decodeCycloneDX(r io.Reader) ([]Package, error) {
+	var doc struct {
+		BOMFormat  string         `json:"bomFormat"`
+		Components []cdxComponent `json:"components"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "decoding CycloneDX document")
+	}
+	if doc.BOMFormat != "CycloneDX" {
+		return nil, errors.Errorf("not a CycloneDX document, bomFormat is %q", doc.BOMFormat)
+	}
+
+	var packages []Package
+	var walk func(components []cdxComponent)
+	walk = func(components []cdxComponent) {
+		for _, component := range components {
+			packages = append(packages, Package{Name: component.Name, Version: component.Version, PURL: component.PURL})
+			walk(component.Components)
+		}
+	}
+	walk(doc.Components)
+	return packages, nil
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+func decodeSPDX(r io.Reader) ([]Package, error) {
+	var doc struct {
+		SPDXVersion string        `json:"spdxVersion"`
+		Packages    []spdxPackage `json:"packages"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "decoding SPDX document")
+	}
+	if !strings.HasPrefix(doc.SPDXVersion, "SPDX-") {
+		return nil, errors.Errorf("not an SPDX document, spdxVersion is %q", doc.SPDXVersion)
+	}
+
+	var packages []Package
+	for _, pkg := range doc.Packages {
+		p := Package{Name: pkg.Name, Version: pkg.VersionInfo}
+		for _, ref := range pkg.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				p.PURL = ref.ReferenceLocator
+				break
+			}
+		}
+		packages = append(packages, p)
+	}
+	return packages, nil
+}
+
+func decodeSyft(r io.Reader) ([]Package, error) {
+	var doc struct {
+		Artifacts []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			PURL    string `json:"purl"`
+		} `json:"artifacts"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "decoding Syft document")
+	}
+
+	var packages []Package
+	for _, artifact := range doc.Artifacts {
+		packages = append(packages, Package{Name: artifact.Name, Version: artifact.Version, PURL: artifact.PURL})
+	}
+	return packages, nil
+}
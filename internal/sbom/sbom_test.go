@@ -0,0 +1,102 @@
+package sbom_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSBOM(t *testing.T) {
+	spec.Run(t, "SBOM", testSBOM, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSBOM(t *testing.T, when spec.G, it spec.S) {
+	when("#FormatOf", func() {
+		it("recognizes the SBOM file names of the buildpack spec", func() {
+			format, ok := sbom.FormatOf("launch/some_bp/sbom.spdx.json")
+			h.AssertTrue(t, ok)
+			h.AssertEq(t, format, sbom.FormatSPDX)
+
+			_, ok = sbom.FormatOf("launch/some_bp/other.json")
+			h.AssertFalse(t, ok)
+		})
+	})
+
+	when("#Decode", func() {
+		it("reads nested CycloneDX components", func() {
+			packages, err := sbom.Decode(sbom.FormatCycloneDX, strings.NewReader(`{
+  "bomFormat": "CycloneDX",
+  "components": [{"name": "a", "version": "1", "components": [{"name": "b", "purl": "pkg:npm/b@2"}]}]
+}`))
+			h.AssertNil(t, err)
+			h.AssertEq(t, packages, []sbom.Package{{Name: "a", Version: "1"}, {Name: "b", PURL: "pkg:npm/b@2"}})
+		})
+
+		it("reads the purl of SPDX packages", func() {
+			packages, err := sbom.Decode(sbom.FormatSPDX, strings.NewReader(`{
+  "spdxVersion": "SPDX-2.2",
+  "packages": [{"name": "a", "versionInfo": "1", "externalRefs": [{"referenceType": "cpe23Type", "referenceLocator": "cpe"}, {"referenceType": "purl", "referenceLocator": "pkg:npm/a@1"}]}]
+}`))
+			h.AssertNil(t, err)
+			h.AssertEq(t, packages, []sbom.Package{{Name: "a", Version: "1", PURL: "pkg:npm/a@1"}})
+		})
+
+		it("reads Syft artifacts", func() {
+			packages, err := sbom.Decode(sbom.FormatSyft, strings.NewReader(`{"artifacts": [{"name": "a", "version": "1", "purl": "pkg:npm/a@1"}]}`))
+			h.AssertNil(t, err)
+			h.AssertEq(t, packages, []sbom.Package{{Name: "a", Version: "1", PURL: "pkg:npm/a@1"}})
+		})
+
+		it("errors on documents of another format", func() {
+			_, err := sbom.Decode(sbom.FormatCycloneDX, strings.NewReader(`{"spdxVersion": "SPDX-2.3"}`))
+			h.AssertError(t, err, "not a CycloneDX document")
+		})
+	})
+
+	when("#Encode", func() {
+		var doc sbom.Document
+
+		it.Before(func() {
+			doc = sbom.Document{
+				Name:        "some/image",
+				Created:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				ToolName:    "pack",
+				ToolVersion: "1.2.3",
+				Packages: []sbom.AttributedPackage{{
+					Package: sbom.Package{Name: "a", Version: "1", PURL: "pkg:npm/a@1"},
+					Sources: []sbom.Source{{Buildpack: "some/bp", Layer: "deps", Scope: "launch"}, {Buildpack: "other/bp", Scope: "build"}},
+				}},
+			}
+		})
+
+		for _, format := range []sbom.Format{sbom.FormatCycloneDX, sbom.FormatSPDX} {
+			format := format
+			it("writes a "+string(format)+" document that can be read back", func() {
+				var buf bytes.Buffer
+				h.AssertNil(t, sbom.Encode(&buf, format, doc))
+
+				packages, err := sbom.Decode(format, &buf)
+				h.AssertNil(t, err)
+				h.AssertEq(t, packages, []sbom.Package{{Name: "a", Version: "1", PURL: "pkg:npm/a@1"}})
+			})
+		}
+
+		it("attributes SPDX packages to their sources", func() {
+			var buf bytes.Buffer
+			h.AssertNil(t, sbom.Encode(&buf, sbom.FormatSPDX, doc))
+			h.AssertContains(t, buf.String(), `"comment": "contributed by some/bp layer deps (launch); contributed by other/bp (build)"`)
+			h.AssertContains(t, buf.String(), `"creators": [`)
+		})
+
+		it("errors for Syft", func() {
+			h.AssertError(t, sbom.Encode(&bytes.Buffer{}, sbom.FormatSyft, doc), "merged SBOMs can only be written as cyclonedx or spdx, got syft")
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const sha256Fragment = "#sha256="
+
+var (
+	sha256Pattern    = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	sha256URIPattern = regexp.MustCompile(`^(.*)` + sha256Fragment + `([0-9a-fA-F]{64})$`)
+)
+
+// SplitSHA256 splits a path or URI ending with `#sha256=<hex>` into the path or URI to download the blob from
+// and the sha256 checksum its contents must match. The checksum is empty when there's none.
+func SplitSHA256(pathOrURI string) (string, string) {
+	matches := sha256URIPattern.FindStringSubmatch(pathOrURI)
+	if matches == nil {
+		return pathOrURI, ""
+	}
+	return matches[1], strings.ToLower(matches[2])
+}
+
+// WithSHA256 returns pathOrURI ending with `#sha256=<hex>`, for the downloader to verify the contents of the
+// blob against checksum. The checksum may be prefixed with `sha256:`; when it's empty, pathOrURI is returned as is.
+func WithSHA256(pathOrURI, checksum string) (string, error) {
+	if checksum == "" {
+		return pathOrURI, nil
+	}
+
+	checksum = strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	if !sha256Pattern.MatchString(checksum) {
+		return "", errors.Errorf("invalid sha256 %s: must be 64 hexadecimal characters", style.Symbol(checksum))
+	}
+
+	base, existing := SplitSHA256(pathOrURI)
+	if existing != "" && existing != checksum {
+		return "", errors.Errorf("sha256 %s doesn't match the sha256 %s of %s", style.Symbol(checksum), style.Symbol(existing), style.Symbol(base))
+	}
+	return base + sha256Fragment + checksum, nil
+}
+
+// SHA256 returns the sha256 checksum of the file b was downloaded as. It errors for blobs of directories.
+func SHA256(b Blob) (string, error) {
+	fileBlob, ok := b.(*blob)
+	if !ok {
+		return "", errors.New("sha256 can only be computed for downloaded blobs")
+	}
+	return fileSHA256(fileBlob.path)
+}
+
+func fileSHA256(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "read blob at path %s", style.Symbol(path))
+	}
+	if fi.IsDir() {
+		return "", errors.Errorf("sha256 of %s can't be computed: it's a directory", style.Symbol(path))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "read blob at path %s", style.Symbol(path))
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.Wrapf(err, "read blob at path %s", style.Symbol(path))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
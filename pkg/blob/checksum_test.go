@@ -0,0 +1,75 @@
+package blob_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/blob"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestChecksum(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Checksum", testChecksum, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testChecksum(t *testing.T, when spec.G, it spec.S) {
+	var checksum = strings.Repeat("ab", 32)
+
+	when("#SplitSHA256", func() {
+		it("splits the sha256 from the uri", func() {
+			uri, actual := blob.SplitSHA256("https://example.com/bp.tgz#sha256=" + strings.ToUpper(checksum))
+			h.AssertEq(t, uri, "https://example.com/bp.tgz")
+			h.AssertEq(t, actual, checksum)
+		})
+
+		it("returns uris without a sha256 as is", func() {
+			uri, actual := blob.SplitSHA256("https://example.com/bp.tgz#sha256=abc")
+			h.AssertEq(t, uri, "https://example.com/bp.tgz#sha256=abc")
+			h.AssertEq(t, actual, "")
+		})
+	})
+
+	when("#WithSHA256", func() {
+		it("appends the sha256 to the uri", func() {
+			uri, err := blob.WithSHA256("https://example.com/bp.tgz", "sha256:"+checksum)
+			h.AssertNil(t, err)
+			h.AssertEq(t, uri, "https://example.com/bp.tgz#sha256="+checksum)
+		})
+
+		it("returns the uri as is without a sha256", func() {
+			uri, err := blob.WithSHA256("https://example.com/bp.tgz", "")
+			h.AssertNil(t, err)
+			h.AssertEq(t, uri, "https://example.com/bp.tgz")
+		})
+
+		it("accepts the sha256 the uri already has", func() {
+			uri, err := blob.WithSHA256("https://example.com/bp.tgz#sha256="+checksum, checksum)
+			h.AssertNil(t, err)
+			h.AssertEq(t, uri, "https://example.com/bp.tgz#sha256="+checksum)
+		})
+
+		it("errors when the sha256 conflicts with the one of the uri", func() {
+			_, err := blob.WithSHA256("https://example.com/bp.tgz#sha256="+checksum, strings.Repeat("0", 64))
+			h.AssertError(t, err, "doesn't match the sha256")
+		})
+
+		it("errors for invalid sha256s", func() {
+			_, err := blob.WithSHA256("https://example.com/bp.tgz", "abc")
+			h.AssertError(t, err, "invalid sha256 'abc': must be 64 hexadecimal characters")
+		})
+	})
+
+	when("#SHA256", func() {
+		it("errors for directories", func() {
+			_, err := blob.SHA256(blob.NewBlob(filepath.Join("testdata", "blob")))
+			h.AssertError(t, err, "it's a directory")
+		})
+	})
+}
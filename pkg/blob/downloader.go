@@ -15,6 +15,7 @@ import (
 
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/mirror"
 )
 
 const (
@@ -36,6 +37,13 @@ func WithClient(client *http.Client) DownloaderOption {
 	}
 }
 
+// WithMirror supply the local mirror that blobs are downloaded from in offline mode.
+func WithMirror(m *mirror.Mirror) DownloaderOption {
+	return func(d *downloader) {
+		d.mirror = m
+	}
+}
+
 type Downloader interface {
 	Download(ctx context.Context, pathOrURI string) (Blob, error)
 }
@@ -44,6 +52,7 @@ type downloader struct {
 	logger       Logger
 	baseCacheDir string
 	client       *http.Client
+	mirror       *mirror.Mirror
 }
 
 func NewDownloader(logger Logger, baseCacheDir string, opts ...DownloaderOption) Downloader {
@@ -60,18 +69,53 @@ func NewDownloader(logger Logger, baseCacheDir string, opts ...DownloaderOption)
 	return d
 }
 
+// Download returns the blob of a local path or a file, http or https URI. When pathOrURI ends with
+// `#sha256=<hex>`, the contents of the blob are verified against the checksum, including when they're cached.
 func (d *downloader) Download(ctx context.Context, pathOrURI string) (Blob, error) {
+	pathOrURI, checksum := SplitSHA256(pathOrURI)
+
+	path, cached, err := d.download(ctx, pathOrURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum != "" {
+		actual, err := fileSHA256(path)
+		if err != nil {
+			return nil, err
+		}
+		if actual != checksum {
+			if cached {
+				// the cached contents are discarded, for the next download to fetch them again
+				_ = os.Remove(path)
+				_ = os.Remove(path + ".etag")
+			}
+			return nil, errors.Errorf("sha256 mismatch for %s: expected %s, got %s", style.Symbol(pathOrURI), style.Symbol(checksum), style.Symbol(actual))
+		}
+	}
+
+	return &blob{path: path}, nil
+}
+
+// download returns the path of the blob, and whether it's in the download cache
+func (d *downloader) download(ctx context.Context, pathOrURI string) (string, bool, error) {
 	if paths.IsURI(pathOrURI) {
 		parsedURL, err := url.Parse(pathOrURI)
 		if err != nil {
-			return nil, errors.Wrapf(err, "parsing path/uri %s", style.Symbol(pathOrURI))
+			return "", false, errors.Wrapf(err, "parsing path/uri %s", style.Symbol(pathOrURI))
 		}
 
 		var path string
+		cached := false
 		switch parsedURL.Scheme {
 		case "file":
 			path, err = paths.URIToFilePath(pathOrURI)
 		case "http", "https":
+			if d.mirror.IsOffline() {
+				path, err = d.mirror.Blob(pathOrURI)
+				break
+			}
+			cached = true
 			path, err = d.handleHTTP(ctx, pathOrURI)
 			if err != nil {
 				// retry as we sometimes see `wsarecv: An existing connection was forcibly closed by the remote host.` on Windows
@@ -81,15 +125,13 @@ func (d *downloader) Download(ctx context.Context, pathOrURI string) (Blob, erro
 			err = fmt.Errorf("unsupported protocol %s in URI %s", style.Symbol(parsedURL.Scheme), style.Symbol(pathOrURI))
 		}
 		if err != nil {
-			return nil, err
+			return "", false, err
 		}
 
-		return &blob{path: path}, nil
+		return path, cached, nil
 	}
 
-	path := d.handleFile(pathOrURI)
-
-	return &blob{path: path}, nil
+	return d.handleFile(pathOrURI), false, nil
 }
 
 func (d *downloader) handleFile(path string) string {
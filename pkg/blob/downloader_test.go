@@ -2,11 +2,13 @@ package blob_test
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/heroku/color"
@@ -17,6 +19,7 @@ import (
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/pkg/archive"
 	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/mirror"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -133,6 +136,41 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 					h.AssertNil(t, err)
 					assertBlob(t, b)
 				})
+
+				when("uri has a sha256", func() {
+					var checksum string
+
+					it.Before(func() {
+						contents, err := os.ReadFile(tgz)
+						h.AssertNil(t, err)
+						checksum = fmt.Sprintf("%x", sha256.Sum256(contents))
+					})
+
+					it("verifies the download", func() {
+						b, err := subject.Download(context.TODO(), uri+"#sha256="+checksum)
+						h.AssertNil(t, err)
+						assertBlob(t, b)
+
+						actual, err := blob.SHA256(b)
+						h.AssertNil(t, err)
+						h.AssertEq(t, actual, checksum)
+					})
+
+					it("verifies the cached download", func() {
+						_, err := subject.Download(context.TODO(), uri)
+						h.AssertNil(t, err)
+
+						b, err := subject.Download(context.TODO(), uri+"#sha256="+checksum)
+						h.AssertNil(t, err)
+						assertBlob(t, b)
+					})
+
+					it("errors when the sha256 doesn't match", func() {
+						wrong := strings.Repeat("0", 64)
+						_, err := subject.Download(context.TODO(), uri+"#sha256="+wrong)
+						h.AssertError(t, err, fmt.Sprintf("sha256 mismatch for '%s': expected '%s', got '%s'", uri, wrong, checksum))
+					})
+				})
 			})
 
 			when("uri is invalid", func() {
@@ -161,6 +199,34 @@ func testDownloader(t *testing.T, when spec.G, it spec.S) {
 					})
 				})
 			})
+
+			when("offline", func() {
+				var localMirror *mirror.Mirror
+
+				it.Before(func() {
+					localMirror = mirror.New(filepath.Join(cacheDir, "mirror"))
+					localMirror.SetOffline(true)
+					subject = blob.NewDownloader(&logger{io.Discard}, cacheDir, blob.WithMirror(localMirror))
+				})
+
+				it("downloads from the mirror", func() {
+					file, err := os.Open(tgz)
+					h.AssertNil(t, err)
+					defer file.Close()
+					h.AssertNil(t, localMirror.AddBlob(uri, file))
+
+					b, err := subject.Download(context.TODO(), uri)
+					h.AssertNil(t, err)
+					assertBlob(t, b)
+					h.AssertEq(t, len(server.ReceivedRequests()), 0)
+				})
+
+				it("errors when the uri isn't mirrored", func() {
+					_, err := subject.Download(context.TODO(), uri)
+					h.AssertError(t, err, "isn't in the mirror")
+					h.AssertEq(t, len(server.ReceivedRequests()), 0)
+				})
+			})
 		})
 	})
 }
@@ -74,7 +74,7 @@ func FromBlob(descriptor Descriptor, blob Blob) BuildModule {
 func FromBuildpackRootBlob(blob Blob, layerWriterFactory archive.TarWriterFactory, logger Logger) (BuildModule, error) {
 	descriptor := dist.BuildpackDescriptor{}
 	descriptor.WithAPI = api.MustParse(dist.AssumedBuildpackAPIVersion)
-	undecodedKeys, err := readDescriptor(KindBuildpack, &descriptor, blob)
+	undecodedKeys, err := ReadDescriptor(KindBuildpack, &descriptor, blob)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +96,7 @@ func FromBuildpackRootBlob(blob Blob, layerWriterFactory archive.TarWriterFactor
 func FromExtensionRootBlob(blob Blob, layerWriterFactory archive.TarWriterFactory, logger Logger) (BuildModule, error) {
 	descriptor := dist.ExtensionDescriptor{}
 	descriptor.WithAPI = api.MustParse(dist.AssumedBuildpackAPIVersion)
-	undecodedKeys, err := readDescriptor(KindExtension, &descriptor, blob)
+	undecodedKeys, err := ReadDescriptor(KindExtension, &descriptor, blob)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +109,9 @@ func FromExtensionRootBlob(blob Blob, layerWriterFactory archive.TarWriterFactor
 	return buildpackFrom(&descriptor, blob, layerWriterFactory)
 }
 
-func readDescriptor(kind string, descriptor interface{}, blob Blob) (undecodedKeys []string, err error) {
+// ReadDescriptor decodes the buildpack.toml or extension.toml, depending on kind, at the root of blob into descriptor.
+// It returns the keys of the file that descriptor doesn't define, apart from the arbitrary keys of [metadata].
+func ReadDescriptor(kind string, descriptor interface{}, blob Blob) (undecodedKeys []string, err error) {
 	rc, err := blob.Open()
 	if err != nil {
 		return undecodedKeys, errors.Wrapf(err, "open %s", kind)
@@ -329,6 +331,76 @@ func validateExtensionDescriptor(extd dist.ExtensionDescriptor) error {
 	return nil
 }
 
+// MinExtensionAPI is the first Buildpack API supporting image extensions
+var MinExtensionAPI = api.MustParse("0.9")
+
+// ExtensionDockerfileSources are the files of an extension, relative to its root, any of which provides Dockerfiles
+var ExtensionDockerfileSources = []string{path.Join("bin", "generate"), path.Join("generate", "build.Dockerfile"), path.Join("generate", "run.Dockerfile")}
+
+// NoDockerfilesHint tells how to fix an extension providing none of ExtensionDockerfileSources
+const NoDockerfilesHint = "add an executable 'bin/generate' writing build.Dockerfile or run.Dockerfile to the output directory, or add 'generate/build.Dockerfile' or 'generate/run.Dockerfile'"
+
+// ValidateExtension checks that an extension, read from a blob with its contents at the root, can be packaged
+// for target: it must use a supported Buildpack API, provide Dockerfiles, and declare target if it declares targets.
+func ValidateExtension(ext BuildModule, blob Blob, target dist.Target, logger Logger) error {
+	descriptor := ext.Descriptor()
+	name := style.Symbol(descriptor.Info().FullName())
+
+	if descriptor.API().Compare(MinExtensionAPI) < 0 {
+		return errors.Errorf("extension %s declares Buildpack API %s, but extensions require Buildpack API %s or later: update %s in extension.toml",
+			name, descriptor.API().String(), MinExtensionAPI.String(), style.Symbol("api"))
+	}
+	if !api.Buildpack.IsSupported(descriptor.API()) {
+		return errors.Errorf("extension %s declares Buildpack API %s, which isn't one of the supported APIs %s: update %s in extension.toml",
+			name, descriptor.API().String(), api.Buildpack.Supported.String(), style.Symbol("api"))
+	}
+
+	var hasDockerfiles bool
+	for _, file := range ExtensionDockerfileSources {
+		found, err := hasFile(blob, file)
+		if err != nil {
+			return err
+		}
+		hasDockerfiles = hasDockerfiles || found
+	}
+	if !hasDockerfiles {
+		return errors.Errorf("extension %s provides no Dockerfiles: %s", name, NoDockerfilesHint)
+	}
+
+	targets := descriptor.Targets()
+	if len(targets) == 0 {
+		if logger != nil {
+			logger.Warnf("extension %s declares no targets: add %s to extension.toml to declare the platforms it supports", name, style.Symbol("[[targets]]"))
+		}
+		return nil
+	}
+	for i, declared := range targets {
+		if declared.OS == "" {
+			return errors.Errorf("target #%d of extension %s has no %s: set it in extension.toml", i+1, name, style.Symbol("os"))
+		}
+	}
+	for _, declared := range targets {
+		if declared.OS != target.OS || (declared.Arch != "" && target.Arch != "" && declared.Arch != target.Arch) {
+			continue
+		}
+		if len(declared.Distributions) == 0 || len(target.Distributions) == 0 {
+			return nil
+		}
+		for _, distro := range declared.Distributions {
+			if distro.Name == target.Distributions[0].Name && distro.Version == target.Distributions[0].Version {
+				return nil
+			}
+		}
+	}
+
+	var declaredPlatforms []string
+	for _, declared := range targets {
+		declaredPlatforms = append(declaredPlatforms, declared.ValuesAsPlatform())
+	}
+	return errors.Errorf("extension %s doesn't declare target %s, it declares %s: add the target to extension.toml or remove it from the package targets",
+		name, style.Symbol(target.ValuesAsPlatform()), strings.Join(declaredPlatforms, ", "))
+}
+
 func ToLayerTar(dest string, module BuildModule) (string, error) {
 	descriptor := module.Descriptor()
 	modReader, err := module.Open()
@@ -88,6 +88,9 @@ func (c *buildpackDownloader) Download(ctx context.Context, moduleURI string, op
 		kind = KindExtension
 	}
 
+	// a `#sha256=<hex>` suffix is the checksum of a module downloaded from a URI
+	moduleURI, checksum := blob.SplitSHA256(moduleURI)
+
 	var err error
 	var locatorType LocatorType
 	if moduleURI == "" && opts.ImageName != "" {
@@ -100,6 +103,10 @@ func (c *buildpackDownloader) Download(ctx context.Context, moduleURI string, op
 			return nil, nil, err
 		}
 	}
+	if checksum != "" && locatorType != URILocator {
+		return nil, nil, errors.Errorf("sha256 of %s can't be verified: only modules downloaded from a URI have one, pin images by digest instead", style.Symbol(moduleURI))
+	}
+
 	var mainBP BuildModule
 	var depBPs []BuildModule
 	switch locatorType {
@@ -137,7 +144,11 @@ func (c *buildpackDownloader) Download(ctx context.Context, moduleURI string, op
 
 		c.logger.Debugf("Downloading %s from URI: %s", kind, style.Symbol(moduleURI))
 
-		blob, err := c.downloader.Download(ctx, moduleURI)
+		downloadURI, err := blob.WithSHA256(moduleURI, checksum)
+		if err != nil {
+			return nil, nil, err
+		}
+		blob, err := c.downloader.Download(ctx, downloadURI)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "downloading %s from %s", kind, style.Symbol(moduleURI))
 		}
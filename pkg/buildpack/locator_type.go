@@ -11,6 +11,7 @@ import (
 
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/dist"
 )
 
@@ -52,8 +53,10 @@ func (l LocatorType) String() string {
 
 // GetLocatorType determines which type of locator is designated by the given input.
 // If a type cannot be determined, `INVALID_LOCATOR` will be returned. If an error
-// is encountered, it will be returned.
+// is encountered, it will be returned. A `#sha256=<hex>` checksum at the end of the locator is ignored.
 func GetLocatorType(locator string, relativeBaseDir string, buildpacksFromBuilder []dist.ModuleInfo) (LocatorType, error) {
+	locator, _ = blob.SplitSHA256(locator)
+
 	if locator == deprecatedFromBuilderPrefix {
 		return FromBuilderLocator, nil
 	}
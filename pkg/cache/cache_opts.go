@@ -13,6 +13,10 @@ type Format int
 type CacheInfo struct {
 	Format Format
 	Source string
+	// Prefix, Endpoint and Region are only used by the s3 format, where Source holds the bucket
+	Prefix   string
+	Endpoint string
+	Region   string
 }
 
 type CacheOpts struct {
@@ -25,6 +29,7 @@ const (
 	CacheVolume Format = iota
 	CacheImage
 	CacheBind
+	CacheS3
 )
 
 func (f Format) String() string {
@@ -35,6 +40,8 @@ func (f Format) String() string {
 		return "volume"
 	case CacheBind:
 		return "bind"
+	case CacheS3:
+		return "s3"
 	}
 	return ""
 }
@@ -47,6 +54,8 @@ func (c *CacheInfo) SourceName() string {
 		return "name"
 	case CacheBind:
 		return "source"
+	case CacheS3:
+		return "bucket"
 	}
 	return ""
 }
@@ -80,6 +89,8 @@ func (c *CacheOpts) Set(value string) error {
 		}
 	}
 
+	// options only the s3 format uses, which would otherwise be silently ignored
+	var s3Options []string
 	for _, field := range fields {
 		parts := strings.SplitN(field, "=", 2)
 		if len(parts) != 2 {
@@ -88,6 +99,10 @@ func (c *CacheOpts) Set(value string) error {
 		key := strings.ToLower(parts[0])
 		value := parts[1]
 		switch key {
+		case "bucket", "prefix", "endpoint", "region":
+			s3Options = append(s3Options, key)
+		}
+		switch key {
 		case "format":
 			switch value {
 			case "image":
@@ -96,6 +111,8 @@ func (c *CacheOpts) Set(value string) error {
 				cache.Format = CacheVolume
 			case "bind":
 				cache.Format = CacheBind
+			case "s3":
+				cache.Format = CacheS3
 			default:
 				return errors.Errorf("invalid cache format '%s'", value)
 			}
@@ -103,9 +120,21 @@ func (c *CacheOpts) Set(value string) error {
 			cache.Source = value
 		case "source":
 			cache.Source = value
+		case "bucket":
+			cache.Source = value
+		case "prefix":
+			cache.Prefix = value
+		case "endpoint":
+			cache.Endpoint = value
+		case "region":
+			cache.Region = value
 		}
 	}
 
+	if len(s3Options) > 0 && cache.Format != CacheS3 {
+		return errors.Errorf("invalid cache option '%s' for format '%s': only supported by format 's3'", s3Options[0], cache.Format)
+	}
+
 	err = sanitize(c)
 	if err != nil {
 		return err
@@ -116,23 +145,41 @@ func (c *CacheOpts) Set(value string) error {
 func (c *CacheOpts) String() string {
 	var cacheFlag string
 	cacheFlag = fmt.Sprintf("type=build;format=%s;", c.Build.Format.String())
-	if c.Build.Source != "" {
-		cacheFlag += fmt.Sprintf("%s=%s;", c.Build.SourceName(), c.Build.Source)
-	}
+	cacheFlag += c.Build.options()
 
 	cacheFlag += fmt.Sprintf("type=launch;format=%s;", c.Launch.Format.String())
-	if c.Launch.Source != "" {
-		cacheFlag += fmt.Sprintf("%s=%s;", c.Launch.SourceName(), c.Launch.Source)
-	}
+	cacheFlag += c.Launch.options()
 
 	return cacheFlag
 }
 
+func (c *CacheInfo) options() string {
+	var options string
+	if c.Source != "" {
+		options += fmt.Sprintf("%s=%s;", c.SourceName(), c.Source)
+	}
+	if c.Prefix != "" {
+		options += fmt.Sprintf("prefix=%s;", c.Prefix)
+	}
+	if c.Endpoint != "" {
+		options += fmt.Sprintf("endpoint=%s;", c.Endpoint)
+	}
+	if c.Region != "" {
+		options += fmt.Sprintf("region=%s;", c.Region)
+	}
+	return options
+}
+
 func (c *CacheOpts) Type() string {
 	return "cache"
 }
 
 func sanitize(c *CacheOpts) error {
+	// the launch cache has to be available to the exporter before the lifecycle runs
+	if c.Launch.Format == CacheS3 {
+		return errors.New("s3 format is only supported for the build cache")
+	}
+
 	for _, v := range []CacheInfo{c.Build, c.Launch} {
 		// volume cache name can be auto-generated
 		if v.Format != CacheVolume && v.Source == "" {
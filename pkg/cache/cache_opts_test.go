@@ -317,4 +317,52 @@ func testCacheOpts(t *testing.T, when spec.G, it spec.S) {
 			}
 		})
 	})
+
+	when("s3 cache format options are passed", func() {
+		it("with complete options", func() {
+			var cacheFlags CacheOpts
+			err := cacheFlags.Set("type=build;format=s3;bucket=my-bucket;prefix=ci/caches;endpoint=http://localhost:9000;region=eu-west-1")
+			h.AssertNil(t, err)
+			h.AssertEq(t, cacheFlags.Build, CacheInfo{
+				Format:   CacheS3,
+				Source:   "my-bucket",
+				Prefix:   "ci/caches",
+				Endpoint: "http://localhost:9000",
+				Region:   "eu-west-1",
+			})
+			h.AssertEq(t, cacheFlags.String(), "type=build;format=s3;bucket=my-bucket;prefix=ci/caches;endpoint=http://localhost:9000;region=eu-west-1;type=launch;format=volume;")
+		})
+
+		it("with missing options", func() {
+			testcases := []CacheOptTestCase{
+				{
+					name:   "Build cache in s3 missing: bucket",
+					input:  "type=build;format=s3;prefix=ci",
+					output: "cache 'bucket' is required",
+				},
+				{
+					name:   "Launch cache in s3",
+					input:  "type=launch;format=s3;bucket=my-bucket",
+					output: "s3 format is only supported for the build cache",
+				},
+				{
+					name:   "Build cache in a volume with a bucket",
+					input:  "type=build;format=volume;bucket=my-bucket",
+					output: "invalid cache option 'bucket' for format 'volume': only supported by format 's3'",
+				},
+				{
+					name:   "Build cache in an image with an endpoint",
+					input:  "type=build;endpoint=http://localhost:9000;format=image;name=my-cache",
+					output: "invalid cache option 'endpoint' for format 'image': only supported by format 's3'",
+				},
+			}
+
+			for _, testcase := range testcases {
+				var cacheFlags CacheOpts
+				t.Logf("Testing cache type: %s", testcase.name)
+				err := cacheFlags.Set(testcase.input)
+				h.AssertError(t, err, testcase.output)
+			}
+		})
+	})
 }
@@ -4,6 +4,21 @@ const (
 	Image Type = iota
 	Volume
 	Bind
+	S3
 )
 
 type Type int
+
+func (t Type) String() string {
+	switch t {
+	case Image:
+		return "image"
+	case Volume:
+		return "volume"
+	case Bind:
+		return "bind"
+	case S3:
+		return "s3"
+	}
+	return ""
+}
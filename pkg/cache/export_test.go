@@ -0,0 +1,6 @@
+package cache
+
+// SetPartSize sets the size of the parts the cache is uploaded in, so that small tarballs are uploaded in parts
+func (c *S3Cache) SetPartSize(size int64) {
+	c.partSize = size
+}
@@ -0,0 +1,393 @@
+package cache
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// credentialsTimeout bounds resolving credentials, which probes the EC2 instance metadata service when no
+	// other credentials are configured
+	credentialsTimeout = 5 * time.Second
+
+	// defaultS3PartSize is the size of the parts of multipart uploads. Tarballs up to that size are uploaded in a
+	// single request, larger ones in parts, as S3 rejects single uploads over 5 GiB.
+	defaultS3PartSize = 64 << 20
+	// s3MaxParts is the most parts a multipart upload may have, which larger tarballs get larger parts for
+	s3MaxParts = 10000
+)
+
+// S3Cache is a build cache stored as a tarball in an S3-compatible bucket.
+// The cache is staged in a volume, which is populated from the bucket before restore and uploaded to it after export.
+type S3Cache struct {
+	volume      *VolumeCache
+	bucket      string
+	key         string
+	url         string
+	region      string
+	credentials aws.CredentialsProvider
+	client      *http.Client
+	logger      logging.Logger
+	partSize    int64
+
+	// unsigned is set once credentials failed to resolve, so requests aren't held up resolving them again
+	mu       sync.Mutex
+	unsigned bool
+}
+
+func NewS3Cache(imageRef name.Reference, cacheType CacheInfo, dockerClient DockerClient, logger logging.Logger) (*S3Cache, error) {
+	volume, err := NewVolumeCache(imageRef, CacheInfo{}, "build", dockerClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "loading AWS config")
+	}
+
+	region := cacheType.Region
+	if region == "" {
+		region = cfg.Region
+	}
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	endpoint := cacheType.Endpoint
+	for _, env := range []string{"AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"} {
+		if endpoint == "" {
+			endpoint = os.Getenv(env)
+		}
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	key := path.Join(cacheType.Prefix, sanitizedRef(imageRef), "build-cache.tar")
+	return &S3Cache{
+		volume: volume,
+		bucket: cacheType.Source,
+		key:    key,
+		// path-style URLs are used as most S3-compatible servers don't support virtual hosted buckets
+		url:         strings.TrimSuffix(endpoint, "/") + escapeS3Path(path.Join(cacheType.Source, key)),
+		region:      region,
+		credentials: cfg.Credentials,
+		client:      newS3HTTPClient(),
+		logger:      logger,
+		partSize:    defaultS3PartSize,
+	}, nil
+}
+
+// escapeS3Path escapes each segment of an object path the way SigV4 canonical requests do, which S3 checks the
+// signature against: every byte but letters, digits and '-', '.', '_' and '~' is percent-encoded.
+func escapeS3Path(p string) string {
+	var escaped strings.Builder
+	for _, segment := range strings.Split(p, "/") {
+		escaped.WriteByte('/')
+		for i := 0; i < len(segment); i++ {
+			b := segment[i]
+			if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || strings.IndexByte("-._~", b) >= 0 {
+				escaped.WriteByte(b)
+				continue
+			}
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+// newS3HTTPClient returns a client that gives up on unresponsive servers. There's no overall timeout, as
+// transferring a large cache can legitimately take long.
+func newS3HTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: time.Minute,
+			ExpectContinueTimeout: time.Second,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+}
+
+// Name returns the name of the volume the cache is staged in
+func (c *S3Cache) Name() string {
+	return c.volume.Name()
+}
+
+// Location returns the s3:// URL of the cache object
+func (c *S3Cache) Location() string {
+	return fmt.Sprintf("s3://%s/%s", c.bucket, c.key)
+}
+
+func (c *S3Cache) Clear(ctx context.Context) error {
+	if err := c.volume.Clear(ctx); err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, http.MethodDelete, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return responseError(resp, "deleting %s", c.Location())
+	}
+	return nil
+}
+
+// ClearStaging empties the volume the cache is staged in, leaving the bucket as it is, so that the volume only holds
+// what is downloaded from the bucket
+func (c *S3Cache) ClearStaging(ctx context.Context) error {
+	return c.volume.Clear(ctx)
+}
+
+func (c *S3Cache) Type() Type {
+	return S3
+}
+
+// Download returns the cache tarball stored in the bucket, or nil if no cache has been uploaded yet.
+// The caller is responsible for closing the returned reader.
+func (c *S3Cache) Download(ctx context.Context) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		c.logger.Debugf("No cache found at %s", c.Location())
+		return nil, nil
+	default:
+		defer resp.Body.Close()
+		return nil, responseError(resp, "downloading %s", c.Location())
+	}
+}
+
+// Upload stores the cache tarball read from content in the bucket, replacing any previous cache
+func (c *S3Cache) Upload(ctx context.Context, content io.Reader) error {
+	// the content length has to be known up front, so the tarball is spooled to disk first
+	tmp, err := os.CreateTemp("", "pack.s3-cache.*.tar")
+	if err != nil {
+		return errors.Wrap(err, "creating temp file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, content)
+	if err != nil {
+		return errors.Wrap(err, "writing cache tarball")
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if size > c.partSize {
+		return c.uploadMultipart(ctx, tmp, size)
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, nil, tmp, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "uploading %s", c.Location())
+	}
+	c.logger.Debugf("Uploaded %d bytes to %s", size, c.Location())
+	return nil
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// uploadMultipart uploads the tarball in parts, aborting the upload if any part fails, so the parts uploaded
+// so far aren't kept
+func (c *S3Cache) uploadMultipart(ctx context.Context, content io.ReaderAt, size int64) error {
+	resp, err := c.do(ctx, http.MethodPost, url.Values{"uploads": {""}}, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "starting upload of %s", c.Location())
+	}
+	var initiated initiateMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&initiated); err != nil {
+		return errors.Wrapf(err, "starting upload of %s", c.Location())
+	}
+
+	parts, err := c.uploadParts(ctx, initiated.UploadID, content, size)
+	if err == nil {
+		err = c.completeMultipartUpload(ctx, initiated.UploadID, parts)
+	}
+	if err != nil {
+		c.abortMultipartUpload(context.WithoutCancel(ctx), initiated.UploadID)
+		return err
+	}
+	c.logger.Debugf("Uploaded %d bytes to %s in %d parts", size, c.Location(), len(parts))
+	return nil
+}
+
+func (c *S3Cache) uploadParts(ctx context.Context, uploadID string, content io.ReaderAt, size int64) ([]completedPart, error) {
+	partSize := c.partSize
+	if minPartSize := (size + s3MaxParts - 1) / s3MaxParts; partSize < minPartSize {
+		partSize = minPartSize
+	}
+
+	var parts []completedPart
+	for offset := int64(0); offset < size; offset += partSize {
+		length := min(partSize, size-offset)
+		number := len(parts) + 1
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+
+		resp, err := c.do(ctx, http.MethodPut, query, io.NewSectionReader(content, offset, length), length)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, responseError(resp, "uploading part %d of %s", number, c.Location())
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+	}
+	return parts, nil
+}
+
+func (c *S3Cache) completeMultipartUpload(ctx context.Context, uploadID string, parts []completedPart) error {
+	body, err := xml.Marshal(completeMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, url.Values{"uploadId": {uploadID}}, strings.NewReader(string(body)), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "completing upload of %s", c.Location())
+	}
+
+	// S3 may report a failure to complete the upload in the body of a successful response
+	var result struct {
+		XMLName xml.Name
+		Code    string
+		Message string
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return errors.Wrapf(err, "completing upload of %s", c.Location())
+	}
+	if result.XMLName.Local == "Error" {
+		return errors.Errorf("completing upload of %s: %s: %s", c.Location(), result.Code, result.Message)
+	}
+	return nil
+}
+
+func (c *S3Cache) abortMultipartUpload(ctx context.Context, uploadID string) {
+	resp, err := c.do(ctx, http.MethodDelete, url.Values{"uploadId": {uploadID}}, nil, 0)
+	if err != nil {
+		c.logger.Debugf("Unable to abort upload of %s: %s", c.Location(), err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func (c *S3Cache) do(ctx context.Context, method string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	target := c.url
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	if creds, ok := c.resolveCredentials(ctx); ok {
+		if err := sign(ctx, req, creds, c.region); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "requesting %s", c.Location())
+	}
+	return resp, nil
+}
+
+// resolveCredentials returns the credentials requests are signed with. Requests are sent unsigned, as for a public
+// bucket or an S3-compatible server without authentication, when none resolve.
+func (c *S3Cache) resolveCredentials(ctx context.Context) (aws.Credentials, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unsigned || c.credentials == nil {
+		return aws.Credentials{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialsTimeout)
+	defer cancel()
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		c.logger.Debugf("Sending unsigned requests to %s, as no AWS credentials were found: %s", c.Location(), err)
+		c.unsigned = true
+		return aws.Credentials{}, false
+	}
+	return creds, true
+}
+
+func sign(ctx context.Context, req *http.Request, creds aws.Credentials, region string) error {
+	signer := v4.NewSigner(func(opts *v4.SignerOptions) {
+		opts.DisableURIPathEscaping = true
+	})
+	return errors.Wrap(signer.SignHTTP(ctx, creds, req, unsignedPayload, "s3", region, time.Now()), "signing request")
+}
+
+func responseError(resp *http.Response, format string, args ...interface{}) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errors.Errorf("%s: unexpected status %s: %s", fmt.Sprintf(format, args...), resp.Status, strings.TrimSpace(string(body)))
+}
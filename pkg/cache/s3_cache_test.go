@@ -0,0 +1,287 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestS3Cache(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "S3Cache", testS3Cache, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+// fakeS3 is a minimal stand-in for an S3-compatible server, such as MinIO, storing objects in memory
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	auths   []string
+	paths   []string
+	aborted []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.auths = append(f.auths, r.Header.Get("Authorization"))
+	f.paths = append(f.paths, r.URL.EscapedPath())
+	query := r.URL.Query()
+	uploadID := query.Get("uploadId")
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID = fmt.Sprintf("upload-%d", len(f.uploads)+1)
+		f.uploads[uploadID] = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", uploadID)
+	case r.Method == http.MethodPut && uploadID != "":
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		body, _ := io.ReadAll(r.Body)
+		f.uploads[uploadID][number] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, number))
+	case r.Method == http.MethodPost && uploadID != "":
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		_ = xml.NewDecoder(r.Body).Decode(&complete)
+		var object []byte
+		for _, part := range complete.Parts {
+			if part.ETag != fmt.Sprintf(`"etag-%d"`, part.PartNumber) {
+				fmt.Fprint(w, "<Error><Code>InvalidPart</Code><Message>unknown part</Message></Error>")
+				return
+			}
+			object = append(object, f.uploads[uploadID][part.PartNumber]...)
+		}
+		delete(f.uploads, uploadID)
+		f.objects[r.URL.Path] = object
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete && uploadID != "":
+		delete(f.uploads, uploadID)
+		f.aborted = append(f.aborted, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		if r.ContentLength < 0 {
+			w.WriteHeader(http.StatusLengthRequired)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func testS3Cache(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server  *httptest.Server
+		fake    *fakeS3
+		ref     name.Reference
+		logger  logging.Logger
+		outBuf  bytes.Buffer
+		options cache.CacheInfo
+	)
+
+	it.Before(func() {
+		fake = &fakeS3{objects: map[string][]byte{}, uploads: map[string]map[int][]byte{}}
+		server = httptest.NewServer(fake)
+
+		tmpDir := t.TempDir()
+		t.Setenv(cache.EnvVolumeKey, "some-volume-key")
+		t.Setenv("AWS_ACCESS_KEY_ID", "some-access-key")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "some-secret-key")
+		t.Setenv("AWS_CONFIG_FILE", filepath.Join(tmpDir, "config"))
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(tmpDir, "credentials"))
+
+		var err error
+		ref, err = name.ParseReference("my/repo", name.WeakValidation)
+		h.AssertNil(t, err)
+		logger = logging.NewSimpleLogger(&outBuf)
+		options = cache.CacheInfo{
+			Format:   cache.CacheS3,
+			Source:   "some-bucket",
+			Prefix:   "some/prefix",
+			Endpoint: server.URL,
+			Region:   "eu-west-1",
+		}
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#NewS3Cache", func() {
+		it("is staged in the build cache volume", func() {
+			subject, err := cache.NewS3Cache(ref, options, nil, logger)
+			h.AssertNil(t, err)
+
+			volume, err := cache.NewVolumeCache(ref, cache.CacheInfo{}, "build", nil, logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, subject.Name(), volume.Name())
+			h.AssertEq(t, subject.Type(), cache.S3)
+		})
+
+		it("keys the object by the prefix and image", func() {
+			subject, err := cache.NewS3Cache(ref, options, nil, logger)
+			h.AssertNil(t, err)
+			h.AssertEq(t, subject.Location(), "s3://some-bucket/some/prefix/my_repo_latest/build-cache.tar")
+		})
+
+		it("escapes each segment of the object path", func() {
+			options.Prefix = "some prefix/a+b"
+			subject, err := cache.NewS3Cache(ref, options, nil, logger)
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, subject.Upload(context.TODO(), strings.NewReader("some-cache-tarball")))
+			h.AssertEq(t, fake.paths, []string{"/some-bucket/some%20prefix/a%2Bb/my_repo_latest/build-cache.tar"})
+			h.AssertEq(t, string(fake.objects["/some-bucket/some prefix/a+b/my_repo_latest/build-cache.tar"]), "some-cache-tarball")
+		})
+	})
+
+	when("#Download", func() {
+		when("nothing has been uploaded", func() {
+			it("returns a nil reader", func() {
+				subject, err := cache.NewS3Cache(ref, options, nil, logger)
+				h.AssertNil(t, err)
+
+				reader, err := subject.Download(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertNil(t, reader)
+			})
+		})
+
+		when("the server errors", func() {
+			it("returns the error", func() {
+				options.Source = "other-bucket"
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte("AccessDenied"))
+				})
+				subject, err := cache.NewS3Cache(ref, options, nil, logger)
+				h.AssertNil(t, err)
+
+				_, err = subject.Download(context.TODO())
+				h.AssertError(t, err, "downloading s3://other-bucket/some/prefix/my_repo_latest/build-cache.tar: unexpected status 403 Forbidden: AccessDenied")
+			})
+		})
+	})
+
+	when("#Upload", func() {
+		it("stores the content so it can be downloaded", func() {
+			subject, err := cache.NewS3Cache(ref, options, nil, logger)
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, subject.Upload(context.TODO(), strings.NewReader("some-cache-tarball")))
+			h.AssertEq(t, string(fake.objects["/some-bucket/some/prefix/my_repo_latest/build-cache.tar"]), "some-cache-tarball")
+
+			reader, err := subject.Download(context.TODO())
+			h.AssertNil(t, err)
+			defer reader.Close()
+			contents, err := io.ReadAll(reader)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "some-cache-tarball")
+		})
+
+		it("signs requests with the configured credentials", func() {
+			subject, err := cache.NewS3Cache(ref, options, nil, logger)
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, subject.Upload(context.TODO(), strings.NewReader("some-cache-tarball")))
+			h.AssertEq(t, len(fake.auths), 1)
+			h.AssertContains(t, fake.auths[0], "AWS4-HMAC-SHA256 Credential=some-access-key/")
+			h.AssertContains(t, fake.auths[0], "/eu-west-1/s3/aws4_request")
+		})
+
+		when("the tarball is larger than a part", func() {
+			it("uploads it in parts", func() {
+				subject, err := cache.NewS3Cache(ref, options, nil, logger)
+				h.AssertNil(t, err)
+				subject.SetPartSize(5)
+
+				h.AssertNil(t, subject.Upload(context.TODO(), strings.NewReader("some-cache-tarball")))
+				// initiate, 4 parts and complete
+				h.AssertEq(t, len(fake.auths), 6)
+				h.AssertEq(t, len(fake.uploads), 0)
+
+				reader, err := subject.Download(context.TODO())
+				h.AssertNil(t, err)
+				defer reader.Close()
+				contents, err := io.ReadAll(reader)
+				h.AssertNil(t, err)
+				h.AssertEq(t, string(contents), "some-cache-tarball")
+			})
+
+			when("a part fails to upload", func() {
+				it("aborts the upload", func() {
+					handler := server.Config.Handler
+					server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if r.URL.Query().Get("partNumber") == "2" {
+							w.WriteHeader(http.StatusInternalServerError)
+							return
+						}
+						handler.ServeHTTP(w, r)
+					})
+					subject, err := cache.NewS3Cache(ref, options, nil, logger)
+					h.AssertNil(t, err)
+					subject.SetPartSize(5)
+
+					err = subject.Upload(context.TODO(), strings.NewReader("some-cache-tarball"))
+					h.AssertError(t, err, "uploading part 2 of s3://some-bucket/some/prefix/my_repo_latest/build-cache.tar: unexpected status 500")
+					h.AssertEq(t, fake.aborted, []string{"upload-1"})
+					h.AssertEq(t, len(fake.uploads), 0)
+					h.AssertEq(t, len(fake.objects), 0)
+				})
+			})
+		})
+
+		when("no credentials are configured", func() {
+			it.Before(func() {
+				t.Setenv("AWS_ACCESS_KEY_ID", "")
+				t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+				t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+			})
+
+			it("sends unsigned requests", func() {
+				subject, err := cache.NewS3Cache(ref, options, nil, logger)
+				h.AssertNil(t, err)
+
+				h.AssertNil(t, subject.Upload(context.TODO(), strings.NewReader("some-cache-tarball")))
+				_, err = subject.Download(context.TODO())
+				h.AssertNil(t, err)
+				h.AssertEq(t, fake.auths, []string{"", ""})
+			})
+		})
+	})
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/buildpacks/pack/internal/config"
+)
+
+// UsageStore records the volume and bind caches builds use in a volume keys file, so they can be listed and pruned
+// later
+type UsageStore struct {
+	volumeKeysPath string
+}
+
+// NewUsageStore returns a store recording cache usage in the volume keys file at volumeKeysPath
+func NewUsageStore(volumeKeysPath string) *UsageStore {
+	return &UsageStore{volumeKeysPath: volumeKeysPath}
+}
+
+// RecordUse notes that the cache with the given name was used to build imageRef. kind is one of build, launch or
+// kaniko. Image caches live in a registry, and caches without a name can't be found again, so neither is recorded.
+// Recording with a nil store does nothing.
+func (s *UsageStore) RecordUse(imageRef name.Reference, cacheName string, cacheType Type, kind string, usedAt time.Time) error {
+	if s == nil || cacheType == Image || cacheName == "" {
+		return nil
+	}
+
+	format := cacheType.String()
+	if cacheType == S3 {
+		// only the volume the cache is staged in is kept locally
+		format = Volume.String()
+	}
+
+	return config.UpdateVolumeKeys(s.volumeKeysPath, func(cfg *config.VolumeConfig) error {
+		if cfg.Caches == nil {
+			cfg.Caches = make(map[string]config.CacheUsage)
+		}
+		cfg.Caches[cacheName] = config.CacheUsage{
+			Image:    imageRef.Name(),
+			Type:     kind,
+			Format:   format,
+			LastUsed: usedAt.UTC(),
+		}
+		return nil
+	})
+}
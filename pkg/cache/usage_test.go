@@ -0,0 +1,102 @@
+package cache_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/cache"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestUsageStore(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+
+	spec.Run(t, "UsageStore", testUsageStore, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testUsageStore(t *testing.T, when spec.G, it spec.S) {
+	var (
+		volumeKeysPath string
+		store          *cache.UsageStore
+		ref            name.Reference
+		usedAt         = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	)
+
+	it.Before(func() {
+		volumeKeysPath = filepath.Join(t.TempDir(), "volume-keys.toml")
+		h.AssertNil(t, config.Write(config.VolumeConfig{VolumeKeys: map[string]string{"some-image": "some-key"}}, volumeKeysPath))
+		store = cache.NewUsageStore(volumeKeysPath)
+
+		var err error
+		ref, err = name.ParseReference("my/repo", name.WeakValidation)
+		h.AssertNil(t, err)
+	})
+
+	it("records the image, type and time the cache was used", func() {
+		h.AssertNil(t, store.RecordUse(ref, "some-volume", cache.Volume, "build", usedAt))
+		h.AssertNil(t, store.RecordUse(ref, "/some/dir", cache.Bind, "launch", usedAt))
+
+		cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+		h.AssertNil(t, err)
+		h.AssertEq(t, cfg.VolumeKeys, map[string]string{"some-image": "some-key"})
+		h.AssertEq(t, cfg.Caches, map[string]config.CacheUsage{
+			"some-volume": {Image: "index.docker.io/my/repo:latest", Type: "build", Format: "volume", LastUsed: usedAt},
+			"/some/dir":   {Image: "index.docker.io/my/repo:latest", Type: "launch", Format: "bind", LastUsed: usedAt},
+		})
+	})
+
+	it("records s3 caches as the volume they are staged in", func() {
+		h.AssertNil(t, store.RecordUse(ref, "some-volume", cache.S3, "build", usedAt))
+
+		cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+		h.AssertNil(t, err)
+		h.AssertEq(t, cfg.Caches["some-volume"].Format, "volume")
+	})
+
+	it("does not record image caches", func() {
+		h.AssertNil(t, store.RecordUse(ref, "registry.example.com/cache", cache.Image, "build", usedAt))
+
+		cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+		h.AssertNil(t, err)
+		h.AssertEq(t, len(cfg.Caches), 0)
+	})
+	it("does not record caches without a name", func() {
+		h.AssertNil(t, store.RecordUse(ref, "", cache.Bind, "build", usedAt))
+
+		cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+		h.AssertNil(t, err)
+		h.AssertEq(t, len(cfg.Caches), 0)
+	})
+
+	it("does nothing without a store", func() {
+		var noStore *cache.UsageStore
+		h.AssertNil(t, noStore.RecordUse(ref, "some-volume", cache.Volume, "build", usedAt))
+	})
+
+	it("keeps the records of concurrent builds", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				h.AssertNil(t, store.RecordUse(ref, fmt.Sprintf("volume-%d", i), cache.Volume, "build", usedAt))
+			}(i)
+		}
+		wg.Wait()
+
+		cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+		h.AssertNil(t, err)
+		h.AssertEq(t, len(cfg.Caches), 10)
+		h.AssertEq(t, cfg.VolumeKeys, map[string]string{"some-image": "some-key"})
+	})
+}
@@ -19,7 +19,11 @@ import (
 	"github.com/buildpacks/pack/pkg/logging"
 )
 
-const EnvVolumeKey = "PACK_VOLUME_KEY"
+const (
+	EnvVolumeKey = "PACK_VOLUME_KEY"
+	// VolumePrefix is the prefix of the names of cache volumes generated by pack
+	VolumePrefix = "pack-cache-"
+)
 
 type VolumeCache struct {
 	docker DockerClient
@@ -33,9 +37,7 @@ func NewVolumeCache(imageRef name.Reference, cacheType CacheInfo, suffix string,
 		if err != nil {
 			return nil, err
 		}
-		sum := sha256.Sum256([]byte(imageRef.Name() + volumeKey))
-		vol := paths.FilterReservedNames(fmt.Sprintf("%s-%x", sanitizedRef(imageRef), sum[:6]))
-		volumeName = fmt.Sprintf("pack-cache-%s.%s", vol, suffix)
+		volumeName = VolumeName(imageRef, volumeKey, suffix)
 	} else {
 		volumeName = paths.FilterReservedNames(cacheType.Source)
 	}
@@ -46,28 +48,31 @@ func NewVolumeCache(imageRef name.Reference, cacheType CacheInfo, suffix string,
 	}, nil
 }
 
-func getVolumeKey(imageRef name.Reference, logger logging.Logger) (string, error) {
-	var foundKey string
-
-	// first, look for key in env
+// VolumeName returns the name of the cache volume generated for imageRef with the given volume key
+func VolumeName(imageRef name.Reference, volumeKey, suffix string) string {
+	sum := sha256.Sum256([]byte(imageRef.Name() + volumeKey))
+	vol := paths.FilterReservedNames(fmt.Sprintf("%s-%x", sanitizedRef(imageRef), sum[:6]))
+	return fmt.Sprintf("%s%s.%s", VolumePrefix, vol, suffix)
+}
 
-	foundKey = os.Getenv(EnvVolumeKey)
-	if foundKey != "" {
-		return foundKey, nil
+// ExistingVolumeName returns the name of the cache volume NewVolumeCache would use for imageRef, without generating a
+// volume key when there's none: the name is empty then, as it's only generated by the first build of the image.
+func ExistingVolumeName(imageRef name.Reference, cacheType CacheInfo, suffix string) (string, error) {
+	if cacheType.Source != "" {
+		return paths.FilterReservedNames(cacheType.Source), nil
 	}
-
-	// then, look for key in existing config
-
-	volumeKeysPath, err := config.DefaultVolumeKeysPath()
-	if err != nil {
+	volumeKey, err := lookupVolumeKey(imageRef)
+	if err != nil || volumeKey == "" {
 		return "", err
 	}
-	cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+	return VolumeName(imageRef, volumeKey, suffix), nil
+}
+
+func getVolumeKey(imageRef name.Reference, logger logging.Logger) (string, error) {
+	foundKey, err := lookupVolumeKey(imageRef)
 	if err != nil {
 		return "", err
 	}
-
-	foundKey = cfg.VolumeKeys[imageRef.Name()]
 	if foundKey != "" {
 		return foundKey, nil
 	}
@@ -80,16 +85,49 @@ func getVolumeKey(imageRef name.Reference, logger logging.Logger) (string, error
 		logger.Warnf("%s is unset; set this environment variable to a secret value to avoid creating a new volume cache on every build", EnvVolumeKey)
 	}
 
-	newKey := randString(20)
-	if cfg.VolumeKeys == nil {
-		cfg.VolumeKeys = make(map[string]string)
+	volumeKeysPath, err := config.DefaultVolumeKeysPath()
+	if err != nil {
+		return "", err
 	}
-	cfg.VolumeKeys[imageRef.Name()] = newKey
-	if err = config.Write(cfg, volumeKeysPath); err != nil {
+	err = config.UpdateVolumeKeys(volumeKeysPath, func(cfg *config.VolumeConfig) error {
+		// another build may have created the key in the meantime
+		if foundKey = cfg.VolumeKeys[imageRef.Name()]; foundKey != "" {
+			return nil
+		}
+		foundKey = randString(20)
+		if cfg.VolumeKeys == nil {
+			cfg.VolumeKeys = make(map[string]string)
+		}
+		cfg.VolumeKeys[imageRef.Name()] = foundKey
+		return nil
+	})
+	if err != nil {
 		return "", err
 	}
 
-	return newKey, nil
+	return foundKey, nil
+}
+
+// lookupVolumeKey returns the volume key of imageRef from the environment or the config, or an empty string when
+// there's none
+func lookupVolumeKey(imageRef name.Reference) (string, error) {
+	// first, look for key in env
+
+	if foundKey := os.Getenv(EnvVolumeKey); foundKey != "" {
+		return foundKey, nil
+	}
+
+	// then, look for key in existing config
+
+	volumeKeysPath, err := config.DefaultVolumeKeysPath()
+	if err != nil {
+		return "", err
+	}
+	cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+	if err != nil {
+		return "", err
+	}
+	return cfg.VolumeKeys[imageRef.Name()], nil
 }
 
 // Returns a string iwith lowercase a-z, of length n
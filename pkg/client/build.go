@@ -28,6 +28,7 @@ import (
 	"github.com/pkg/errors"
 	ignore "github.com/sabhiram/go-gitignore"
 
+	pubbldr "github.com/buildpacks/pack/builder"
 	"github.com/buildpacks/pack/buildpackage"
 	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
@@ -40,11 +41,15 @@ import (
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/internal/termui"
 	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/metrics"
+	"github.com/buildpacks/pack/pkg/policy"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 	v02 "github.com/buildpacks/pack/pkg/project/v02"
 )
@@ -220,12 +225,29 @@ type BuildOptions struct {
 	// trusted
 	TrustExtraBuildpacks bool
 
+	// PEM encoded public key the signature of the builder is verified with before trusting it.
+	// A trusted builder whose signature doesn't verify is used as an untrusted builder.
+	BuilderKey []byte
+
+	// PEM encoded public key the signatures of the run image and of buildpack package images are verified with.
+	// The build fails when one of them doesn't verify.
+	ImageKey []byte
+
+	// Policy the builder, run image, buildpacks, extensions and volumes are evaluated against before building, if any.
+	Policy *policy.Policy
+
 	// Directory to output any SBOM artifacts
 	SBOMDestinationDir string
 
 	// Directory to output the report.toml metadata artifact
+	// and the build-metrics.json file describing each lifecycle phase
 	ReportDestinationDir string
 
+	// Directory to output an in-toto statement with the SLSA provenance of the image.
+	// When publishing, the statement is also attached to the image as an OCI referrer. Otherwise the image,
+	// which has no manifest digest in the daemon, is identified by its image ID in an 'imageId' annotation.
+	ProvenanceDestinationDir string
+
 	// Desired create time in the output image config
 	CreationTime *time.Time
 
@@ -236,6 +258,29 @@ type BuildOptions struct {
 	EnableUsernsHost bool
 
 	InsecureRegistries []string
+
+	// EventHandler, when set, receives structured events describing the progress of the build,
+	// such as phase timings, detection results and layer decisions.
+	EventHandler events.Handler
+
+	// MetricsHandler, when set, receives the wall-clock time, container exit code
+	// and bytes copied for each lifecycle phase once the lifecycle has finished running,
+	// whether or not the build succeeded.
+	MetricsHandler func(metrics.Build)
+
+	// DryRun resolves the builder, run image, lifecycle, buildpacks, env vars, volumes and caches
+	// for the build without creating any images or running the lifecycle. Images are read from the
+	// daemon when they're there and from the registry otherwise, rather than pulled, and no volume
+	// key is generated: cache volumes of images never built are left unnamed. Buildpack archives are
+	// still downloaded, to pack's download cache, as the plan lists the buildpacks they contain.
+	DryRun bool
+
+	// PlanHandler, when set, receives the fully resolved plan right before the lifecycle would run.
+	PlanHandler func(BuildPlan)
+
+	// layerHandler, when set, receives the events of layers being added or reused, without the build
+	// emitting the other events EventHandler receives
+	layerHandler events.Handler
 }
 
 func (b *BuildOptions) Layout() bool {
@@ -306,6 +351,47 @@ type layoutPathConfig struct {
 // If any configuration is deemed invalid, or if any lifecycle phases fail,
 // an error will be returned and no image produced.
 func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
+	return c.build(ctx, opts, nil)
+}
+
+// partialRun holds the settings and results of a build that stops before exporting an image
+type partialRun struct {
+	// build runs the build phase after detection, and copies the layers dir to destinationDir
+	// instead of group.toml and plan.toml
+	build          bool
+	destinationDir string
+	order          pubbldr.DetectionOrder
+	// ephemeralCaches skips recording the use of the caches, which are removed after the run
+	ephemeralCaches bool
+}
+
+// ephemeralVolumeCaches names the volume caches of a run that aren't named yet after name, rather than after a volume
+// key the run would persist, and returns their names so they can be removed once the run is done
+func ephemeralVolumeCaches(cacheOpts *cache.CacheOpts, name string) []string {
+	var volumes []string
+	for _, c := range []struct {
+		info   *cache.CacheInfo
+		suffix string
+	}{{&cacheOpts.Build, "build"}, {&cacheOpts.Launch, "launch"}, {&cacheOpts.Kaniko, "kaniko"}} {
+		if c.info.Format != cache.CacheVolume || c.info.Source != "" {
+			continue
+		}
+		c.info.Source = fmt.Sprintf("%s%s.%s", cache.VolumePrefix, name, c.suffix)
+		volumes = append(volumes, c.info.Source)
+	}
+	return volumes
+}
+
+// removeVolumes removes the volumes, logging the ones that can't be removed
+func (c *Client) removeVolumes(volumes []string) {
+	for _, volume := range volumes {
+		if err := c.removeVolume(context.Background(), volume); err != nil {
+			c.logger.Debugf("Failed to remove volume %s: %s", style.Symbol(volume), err)
+		}
+	}
+}
+
+func (c *Client) build(ctx context.Context, opts BuildOptions, partial *partialRun) error {
 	var pathsConfig layoutPathConfig
 
 	if RunningInContainer() && (opts.PullPolicy != image.PullAlways) {
@@ -327,6 +413,10 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 	imgRegistry := imageRef.Context().RegistryStr()
 	imageName := imageRef.Name()
 
+	if opts.Layout() && opts.ProvenanceDestinationDir != "" {
+		return errors.New("provenance cannot be generated for images exported to OCI layout")
+	}
+
 	if opts.Layout() {
 		pathsConfig, err = c.processLayoutPath(opts.LayoutConfig.InputImage, opts.LayoutConfig.PreviousInputImage)
 		if err != nil {
@@ -367,7 +457,12 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		}
 	}()
 
-	rawBuilderImage, err := c.imageFetcher.FetchForPlatform(
+	fetchImage := c.imageFetcher.FetchForPlatform
+	if opts.DryRun {
+		fetchImage = c.fetchWithoutPulling
+	}
+
+	rawBuilderImage, err := fetchImage(
 		ctx,
 		builderRef.Name(),
 		image.FetchOptions{
@@ -420,10 +515,34 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		pathsConfig.hostRunImagePath = hostRunImagePath
 	}
 
-	runImage, warnings, err := c.validateRunImage(ctx, runImageName, fetchOptions, bldr.StackID)
+	if opts.Policy != nil {
+		if err := opts.Policy.Enforce(c.logger, buildPolicyInputs(opts, builderRef.Name(), runImageName)); err != nil {
+			return err
+		}
+	}
+
+	var verifiedRunImage *verifiedImage
+	if len(opts.ImageKey) > 0 {
+		verified, err := c.verifySignature(ctx, runImageName, opts.ImageKey, opts.InsecureRegistries)
+		if err != nil {
+			return errors.Wrapf(err, "verifying run-image %s", style.Symbol(runImageName))
+		}
+		verifiedRunImage = &verified
+	}
+
+	fetchRunImage := c.imageFetcher.Fetch
+	if opts.DryRun {
+		fetchRunImage = c.fetchWithoutPulling
+	}
+	runImage, warnings, err := c.validateRunImage(ctx, fetchRunImage, runImageName, fetchOptions, bldr.StackID)
 	if err != nil {
 		return errors.Wrapf(err, "invalid run-image '%s'", runImageName)
 	}
+	if verifiedRunImage != nil {
+		if err := verifiedRunImage.check(runImage); err != nil {
+			return errors.Wrapf(err, "verifying run-image %s", style.Symbol(runImageName))
+		}
+	}
 	for _, warning := range warnings {
 		c.logger.Warn(warning)
 	}
@@ -453,6 +572,25 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		opts.TrustBuilder = builder.IsKnownTrustedBuilder
 	}
 
+	// A tag can be repointed: a builder with a key is only trusted when the image it refers to is signed with it
+	if len(opts.BuilderKey) > 0 && opts.TrustBuilder(opts.Builder) {
+		verified, err := c.verifySignature(ctx, builderRef.Name(), opts.BuilderKey, opts.InsecureRegistries)
+		if err == nil {
+			// the builder the build uses was fetched on its own, possibly from the daemon
+			err = verified.check(rawBuilderImage)
+		}
+		if err != nil {
+			c.logger.Warnf("Builder %s is not trusted, as its signature doesn't verify: %s", style.Symbol(opts.Builder), err)
+			opts.TrustBuilder = func(string) bool { return false }
+		}
+	}
+
+	if opts.Policy != nil && opts.TrustBuilder(opts.Builder) {
+		if err := opts.Policy.Enforce(c.logger, policy.Inputs{TrustedBuilder: opts.Builder}); err != nil {
+			return err
+		}
+	}
+
 	// Ensure the builder's platform APIs are supported
 	var builderPlatformAPIs builder.APISet
 	builderPlatformAPIs = append(builderPlatformAPIs, bldr.LifecycleDescriptor().APIs.Platform.Deprecated...)
@@ -480,6 +618,10 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		c.logger.Warnf("Builder is trusted but additional modules were added; using the untrusted (5 phases) build flow")
 		useCreator = false
 	}
+	if partial != nil {
+		// the creator cannot stop before exporting
+		useCreator = false
+	}
 	var (
 		lifecycleOptsLifecycleImage string
 		lifecycleAPIs               []string
@@ -492,16 +634,13 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 				lifecycleImageName = fmt.Sprintf("%s:%s", internalConfig.DefaultLifecycleImageRepo, lifecycleVersion.String())
 			}
 
-			lifecycleImage, err := c.imageFetcher.FetchForPlatform(
-				ctx,
-				lifecycleImageName,
-				image.FetchOptions{
-					Daemon:             true,
-					PullPolicy:         opts.PullPolicy,
-					Target:             targetToUse,
-					InsecureRegistries: opts.InsecureRegistries,
-				},
-			)
+			lifecycleFetchOptions := image.FetchOptions{
+				Daemon:             true,
+				PullPolicy:         opts.PullPolicy,
+				Target:             targetToUse,
+				InsecureRegistries: opts.InsecureRegistries,
+			}
+			lifecycleImage, err := fetchImage(ctx, lifecycleImageName, lifecycleFetchOptions)
 			if err != nil {
 				return fmt.Errorf("fetching lifecycle image: %w", err)
 			}
@@ -511,7 +650,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 			if err != nil {
 				return errors.Wrap(err, "getting lifecycle image OS")
 			}
-			if imageOS != "windows" {
+			if imageOS != "windows" && !opts.DryRun {
 				// obtain uid/gid from builder to use when extending lifecycle image
 				uid, gid, err := userAndGroupIDs(rawBuilderImage)
 				if err != nil {
@@ -564,8 +703,14 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		buildEnvs[k] = v
 	}
 
+	// a dry run only reads the ephemeral builder, so it isn't saved
+	newBuilder := c.createEphemeralBuilder
+	if opts.DryRun {
+		newBuilder = c.newEphemeralBuilder
+	}
+
 	origBuilderName := rawBuilderImage.Name()
-	ephemeralBuilder, err := c.createEphemeralBuilder(
+	ephemeralBuilder, err := newBuilder(
 		rawBuilderImage,
 		buildEnvs,
 		order,
@@ -581,7 +726,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return err
 	}
 	defer func() {
-		if ephemeralBuilder.Name() == origBuilderName {
+		if ephemeralBuilder.Name() == origBuilderName || opts.DryRun {
 			return
 		}
 		_, _ = c.docker.ImageRemove(context.Background(), ephemeralBuilder.Name(), client.ImageRemoveOptions{Force: true})
@@ -614,9 +759,14 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return err
 	}
 
-	runImageName, err = pname.TranslateRegistry(runImageName, c.registryMirrors, c.logger)
-	if err != nil {
-		return err
+	if c.mirror.IsOffline() && fetchOptions.Daemon {
+		// the run image may have been loaded from the mirror under another name, e.g. tagged with its digest
+		runImageName = runImage.Name()
+	} else {
+		runImageName, err = pname.TranslateRegistry(runImageName, c.registryMirrors, c.logger)
+		if err != nil {
+			return err
+		}
 	}
 
 	projectMetadata := files.ProjectMetadata{}
@@ -649,6 +799,7 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		UseCreatorWithExtensions: supportsCreatorWithExtensions(lifecycleVersion),
 		DockerHost:               opts.DockerHost,
 		Cache:                    opts.Cache,
+		CacheUsage:               cacheUsageStore(),
 		CacheImage:               opts.CacheImage,
 		HTTPProxy:                proxyConfig.HTTPProxy,
 		HTTPSProxy:               proxyConfig.HTTPSProxy,
@@ -672,6 +823,9 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		EnableUsernsHost:         opts.EnableUsernsHost,
 		ExecutionEnvironment:     opts.CNBExecutionEnv,
 		InsecureRegistries:       opts.InsecureRegistries,
+		EventHandler:             opts.EventHandler,
+		LayerHandler:             opts.layerHandler,
+		MetricsHandler:           opts.MetricsHandler,
 	}
 
 	switch {
@@ -684,6 +838,40 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return errors.Errorf("Lifecycle %s does not have an associated lifecycle image. Builder must be trusted.", lifecycleVersion.String())
 	}
 
+	if opts.PlanHandler != nil || opts.DryRun {
+		plan, err := c.newBuildPlan(lifecycleOpts, builderRef.Name(), ephemeralBuilder, ephemeralBuilder.Name() != origBuilderName, usingPlatformAPI, targetToUse, fetchedBPs, fetchedExs, buildEnvs)
+		if err != nil {
+			return errors.Wrap(err, "resolving build plan")
+		}
+		if opts.PlanHandler != nil {
+			opts.PlanHandler(plan)
+		}
+		if opts.DryRun {
+			return nil
+		}
+	}
+
+	if partial != nil {
+		buildpackLayers := dist.ModuleLayers{}
+		if _, err := dist.GetLabel(ephemeralBuilder.Image(), dist.BuildpackLayersLabel, &buildpackLayers); err != nil {
+			return errors.Wrapf(err, "getting label %s", dist.BuildpackLayersLabel)
+		}
+		partial.order, err = builder.NewDetectionOrderCalculator().Order(ephemeralBuilder.Order(), buildpackLayers, pubbldr.OrderDetectionMaxDepth)
+		if err != nil {
+			return errors.Wrap(err, "calculating detection order")
+		}
+		if partial.build {
+			lifecycleOpts.BuildOnly = true
+			lifecycleOpts.BuildDestinationDir = partial.destinationDir
+		} else {
+			lifecycleOpts.DetectOnly = true
+			lifecycleOpts.DetectDestinationDir = partial.destinationDir
+		}
+		if partial.ephemeralCaches {
+			lifecycleOpts.CacheUsage = nil
+		}
+	}
+
 	lifecycleOpts.FetchRunImageWithLifecycleLayer = func(runImageName string) (string, error) {
 		ephemeralRunImageName := fmt.Sprintf("pack.local/run-image/%x:latest", randString(10))
 		runImage, err := c.imageFetcher.Fetch(ctx, runImageName, fetchOptions)
@@ -831,9 +1019,35 @@ func (c *Client) Build(ctx context.Context, opts BuildOptions) error {
 		return ephemeralRunImageName, nil
 	}
 
+	buildStarted := time.Now()
 	if err = c.lifecycleExecutor.Execute(ctx, lifecycleOpts); err != nil {
 		return fmt.Errorf("executing lifecycle: %w", err)
 	}
+	if partial != nil {
+		return nil
+	}
+	if opts.ProvenanceDestinationDir != "" {
+		if err := c.generateProvenance(ctx, opts, imageRef, provenanceInputs{
+			builderName:      builderRef.Name(),
+			builderImage:     rawBuilderImage,
+			ephemeralBuilder: ephemeralBuilder,
+			lifecycleVersion: lifecycleVersion.String(),
+			platformAPI:      usingPlatformAPI.String(),
+			runImageName:     runImageName,
+			runImage:         runImage,
+			appPath:          appPath,
+			fileFilter:       fileFilter,
+			env:              buildEnvs,
+			startedOn:        buildStarted,
+		}); err != nil {
+			return errors.Wrap(err, "generating provenance")
+		}
+	}
+	if opts.EventHandler != nil {
+		if err := c.emitBuildFinished(ctx, opts, imageRef); err != nil {
+			return err
+		}
+	}
 	return c.logImageNameAndSha(ctx, opts.Publish, imageRef, opts.InsecureRegistries)
 }
 
@@ -968,11 +1182,26 @@ func (c *Client) getBuilder(img imgutil.Image) (*builder.Builder, error) {
 	return bldr, nil
 }
 
-func (c *Client) validateRunImage(context context.Context, name string, opts image.FetchOptions, expectedStack string) (runImage imgutil.Image, warnings []string, err error) {
+// fetchWithoutPulling fetches an image that's only inspected, as by a dry run: from the daemon when it's there and
+// the pull policy allows it, otherwise from its registry, so that it isn't pulled
+func (c *Client) fetchWithoutPulling(ctx context.Context, name string, options image.FetchOptions) (imgutil.Image, error) {
+	if options.PullPolicy != image.PullAlways {
+		daemonOptions := options
+		daemonOptions.PullPolicy = image.PullNever
+		img, err := c.imageFetcher.FetchForPlatform(ctx, name, daemonOptions)
+		if err == nil || !errors.Is(err, image.ErrNotFound) || options.PullPolicy == image.PullNever {
+			return img, err
+		}
+	}
+	options.Daemon = false
+	return c.imageFetcher.FetchForPlatform(ctx, name, options)
+}
+
+func (c *Client) validateRunImage(context context.Context, fetch func(context.Context, string, image.FetchOptions) (imgutil.Image, error), name string, opts image.FetchOptions, expectedStack string) (runImage imgutil.Image, warnings []string, err error) {
 	if name == "" {
 		return nil, nil, errors.New("run image must be specified")
 	}
-	img, err := c.imageFetcher.Fetch(context, name, opts)
+	img, err := fetch(context, name, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1356,6 +1585,22 @@ func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir
 			Version: version,
 		}
 	default:
+		if bp, err = c.enforceModulePolicy(opts.Policy, opts.Registry, bp, relativeBaseDir, builderBPs); err != nil {
+			return nil, nil, err
+		}
+		if strings.HasPrefix(bp, "docker://") {
+			locatorType = buildpack.PackageLocator
+		}
+		if locatorType == buildpack.PackageLocator && len(opts.ImageKey) > 0 {
+			imageName := buildpack.ParsePackageLocator(bp)
+			verified, err := c.verifySignature(ctx, imageName, opts.ImageKey, opts.InsecureRegistries)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "verifying buildpack %s", style.Symbol(imageName))
+			}
+			// download the image whose signature verified, rather than whatever the tag refers to by then
+			bp = "docker://" + verified.digest.Name()
+		}
+
 		downloadOptions := buildpack.DownloadOptions{
 			RegistryName:    registry,
 			Target:          targetToUse,
@@ -1366,7 +1611,7 @@ func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir
 		if kind == buildpack.KindExtension {
 			downloadOptions.ModuleKind = kind
 		}
-		mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, bp, downloadOptions)
+		mainBP, depBPs, err := c.downloadModule(ctx, bp, downloadOptions, opts)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "downloading buildpack")
 		}
@@ -1377,7 +1622,7 @@ func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir
 		packageCfgPath := filepath.Join(bp, "package.toml")
 		_, err = os.Stat(packageCfgPath)
 		if err == nil {
-			fetchedDeps, err := c.fetchBuildpackDependencies(ctx, bp, packageCfgPath, downloadOptions)
+			fetchedDeps, err := c.fetchBuildpackDependencies(ctx, bp, packageCfgPath, downloadOptions, opts)
 			if err != nil {
 				return nil, nil, errors.Wrapf(err, "fetching package.toml dependencies (path=%s)", style.Symbol(packageCfgPath))
 			}
@@ -1387,19 +1632,27 @@ func (c *Client) fetchBuildpack(ctx context.Context, bp string, relativeBaseDir
 	return fetchedBPs, moduleInfo, nil
 }
 
-func (c *Client) fetchBuildpackDependencies(ctx context.Context, bp string, packageCfgPath string, downloadOptions buildpack.DownloadOptions) ([]buildpack.BuildModule, error) {
+func (c *Client) fetchBuildpackDependencies(ctx context.Context, bp string, packageCfgPath string, downloadOptions buildpack.DownloadOptions, opts BuildOptions) ([]buildpack.BuildModule, error) {
 	packageReader := buildpackage.NewConfigReader()
 	packageCfg, err := packageReader.Read(packageCfgPath)
 	if err == nil {
 		fetchedBPs := []buildpack.BuildModule{}
 		for _, dep := range packageCfg.Dependencies {
-			mainBP, deps, err := c.buildpackDownloader.Download(ctx, dep.URI, buildpack.DownloadOptions{
+			depURI, err := blob.WithSHA256(dep.URI, dep.SHA256)
+			if err != nil {
+				return nil, err
+			}
+			relativeBaseDir := filepath.Join(bp, packageCfg.Buildpack.URI)
+			if depURI, err = c.enforceModulePolicy(opts.Policy, opts.Registry, depURI, relativeBaseDir, nil); err != nil {
+				return nil, err
+			}
+			mainBP, deps, err := c.downloadModule(ctx, depURI, buildpack.DownloadOptions{
 				RegistryName:    downloadOptions.RegistryName,
 				Target:          downloadOptions.Target,
 				Daemon:          downloadOptions.Daemon,
 				PullPolicy:      downloadOptions.PullPolicy,
-				RelativeBaseDir: filepath.Join(bp, packageCfg.Buildpack.URI),
-			})
+				RelativeBaseDir: relativeBaseDir,
+			}, opts)
 
 			if err != nil {
 				return nil, errors.Wrapf(err, "fetching dependencies (uri=%s,image=%s)", style.Symbol(dep.URI), style.Symbol(dep.ImageName))
@@ -1412,6 +1665,24 @@ func (c *Client) fetchBuildpackDependencies(ctx context.Context, bp string, pack
 	return nil, err
 }
 
+// downloadModule downloads a buildpack or extension. Like the images of a dry run, the module images of a dry run
+// are read from the daemon when they're there and from the registry otherwise, rather than pulled.
+func (c *Client) downloadModule(ctx context.Context, uri string, options buildpack.DownloadOptions, opts BuildOptions) (buildpack.BuildModule, []buildpack.BuildModule, error) {
+	if !opts.DryRun || !options.Daemon {
+		return c.buildpackDownloader.Download(ctx, uri, options)
+	}
+	if options.PullPolicy != image.PullAlways {
+		daemonOptions := options
+		daemonOptions.PullPolicy = image.PullNever
+		mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, uri, daemonOptions)
+		if err == nil || !errors.Is(err, image.ErrNotFound) || options.PullPolicy == image.PullNever {
+			return mainBP, depBPs, err
+		}
+	}
+	options.Daemon = false
+	return c.buildpackDownloader.Download(ctx, uri, options)
+}
+
 func getBuildpackLocator(bp projectTypes.Buildpack, stackID string) (locator string, isInline bool, err error) {
 	switch {
 	case bp.ID != "" && bp.Script.Inline != "" && bp.URI == "":
@@ -1425,7 +1696,8 @@ func getBuildpackLocator(bp projectTypes.Buildpack, stackID string) (locator str
 		}
 		return pathToInlineBuildpack, true, nil
 	case bp.URI != "":
-		return bp.URI, false, nil
+		locator, err := blob.WithSHA256(bp.URI, bp.SHA256)
+		return locator, false, err
 	case bp.ID != "" && bp.Version != "":
 		return fmt.Sprintf("%s@%s", bp.ID, bp.Version), false, nil
 	case bp.ID != "" && bp.Version == "":
@@ -1605,6 +1877,32 @@ func (c *Client) createEphemeralBuilder(
 	runImage string,
 	system dist.System,
 	disableSystem bool,
+) (*builder.Builder, error) {
+	// the builder renames the image it's created from
+	origBuilderName := rawBuilderImage.Name()
+	bldr, err := c.newEphemeralBuilder(rawBuilderImage, env, order, buildpacks, orderExtensions, extensions, validateMixins, runImage, system, disableSystem)
+	if err != nil || bldr.Name() == origBuilderName {
+		return bldr, err
+	}
+	if err := bldr.Save(c.logger, builder.CreatorMetadata{Version: c.version}); err != nil {
+		return nil, err
+	}
+	return bldr, nil
+}
+
+// newEphemeralBuilder returns the builder the build uses, without saving it: the builder itself when
+// nothing is added to it, and a builder named pack.local/builder/<random> otherwise
+func (c *Client) newEphemeralBuilder(
+	rawBuilderImage imgutil.Image,
+	env map[string]string,
+	order dist.Order,
+	buildpacks []buildpack.BuildModule,
+	orderExtensions dist.Order,
+	extensions []buildpack.BuildModule,
+	validateMixins bool,
+	runImage string,
+	system dist.System,
+	disableSystem bool,
 ) (*builder.Builder, error) {
 	if !ephemeralBuilderNeeded(env, order, buildpacks, orderExtensions, extensions, runImage) && !disableSystem {
 		return builder.New(rawBuilderImage, rawBuilderImage.Name(), builder.WithoutSave())
@@ -1639,10 +1937,6 @@ func (c *Client) createEphemeralBuilder(
 
 	bldr.SetValidateMixins(validateMixins)
 	bldr.SetSystem(system)
-
-	if err := bldr.Save(c.logger, builder.CreatorMetadata{Version: c.version}); err != nil {
-		return nil, err
-	}
 	return bldr, nil
 }
 
@@ -1694,25 +1988,75 @@ func (c *Client) logImageNameAndSha(ctx context.Context, publish bool, imageRef
 		return nil
 	}
 
-	img, err := c.imageFetcher.Fetch(ctx, imageRef.Name(), image.FetchOptions{Daemon: !publish, PullPolicy: image.PullNever, InsecureRegistries: insecureRegistries})
-	if err != nil {
-		return fmt.Errorf("fetching built image: %w", err)
-	}
-
-	id, err := img.Identifier()
+	digest, err := c.builtImageDigest(ctx, publish, imageRef, insecureRegistries)
 	if err != nil {
-		return fmt.Errorf("reading image sha: %w", err)
+		return err
 	}
 
 	// Remove tag, if it exists, from the image name
 	imgName := strings.TrimSuffix(imageRef.String(), imageRef.Identifier())
-	imgNameAndSha := fmt.Sprintf("%s@%s\n", imgName, parseDigestFromImageID(id))
+	imgNameAndSha := fmt.Sprintf("%s@%s\n", imgName, digest)
 
-	// Access the logger's Writer directly to bypass ReportSuccessfulQuietBuild mode
-	_, err = c.logger.Writer().Write([]byte(imgNameAndSha))
+	// Access the logger's Writer directly to bypass ReportSuccessfulQuietBuild mode, unless the Writer
+	// is kept for other output, such as build events, with the logs on the error writer
+	out := c.logger.Writer()
+	if logging.UsesStderr(c.logger) {
+		out = logging.GetWriterForLevel(c.logger, logging.ErrorLevel)
+	}
+	_, err = out.Write([]byte(imgNameAndSha))
 	return err
 }
 
+func (c *Client) emitBuildFinished(ctx context.Context, opts BuildOptions, imageRef name.Reference) error {
+	finished := events.Event{
+		Type:  events.BuildFinished,
+		Time:  time.Now(),
+		Image: imageRef.Name(),
+		Tags:  opts.AdditionalTags,
+	}
+
+	// images exported to OCI layout are not available from the daemon or a registry
+	if !opts.Layout() {
+		digest, err := c.builtImageDigest(ctx, opts.Publish, imageRef, opts.InsecureRegistries)
+		if err != nil {
+			return err
+		}
+		finished.Digest = digest
+	}
+
+	opts.EventHandler(finished)
+	return nil
+}
+
+func (c *Client) builtImageDigest(ctx context.Context, publish bool, imageRef name.Reference, insecureRegistries []string) (string, error) {
+	id, err := c.builtImageIdentifier(ctx, publish, imageRef, insecureRegistries)
+	if err != nil {
+		return "", err
+	}
+	return parseDigestFromImageID(id), nil
+}
+
+func (c *Client) builtImageIdentifier(ctx context.Context, publish bool, imageRef name.Reference, insecureRegistries []string) (imgutil.Identifier, error) {
+	img, err := c.fetchBuiltImage(ctx, publish, imageRef, insecureRegistries)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := img.Identifier()
+	if err != nil {
+		return nil, fmt.Errorf("reading image sha: %w", err)
+	}
+	return id, nil
+}
+
+func (c *Client) fetchBuiltImage(ctx context.Context, publish bool, imageRef name.Reference, insecureRegistries []string) (imgutil.Image, error) {
+	img, err := c.imageFetcher.Fetch(ctx, imageRef.Name(), image.FetchOptions{Daemon: !publish, PullPolicy: image.PullNever, InsecureRegistries: insecureRegistries})
+	if err != nil {
+		return nil, fmt.Errorf("fetching built image: %w", err)
+	}
+	return img, nil
+}
+
 func parseDigestFromImageID(id imgutil.Identifier) string {
 	var digest string
 	switch v := id.(type) {
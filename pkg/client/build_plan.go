@@ -0,0 +1,165 @@
+package client
+
+import (
+	"sort"
+
+	"github.com/buildpacks/lifecycle/api"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/buildpacks/pack/internal/build"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// BuildPlan describes everything Build resolved for a build before running the lifecycle.
+type BuildPlan struct {
+	Image          string   `json:"image"`
+	AdditionalTags []string `json:"additionalTags,omitempty"`
+	Publish        bool     `json:"publish"`
+
+	Builder string `json:"builder"`
+	// EphemeralBuilder is true when the builder has to be extended with additional buildpacks,
+	// extensions, env vars or a run image before running the build
+	EphemeralBuilder bool   `json:"ephemeralBuilder"`
+	LifecycleVersion string `json:"lifecycleVersion"`
+	PlatformAPI      string `json:"platformApi"`
+	Platform         string `json:"platform"`
+
+	// RunImage is the run image after mirror selection and registry mirror translation
+	RunImage string `json:"runImage"`
+	// LifecycleImage is empty when the lifecycle from the builder is used
+	LifecycleImage string `json:"lifecycleImage,omitempty"`
+
+	TrustedBuilder bool `json:"trustedBuilder"`
+	// UseCreator is true when all phases run in a single container using the creator
+	UseCreator bool `json:"useCreator"`
+
+	// Buildpacks and Extensions are the modules added to the builder for this build
+	Buildpacks      []dist.ModuleInfo `json:"buildpacks,omitempty"`
+	Extensions      []dist.ModuleInfo `json:"extensions,omitempty"`
+	Order           dist.Order        `json:"order"`
+	OrderExtensions dist.Order        `json:"orderExtensions,omitempty"`
+
+	// Env holds the names of the build-time env vars; values are omitted as they may contain secrets
+	Env     []string `json:"env,omitempty"`
+	Volumes []string `json:"volumes,omitempty"`
+	// Network is empty when an ephemeral bridge network is created for the build
+	Network   string `json:"network,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+
+	Caches BuildPlanCaches `json:"caches"`
+}
+
+// BuildPlanCaches describes the caches used by a build
+type BuildPlanCaches struct {
+	Build  BuildPlanCache `json:"build"`
+	Launch BuildPlanCache `json:"launch"`
+	Kaniko BuildPlanCache `json:"kaniko"`
+}
+
+// BuildPlanCache describes a single cache: a docker volume, an image or a bind-mounted directory
+type BuildPlanCache struct {
+	Format string `json:"format"`
+	// Name is empty for a volume named after a volume key the first build of the image generates
+	Name string `json:"name"`
+}
+
+func (c *Client) newBuildPlan(
+	lifecycleOpts build.LifecycleOptions,
+	builderName string,
+	bldr *builder.Builder,
+	ephemeralBuilder bool,
+	platformAPI *api.Version,
+	target *dist.Target,
+	buildpacks []buildpack.BuildModule,
+	extensions []buildpack.BuildModule,
+	env map[string]string,
+) (BuildPlan, error) {
+	plan := BuildPlan{
+		Image:            lifecycleOpts.Image.Name(),
+		AdditionalTags:   lifecycleOpts.AdditionalTags,
+		Publish:          lifecycleOpts.Publish,
+		Builder:          builderName,
+		EphemeralBuilder: ephemeralBuilder,
+		LifecycleVersion: bldr.LifecycleDescriptor().Info.Version.String(),
+		PlatformAPI:      platformAPI.String(),
+		Platform:         target.ValuesAsPlatform(),
+		RunImage:         lifecycleOpts.RunImage,
+		TrustedBuilder:   lifecycleOpts.TrustBuilder,
+		UseCreator:       lifecycleOpts.UseCreator,
+		Order:            bldr.Order(),
+		OrderExtensions:  bldr.OrderExtensions(),
+		Volumes:          lifecycleOpts.Volumes,
+		Network:          lifecycleOpts.Network,
+		Workspace:        lifecycleOpts.Workspace,
+	}
+	if !lifecycleOpts.UseCreator {
+		plan.LifecycleImage = lifecycleOpts.LifecycleImage
+	}
+
+	for _, bp := range buildpacks {
+		plan.Buildpacks = append(plan.Buildpacks, bp.Descriptor().Info())
+	}
+	for _, ex := range extensions {
+		plan.Extensions = append(plan.Extensions, ex.Descriptor().Info())
+	}
+
+	for k := range env {
+		plan.Env = append(plan.Env, k)
+	}
+	sort.Strings(plan.Env)
+
+	// the caches are described without creating them, or generating the volume keys their names derive from
+	buildCache, err := planBuildCache(lifecycleOpts)
+	if err != nil {
+		return BuildPlan{}, err
+	}
+	plan.Caches.Build = buildCache
+
+	plan.Caches.Launch, err = planVolumeCache(lifecycleOpts.Image, lifecycleOpts.Cache.Launch, "launch")
+	if err != nil {
+		return BuildPlan{}, err
+	}
+
+	// older lifecycles re-use a build cache volume as the kaniko cache
+	if buildCache.Format == cache.CacheVolume.String() && platformAPI.LessThan("0.12") {
+		plan.Caches.Kaniko = plan.Caches.Build
+	} else {
+		plan.Caches.Kaniko, err = planVolumeCache(lifecycleOpts.Image, lifecycleOpts.Cache.Kaniko, "kaniko")
+		if err != nil {
+			return BuildPlan{}, err
+		}
+	}
+
+	return plan, nil
+}
+
+// planBuildCache describes the build cache build.NewBuildCache creates
+func planBuildCache(lifecycleOpts build.LifecycleOptions) (BuildPlanCache, error) {
+	if lifecycleOpts.CacheImage != "" {
+		return BuildPlanCache{Format: cache.CacheImage.String(), Name: lifecycleOpts.CacheImage}, nil
+	}
+
+	info := lifecycleOpts.Cache.Build
+	switch info.Format {
+	case cache.CacheImage, cache.CacheBind:
+		return BuildPlanCache{Format: info.Format.String(), Name: info.Source}, nil
+	case cache.CacheS3:
+		// s3 caches are staged in a build cache volume
+		volume, err := planVolumeCache(lifecycleOpts.Image, cache.CacheInfo{}, "build")
+		volume.Format = cache.CacheS3.String()
+		return volume, err
+	default:
+		return planVolumeCache(lifecycleOpts.Image, info, "build")
+	}
+}
+
+func planVolumeCache(imageRef name.Reference, info cache.CacheInfo, suffix string) (BuildPlanCache, error) {
+	volume, err := cache.ExistingVolumeName(imageRef, info, suffix)
+	if err != nil {
+		return BuildPlanCache{}, err
+	}
+	return BuildPlanCache{Format: cache.CacheVolume.String(), Name: volume}, nil
+}
@@ -3,12 +3,17 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -22,13 +27,19 @@ import (
 	"github.com/buildpacks/imgutil/remote"
 	"github.com/buildpacks/lifecycle/api"
 	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	ggcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/heroku/color"
 	dockerclient "github.com/moby/moby/client"
 	"github.com/onsi/gomega/ghttp"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 
+	"github.com/buildpacks/pack/internal/build"
 	"github.com/buildpacks/pack/internal/builder"
 	cfg "github.com/buildpacks/pack/internal/config"
 	ifakes "github.com/buildpacks/pack/internal/fakes"
@@ -36,9 +47,13 @@ import (
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/cache"
 	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/events"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
+	"github.com/buildpacks/pack/pkg/policy"
 	projectTypes "github.com/buildpacks/pack/pkg/project/types"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -297,6 +312,98 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 
 					h.AssertEq(t, strings.TrimSpace(outBuf.String()), "some/app@sha256:363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
 				})
+
+				it("prints app name and sha when an event handler is set, and emits the build-finished event", func() {
+					logger.WantQuiet(true)
+
+					var finished []events.Event
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:   "some/app",
+						Builder: defaultBuilderName,
+						AppPath: filepath.Join("testdata", "some-app"),
+						EventHandler: func(e events.Event) {
+							if e.Type == events.BuildFinished {
+								finished = append(finished, e)
+							}
+						},
+					}))
+
+					h.AssertEq(t, strings.TrimSpace(outBuf.String()), "some/app@sha256:363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
+					h.AssertEq(t, len(finished), 1)
+					h.AssertEq(t, finished[0].Digest, "sha256:363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4")
+				})
+			})
+		})
+
+		when("ProvenanceDestinationDir option", func() {
+			var (
+				builtImage    *fakes.Image
+				provenanceDir string
+			)
+
+			it.Before(func() {
+				builtImage = fakes.NewImage("index.docker.io/some/app:latest", "", local.IDIdentifier{
+					ImageID: "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4",
+				})
+				h.AssertNil(t, builtImage.SetLabel("io.buildpacks.build.metadata", `{"buildpacks": [{"id": "buildpack.2.id", "version": "buildpack.2.version"}]}`))
+				fakeImageFetcher.LocalImages[builtImage.Name()] = builtImage
+				provenanceDir = filepath.Join(tmpDir, "provenance")
+			})
+
+			it.After(func() {
+				h.AssertNilE(t, builtImage.Cleanup())
+			})
+
+			it("writes the provenance of the image", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:                    "some/app",
+					Builder:                  defaultBuilderName,
+					AppPath:                  filepath.Join("testdata", "some-app"),
+					Env:                      map[string]string{"SOME_SECRET": "some-secret-value"},
+					ProvenanceDestinationDir: provenanceDir,
+				}))
+
+				contents, err := os.ReadFile(filepath.Join(provenanceDir, ProvenanceFileName))
+				h.AssertNil(t, err)
+				h.AssertNotContains(t, string(contents), "some-secret-value")
+
+				var statement ProvenanceStatement
+				h.AssertNil(t, json.Unmarshal(contents, &statement))
+				h.AssertEq(t, statement.Type, InTotoStatementType)
+				h.AssertEq(t, statement.PredicateType, SLSAProvenancePredicateType)
+				h.AssertEq(t, statement.Subject, []ResourceDescriptor{{
+					Name:        "index.docker.io/some/app",
+					Annotations: map[string]string{"imageId": "sha256:363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4"},
+				}})
+
+				definition := statement.Predicate.BuildDefinition
+				h.AssertEq(t, definition.ExternalParameters.Builder, defaultBuilderName)
+				h.AssertEq(t, definition.ExternalParameters.RunImage, "default/run")
+				h.AssertEq(t, definition.ExternalParameters.Env, []string{"SOME_SECRET"})
+				h.AssertEq(t, len(definition.ExternalParameters.Source.Digest["sha256"]), 64)
+				h.AssertEq(t, definition.InternalParameters.LifecycleVersion, builder.DefaultLifecycleVersion)
+
+				var names []string
+				for _, dependency := range definition.ResolvedDependencies {
+					names = append(names, dependency.Name)
+				}
+				// only the buildpacks of the group that passed detection took part in the build
+				h.AssertEq(t, names, []string{"builder", "run-image", "lifecycle", "buildpack.2.id@buildpack.2.version"})
+			})
+
+			when("exporting to OCI layout", func() {
+				it("errors", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:                    "some/app",
+						Builder:                  defaultBuilderName,
+						ProvenanceDestinationDir: provenanceDir,
+						LayoutConfig: &LayoutConfig{
+							InputImage:    ParseInputImageReference("oci:some-app"),
+							LayoutRepoDir: tmpDir,
+						},
+					})
+					h.AssertError(t, err, "provenance cannot be generated for images exported to OCI layout")
+				})
 			})
 		})
 
@@ -535,6 +642,32 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("offline and the run image was loaded from the mirror under another name", func() {
+				var loadedRunImage *fakes.Image
+
+				it.Before(func() {
+					loadedRunImage = fakes.NewImage("custom/run:"+strings.Repeat("a", 64), "", nil)
+					h.AssertNil(t, loadedRunImage.SetLabel("io.buildpacks.stack.id", defaultBuilderStackID))
+					fakeImageFetcher.LocalImages["custom/run@sha256:"+strings.Repeat("a", 64)] = loadedRunImage
+
+					subject.mirror = mirror.New(filepath.Join(tmpDir, "mirror"))
+					subject.mirror.SetOffline(true)
+				})
+
+				it.After(func() {
+					h.AssertNilE(t, loadedRunImage.Cleanup())
+				})
+
+				it("passes the name the run image was loaded as to the lifecycle", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:    "some/app",
+						Builder:  defaultBuilderName,
+						RunImage: "custom/run@sha256:" + strings.Repeat("a", 64),
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.RunImage, loadedRunImage.Name())
+				})
+			})
+
 			when("run image stack does not match the builder stack", func() {
 				it.Before(func() {
 					h.AssertNil(t, fakeRunImage.SetLabel("io.buildpacks.stack.id", "other.stack"))
@@ -681,6 +814,401 @@ func testBuild(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("DryRun option", func() {
+			var (
+				plan       BuildPlan
+				planCalled bool
+				cacheOpts  cache.CacheOpts
+			)
+
+			it.Before(func() {
+				planCalled = false
+				fakeImageFetcher.RemoteImages[fakeLifecycleImage.Name()] = fakeLifecycleImage
+				fakeImageFetcher.RemoteImages[defaultBuilderImage.Name()] = defaultBuilderImage
+				fakeImageFetcher.RemoteImages[fakeDefaultRunImage.Name()] = fakeDefaultRunImage
+				cacheOpts = cache.CacheOpts{
+					Build:  cache.CacheInfo{Format: cache.CacheVolume, Source: "some-build-volume"},
+					Launch: cache.CacheInfo{Format: cache.CacheVolume, Source: "some-launch-volume"},
+					Kaniko: cache.CacheInfo{Format: cache.CacheVolume, Source: "some-kaniko-volume"},
+				}
+			})
+
+			it("resolves the plan without running the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Env:     map[string]string{"SOME_KEY": "some-secret-value"},
+					Cache:   cacheOpts,
+					DryRun:  true,
+					PlanHandler: func(p BuildPlan) {
+						planCalled = true
+						plan = p
+					},
+				}))
+				h.AssertNil(t, fakeLifecycle.Opts.Image)
+
+				h.AssertTrue(t, planCalled)
+				h.AssertEq(t, plan.Image, "index.docker.io/some/app:latest")
+				h.AssertEq(t, plan.Builder, defaultBuilderName)
+				h.AssertEq(t, plan.RunImage, "default/run")
+				h.AssertEq(t, plan.EphemeralBuilder, true)
+				h.AssertEq(t, plan.Env, []string{"SOME_KEY"})
+				h.AssertEq(t, plan.Caches.Build, BuildPlanCache{Format: "volume", Name: "some-build-volume"})
+				h.AssertEq(t, plan.Caches.Launch, BuildPlanCache{Format: "volume", Name: "some-launch-volume"})
+				h.AssertEq(t, plan.Caches.Kaniko, BuildPlanCache{Format: "volume", Name: "some-kaniko-volume"})
+				h.AssertEq(t, len(plan.Order), 2)
+			})
+
+			it("doesn't pull the lifecycle image", func() {
+				delete(fakeImageFetcher.LocalImages, fakeLifecycleImage.Name())
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Cache:      cacheOpts,
+					PullPolicy: image.PullIfNotPresent,
+					DryRun:     true,
+				}))
+				_, pulled := fakeImageFetcher.LocalImages[fakeLifecycleImage.Name()]
+				h.AssertFalse(t, pulled)
+			})
+
+			it("doesn't pull the builder or run image", func() {
+				delete(fakeImageFetcher.LocalImages, defaultBuilderImage.Name())
+				delete(fakeImageFetcher.LocalImages, fakeDefaultRunImage.Name())
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Cache:      cacheOpts,
+					PullPolicy: image.PullIfNotPresent,
+					DryRun:     true,
+					PlanHandler: func(p BuildPlan) {
+						plan = p
+					},
+				}))
+				h.AssertEq(t, plan.RunImage, "default/run")
+				_, pulled := fakeImageFetcher.LocalImages[defaultBuilderImage.Name()]
+				h.AssertFalse(t, pulled)
+				_, pulled = fakeImageFetcher.LocalImages[fakeDefaultRunImage.Name()]
+				h.AssertFalse(t, pulled)
+			})
+
+			it("doesn't save the ephemeral builder", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Env:     map[string]string{"SOME_KEY": "some-value"},
+					Cache:   cacheOpts,
+					DryRun:  true,
+					PlanHandler: func(p BuildPlan) {
+						plan = p
+					},
+				}))
+				h.AssertTrue(t, plan.EphemeralBuilder)
+				_, err := defaultBuilderImage.FindLayerWithPath("/platform/env/SOME_KEY")
+				h.AssertNotNil(t, err)
+			})
+
+			it("doesn't generate volume keys for the caches", func() {
+				packHome := filepath.Join(tmpDir, "pack-home")
+				t.Setenv("PACK_HOME", packHome)
+
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					DryRun:  true,
+					PlanHandler: func(p BuildPlan) {
+						plan = p
+					},
+				}))
+				h.AssertEq(t, plan.Caches.Build, BuildPlanCache{Format: "volume"})
+				h.AssertEq(t, plan.Caches.Launch, BuildPlanCache{Format: "volume"})
+				h.AssertPathDoesNotExists(t, filepath.Join(packHome, "volume-keys.toml"))
+			})
+
+			it("reports the plan before running the lifecycle", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Cache:   cacheOpts,
+					PlanHandler: func(p BuildPlan) {
+						planCalled = true
+						plan = p
+					},
+				}))
+				h.AssertTrue(t, planCalled)
+				h.AssertEq(t, plan.RunImage, fakeLifecycle.Opts.RunImage)
+				h.AssertEq(t, plan.UseCreator, fakeLifecycle.Opts.UseCreator)
+			})
+		})
+
+		when("#Detect", func() {
+			it("runs only detection and reports the group and plan", func() {
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					opts.EventHandler(events.Event{Type: events.BuildpackDetected, Group: 1, Buildpack: "buildpack.1.id@buildpack.1.version", Result: events.ResultFail, Output: "nothing to build"})
+					opts.EventHandler(events.Event{Type: events.BuildpackDetected, Group: 2, Buildpack: "buildpack.2.id@buildpack.2.version", Result: events.ResultPass})
+					opts.EventHandler(events.Event{Type: events.GroupSelected, Buildpacks: []string{"buildpack.2.id@buildpack.2.version"}})
+					h.AssertNil(t, os.WriteFile(filepath.Join(opts.DetectDestinationDir, "group.toml"), []byte("some-group"), 0600))
+					h.AssertNil(t, os.WriteFile(filepath.Join(opts.DetectDestinationDir, "plan.toml"), []byte("some-plan"), 0600))
+					return nil
+				}
+
+				result, err := subject.Detect(context.TODO(), BuildOptions{
+					Builder:      defaultBuilderName,
+					TrustBuilder: func(string) bool { return true },
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, fakeLifecycle.Opts.DetectOnly, true)
+				h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+				h.AssertContains(t, fakeLifecycle.Opts.Image.Name(), DetectImageName+"/")
+
+				h.AssertEq(t, result.Group, []string{"buildpack.2.id@buildpack.2.version"})
+				h.AssertEq(t, result.GroupTOML, "some-group")
+				h.AssertEq(t, result.PlanTOML, "some-plan")
+				h.AssertEq(t, len(result.Groups), 2)
+				h.AssertEq(t, result.Groups[0].Passed, false)
+				h.AssertEq(t, result.Groups[0].Buildpacks, []DetectBuildpackResult{{Buildpack: "buildpack.1.id@buildpack.1.version", Result: "fail", Output: "nothing to build"}})
+				h.AssertEq(t, result.Groups[1].Passed, true)
+				h.AssertEq(t, len(result.Order), 2)
+			})
+
+			it("gives each detection its own caches, whose use isn't recorded", func() {
+				var images, buildCaches []string
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					images = append(images, opts.Image.Name())
+					buildCaches = append(buildCaches, opts.Cache.Build.Source)
+					h.AssertNil(t, opts.CacheUsage)
+					h.AssertNil(t, os.WriteFile(filepath.Join(opts.DetectDestinationDir, "group.toml"), []byte("some-group"), 0600))
+					return os.WriteFile(filepath.Join(opts.DetectDestinationDir, "plan.toml"), []byte("some-plan"), 0600)
+				}
+
+				for range 2 {
+					_, err := subject.Detect(context.TODO(), BuildOptions{
+						Builder: defaultBuilderName,
+					})
+					h.AssertNil(t, err)
+				}
+
+				h.AssertNotEq(t, images[0], images[1])
+				h.AssertNotEq(t, buildCaches[0], buildCaches[1])
+				h.AssertContains(t, buildCaches[0], cache.VolumePrefix+"detect-")
+			})
+
+			it("reports the groups that were tried when detection fails", func() {
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					opts.EventHandler(events.Event{Type: events.BuildpackDetected, Group: 1, Buildpack: "buildpack.1.id@buildpack.1.version", Result: events.ResultError, Output: "exit status 1"})
+					return errors.New("failed with status code: 20")
+				}
+
+				result, err := subject.Detect(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+				})
+				h.AssertError(t, err, "failed with status code: 20")
+				h.AssertEq(t, result.Groups, []DetectGroupResult{{Buildpacks: []DetectBuildpackResult{{Buildpack: "buildpack.1.id@buildpack.1.version", Result: "error", Output: "exit status 1"}}}})
+				h.AssertEq(t, result.GroupTOML, "")
+			})
+		})
+
+		when("#TestBuildpack", func() {
+			var bpDir, appDir string
+
+			it.Before(func() {
+				bpDir = filepath.Join(tmpDir, "test-bp")
+				h.AssertNil(t, os.MkdirAll(filepath.Join(bpDir, "bin"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(bpDir, "buildpack.toml"), []byte(`
+api = "0.10"
+
+[buildpack]
+  id = "local/test-bp"
+  version = "0.0.1"
+
+[[stacks]]
+  id = "*"
+`), 0644))
+				h.AssertNil(t, os.WriteFile(filepath.Join(bpDir, "bin", "detect"), []byte("#!/usr/bin/env bash"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(bpDir, "bin", "build"), []byte("#!/usr/bin/env bash"), 0755))
+
+				appDir = filepath.Join(tmpDir, "fixture")
+				h.AssertNil(t, os.MkdirAll(appDir, 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "test.toml"), []byte(`
+layers = ["node"]
+
+[env]
+NODE_ENV = "production"
+
+[[processes]]
+type = "web"
+command = "node server.js"
+`), 0644))
+			})
+
+			// buildLayers simulates a passing build of the buildpack under test
+			buildLayers := func(opts build.LifecycleOptions, layer, nodeEnv string) error {
+				opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "detector"})
+				opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "builder"})
+
+				layersDir := filepath.Join(opts.BuildDestinationDir, "layers")
+				h.AssertNil(t, os.MkdirAll(filepath.Join(layersDir, "local_test-bp", layer, "env.launch"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(layersDir, "local_test-bp", layer+".toml"), []byte("[types]\nlaunch = true\n"), 0600))
+				h.AssertNil(t, os.WriteFile(filepath.Join(layersDir, "local_test-bp", layer, "env.launch", "NODE_ENV.default"), []byte(nodeEnv), 0600))
+				h.AssertNil(t, os.MkdirAll(filepath.Join(layersDir, "config"), 0755))
+				return os.WriteFile(filepath.Join(layersDir, "config", "metadata.toml"), []byte(`
+[[processes]]
+type = "web"
+command = ["node", "server.js"]
+buildpack-id = "local/test-bp"
+`), 0600)
+			}
+
+			it("builds the fixture app with the buildpack and checks the expectations", func() {
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					return buildLayers(opts, "node", "production")
+				}
+
+				result, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+					BuildpackPath: bpDir,
+					BuildOptions: BuildOptions{
+						AppPath: appDir,
+						Builder: defaultBuilderName,
+					},
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, fakeLifecycle.Opts.BuildOnly, true)
+				h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+				h.AssertContains(t, fakeLifecycle.Opts.Image.Name(), BuildpackTestImageName+"/")
+				h.AssertContains(t, fakeLifecycle.Opts.Builder.Name(), "pack.local/builder/")
+
+				h.AssertEq(t, result.Buildpack, "local/test-bp@0.0.1")
+				h.AssertEq(t, result.Cases, []BuildpackTestCase{
+					{Name: "detect"},
+					{Name: "build"},
+					{Name: "layer node"},
+					{Name: "env NODE_ENV"},
+					{Name: "process web"},
+				})
+				h.AssertEq(t, result.Failures(), 0)
+			})
+
+			it("gives each run its own caches, whose use isn't recorded", func() {
+				var images, buildCaches []string
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					images = append(images, opts.Image.Name())
+					buildCaches = append(buildCaches, opts.Cache.Build.Source)
+					h.AssertNil(t, opts.CacheUsage)
+					return buildLayers(opts, "node", "production")
+				}
+
+				for range 2 {
+					_, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+						BuildpackPath: bpDir,
+						BuildOptions: BuildOptions{
+							AppPath: appDir,
+							Builder: defaultBuilderName,
+						},
+					})
+					h.AssertNil(t, err)
+				}
+
+				h.AssertNotEq(t, images[0], images[1])
+				h.AssertNotEq(t, buildCaches[0], buildCaches[1])
+				h.AssertContains(t, buildCaches[0], cache.VolumePrefix+"buildpack-test-")
+			})
+
+			it("reports the expectations that aren't met", func() {
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					return buildLayers(opts, "npm", "development")
+				}
+
+				result, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+					BuildpackPath: bpDir,
+					BuildOptions: BuildOptions{
+						AppPath: appDir,
+						Builder: defaultBuilderName,
+					},
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, result.Failures(), 2)
+				h.AssertEq(t, result.Cases[2].Failure, "expected layer 'node' to be contributed")
+				h.AssertEq(t, result.Cases[3].Failure, "expected 'NODE_ENV' to be set to 'production', but it is set to 'development'")
+			})
+
+			it("skips the expectations on layers when the build fails", func() {
+				fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+					opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "detector"})
+					opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "builder", Error: "failed with status code: 51"})
+					return errors.New("failed with status code: 51")
+				}
+
+				result, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+					BuildpackPath: bpDir,
+					BuildOptions: BuildOptions{
+						AppPath: appDir,
+						Builder: defaultBuilderName,
+					},
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, result.Cases[0].Failure, "")
+				h.AssertContains(t, result.Cases[1].Failure, "expected the build to pass, but it failed")
+				h.AssertEq(t, result.Cases[2].Skipped, "the build failed")
+				h.AssertEq(t, result.Failures(), 1)
+				h.AssertEq(t, result.Skipped(), 3)
+			})
+
+			when("detection is expected to fail", func() {
+				it("passes when the buildpack fails detection", func() {
+					h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "test.toml"), []byte("detect = false"), 0644))
+					fakeLifecycle.ExecuteFunc = func(opts build.LifecycleOptions) error {
+						opts.EventHandler(events.Event{Type: events.PhaseFinished, Phase: "detector", Error: "failed with status code: 20"})
+						return errors.New("failed with status code: 20")
+					}
+
+					result, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+						BuildpackPath: bpDir,
+						BuildOptions: BuildOptions{
+							AppPath: appDir,
+							Builder: defaultBuilderName,
+						},
+					})
+					h.AssertNil(t, err)
+					h.AssertEq(t, result.Cases, []BuildpackTestCase{{Name: "detect"}})
+				})
+			})
+
+			when("the test config has unknown keys", func() {
+				it("errors", func() {
+					h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "test.toml"), []byte("layer = \"node\""), 0644))
+
+					_, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+						BuildpackPath: bpDir,
+						BuildOptions: BuildOptions{
+							AppPath: appDir,
+							Builder: defaultBuilderName,
+						},
+					})
+					h.AssertError(t, err, "unknown keys in buildpack test config")
+				})
+			})
+
+			when("the path isn't a buildpack", func() {
+				it("errors", func() {
+					_, err := subject.TestBuildpack(context.TODO(), TestBuildpackOptions{
+						BuildpackPath: appDir,
+						BuildOptions: BuildOptions{
+							AppPath: appDir,
+							Builder: defaultBuilderName,
+						},
+					})
+					h.AssertError(t, err, "reading buildpack from")
+				})
+			})
+		})
+
 		when("ImageCache option", func() {
 			it("passes it through to lifecycle", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -1079,6 +1607,34 @@ api = "0.2"
 					})
 				})
 
+				it("enforces the policy on the dependencies", func() {
+					metaBuildpackFolder := filepath.Join(tmpDir, "meta-buildpack")
+					h.AssertNil(t, os.Mkdir(metaBuildpackFolder, os.ModePerm))
+					h.AssertNil(t, os.WriteFile(filepath.Join(metaBuildpackFolder, "buildpack.toml"), []byte(`
+api = "0.2"
+
+[buildpack]
+  id = "local/meta-bp"
+  version = "local-meta-bp-version"
+					`), 0644))
+					h.AssertNil(t, os.WriteFile(filepath.Join(metaBuildpackFolder, "package.toml"), []byte(`
+[buildpack]
+uri = "."
+
+[[dependencies]]
+uri = "docker://example.com/some/dependency:latest"
+					`), 0644))
+
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:      "some/app",
+						Builder:    defaultBuilderName,
+						ClearCache: true,
+						Buildpacks: []string{metaBuildpackFolder},
+						Policy:     &policy.Policy{Buildpacks: policy.BuildpackRule{RequireDigest: true}},
+					})
+					h.AssertError(t, err, "buildpack 'example.com/some/dependency:latest' isn't pinned by digest (buildpacks.require-digest)")
+				})
+
 				it("fails if buildpack dependency could not be fetched", func() {
 					metaBuildpackFolder := filepath.Join(tmpDir, "meta-buildpack")
 					err := os.Mkdir(metaBuildpackFolder, os.ModePerm)
@@ -1798,6 +2354,20 @@ api = "0.2"
 						h.AssertNil(t, os.RemoveAll(tmpDir))
 					})
 
+					it("enforces the policy on the package image the buildpack resolves to", func() {
+						err := subject.Build(context.TODO(), BuildOptions{
+							Image:      "some/app",
+							Builder:    defaultBuilderName,
+							ClearCache: true,
+							Buildpacks: []string{
+								"urn:cnb:registry:example/foo@1.0.0",
+							},
+							Registry: "some-registry",
+							Policy:   &policy.Policy{Buildpacks: policy.BuildpackRule{Allowed: []string{"registry.example.com"}}},
+						})
+						h.AssertError(t, err, "buildpack 'example.com/some/package@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566' isn't from an allowed registry or repository (buildpacks.allowed)")
+					})
+
 					it("all buildpacks are added to ephemeral builder", func() {
 						err := subject.Build(context.TODO(), BuildOptions{
 							Image:      "some/app",
@@ -2100,6 +2670,135 @@ api = "0.2"
 							h.AssertNil(t, args)
 						})
 
+						when("the builder has a key", func() {
+							var (
+								server      *httptest.Server
+								builderRef  name.Reference
+								builderName string
+								privateKey  *ecdsa.PrivateKey
+								builderKey  []byte
+							)
+
+							it.Before(func() {
+								server = httptest.NewServer(ggcrregistry.New(ggcrregistry.Logger(log.New(io.Discard, "", 0))))
+								builderName = strings.TrimPrefix(server.URL, "http://") + "/some/builder:tag"
+								fakeImageFetcher.LocalImages[builderName] = defaultBuilderImage
+
+								var err error
+								builderRef, err = name.ParseReference(builderName)
+								h.AssertNil(t, err)
+								h.AssertNil(t, ggcrremote.Write(builderRef, empty.Image))
+
+								privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+								h.AssertNil(t, err)
+								builderKey, err = os.ReadFile(h.WritePublicKey(t, tmpDir, privateKey))
+								h.AssertNil(t, err)
+								subject.keychain = authn.DefaultKeychain
+							})
+
+							it.After(func() {
+								server.Close()
+							})
+
+							it("uses the creator when the builder is signed with the key", func() {
+								digest, err := empty.Image.Digest()
+								h.AssertNil(t, err)
+								h.SignImage(t, builderRef, digest, privateKey)
+								// the daemon identifies the builder it pulled by the digest of its config
+								configName, err := empty.Image.ConfigName()
+								h.AssertNil(t, err)
+								defaultBuilderImage.SetIdentifier(&fakeIdentifier{name: configName.Hex})
+
+								h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+									Image:        "some/app",
+									Builder:      builderName,
+									Publish:      true,
+									TrustBuilder: func(string) bool { return true },
+									BuilderKey:   builderKey,
+								}))
+								h.AssertEq(t, fakeLifecycle.Opts.UseCreator, true)
+							})
+
+							it("doesn't trust the builder when the image in the daemon isn't the signed one", func() {
+								digest, err := empty.Image.Digest()
+								h.AssertNil(t, err)
+								h.SignImage(t, builderRef, digest, privateKey)
+								defaultBuilderImage.SetIdentifier(&fakeIdentifier{name: strings.Repeat("ab", 32)})
+
+								h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+									Image:        "some/app",
+									Builder:      builderName,
+									Publish:      true,
+									TrustBuilder: func(string) bool { return true },
+									BuilderKey:   builderKey,
+								}))
+								h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+								h.AssertContains(t, outBuf.String(), "isn't the image")
+								h.AssertContains(t, outBuf.String(), "whose signature was verified")
+							})
+
+							when("the run image is verified with a key", func() {
+								var (
+									runImageName string
+									runImage     *fakes.Image
+								)
+
+								it.Before(func() {
+									runImageName = strings.TrimPrefix(server.URL, "http://") + "/some/run:tag"
+									runRef, err := name.ParseReference(runImageName)
+									h.AssertNil(t, err)
+									h.AssertNil(t, ggcrremote.Write(runRef, empty.Image))
+									digest, err := empty.Image.Digest()
+									h.AssertNil(t, err)
+									h.SignImage(t, runRef, digest, privateKey)
+
+									runImage = newLinuxImage(runImageName, "", nil)
+									h.AssertNil(t, runImage.SetLabel("io.buildpacks.stack.id", defaultBuilderStackID))
+									fakeImageFetcher.RemoteImages[runImageName] = runImage
+								})
+
+								it("builds with the signed run image", func() {
+									digest, err := empty.Image.Digest()
+									h.AssertNil(t, err)
+									runImage.SetIdentifier(&fakeIdentifier{name: runImageName + "@" + digest.String()})
+
+									h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+										Image:    "some/app",
+										Builder:  defaultBuilderName,
+										RunImage: runImageName,
+										Publish:  true,
+										ImageKey: builderKey,
+									}))
+									h.AssertEq(t, fakeLifecycle.Opts.RunImage, runImageName)
+								})
+
+								it("fails when the fetched run image isn't the signed one", func() {
+									runImage.SetIdentifier(&fakeIdentifier{name: runImageName + "@sha256:" + strings.Repeat("ab", 32)})
+
+									err := subject.Build(context.TODO(), BuildOptions{
+										Image:    "some/app",
+										Builder:  defaultBuilderName,
+										RunImage: runImageName,
+										Publish:  true,
+										ImageKey: builderKey,
+									})
+									h.AssertError(t, err, "whose signature was verified")
+								})
+							})
+
+							it("doesn't trust the builder when it isn't signed", func() {
+								h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+									Image:        "some/app",
+									Builder:      builderName,
+									Publish:      true,
+									TrustBuilder: func(string) bool { return true },
+									BuilderKey:   builderKey,
+								}))
+								h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+								h.AssertContains(t, outBuf.String(), "is not trusted, as its signature doesn't verify")
+							})
+						})
+
 						when("additional buildpacks were added", func() {
 							it("uses creator when additional buildpacks are provided and TrustExtraBuildpacks is set", func() {
 								additionalBP := ifakes.CreateBuildpackTar(t, tmpDir, dist.BuildpackDescriptor{
@@ -3133,6 +3832,84 @@ api = "0.2"
 			})
 		})
 
+		when("Policy option", func() {
+			it("fails when the inputs violate the policy", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					ContainerConfig: ContainerConfig{
+						Volumes: []string{"/a:/x:rw"},
+					},
+					Policy: &policy.Policy{
+						Builders:  policy.ImageRule{Allowed: []string{"registry.example.com"}},
+						RunImages: policy.ImageRule{RequireDigest: true},
+						Build:     policy.BuildRule{ForbidWritableVolumes: true},
+					},
+				})
+				h.AssertError(t, err, "policy violated")
+				h.AssertError(t, err, "builder 'example.com/default/builder:tag' isn't from an allowed registry or repository (builders.allowed)")
+				h.AssertError(t, err, "run image 'default/run' isn't pinned by digest (run-images.require-digest)")
+				h.AssertError(t, err, "volume '/a:/x:rw' is mounted read-write (build.forbid-writable-volumes)")
+				h.AssertNil(t, fakeLifecycle.Opts.Image)
+			})
+
+			it("fails when a buildpack is downloaded from a URL", func() {
+				err := subject.Build(context.TODO(), BuildOptions{
+					Image:      "some/app",
+					Builder:    defaultBuilderName,
+					Buildpacks: []string{"https://example.com/some-buildpack.tgz"},
+					Policy:     &policy.Policy{Buildpacks: policy.BuildpackRule{ForbidURLs: true}},
+				})
+				h.AssertError(t, err, "buildpack 'https://example.com/some-buildpack.tgz' is downloaded from a URL (buildpacks.forbid-urls)")
+			})
+
+			it("only warns in audit-only mode", func() {
+				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+					Image:   "some/app",
+					Builder: defaultBuilderName,
+					Policy: &policy.Policy{
+						AuditOnly: true,
+						Builders:  policy.ImageRule{Allowed: []string{"registry.example.com"}},
+					},
+				}))
+				h.AssertContains(t, outBuf.String(), "Warning: Policy violation: builder 'example.com/default/builder:tag' isn't from an allowed registry or repository (builders.allowed)")
+			})
+
+			when("the policy forbids trusted builders", func() {
+				it("fails when the builder is trusted", func() {
+					err := subject.Build(context.TODO(), BuildOptions{
+						Image:        "some/app",
+						Builder:      defaultBuilderName,
+						TrustBuilder: func(string) bool { return true },
+						Policy:       &policy.Policy{Build: policy.BuildRule{ForbidTrustedBuilder: true}},
+					})
+					h.AssertError(t, err, "builder 'example.com/default/builder:tag' is trusted (build.forbid-trusted-builder)")
+					h.AssertNil(t, fakeLifecycle.Opts.Image)
+				})
+
+				it("builds with a builder that isn't trusted", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:        "some/app",
+						Builder:      defaultBuilderName,
+						TrustBuilder: func(string) bool { return false },
+						Policy:       &policy.Policy{Build: policy.BuildRule{ForbidTrustedBuilder: true}},
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.UseCreator, false)
+				})
+
+				it("only warns in audit-only mode", func() {
+					h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
+						Image:        "some/app",
+						Builder:      defaultBuilderName,
+						TrustBuilder: func(string) bool { return true },
+						Policy:       &policy.Policy{AuditOnly: true, Build: policy.BuildRule{ForbidTrustedBuilder: true}},
+					}))
+					h.AssertEq(t, fakeLifecycle.Opts.UseCreator, true)
+					h.AssertContains(t, outBuf.String(), "Warning: Policy violation: builder 'example.com/default/builder:tag' is trusted (build.forbid-trusted-builder)")
+				})
+			})
+		})
+
 		when("gid option", func() {
 			it("gid is passthroughs to lifecycle", func() {
 				h.AssertNil(t, subject.Build(context.TODO(), BuildOptions{
@@ -0,0 +1,304 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// Names of the built-in buildpack templates
+const (
+	BashTemplate    = "bash"
+	GoTemplate      = "go"
+	ExecDTemplate   = "exec.d"
+	PackageTemplate = "package"
+)
+
+var (
+	goMod = `module {{ .ID }}
+
+go 1.22
+
+require github.com/buildpacks/libcnb/v2 v2.0.0
+`
+	goMain = `package main
+
+import (
+	"github.com/buildpacks/libcnb/v2"
+
+	"{{ .ID }}/buildpack"
+)
+
+func main() {
+	libcnb.BuildpackMain(buildpack.Detect, buildpack.Build)
+}
+`
+	goDetect = `package buildpack
+
+import (
+	"github.com/buildpacks/libcnb/v2"
+)
+
+// Detect passes when the buildpack applies to the application found in context.ApplicationPath
+func Detect(context libcnb.DetectContext) (libcnb.DetectResult, error) {
+	return libcnb.DetectResult{Pass: true}, nil
+}
+`
+	goBuild = `package buildpack
+
+import (
+	"github.com/buildpacks/libcnb/v2"
+)
+
+// Build contributes layers to context.Layers.Path and returns the processes of the application
+func Build(context libcnb.BuildContext) (libcnb.BuildResult, error) {
+	return libcnb.NewBuildResult(), nil
+}
+`
+	goBuildScript = `#!/usr/bin/env bash
+
+set -euo pipefail
+
+cd "$(dirname "$0")/.."
+
+GOOS="${GOOS:-linux}" CGO_ENABLED=0 go build -mod=mod -trimpath -ldflags="-s -w" -o bin/main ./cmd/main
+ln -sf main bin/build
+ln -sf main bin/detect
+`
+	goGitignore = `bin/
+`
+	execDBinBuild = `#!/usr/bin/env bash
+
+set -euo pipefail
+
+layers_dir="$1"
+env_dir="$2/env"
+plan_path="$3"
+
+# Contribute a launch layer with the exec.d executables of the buildpack
+exec_layer="${layers_dir}/exec"
+mkdir -p "${exec_layer}/exec.d"
+cp "${CNB_BUILDPACK_DIR}/exec.d/setup" "${exec_layer}/exec.d/setup"
+cat > "${layers_dir}/exec.toml" <<EOF
+[types]
+launch = true
+EOF
+
+exit 0
+`
+	execDSetup = `#!/usr/bin/env bash
+
+set -euo pipefail
+
+# exec.d executables run before the app process starts. They set environment variables
+# of the process by writing them as TOML to file descriptor 3.
+echo 'GREETING = "Hello from {{ .ID }}"' >&3
+`
+	packageTOML = `[buildpack]
+uri = "."
+{{ range .Targets }}
+[[targets]]
+os = "{{ .OS }}"
+arch = "{{ .Arch }}"
+{{- if .ArchVariant }}
+variant = "{{ .ArchVariant }}"
+{{- end }}
+{{- range .Distributions }}
+
+[[targets.distros]]
+name = "{{ .Name }}"
+version = "{{ .Version }}"
+{{- end }}
+{{ end -}}
+`
+)
+
+// templateFile is a file generated by a template. Unless it is raw, its contents are rendered with text/template.
+type templateFile struct {
+	path     string
+	contents string
+	mode     os.FileMode
+	raw      bool
+}
+
+// templateData is what templates can refer to
+type templateData struct {
+	ID      string
+	Name    string
+	Version string
+	API     string
+	Targets []dist.Target
+}
+
+var buildpackTemplates = map[string][]templateFile{
+	BashTemplate: {
+		{path: "bin/build", contents: bashBinBuild, mode: 0755},
+		{path: "bin/detect", contents: bashBinDetect, mode: 0755},
+	},
+	GoTemplate: {
+		{path: ".gitignore", contents: goGitignore, mode: 0644},
+		{path: "go.mod", contents: goMod, mode: 0644},
+		{path: "cmd/main/main.go", contents: goMain, mode: 0644},
+		{path: "buildpack/detect.go", contents: goDetect, mode: 0644},
+		{path: "buildpack/build.go", contents: goBuild, mode: 0644},
+		{path: "scripts/build.sh", contents: goBuildScript, mode: 0755},
+	},
+	ExecDTemplate: {
+		{path: "bin/build", contents: execDBinBuild, mode: 0755},
+		{path: "bin/detect", contents: bashBinDetect, mode: 0755},
+		{path: "exec.d/setup", contents: execDSetup, mode: 0755},
+	},
+	PackageTemplate: {
+		{path: "bin/build", contents: bashBinBuild, mode: 0755},
+		{path: "bin/detect", contents: bashBinDetect, mode: 0755},
+		{path: "package.toml", contents: packageTOML, mode: 0644},
+	},
+}
+
+// BuildpackTemplates returns the names of the built-in buildpack templates
+func BuildpackTemplates() []string {
+	return templateNames(buildpackTemplates)
+}
+
+func templateNames(templates map[string][]templateFile) []string {
+	var names []string
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateFiles returns the files of a built-in template, of a local template directory, or of a template
+// in a git repository
+func (c *Client) templateFiles(ctx context.Context, name string, builtIn map[string][]templateFile) ([]templateFile, error) {
+	if files, ok := builtIn[name]; ok {
+		return files, nil
+	}
+
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		return readTemplateDir(name)
+	}
+
+	if !isGitURL(name) {
+		return nil, errors.Errorf("template %s is neither one of %s, a directory nor a git repository URL", style.Symbol(name), strings.Join(templateNames(builtIn), ", "))
+	}
+
+	cloneDir, err := os.MkdirTemp("", "pack.template.")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(cloneDir)
+
+	c.logger.Debugf("Cloning template %s", style.Symbol(name))
+	if _, err := git.PlainCloneContext(ctx, cloneDir, false, &git.CloneOptions{URL: name, Depth: 1}); err != nil {
+		return nil, errors.Wrapf(err, "cloning template %s", style.Symbol(name))
+	}
+	return readTemplateDir(cloneDir)
+}
+
+func isGitURL(name string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "git@", "file://"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return strings.HasSuffix(name, ".git")
+}
+
+// readTemplateDir returns the files of a template directory. Files with the '.tmpl' extension are rendered, and
+// are generated without the extension. Other files are copied as they are.
+func readTemplateDir(dir string) ([]templateFile, error) {
+	var files []templateFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		contents, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		file := templateFile{path: filepath.ToSlash(relPath), contents: string(contents), mode: info.Mode().Perm(), raw: true}
+		if strings.HasSuffix(file.path, ".tmpl") {
+			file.path = strings.TrimSuffix(file.path, ".tmpl")
+			file.raw = false
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading template directory %s", style.Symbol(dir))
+	}
+	return files, nil
+}
+
+// createTemplateFiles generates the files of a template in path, leaving files that already exist untouched
+func createTemplateFiles(path string, files []templateFile, data templateData, c *Client) error {
+	for _, file := range files {
+		filePath := filepath.Join(path, filepath.FromSlash(file.path))
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			continue
+		}
+
+		contents := []byte(file.contents)
+		if !file.raw {
+			tmpl, err := template.New(file.path).Option("missingkey=error").Parse(file.contents)
+			if err != nil {
+				return errors.Wrapf(err, "parsing template file %s", style.Symbol(file.path))
+			}
+			buf := &bytes.Buffer{}
+			if err := tmpl.Execute(buf, data); err != nil {
+				return errors.Wrapf(err, "rendering template file %s", style.Symbol(file.path))
+			}
+			contents = buf.Bytes()
+		}
+
+		// The following line's comment is for gosec, it will ignore rule 301 in this case
+		// G301: Expect directory permissions to be 0750 or less
+		/* #nosec G301 */
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		// The following line's comment is for gosec, it will ignore rule 306 in this case
+		// G306: Expect WriteFile permissions to be 0600 or less
+		/* #nosec G306 */
+		if err := os.WriteFile(filePath, contents, file.mode); err != nil {
+			return err
+		}
+
+		if c != nil {
+			c.logger.Infof("    %s  %s", style.Symbol("create"), file.path)
+		}
+	}
+	return nil
+}
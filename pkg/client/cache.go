@@ -0,0 +1,342 @@
+package client
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/moby/moby/api/types/volume"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/cache"
+)
+
+var cacheKinds = []string{"build", "launch", "kaniko"}
+
+// CacheEntry describes a volume or bind cache created by pack.
+type CacheEntry struct {
+	// Name is the volume name, or the directory of a bind cache
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	// Type is one of build, launch or kaniko, if known
+	Type string `json:"type,omitempty"`
+	// Image is the app image the cache belongs to, if known
+	Image string `json:"image,omitempty"`
+	// Size is in bytes, or -1 if it could not be determined
+	Size int64 `json:"size"`
+	// LastUsed is the last time pack used the cache, or when it was created if pack has no record of using it
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// ListCachesOptions define options for listing caches.
+type ListCachesOptions struct {
+	// Image only lists the caches of this app image
+	Image string
+}
+
+// PruneCachesOptions define which caches to remove.
+// When no option is set no cache is removed, only entries in volume-keys.toml for images without caches.
+type PruneCachesOptions struct {
+	// OlderThan removes caches that were last used longer ago than this
+	OlderThan time.Duration
+	// Image removes the caches of this app image
+	Image string
+	// Unattributed removes caches that can't be attributed to an image, such as volumes named with
+	// PACK_VOLUME_KEY or created before pack recorded the caches it uses
+	Unattributed bool
+}
+
+// PruneCachesResult describes what was removed by PruneCaches.
+type PruneCachesResult struct {
+	Removed []CacheEntry `json:"removed"`
+	// RemovedKeys are the images whose orphaned entries were removed from volume-keys.toml
+	RemovedKeys []string `json:"removedKeys,omitempty"`
+	// BindCaches are the bind caches matching the options. Their directories were provided by the user, so they are
+	// reported rather than removed.
+	BindCaches []CacheEntry `json:"bindCaches,omitempty"`
+	// Unattributed is the number of caches that can't be attributed to an image and were kept, as
+	// PruneCachesOptions.Unattributed wasn't set
+	Unattributed int `json:"unattributed,omitempty"`
+}
+
+// ListCaches lists the volume and bind caches pack has created, along with their size, last use and image.
+// Volumes are attributed to images using the keys stored in volume-keys.toml.
+func (c *Client) ListCaches(ctx context.Context, opts ListCachesOptions) ([]CacheEntry, error) {
+	imageFilter, err := normalizeImageName(opts.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	_, cfg, err := readVolumeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.listCaches(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return filterCaches(entries, func(e CacheEntry) bool {
+		return imageFilter == "" || e.Image == imageFilter
+	}), nil
+}
+
+// PruneCaches removes the caches matching the options, along with entries in volume-keys.toml for images that
+// no longer have any caches.
+func (c *Client) PruneCaches(ctx context.Context, opts PruneCachesOptions) (PruneCachesResult, error) {
+	imageFilter, err := normalizeImageName(opts.Image)
+	if err != nil {
+		return PruneCachesResult{}, err
+	}
+
+	volumeKeysPath, cfg, err := readVolumeConfig()
+	if err != nil {
+		return PruneCachesResult{}, err
+	}
+
+	entries, err := c.listCaches(ctx, cfg)
+	if err != nil {
+		return PruneCachesResult{}, err
+	}
+
+	var result PruneCachesResult
+	cutoff := time.Now().Add(-opts.OlderThan)
+	selected := func(e CacheEntry) bool {
+		if opts.OlderThan != 0 && !e.LastUsed.Before(cutoff) {
+			return false
+		}
+		if e.Image == "" {
+			if imageFilter == "" && !opts.Unattributed {
+				result.Unattributed++
+			}
+			return imageFilter == "" && opts.Unattributed
+		}
+		return (imageFilter != "" || opts.OlderThan != 0) && (imageFilter == "" || e.Image == imageFilter)
+	}
+
+	var (
+		removedCaches = map[string]bool{}
+		remaining     = map[string]bool{}
+	)
+	for _, entry := range entries {
+		if !selected(entry) {
+			remaining[entry.Image] = true
+			continue
+		}
+		if entry.Format == cache.Bind.String() {
+			// pack didn't create the directory of a bind cache, so it isn't pack's to delete
+			result.BindCaches = append(result.BindCaches, entry)
+			remaining[entry.Image] = true
+			continue
+		}
+
+		if err := c.removeVolume(ctx, entry.Name); err != nil {
+			c.logger.Warnf("Unable to remove cache %s: %s", style.Symbol(entry.Name), err)
+			remaining[entry.Image] = true
+			continue
+		}
+		c.logger.Debugf("Removed cache %s", style.Symbol(entry.Name))
+		removedCaches[entry.Name] = true
+		result.Removed = append(result.Removed, entry)
+	}
+
+	// builds running meanwhile may have added keys and records, so only what was listed above is dropped
+	err = config.UpdateVolumeKeys(volumeKeysPath, func(current *config.VolumeConfig) error {
+		for image := range current.VolumeKeys {
+			if _, listed := cfg.VolumeKeys[image]; !listed || remaining[image] || (imageFilter != "" && image != imageFilter) {
+				continue
+			}
+			delete(current.VolumeKeys, image)
+			result.RemovedKeys = append(result.RemovedKeys, image)
+		}
+
+		// drop records of the caches removed, and of caches that no longer exist
+		for cacheName, usage := range current.Caches {
+			if _, listed := cfg.Caches[cacheName]; !listed {
+				continue
+			}
+			if removedCaches[cacheName] || (!containsCache(entries, cacheName) && (imageFilter == "" || usage.Image == imageFilter)) {
+				delete(current.Caches, cacheName)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, errors.Wrapf(err, "writing %s", volumeKeysPath)
+	}
+	sort.Strings(result.RemovedKeys)
+	return result, nil
+}
+
+func (c *Client) listCaches(ctx context.Context, cfg config.VolumeConfig) ([]CacheEntry, error) {
+	type owner struct{ image, kind string }
+	owners := map[string]owner{}
+	for image, key := range cfg.VolumeKeys {
+		ref, err := name.ParseReference(image, name.WeakValidation)
+		if err != nil {
+			continue
+		}
+		for _, kind := range cacheKinds {
+			owners[cache.VolumeName(ref, key, kind)] = owner{image: image, kind: kind}
+		}
+	}
+
+	usage, err := c.docker.DiskUsage(ctx, dockerClient.DiskUsageOptions{Volumes: true, Verbose: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing volumes")
+	}
+
+	var entries []CacheEntry
+	for _, vol := range usage.Volumes.Items {
+		used, recorded := cfg.Caches[vol.Name]
+		if !strings.HasPrefix(vol.Name, cache.VolumePrefix) && !(recorded && used.Format == cache.Volume.String()) {
+			continue
+		}
+
+		entry := CacheEntry{
+			Name:     vol.Name,
+			Format:   cache.Volume.String(),
+			Size:     volumeSize(vol),
+			LastUsed: used.LastUsed,
+		}
+		if o, ok := owners[vol.Name]; ok {
+			entry.Image, entry.Type = o.image, o.kind
+		}
+		if recorded {
+			entry.Image, entry.Type = used.Image, used.Type
+		}
+		if entry.Type == "" {
+			for _, kind := range cacheKinds {
+				if strings.HasSuffix(vol.Name, "."+kind) {
+					entry.Type = kind
+				}
+			}
+		}
+		if entry.LastUsed.IsZero() {
+			entry.LastUsed, _ = time.Parse(time.RFC3339, vol.CreatedAt)
+		}
+		entries = append(entries, entry)
+	}
+
+	for dir, used := range cfg.Caches {
+		if used.Format != cache.Bind.String() {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		entries = append(entries, CacheEntry{
+			Name:     dir,
+			Format:   used.Format,
+			Type:     used.Type,
+			Image:    used.Image,
+			Size:     dirSize(dir),
+			LastUsed: used.LastUsed,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Image != entries[j].Image {
+			return entries[i].Image < entries[j].Image
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+func (c *Client) removeVolume(ctx context.Context, volumeName string) error {
+	_, err := c.docker.VolumeRemove(ctx, volumeName, dockerClient.VolumeRemoveOptions{})
+	if err != nil && !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// cacheUsageStore returns the store recording the caches builds use in the volume keys file of pack home, or nil
+// when pack home can't be found
+func cacheUsageStore() *cache.UsageStore {
+	volumeKeysPath, err := config.DefaultVolumeKeysPath()
+	if err != nil {
+		return nil
+	}
+	return cache.NewUsageStore(volumeKeysPath)
+}
+
+func readVolumeConfig() (string, config.VolumeConfig, error) {
+	volumeKeysPath, err := config.DefaultVolumeKeysPath()
+	if err != nil {
+		return "", config.VolumeConfig{}, err
+	}
+	cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+	if err != nil {
+		return "", config.VolumeConfig{}, err
+	}
+	return volumeKeysPath, cfg, nil
+}
+
+func normalizeImageName(image string) (string, error) {
+	if image == "" {
+		return "", nil
+	}
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid image name %s", style.Symbol(image))
+	}
+	return ref.Name(), nil
+}
+
+func filterCaches(entries []CacheEntry, keep func(CacheEntry) bool) []CacheEntry {
+	var filtered []CacheEntry
+	for _, entry := range entries {
+		if keep(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func containsCache(entries []CacheEntry, cacheName string) bool {
+	for _, entry := range entries {
+		if entry.Name == cacheName {
+			return true
+		}
+	}
+	return false
+}
+
+func volumeSize(vol volume.Volume) int64 {
+	if vol.UsageData == nil {
+		return -1
+	}
+	return vol.UsageData.Size
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return -1
+	}
+	return size
+}
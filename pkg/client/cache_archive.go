@@ -0,0 +1,295 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buildpacks/imgutil/layout"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/google/go-containerregistry/pkg/name"
+	darchive "github.com/moby/go-archive"
+	"github.com/moby/moby/api/types/container"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+const (
+	// CacheImageLabel is set on exported caches to the name of the app image the cache belongs to
+	CacheImageLabel = "io.buildpacks.pack.cache.image"
+
+	cacheMountPath = "/cache"
+)
+
+// ExportCacheOptions define which build cache to export and where to write it.
+type ExportCacheOptions struct {
+	// Image is the app image whose build cache is exported
+	Image string
+	// Cache selects the build cache; by default the volume pack generates for Image is used.
+	// Only volume and bind build caches can be exported.
+	Cache cache.CacheOpts
+	// OutputPath is where the OCI layout archive is written
+	OutputPath string
+	// HelperImage is used to create the container the cache is read through; defaults to the lifecycle image
+	HelperImage string
+}
+
+// ImportCacheOptions define which archive to import and the build cache to restore it into.
+type ImportCacheOptions struct {
+	// Image is the app image the build cache is restored for
+	Image string
+	// Cache selects the build cache; by default the volume pack generates for Image is used.
+	// Only volume and bind build caches can be imported into.
+	Cache cache.CacheOpts
+	// InputPath is the OCI layout archive written by ExportCache
+	InputPath string
+	// HelperImage is used to create the container the cache is written through; defaults to the lifecycle image
+	HelperImage string
+}
+
+type archivedCache interface {
+	Name() string
+	Type() cache.Type
+	Clear(context.Context) error
+}
+
+// ExportCache writes the build cache of an image to a tarball containing an OCI layout, with the cache contents as
+// its only layer, so it can be shipped as an artifact without a registry.
+func (c *Client) ExportCache(ctx context.Context, opts ExportCacheOptions) error {
+	ref, err := parseCacheImageName(opts.Image)
+	if err != nil {
+		return err
+	}
+
+	var cacheName string
+	switch opts.Cache.Build.Format {
+	case cache.CacheVolume:
+		// the volume key of an image that was never built isn't generated, as there's no cache to export then
+		cacheName, err = cache.ExistingVolumeName(ref, opts.Cache.Build, "build")
+		if err != nil {
+			return err
+		}
+		if cacheName == "" {
+			return errors.Errorf("no build cache found for %s", style.Symbol(ref.Name()))
+		}
+		if _, err := c.docker.VolumeInspect(ctx, cacheName, dockerClient.VolumeInspectOptions{}); err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return errors.Errorf("no build cache found for %s", style.Symbol(ref.Name()))
+			}
+			return errors.Wrapf(err, "inspecting volume %s", style.Symbol(cacheName))
+		}
+	case cache.CacheBind:
+		cacheName = opts.Cache.Build.Source
+		if _, err := os.Stat(cacheName); err != nil {
+			return errors.Wrapf(err, "reading build cache %s", style.Symbol(cacheName))
+		}
+	default:
+		return errArchivedCacheFormat(opts.Cache.Build.Format)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pack.cache-export.")
+	if err != nil {
+		return errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	layerPath := filepath.Join(tmpDir, "cache.tar")
+	if err := c.withCacheContainer(ctx, opts.HelperImage, cacheName, func(ctrID string) error {
+		result, err := c.docker.CopyFromContainer(ctx, ctrID, dockerClient.CopyFromContainerOptions{SourcePath: cacheMountPath})
+		if err != nil {
+			return errors.Wrapf(err, "copying build cache %s", style.Symbol(cacheName))
+		}
+		defer result.Content.Close()
+		return writeFile(layerPath, result.Content)
+	}); err != nil {
+		return err
+	}
+
+	layoutDir := filepath.Join(tmpDir, "layout")
+	img, err := layout.NewImage(layoutDir)
+	if err != nil {
+		return errors.Wrap(err, "creating OCI layout")
+	}
+	if err := img.AddLayer(layerPath); err != nil {
+		return errors.Wrap(err, "adding cache layer")
+	}
+	if err := img.SetLabel(CacheImageLabel, ref.Name()); err != nil {
+		return err
+	}
+	if err := img.Save(); err != nil {
+		return errors.Wrap(err, "saving OCI layout")
+	}
+
+	layoutTar := archive.ReadDirAsTar(layoutDir, ".", 0, 0, -1, true, false, nil)
+	defer layoutTar.Close()
+	if err := writeFile(opts.OutputPath, layoutTar); err != nil {
+		return errors.Wrapf(err, "writing %s", style.Symbol(opts.OutputPath))
+	}
+
+	c.logger.Debugf("Exported build cache %s to %s", style.Symbol(cacheName), style.Symbol(opts.OutputPath))
+	return nil
+}
+
+// ImportCache restores a build cache exported by ExportCache, replacing any existing build cache of the image.
+func (c *Client) ImportCache(ctx context.Context, opts ImportCacheOptions) error {
+	ref, buildCache, err := c.resolveArchivedCache(opts.Image, opts.Cache)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pack.cache-import.")
+	if err != nil {
+		return errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archiveFile, err := os.Open(opts.InputPath)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", style.Symbol(opts.InputPath))
+	}
+	defer archiveFile.Close()
+	if err := darchive.Untar(archiveFile, tmpDir, &darchive.TarOptions{NoLchown: true}); err != nil {
+		return errors.Wrapf(err, "extracting %s", style.Symbol(opts.InputPath))
+	}
+
+	img, err := layout.NewImage(tmpDir, layout.FromBaseImagePath(tmpDir))
+	if err != nil {
+		return errors.Wrapf(err, "reading OCI layout from %s", style.Symbol(opts.InputPath))
+	}
+	exportedFor, err := img.Label(CacheImageLabel)
+	if err != nil || exportedFor == "" {
+		return errors.Errorf("%s is not a build cache exported by pack", style.Symbol(opts.InputPath))
+	}
+	if exportedFor != ref.Name() {
+		c.logger.Debugf("Importing build cache of %s for %s", style.Symbol(exportedFor), style.Symbol(ref.Name()))
+	}
+
+	layers, err := img.UnderlyingImage().Layers()
+	if err != nil {
+		return errors.Wrap(err, "reading cache layers")
+	}
+	if len(layers) != 1 {
+		return errors.Errorf("expected a single cache layer in %s, found %d", style.Symbol(opts.InputPath), len(layers))
+	}
+
+	if err := buildCache.Clear(ctx); err != nil {
+		return errors.Wrapf(err, "clearing build cache %s", style.Symbol(buildCache.Name()))
+	}
+	if buildCache.Type() == cache.Bind {
+		if err := os.MkdirAll(buildCache.Name(), 0755); err != nil {
+			return errors.Wrapf(err, "creating build cache %s", style.Symbol(buildCache.Name()))
+		}
+	}
+
+	if err := c.withCacheContainer(ctx, opts.HelperImage, buildCache.Name(), func(ctrID string) error {
+		content, err := layers[0].Uncompressed()
+		if err != nil {
+			return errors.Wrap(err, "reading cache layer")
+		}
+		defer content.Close()
+
+		// the layer holds the mount directory itself, so it is extracted at the root
+		_, err = c.docker.CopyToContainer(ctx, ctrID, dockerClient.CopyToContainerOptions{
+			DestinationPath: "/",
+			Content:         content,
+		})
+		return errors.Wrapf(err, "copying to build cache %s", style.Symbol(buildCache.Name()))
+	}); err != nil {
+		return err
+	}
+
+	if err := cacheUsageStore().RecordUse(ref, buildCache.Name(), buildCache.Type(), "build", time.Now()); err != nil {
+		c.logger.Debugf("Unable to record use of cache %s: %s", style.Symbol(buildCache.Name()), err)
+	}
+	c.logger.Debugf("Imported %s into build cache %s", style.Symbol(opts.InputPath), style.Symbol(buildCache.Name()))
+	return nil
+}
+
+func parseCacheImageName(imageName string) (name.Reference, error) {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	return ref, errors.Wrapf(err, "invalid image name %s", style.Symbol(imageName))
+}
+
+func errArchivedCacheFormat(format cache.Format) error {
+	return errors.Errorf("only volume and bind build caches can be archived, got %s", style.Symbol(format.String()))
+}
+
+func (c *Client) resolveArchivedCache(imageName string, opts cache.CacheOpts) (name.Reference, archivedCache, error) {
+	ref, err := parseCacheImageName(imageName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch opts.Build.Format {
+	case cache.CacheVolume:
+		volumeCache, err := cache.NewVolumeCache(ref, opts.Build, "build", c.docker, c.logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ref, volumeCache, nil
+	case cache.CacheBind:
+		return ref, cache.NewBindCache(opts.Build, c.docker), nil
+	default:
+		return nil, nil, errArchivedCacheFormat(opts.Build.Format)
+	}
+}
+
+// withCacheContainer creates, but does not start, a container with the build cache volume or directory cacheName
+// mounted, so it can be copied to and from through the daemon regardless of who owns the files in it.
+func (c *Client) withCacheContainer(ctx context.Context, helperImage string, cacheName string, fn func(ctrID string) error) error {
+	if helperImage == "" {
+		helperImage = fmt.Sprintf("%s:%s", config.DefaultLifecycleImageRepo, builder.DefaultLifecycleVersion)
+	}
+	if _, err := c.imageFetcher.Fetch(ctx, helperImage, image.FetchOptions{Daemon: true, PullPolicy: image.PullIfNotPresent}); err != nil {
+		return errors.Wrapf(err, "fetching helper image %s", style.Symbol(helperImage))
+	}
+
+	ctr, err := c.docker.ContainerCreate(ctx, dockerClient.ContainerCreateOptions{
+		Config: &container.Config{
+			Image: helperImage,
+			Cmd:   []string{"/cnb/lifecycle/lifecycle"},
+		},
+		HostConfig: &container.HostConfig{
+			Binds: []string{fmt.Sprintf("%s:%s", cacheName, cacheMountPath)},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating helper container")
+	}
+	defer c.docker.ContainerRemove(context.Background(), ctr.ID, dockerClient.ContainerRemoveOptions{Force: true})
+
+	return fn(ctr.ID)
+}
+
+// writeFile writes content to a temp file next to path, renamed to path once all of it is written, so that a copy
+// that fails doesn't leave a truncated file behind, or replace an existing one
+func writeFile(path string, content io.Reader) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
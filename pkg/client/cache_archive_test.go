@@ -0,0 +1,277 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCacheArchive(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "CacheArchive", testCacheArchive, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testCacheArchive(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *client.Client
+		mockController   *gomock.Controller
+		mockDockerClient *testmocks.MockAPIClient
+		mockFetcher      *testmocks.MockImageFetcher
+		out              bytes.Buffer
+		tmpDir           string
+		packHome         string
+		buildVolume      string
+		volumeCache      cache.CacheOpts
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockAPIClient(mockController)
+		mockFetcher = testmocks.NewMockImageFetcher(mockController)
+
+		var err error
+		subject, err = client.NewClient(
+			client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+			client.WithDockerClient(mockDockerClient),
+			client.WithFetcher(mockFetcher),
+		)
+		h.AssertNil(t, err)
+
+		tmpDir = t.TempDir()
+		packHome = t.TempDir()
+		t.Setenv("PACK_HOME", packHome)
+		t.Setenv(cache.EnvVolumeKey, "some-volume-key")
+
+		ref, err := name.ParseReference("my/app", name.WeakValidation)
+		h.AssertNil(t, err)
+		buildVolume = cache.VolumeName(ref, "some-volume-key", "build")
+		volumeCache = cache.CacheOpts{Build: cache.CacheInfo{Format: cache.CacheVolume}}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	expectHelperContainer := func(bind string) {
+		mockFetcher.EXPECT().
+			Fetch(gomock.Any(), "docker.io/buildpacksio/lifecycle:0.21.0", image.FetchOptions{Daemon: true, PullPolicy: image.PullIfNotPresent}).
+			Return(nil, nil)
+		mockDockerClient.EXPECT().
+			ContainerCreate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, opts dockerClient.ContainerCreateOptions) (dockerClient.ContainerCreateResult, error) {
+				h.AssertEq(t, opts.HostConfig.Binds, []string{bind + ":/cache"})
+				return dockerClient.ContainerCreateResult{ID: "some-container"}, nil
+			})
+		mockDockerClient.EXPECT().
+			ContainerRemove(gomock.Any(), "some-container", dockerClient.ContainerRemoveOptions{Force: true}).
+			Return(dockerClient.ContainerRemoveResult{}, nil)
+	}
+
+	exportCache := func(outputPath string) {
+		mockDockerClient.EXPECT().VolumeInspect(gomock.Any(), buildVolume, gomock.Any()).Return(dockerClient.VolumeInspectResult{}, nil)
+		expectHelperContainer(buildVolume)
+
+		tb := archive.TarBuilder{}
+		tb.AddDir("cache", 0755, time.Now())
+		tb.AddFile("cache/committed/some-layer.tar", 0644, time.Now(), []byte("some-layer-content"))
+		mockDockerClient.EXPECT().
+			CopyFromContainer(gomock.Any(), "some-container", dockerClient.CopyFromContainerOptions{SourcePath: "/cache"}).
+			Return(dockerClient.CopyFromContainerResult{Content: tb.Reader(archive.DefaultTarWriterFactory())}, nil)
+
+		h.AssertNil(t, subject.ExportCache(context.TODO(), client.ExportCacheOptions{
+			Image:      "my/app",
+			Cache:      volumeCache,
+			OutputPath: outputPath,
+		}))
+	}
+
+	when("#ExportCache", func() {
+		it("writes the build cache volume to an OCI layout archive", func() {
+			outputPath := filepath.Join(tmpDir, "cache.tar")
+			exportCache(outputPath)
+
+			f, err := os.Open(outputPath)
+			h.AssertNil(t, err)
+			defer f.Close()
+			_, layout, err := archive.ReadTarEntry(f, "oci-layout")
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(layout), "imageLayoutVersion")
+		})
+
+		when("the build cache volume doesn't exist", func() {
+			it("errors", func() {
+				mockDockerClient.EXPECT().
+					VolumeInspect(gomock.Any(), buildVolume, gomock.Any()).
+					Return(dockerClient.VolumeInspectResult{}, cerrdefs.ErrNotFound)
+
+				err := subject.ExportCache(context.TODO(), client.ExportCacheOptions{
+					Image:      "my/app",
+					Cache:      volumeCache,
+					OutputPath: filepath.Join(tmpDir, "cache.tar"),
+				})
+				h.AssertError(t, err, "no build cache found for 'index.docker.io/my/app:latest'")
+			})
+		})
+
+		when("the image was never built", func() {
+			it("errors without generating a volume key", func() {
+				t.Setenv(cache.EnvVolumeKey, "")
+
+				err := subject.ExportCache(context.TODO(), client.ExportCacheOptions{
+					Image:      "my/app",
+					Cache:      volumeCache,
+					OutputPath: filepath.Join(tmpDir, "cache.tar"),
+				})
+				h.AssertError(t, err, "no build cache found for 'index.docker.io/my/app:latest'")
+				h.AssertPathDoesNotExists(t, filepath.Join(packHome, "volume-keys.toml"))
+			})
+		})
+
+		when("the output can't be written", func() {
+			it("doesn't leave a partial file behind", func() {
+				outputPath := filepath.Join(tmpDir, "some-dir")
+				h.AssertNil(t, os.MkdirAll(filepath.Join(outputPath, "some-file"), 0755))
+
+				mockDockerClient.EXPECT().VolumeInspect(gomock.Any(), buildVolume, gomock.Any()).Return(dockerClient.VolumeInspectResult{}, nil)
+				expectHelperContainer(buildVolume)
+				mockDockerClient.EXPECT().
+					CopyFromContainer(gomock.Any(), "some-container", gomock.Any()).
+					Return(dockerClient.CopyFromContainerResult{Content: (&archive.TarBuilder{}).Reader(archive.DefaultTarWriterFactory())}, nil)
+
+				err := subject.ExportCache(context.TODO(), client.ExportCacheOptions{
+					Image:      "my/app",
+					Cache:      volumeCache,
+					OutputPath: outputPath,
+				})
+				h.AssertError(t, err, "writing '"+outputPath+"'")
+
+				entries, err := os.ReadDir(tmpDir)
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(entries), 1)
+			})
+		})
+
+		when("the build cache is an image", func() {
+			it("errors", func() {
+				err := subject.ExportCache(context.TODO(), client.ExportCacheOptions{
+					Image:      "my/app",
+					Cache:      cache.CacheOpts{Build: cache.CacheInfo{Format: cache.CacheImage, Source: "my/cache"}},
+					OutputPath: filepath.Join(tmpDir, "cache.tar"),
+				})
+				h.AssertError(t, err, "only volume and bind build caches can be archived, got 'image'")
+			})
+		})
+	})
+
+	when("#ImportCache", func() {
+		var archivePath string
+
+		it.Before(func() {
+			archivePath = filepath.Join(tmpDir, "cache.tar")
+			exportCache(archivePath)
+		})
+
+		it("restores the cache into a fresh volume", func() {
+			mockDockerClient.EXPECT().
+				VolumeRemove(gomock.Any(), buildVolume, dockerClient.VolumeRemoveOptions{Force: true}).
+				Return(dockerClient.VolumeRemoveResult{}, nil)
+			expectHelperContainer(buildVolume)
+
+			var copied []byte
+			mockDockerClient.EXPECT().
+				CopyToContainer(gomock.Any(), "some-container", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, opts dockerClient.CopyToContainerOptions) (dockerClient.CopyToContainerResult, error) {
+					h.AssertEq(t, opts.DestinationPath, "/")
+					var err error
+					copied, err = io.ReadAll(opts.Content)
+					return dockerClient.CopyToContainerResult{}, err
+				})
+
+			h.AssertNil(t, subject.ImportCache(context.TODO(), client.ImportCacheOptions{
+				Image:     "my/app",
+				Cache:     volumeCache,
+				InputPath: archivePath,
+			}))
+
+			_, contents, err := archive.ReadTarEntry(bytes.NewReader(copied), "cache/committed/some-layer.tar")
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "some-layer-content")
+
+			cfg, err := config.ReadVolumeKeys(filepath.Join(packHome, "volume-keys.toml"))
+			h.AssertNil(t, err)
+			h.AssertEq(t, cfg.Caches[buildVolume].Image, "index.docker.io/my/app:latest")
+		})
+
+		it("restores the cache into a bind directory", func() {
+			bindDir := filepath.Join(tmpDir, "bind-cache")
+			h.AssertNil(t, os.MkdirAll(bindDir, 0755))
+			h.AssertNil(t, os.WriteFile(filepath.Join(bindDir, "stale"), []byte("stale"), 0600))
+
+			expectHelperContainer(bindDir)
+			mockDockerClient.EXPECT().
+				CopyToContainer(gomock.Any(), "some-container", gomock.Any()).
+				Return(dockerClient.CopyToContainerResult{}, nil)
+
+			h.AssertNil(t, subject.ImportCache(context.TODO(), client.ImportCacheOptions{
+				Image:     "my/app",
+				Cache:     cache.CacheOpts{Build: cache.CacheInfo{Format: cache.CacheBind, Source: bindDir}},
+				InputPath: archivePath,
+			}))
+			h.AssertPathDoesNotExists(t, filepath.Join(bindDir, "stale"))
+		})
+
+		when("the archive wasn't exported by pack", func() {
+			it("errors", func() {
+				otherPath := filepath.Join(tmpDir, "other.tar")
+				h.AssertNil(t, archive.CreateSingleFileTar(otherPath, "some-file", "some-content"))
+
+				err := subject.ImportCache(context.TODO(), client.ImportCacheOptions{
+					Image:     "my/app",
+					Cache:     volumeCache,
+					InputPath: otherPath,
+				})
+				h.AssertNotNil(t, err)
+			})
+		})
+
+		when("copying to the volume fails", func() {
+			it("errors", func() {
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), buildVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, nil)
+				expectHelperContainer(buildVolume)
+				mockDockerClient.EXPECT().
+					CopyToContainer(gomock.Any(), "some-container", gomock.Any()).
+					Return(dockerClient.CopyToContainerResult{}, errors.New("no space left on device"))
+
+				err := subject.ImportCache(context.TODO(), client.ImportCacheOptions{
+					Image:     "my/app",
+					Cache:     volumeCache,
+					InputPath: archivePath,
+				})
+				h.AssertError(t, err, "no space left on device")
+			})
+		})
+	})
+}
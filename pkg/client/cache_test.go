@@ -0,0 +1,206 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/heroku/color"
+	"github.com/moby/moby/api/types/volume"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/cache"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestCaches(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Caches", testCaches, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testCaches(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *client.Client
+		mockController   *gomock.Controller
+		mockDockerClient *testmocks.MockAPIClient
+		out              bytes.Buffer
+		volumeKeysPath   string
+		bindDir          string
+		appBuildVolume   string
+		appLaunchVolume  string
+		otherVolume      string
+		lastWeek         = time.Now().Add(-7 * 24 * time.Hour).UTC().Truncate(time.Second)
+		yesterday        = time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockAPIClient(mockController)
+
+		var err error
+		subject, err = client.NewClient(
+			client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+			client.WithDockerClient(mockDockerClient),
+		)
+		h.AssertNil(t, err)
+
+		packHome := t.TempDir()
+		t.Setenv("PACK_HOME", packHome)
+		volumeKeysPath = filepath.Join(packHome, "volume-keys.toml")
+
+		appRef, err := name.ParseReference("app", name.WeakValidation)
+		h.AssertNil(t, err)
+		appBuildVolume = cache.VolumeName(appRef, "app-key", "build")
+		appLaunchVolume = cache.VolumeName(appRef, "app-key", "launch")
+		otherVolume = "pack-cache-unknown_latest-123456.build"
+
+		bindDir = filepath.Join(t.TempDir(), "build-cache")
+		h.AssertNil(t, os.MkdirAll(bindDir, 0755))
+		h.AssertNil(t, os.WriteFile(filepath.Join(bindDir, "layer"), []byte("some-content"), 0600))
+
+		h.AssertNil(t, config.Write(config.VolumeConfig{
+			VolumeKeys: map[string]string{
+				"index.docker.io/library/app:latest":  "app-key",
+				"index.docker.io/library/gone:latest": "gone-key",
+			},
+			Caches: map[string]config.CacheUsage{
+				appBuildVolume: {Image: "index.docker.io/library/app:latest", Type: "build", Format: "volume", LastUsed: yesterday},
+				bindDir:        {Image: "index.docker.io/library/bind:latest", Type: "build", Format: "bind", LastUsed: lastWeek},
+			},
+		}, volumeKeysPath))
+
+		mockDockerClient.EXPECT().
+			DiskUsage(gomock.Any(), dockerClient.DiskUsageOptions{Volumes: true, Verbose: true}).
+			Return(dockerClient.DiskUsageResult{Volumes: dockerClient.VolumesDiskUsage{Items: []volume.Volume{
+				{Name: appBuildVolume, CreatedAt: lastWeek.Format(time.RFC3339), UsageData: &volume.UsageData{Size: 1024}},
+				{Name: appLaunchVolume, CreatedAt: lastWeek.Format(time.RFC3339), UsageData: &volume.UsageData{Size: 2048}},
+				{Name: otherVolume, CreatedAt: yesterday.Format(time.RFC3339)},
+				{Name: "not-a-pack-volume"},
+			}}}, nil).AnyTimes()
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	when("#ListCaches", func() {
+		it("lists volumes and bind caches with their image, size and last use", func() {
+			entries, err := subject.ListCaches(context.TODO(), client.ListCachesOptions{})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, entries, []client.CacheEntry{
+				{Name: otherVolume, Format: "volume", Type: "build", Size: -1, LastUsed: yesterday},
+				{Name: appBuildVolume, Format: "volume", Type: "build", Image: "index.docker.io/library/app:latest", Size: 1024, LastUsed: yesterday},
+				{Name: appLaunchVolume, Format: "volume", Type: "launch", Image: "index.docker.io/library/app:latest", Size: 2048, LastUsed: lastWeek},
+				{Name: bindDir, Format: "bind", Type: "build", Image: "index.docker.io/library/bind:latest", Size: 12, LastUsed: lastWeek},
+			})
+		})
+
+		when("an image is provided", func() {
+			it("only lists the caches of the image", func() {
+				entries, err := subject.ListCaches(context.TODO(), client.ListCachesOptions{Image: "app"})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(entries), 2)
+				h.AssertEq(t, entries[0].Name, appBuildVolume)
+				h.AssertEq(t, entries[1].Name, appLaunchVolume)
+			})
+		})
+	})
+
+	when("#PruneCaches", func() {
+		when("no options are provided", func() {
+			it("only removes orphaned volume keys", func() {
+				result, err := subject.PruneCaches(context.TODO(), client.PruneCachesOptions{})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(result.Removed), 0)
+				h.AssertEq(t, result.Unattributed, 1)
+				h.AssertEq(t, result.RemovedKeys, []string{"index.docker.io/library/gone:latest"})
+
+				cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.VolumeKeys, map[string]string{"index.docker.io/library/app:latest": "app-key"})
+			})
+		})
+
+		when("unattributed caches are to be removed", func() {
+			it("removes caches without an image", func() {
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), otherVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, nil)
+
+				result, err := subject.PruneCaches(context.TODO(), client.PruneCachesOptions{Unattributed: true})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(result.Removed), 1)
+				h.AssertEq(t, result.Removed[0].Name, otherVolume)
+				h.AssertEq(t, result.Unattributed, 0)
+			})
+		})
+
+		when("--older-than is provided", func() {
+			it("removes volumes last used before then, and only reports bind caches", func() {
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appLaunchVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, nil)
+
+				result, err := subject.PruneCaches(context.TODO(), client.PruneCachesOptions{OlderThan: 3 * 24 * time.Hour})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(result.Removed), 1)
+				h.AssertEq(t, result.Removed[0].Name, appLaunchVolume)
+				h.AssertEq(t, len(result.BindCaches), 1)
+				h.AssertEq(t, result.BindCaches[0].Name, bindDir)
+				h.AssertPathExists(t, filepath.Join(bindDir, "layer"))
+
+				cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, len(cfg.Caches), 2)
+				h.AssertEq(t, cfg.VolumeKeys, map[string]string{"index.docker.io/library/app:latest": "app-key"})
+			})
+		})
+
+		when("an image is provided", func() {
+			it("removes the caches and volume key of the image", func() {
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appBuildVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, nil)
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appLaunchVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, nil)
+
+				result, err := subject.PruneCaches(context.TODO(), client.PruneCachesOptions{Image: "app"})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(result.Removed), 2)
+				h.AssertEq(t, result.RemovedKeys, []string{"index.docker.io/library/app:latest"})
+
+				cfg, err := config.ReadVolumeKeys(volumeKeysPath)
+				h.AssertNil(t, err)
+				h.AssertEq(t, cfg.VolumeKeys, map[string]string{"index.docker.io/library/gone:latest": "gone-key"})
+				h.AssertEq(t, len(cfg.Caches), 1)
+			})
+		})
+
+		when("a volume can't be removed", func() {
+			it("warns and keeps the volume key", func() {
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appBuildVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, nil)
+				mockDockerClient.EXPECT().VolumeRemove(gomock.Any(), appLaunchVolume, gomock.Any()).Return(dockerClient.VolumeRemoveResult{}, errors.New("volume is in use"))
+
+				result, err := subject.PruneCaches(context.TODO(), client.PruneCachesOptions{Image: "app"})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, len(result.Removed), 1)
+				h.AssertEq(t, len(result.RemovedKeys), 0)
+				h.AssertContains(t, out.String(), "Unable to remove cache")
+				h.AssertContains(t, out.String(), "volume is in use")
+			})
+		})
+	})
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/index"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
 )
 
 const (
@@ -136,6 +137,7 @@ type Client struct {
 
 	experimental    bool
 	registryMirrors map[string]string
+	mirror          *mirror.Mirror
 	version         string
 }
 
@@ -266,6 +268,14 @@ func WithKeychain(keychain authn.Keychain) Option {
 	}
 }
 
+// WithMirror sets the local mirror that images, blobs and registry indexes are resolved from in offline mode,
+// and that SyncMirror fetches artifacts into.
+func WithMirror(m *mirror.Mirror) Option {
+	return func(c *Client) {
+		c.mirror = m
+	}
+}
+
 const DockerAPIVersion = "1.38"
 
 // NewClient allocates and returns a Client configured with the specified options.
@@ -293,16 +303,24 @@ func NewClient(opts ...Option) (*Client, error) {
 		}
 	}
 
+	if client.mirror == nil {
+		packHome, err := iconfig.PackHome()
+		if err != nil {
+			return nil, errors.Wrap(err, "getting pack home")
+		}
+		client.mirror = mirror.New(filepath.Join(packHome, "mirror"))
+	}
+
 	if client.downloader == nil {
 		packHome, err := iconfig.PackHome()
 		if err != nil {
 			return nil, errors.Wrap(err, "getting pack home")
 		}
-		client.downloader = blob.NewDownloader(client.logger, filepath.Join(packHome, "download-cache"))
+		client.downloader = blob.NewDownloader(client.logger, filepath.Join(packHome, "download-cache"), blob.WithMirror(client.mirror))
 	}
 
 	if client.imageFetcher == nil {
-		client.imageFetcher = image.NewFetcher(client.logger, client.docker, image.WithRegistryMirrors(client.registryMirrors), image.WithKeychain(client.keychain))
+		client.imageFetcher = image.NewFetcher(client.logger, client.docker, image.WithRegistryMirrors(client.registryMirrors), image.WithKeychain(client.keychain), image.WithMirror(client.mirror))
 	}
 
 	if client.imageFactory == nil {
@@ -330,7 +348,9 @@ func NewClient(opts ...Option) (*Client, error) {
 			client.imageFetcher,
 			client.downloader,
 			&registryResolver{
-				logger: client.logger,
+				logger:   client.logger,
+				keychain: client.keychain,
+				mirror:   client.mirror,
 			},
 		)
 	}
@@ -341,11 +361,13 @@ func NewClient(opts ...Option) (*Client, error) {
 }
 
 type registryResolver struct {
-	logger logging.Logger
+	logger   logging.Logger
+	keychain authn.Keychain
+	mirror   *mirror.Mirror
 }
 
 func (r *registryResolver) Resolve(registryName, bpName string) (string, error) {
-	cache, err := getRegistry(r.logger, registryName)
+	cache, err := getRegistry(r.logger, r.keychain, registryName, r.mirror)
 	if err != nil {
 		return "", errors.Wrapf(err, "lookup registry %s", style.Symbol(registryName))
 	}
@@ -6,14 +6,18 @@ import (
 	"fmt"
 
 	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/lock"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
 )
 
 func (c *Client) addManifestToIndex(ctx context.Context, repoName string, index imgutil.ImageIndex) error {
@@ -77,32 +81,67 @@ func (c *Client) resolveRunImage(runImage, imgRegistry, bldrRegistry string, run
 	return runImageName
 }
 
-func getRegistry(logger logging.Logger, registryName string) (registry.Cache, error) {
-	home, err := config.PackHome()
+// getRegistry returns the cache of the registry named registryName. When m is offline, it's the clone of the
+// registry in the mirror.
+func getRegistry(logger logging.Logger, keychain authn.Keychain, registryName string, m *mirror.Mirror) (registry.Cache, error) {
+	cfg, err := getConfig()
 	if err != nil {
 		return registry.Cache{}, err
 	}
 
-	if err := config.MkdirAll(home); err != nil {
+	reg, err := lookupRegistry(cfg, registryName)
+	if err != nil {
 		return registry.Cache{}, err
 	}
 
-	cfg, err := getConfig()
+	return newRegistryCache(logger, keychain, reg, m)
+}
+
+// newRegistryCache returns the cache of reg in pack home or, when m is offline, in the mirror. The index of
+// registries of type oci is pulled with the credentials of keychain.
+func newRegistryCache(logger logging.Logger, keychain authn.Keychain, reg config.Registry, m *mirror.Mirror) (registry.Cache, error) {
+	home, err := config.PackHome()
 	if err != nil {
 		return registry.Cache{}, err
 	}
 
+	if err := config.MkdirAll(home); err != nil {
+		return registry.Cache{}, err
+	}
+
+	switch {
+	case reg.Type == "oci" && m.IsOffline():
+		return registry.NewOfflineOCIRegistryCache(logger, m.RegistriesDir(), reg.URL)
+	case reg.Type == "oci":
+		return registry.NewOCIRegistryCache(logger, home, reg.URL, keychain)
+	case m.IsOffline():
+		return registry.NewOfflineRegistryCache(logger, m.RegistriesDir(), reg.URL)
+	default:
+		return registry.NewRegistryCache(logger, home, reg.URL)
+	}
+}
+
+func lookupRegistry(cfg config.Config, registryName string) (config.Registry, error) {
 	if registryName == "" {
-		return registry.NewDefaultRegistryCache(logger, home)
+		return config.DefaultRegistry(), nil
 	}
 
 	for _, reg := range config.GetRegistries(cfg) {
 		if reg.Name == registryName {
-			return registry.NewRegistryCache(logger, home, reg.URL)
+			return reg, nil
 		}
 	}
 
-	return registry.Cache{}, fmt.Errorf("registry %s is not defined in your config file", style.Symbol(registryName))
+	return config.Registry{}, fmt.Errorf("registry %s is not defined in your config file", style.Symbol(registryName))
+}
+
+// pinURI returns the URI of a module or lifecycle as pinned by the lockfile, with the sha256 checksum given in its config
+func pinURI(uri, checksum string, lockfile lock.File) (string, error) {
+	pinned, err := lockfile.Pin(uri)
+	if err != nil {
+		return "", err
+	}
+	return blob.WithSHA256(pinned, checksum)
 }
 
 func getConfig() (config.Config, error) {
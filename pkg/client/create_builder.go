@@ -22,9 +22,12 @@ import (
 	"github.com/buildpacks/pack/internal/builder"
 	"github.com/buildpacks/pack/internal/paths"
 	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/lock"
+	"github.com/buildpacks/pack/pkg/policy"
 )
 
 // CreateBuilderOptions is a configuration object used to change the behavior of
@@ -70,11 +73,31 @@ type CreateBuilderOptions struct {
 
 	// Additional image tags to push to, each will contain contents identical to Image
 	AdditionalTags []string
+
+	// Lockfile pinning the buildpacks, extensions and lifecycle of the config
+	Lock lock.File
+
+	// PEM encoded private key to sign the builder with, in its registry. Requires Publish to be true.
+	SignKey []byte
+
+	// Policy the run images, buildpacks and extensions of the config are evaluated against before creating the
+	// builder, if any.
+	Policy *policy.Policy
 }
 
 // CreateBuilder creates and saves a builder image to a registry with the provided options.
 // If any configuration is invalid, it will error and exit without creating any images.
 func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) error {
+	if err := validateSignKey(opts.SignKey, opts.Publish, FormatImage); err != nil {
+		return err
+	}
+
+	if opts.Policy != nil {
+		if err := opts.Policy.Enforce(c.logger, builderPolicyInputs(opts)); err != nil {
+			return err
+		}
+	}
+
 	targets, err := c.processBuilderCreateTargets(ctx, opts)
 	if err != nil {
 		return err
@@ -98,11 +121,17 @@ func (c *Client) CreateBuilder(ctx context.Context, opts CreateBuilderOptions) e
 		}
 
 		if multiArch && len(digests) > 1 {
-			return c.CreateManifest(ctx, CreateManifestOptions{
+			digest, err := c.createManifest(ctx, CreateManifestOptions{
 				IndexRepoName: opts.BuilderName,
 				RepoNames:     digests,
 				Publish:       true,
 			})
+			if err != nil {
+				return err
+			}
+			if len(opts.SignKey) > 0 {
+				return c.signImage(ctx, digest, opts.SignKey)
+			}
 		}
 	}
 
@@ -142,13 +171,20 @@ func (c *Client) createBuilderTarget(ctx context.Context, opts CreateBuilderOpti
 		return "", err
 	}
 
-	if multiArch {
-		// We need to keep the identifier to create the image index
+	if multiArch || len(opts.SignKey) > 0 {
+		// We need to keep the identifier to create the image index, and to sign the image
 		id, err := bldr.Image().Identifier()
 		if err != nil {
 			return "", errors.Wrapf(err, "determining image manifest digest")
 		}
-		return id.String(), nil
+		if len(opts.SignKey) > 0 {
+			if err := c.signImage(ctx, id.String(), opts.SignKey); err != nil {
+				return "", err
+			}
+		}
+		if multiArch {
+			return id.String(), nil
+		}
 	}
 	return "", nil
 }
@@ -291,8 +327,17 @@ func (c *Client) fetchLifecycle(ctx context.Context, opts CreateBuilderOptions,
 		)
 	}
 
+	if buildpack.HasDockerLocator(config.URI) && config.SHA256 != "" {
+		return nil, errors.Errorf("%s can't be verified for a lifecycle image: pin the image by digest instead", style.Symbol("lifecycle.sha256"))
+	}
+
 	var uri string
 	var err error
+	if config.URI != "" {
+		if config.URI, err = pinURI(config.URI, config.SHA256, opts.Lock); err != nil {
+			return nil, errors.Wrap(err, "pinning lifecycle")
+		}
+	}
 	switch {
 	case buildpack.HasDockerLocator(config.URI):
 		uri, err = c.uriFromLifecycleImage(ctx, opts.TempDirectory, config)
@@ -307,7 +352,12 @@ func (c *Client) fetchLifecycle(ctx context.Context, opts CreateBuilderOptions,
 
 		uri = c.uriFromLifecycleVersion(*v, os, architecture)
 	case config.URI != "":
-		uri, err = paths.FilePathToURI(config.URI, opts.RelativeBaseDir)
+		path, checksum := blob.SplitSHA256(config.URI)
+		uri, err = paths.FilePathToURI(path, opts.RelativeBaseDir)
+		if err != nil {
+			return nil, err
+		}
+		uri, err = blob.WithSHA256(uri, checksum)
 		if err != nil {
 			return nil, err
 		}
@@ -315,6 +365,12 @@ func (c *Client) fetchLifecycle(ctx context.Context, opts CreateBuilderOptions,
 		uri = c.uriFromLifecycleVersion(*semver.MustParse(builder.DefaultLifecycleVersion), os, architecture)
 	}
 
+	if config.URI == "" {
+		if uri, err = pinURI(uri, config.SHA256, opts.Lock); err != nil {
+			return nil, errors.Wrap(err, "pinning lifecycle")
+		}
+	}
+
 	blob, err := c.downloader.Download(ctx, uri)
 	if err != nil {
 		return nil, errors.Wrap(err, "downloading lifecycle")
@@ -361,7 +417,18 @@ func (c *Client) addConfig(ctx context.Context, kind string, config pubbldr.Modu
 	target := &dist.Target{OS: builderOS, Arch: builderArch}
 	c.logger.Debugf("Downloading buildpack for platform: %s", target.ValuesAsPlatform())
 
-	mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, config.URI, buildpack.DownloadOptions{
+	uri, err := pinURI(config.URI, config.SHA256, opts.Lock)
+	if err != nil {
+		return errors.Wrapf(err, "pinning %s", kind)
+	}
+	// the other modules were evaluated with the config
+	if locatorType, err := buildpack.GetLocatorType(uri, opts.RelativeBaseDir, nil); err == nil && locatorType == buildpack.RegistryLocator {
+		if uri, err = c.enforceModulePolicy(opts.Policy, opts.Registry, uri, opts.RelativeBaseDir, nil); err != nil {
+			return err
+		}
+	}
+
+	mainBP, depBPs, err := c.buildpackDownloader.Download(ctx, uri, buildpack.DownloadOptions{
 		Daemon:          !opts.Publish,
 		ImageName:       config.ImageName,
 		ModuleKind:      kind,
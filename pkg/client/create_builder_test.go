@@ -3,6 +3,9 @@ package client_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -34,7 +37,9 @@ import (
 	"github.com/buildpacks/pack/pkg/client"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/lock"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/policy"
 	"github.com/buildpacks/pack/pkg/testmocks"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -311,6 +316,41 @@ func testCreateBuilder(t *testing.T, when spec.G, it spec.S) {
 				h.AssertError(t, err, "'lifecycle' can only declare 'version' or 'uri', not both")
 			})
 
+			it("should fail when the lockfile pins a buildpack to a different sha256", func() {
+				prepareFetcherWithBuildImage()
+				prepareFetcherWithRunImages()
+				checksum := strings.Repeat("ab", 32)
+				opts.Lock = lock.File{Entries: []lock.Entry{{URI: "https://example.fake/bp-one.tgz", SHA256: checksum}}}
+				mockBuildpackDownloader.EXPECT().Download(gomock.Any(), "https://example.fake/bp-one.tgz#sha256="+checksum, gomock.Any()).Return(nil, nil, errors.New("sha256 mismatch"))
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "sha256 mismatch")
+			})
+
+			it("should fail when a sign key is given for a builder that isn't published", func() {
+				privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				h.AssertNil(t, err)
+				opts.SignKey, err = os.ReadFile(h.WritePrivateKey(t, t.TempDir(), privateKey))
+				h.AssertNil(t, err)
+
+				err = subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "images can only be signed when they are published")
+			})
+
+			it("should fail when the config violates the policy", func() {
+				opts.Policy = &policy.Policy{
+					RunImages:  policy.ImageRule{RequireDigest: true},
+					Buildpacks: policy.BuildpackRule{ForbidURLs: true},
+				}
+
+				err := subject.CreateBuilder(context.TODO(), opts)
+
+				h.AssertError(t, err, "run image 'some/run-image' isn't pinned by digest (run-images.require-digest)")
+				h.AssertError(t, err, "buildpack 'https://example.fake/bp-one.tgz' is downloaded from a URL (buildpacks.forbid-urls)")
+			})
+
 			it("should fail when buildpack ID does not match downloaded buildpack", func() {
 				prepareFetcherWithBuildImage()
 				prepareFetcherWithRunImages()
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/pkg/events"
+)
+
+// DetectImageName is the prefix of the app image name used when detecting without an image name.
+// Each detection gets its own image name and cache volumes, which are removed once it's done.
+const DetectImageName = "pack.local/detect"
+
+// DetectResult describes the outcome of running the detect phase of a build.
+type DetectResult struct {
+	// Group holds the buildpacks participating in the build, as id@version, if detection passed
+	Group []string `json:"group,omitempty"`
+	// GroupTOML and PlanTOML hold the group.toml and plan.toml written by the detector if detection passed
+	GroupTOML string `json:"groupToml,omitempty"`
+	PlanTOML  string `json:"planToml,omitempty"`
+	// Groups holds the result of every buildpack in each group that was tried, in the order they were tried
+	Groups []DetectGroupResult `json:"groups"`
+	// Order is the detection order of the builder, including any buildpacks added for the build
+	Order pubbldr.DetectionOrder `json:"order"`
+}
+
+// DetectGroupResult describes a group of buildpacks the detector tried.
+type DetectGroupResult struct {
+	Passed     bool                    `json:"passed"`
+	Buildpacks []DetectBuildpackResult `json:"buildpacks"`
+}
+
+// DetectBuildpackResult describes the result of running detect for a single buildpack.
+type DetectBuildpackResult struct {
+	Buildpack string `json:"buildpack"`
+	// Result is one of pass, fail, skip or error
+	Result string `json:"result"`
+	Output string `json:"output,omitempty"`
+}
+
+// Detect resolves the build exactly as Build would, but only runs the analyzer and detector.
+// If no group passes detection an error is returned along with a result describing
+// every group that was tried, so the failing buildpacks and their output can be reported.
+func (c *Client) Detect(ctx context.Context, opts BuildOptions) (*DetectResult, error) {
+	destinationDir, err := os.MkdirTemp("", "pack.detect")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(destinationDir)
+
+	run := &partialRun{destinationDir: destinationDir}
+	if opts.Image == "" {
+		// detections of unrelated apps don't share caches, nor do they record volume keys for them
+		id := randString(10)
+		opts.Image = fmt.Sprintf("%s/%s", DetectImageName, id)
+		volumes := ephemeralVolumeCaches(&opts.Cache, "detect-"+id)
+		defer c.removeVolumes(volumes)
+		run.ephemeralCaches = true
+	}
+	opts.Publish = false
+
+	result := &DetectResult{}
+	var mu sync.Mutex
+	handler := opts.EventHandler
+	opts.EventHandler = func(e events.Event) {
+		mu.Lock()
+		result.record(e)
+		mu.Unlock()
+		if handler != nil {
+			handler(e)
+		}
+	}
+
+	buildErr := c.build(ctx, opts, run)
+	result.Order = run.order
+	if buildErr != nil {
+		return result, buildErr
+	}
+
+	groupTOML, err := os.ReadFile(filepath.Join(destinationDir, "group.toml"))
+	if err != nil {
+		return result, errors.Wrap(err, "reading group.toml")
+	}
+	result.GroupTOML = string(groupTOML)
+
+	planTOML, err := os.ReadFile(filepath.Join(destinationDir, "plan.toml"))
+	if err != nil {
+		return result, errors.Wrap(err, "reading plan.toml")
+	}
+	result.PlanTOML = string(planTOML)
+
+	return result, nil
+}
+
+func (r *DetectResult) record(e events.Event) {
+	switch e.Type {
+	case events.BuildpackDetected:
+		if e.Group < 1 {
+			return
+		}
+		for len(r.Groups) < e.Group {
+			r.Groups = append(r.Groups, DetectGroupResult{})
+		}
+		group := &r.Groups[e.Group-1]
+		group.Buildpacks = append(group.Buildpacks, DetectBuildpackResult{
+			Buildpack: e.Buildpack,
+			Result:    e.Result,
+			Output:    e.Output,
+		})
+	case events.GroupSelected:
+		// the detector stops at the first group that passes, so it's the last group tried
+		if len(r.Groups) > 0 {
+			r.Groups[len(r.Groups)-1].Passed = true
+		}
+		r.Group = e.Buildpacks
+	}
+}
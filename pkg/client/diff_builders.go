@@ -0,0 +1,375 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	lifecycleplatform "github.com/buildpacks/lifecycle/platform"
+	"github.com/pkg/errors"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// DiffBuildersOptions define how builders are compared.
+type DiffBuildersOptions struct {
+	// Daemon compares builders in the daemon, rather than in a registry
+	Daemon bool
+}
+
+// BuilderDiff describes how a builder differs from another one, typically an older version of it.
+// Only what changed is listed.
+type BuilderDiff struct {
+	// Lifecycle is nil when both builders have the same lifecycle version and APIs
+	Lifecycle *LifecycleDiff
+
+	// Buildpacks and Extensions that were added, removed or bumped to other versions
+	Buildpacks []ModuleDiff
+	Extensions []ModuleDiff
+
+	// Order and OrderExtensions are nil when the detection order didn't change
+	Order           *DetectionOrderDiff
+	OrderExtensions *DetectionOrderDiff
+
+	// RunImages that were added or removed, or whose mirrors changed
+	RunImages []RunImageConfigDiff
+
+	// Target is nil when both builders target the same platform and distribution
+	Target *TargetDiff
+
+	// Env lists the environment variables of the builder image that were added, removed or changed
+	Env []EnvDiff
+}
+
+// LifecycleDiff describes how the lifecycle of a builder changed
+type LifecycleDiff struct {
+	VersionBefore string
+	VersionAfter  string
+	BuildpackAPIs APIVersionsDiff
+	PlatformAPIs  APIVersionsDiff
+}
+
+// APIVersionsDiff lists the API versions a lifecycle started or stopped supporting or deprecating
+type APIVersionsDiff struct {
+	SupportedAdded    []string
+	SupportedRemoved  []string
+	DeprecatedAdded   []string
+	DeprecatedRemoved []string
+}
+
+// Empty is true when the supported and deprecated APIs didn't change
+func (d APIVersionsDiff) Empty() bool {
+	return len(d.SupportedAdded) == 0 && len(d.SupportedRemoved) == 0 &&
+		len(d.DeprecatedAdded) == 0 && len(d.DeprecatedRemoved) == 0
+}
+
+// ModuleDiff describes how the versions of a buildpack or extension in a builder changed
+type ModuleDiff struct {
+	ID string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change         string
+	VersionsBefore []string
+	VersionsAfter  []string
+}
+
+// DetectionOrderDiff is the complete detection order of each builder
+type DetectionOrderDiff struct {
+	Before pubbldr.DetectionOrder
+	After  pubbldr.DetectionOrder
+}
+
+// RunImageConfigDiff describes how a run image of a builder changed
+type RunImageConfigDiff struct {
+	Image string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change         string
+	MirrorsAdded   []string
+	MirrorsRemoved []string
+}
+
+// TargetDiff is the target of each builder
+type TargetDiff struct {
+	Before dist.Target
+	After  dist.Target
+}
+
+// EnvDiff describes how an environment variable of a builder changed
+type EnvDiff struct {
+	Name string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change string
+	Before string
+	After  string
+}
+
+// diffedBuilder is what is compared of a builder
+type diffedBuilder struct {
+	info   *BuilderInfo
+	target dist.Target
+	env    map[string]string
+}
+
+// DiffBuilders compares builder newName to builder oldName, reporting lifecycle, buildpack, detection order,
+// run image, target and environment changes.
+func (c *Client) DiffBuilders(ctx context.Context, oldName, newName string, opts DiffBuildersOptions) (*BuilderDiff, error) {
+	before, err := c.diffedBuilder(ctx, oldName, opts.Daemon)
+	if err != nil {
+		return nil, err
+	}
+	after, err := c.diffedBuilder(ctx, newName, opts.Daemon)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &BuilderDiff{
+		Lifecycle:  diffLifecycles(before.info.Lifecycle, after.info.Lifecycle),
+		Buildpacks: diffModules(before.info.Buildpacks, after.info.Buildpacks),
+		Extensions: diffModules(before.info.Extensions, after.info.Extensions),
+		RunImages:  diffRunImageConfigs(before.info.RunImages, after.info.RunImages),
+		Env:        diffEnv(before.env, after.env),
+	}
+	if !reflect.DeepEqual(before.info.Order, after.info.Order) {
+		diff.Order = &DetectionOrderDiff{Before: before.info.Order, After: after.info.Order}
+	}
+	if !reflect.DeepEqual(before.info.OrderExtensions, after.info.OrderExtensions) {
+		diff.OrderExtensions = &DetectionOrderDiff{Before: before.info.OrderExtensions, After: after.info.OrderExtensions}
+	}
+	if before.target.ValuesAsPlatform() != after.target.ValuesAsPlatform() {
+		diff.Target = &TargetDiff{Before: before.target, After: after.target}
+	}
+
+	return diff, nil
+}
+
+func (c *Client) diffedBuilder(ctx context.Context, name string, daemon bool) (diffedBuilder, error) {
+	img, err := c.imageFetcher.Fetch(ctx, name, image.FetchOptions{Daemon: daemon, PullPolicy: image.PullNever})
+	if err != nil {
+		if errors.Cause(err) == image.ErrNotFound {
+			return diffedBuilder{}, errors.Wrapf(image.ErrNotFound, "builder %s cannot be found", style.Symbol(name))
+		}
+		return diffedBuilder{}, err
+	}
+
+	info, err := c.InspectBuilder(name, daemon, WithDetectionOrderDepth(pubbldr.OrderDetectionMaxDepth))
+	if err != nil {
+		return diffedBuilder{}, errors.Wrapf(err, "inspecting builder %s", style.Symbol(name))
+	}
+	if info == nil {
+		return diffedBuilder{}, errors.Errorf("builder %s cannot be found", style.Symbol(name))
+	}
+
+	target, err := builderTarget(img)
+	if err != nil {
+		return diffedBuilder{}, errors.Wrapf(err, "reading target of builder %s", style.Symbol(name))
+	}
+	env, err := builderEnv(img)
+	if err != nil {
+		return diffedBuilder{}, errors.Wrapf(err, "reading environment of builder %s", style.Symbol(name))
+	}
+
+	return diffedBuilder{info: info, target: target, env: env}, nil
+}
+
+func builderTarget(img imgutil.Image) (dist.Target, error) {
+	var (
+		target dist.Target
+		err    error
+	)
+	if target.OS, err = img.OS(); err != nil {
+		return dist.Target{}, err
+	}
+	if target.Arch, err = img.Architecture(); err != nil {
+		return dist.Target{}, err
+	}
+	if target.ArchVariant, err = img.Variant(); err != nil {
+		return dist.Target{}, err
+	}
+
+	distroName, err := img.Label(lifecycleplatform.OSDistroNameLabel)
+	if err != nil {
+		return dist.Target{}, err
+	}
+	distroVersion, err := img.Label(lifecycleplatform.OSDistroVersionLabel)
+	if err != nil {
+		return dist.Target{}, err
+	}
+	if distroName != "" || distroVersion != "" {
+		target.Distributions = []dist.Distribution{{Name: distroName, Version: distroVersion}}
+	}
+	return target, nil
+}
+
+// builderEnv returns the environment of the builder image config, or nothing if the config can't be read
+func builderEnv(img imgutil.Image) (map[string]string, error) {
+	underlying := img.UnderlyingImage()
+	if underlying == nil {
+		return nil, nil
+	}
+	configFile, err := underlying.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for _, entry := range configFile.Config.Env {
+		name, value, _ := strings.Cut(entry, "=")
+		env[name] = value
+	}
+	return env, nil
+}
+
+func diffLifecycles(before, after builder.LifecycleDescriptor) *LifecycleDiff {
+	version := func(descriptor builder.LifecycleDescriptor) string {
+		if descriptor.Info.Version == nil {
+			return ""
+		}
+		return descriptor.Info.Version.String()
+	}
+
+	diff := &LifecycleDiff{
+		VersionBefore: version(before),
+		VersionAfter:  version(after),
+		BuildpackAPIs: diffAPIVersions(before.APIs.Buildpack, after.APIs.Buildpack),
+		PlatformAPIs:  diffAPIVersions(before.APIs.Platform, after.APIs.Platform),
+	}
+	if diff.VersionBefore == diff.VersionAfter && diff.BuildpackAPIs.Empty() && diff.PlatformAPIs.Empty() {
+		return nil
+	}
+	return diff
+}
+
+func diffAPIVersions(before, after builder.APIVersions) APIVersionsDiff {
+	return APIVersionsDiff{
+		SupportedAdded:    missingFrom(after.Supported.AsStrings(), before.Supported.AsStrings()),
+		SupportedRemoved:  missingFrom(before.Supported.AsStrings(), after.Supported.AsStrings()),
+		DeprecatedAdded:   missingFrom(after.Deprecated.AsStrings(), before.Deprecated.AsStrings()),
+		DeprecatedRemoved: missingFrom(before.Deprecated.AsStrings(), after.Deprecated.AsStrings()),
+	}
+}
+
+func diffModules(before, after []dist.ModuleInfo) []ModuleDiff {
+	versions := func(modules []dist.ModuleInfo) map[string][]string {
+		result := map[string][]string{}
+		for _, module := range modules {
+			if !contains(result[module.ID], module.Version) {
+				result[module.ID] = append(result[module.ID], module.Version)
+			}
+		}
+		for id := range result {
+			sort.Strings(result[id])
+		}
+		return result
+	}
+	versionsBefore := versions(before)
+	versionsAfter := versions(after)
+
+	ids := map[string]bool{}
+	for id := range versionsBefore {
+		ids[id] = true
+	}
+	for id := range versionsAfter {
+		ids[id] = true
+	}
+
+	var diffs []ModuleDiff
+	for id := range ids {
+		diff := ModuleDiff{
+			ID:             id,
+			Change:         DiffChanged,
+			VersionsBefore: versionsBefore[id],
+			VersionsAfter:  versionsAfter[id],
+		}
+		switch {
+		case diff.VersionsBefore == nil:
+			diff.Change = DiffAdded
+		case diff.VersionsAfter == nil:
+			diff.Change = DiffRemoved
+		case slices.Equal(diff.VersionsBefore, diff.VersionsAfter):
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].ID < diffs[j].ID
+	})
+	return diffs
+}
+
+func diffRunImageConfigs(before, after []pubbldr.RunImageConfig) []RunImageConfigDiff {
+	find := func(runImages []pubbldr.RunImageConfig, name string) (pubbldr.RunImageConfig, bool) {
+		for _, runImage := range runImages {
+			if runImage.Image == name {
+				return runImage, true
+			}
+		}
+		return pubbldr.RunImageConfig{}, false
+	}
+
+	var diffs []RunImageConfigDiff
+	for _, runImage := range after {
+		previous, ok := find(before, runImage.Image)
+		if !ok {
+			diffs = append(diffs, RunImageConfigDiff{Image: runImage.Image, Change: DiffAdded, MirrorsAdded: runImage.Mirrors})
+			continue
+		}
+
+		diff := RunImageConfigDiff{
+			Image:          runImage.Image,
+			Change:         DiffChanged,
+			MirrorsAdded:   missingFrom(runImage.Mirrors, previous.Mirrors),
+			MirrorsRemoved: missingFrom(previous.Mirrors, runImage.Mirrors),
+		}
+		if len(diff.MirrorsAdded) > 0 || len(diff.MirrorsRemoved) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	for _, runImage := range before {
+		if _, ok := find(after, runImage.Image); !ok {
+			diffs = append(diffs, RunImageConfigDiff{Image: runImage.Image, Change: DiffRemoved, MirrorsRemoved: runImage.Mirrors})
+		}
+	}
+	return diffs
+}
+
+func diffEnv(before, after map[string]string) []EnvDiff {
+	var diffs []EnvDiff
+	for name, valueAfter := range after {
+		valueBefore, ok := before[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, EnvDiff{Name: name, Change: DiffAdded, After: valueAfter})
+		case valueBefore != valueAfter:
+			diffs = append(diffs, EnvDiff{Name: name, Change: DiffChanged, Before: valueBefore, After: valueAfter})
+		}
+	}
+	for name, valueBefore := range before {
+		if _, ok := after[name]; !ok {
+			diffs = append(diffs, EnvDiff{Name: name, Change: DiffRemoved, Before: valueBefore})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+// missingFrom returns the values of list that aren't in other
+func missingFrom(list, other []string) []string {
+	var result []string
+	for _, value := range list {
+		if !contains(other, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
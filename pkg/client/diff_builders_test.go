@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDiffBuilders(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DiffBuilders", testDiffBuilders, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDiffBuilders(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+
+		subject = &Client{
+			logger:       logging.NewLogWithWriters(&out, &out),
+			imageFetcher: mockImageFetcher,
+		}
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	type builderImage struct {
+		name       string
+		lifecycle  string
+		buildpacks string
+		order      string
+		layers     string
+		runImages  string
+		arch       string
+		distro     string
+		env        []string
+	}
+
+	// expectBuilder makes a builder image available from the daemon
+	expectBuilder := func(b builderImage) {
+		v1Image := empty.Image
+		configFile, err := v1Image.ConfigFile()
+		h.AssertNil(t, err)
+		configFile.Config.Env = b.env
+		v1Image, err = mutate.Config(v1Image, configFile.Config)
+		h.AssertNil(t, err)
+
+		img := h.NewFakeWithUnderlyingV1Image(b.name, nil, v1Image)
+		h.AssertNil(t, img.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+		h.AssertNil(t, img.SetArchitecture(b.arch))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.base.distro.name", "ubuntu"))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.base.distro.version", b.distro))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.builder.metadata", fmt.Sprintf(`{
+  "buildpacks": [%s],
+  "lifecycle": %s,
+  "images": [%s]
+}`, b.buildpacks, b.lifecycle, b.runImages)))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.buildpack.order", b.order))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.buildpack.layers", b.layers))
+
+		mockImageFetcher.EXPECT().
+			Fetch(gomock.Any(), b.name, image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).
+			Return(img, nil).
+			AnyTimes()
+	}
+
+	oldBuilder := builderImage{
+		name:       "some/builder:v1",
+		lifecycle:  `{"version": "0.17.0", "apis": {"buildpack": {"deprecated": [], "supported": ["0.9", "0.10"]}, "platform": {"deprecated": [], "supported": ["0.12"]}}}`,
+		buildpacks: `{"id": "some/bp", "version": "1.0.0"}, {"id": "same/bp", "version": "1.0.0"}, {"id": "old/bp", "version": "2.0.0"}`,
+		order:      `[{"group": [{"id": "some/bp", "version": "1.0.0"}, {"id": "old/bp", "version": "2.0.0", "optional": true}]}]`,
+		layers: `{
+  "some/bp": {"1.0.0": {"api": "0.10", "layerDiffID": "sha256:some-bp-1"}},
+  "same/bp": {"1.0.0": {"api": "0.10", "layerDiffID": "sha256:same-bp"}},
+  "old/bp": {"2.0.0": {"api": "0.10", "layerDiffID": "sha256:old-bp"}}
+}`,
+		runImages: `{"image": "some/run:jammy", "mirrors": ["mirror.example.com/run:jammy"]}, {"image": "other/run", "mirrors": ["a.example.com/run"]}`,
+		arch:      "amd64",
+		distro:    "22.04",
+		env:       []string{"CNB_USER_ID=1000", "CNB_GROUP_ID=1000", "OLD_VAR=old"},
+	}
+
+	when("#DiffBuilders", func() {
+		it("lists what changed between the builders", func() {
+			expectBuilder(oldBuilder)
+			expectBuilder(builderImage{
+				name:       "some/builder:v2",
+				lifecycle:  `{"version": "0.20.0", "apis": {"buildpack": {"deprecated": ["0.9"], "supported": ["0.9", "0.10", "0.11"]}, "platform": {"deprecated": [], "supported": ["0.13"]}}}`,
+				buildpacks: `{"id": "some/bp", "version": "1.1.0"}, {"id": "same/bp", "version": "1.0.0"}, {"id": "new/bp", "version": "0.1.0"}`,
+				order:      `[{"group": [{"id": "some/bp", "version": "1.1.0"}, {"id": "new/bp", "version": "0.1.0", "optional": true}]}]`,
+				layers: `{
+  "some/bp": {"1.1.0": {"api": "0.10", "layerDiffID": "sha256:some-bp-1.1"}},
+  "same/bp": {"1.0.0": {"api": "0.10", "layerDiffID": "sha256:same-bp"}},
+  "new/bp": {"0.1.0": {"api": "0.10", "layerDiffID": "sha256:new-bp"}}
+}`,
+				runImages: `{"image": "some/run:noble"}, {"image": "other/run", "mirrors": ["b.example.com/run"]}`,
+				arch:      "arm64",
+				distro:    "24.04",
+				env:       []string{"CNB_USER_ID=1001", "CNB_GROUP_ID=1000", "NEW_VAR=new"},
+			})
+
+			diff, err := subject.DiffBuilders(context.TODO(), "some/builder:v1", "some/builder:v2", DiffBuildersOptions{Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, diff.Lifecycle, &LifecycleDiff{
+				VersionBefore: "0.17.0",
+				VersionAfter:  "0.20.0",
+				BuildpackAPIs: APIVersionsDiff{SupportedAdded: []string{"0.11"}, DeprecatedAdded: []string{"0.9"}},
+				PlatformAPIs:  APIVersionsDiff{SupportedAdded: []string{"0.13"}, SupportedRemoved: []string{"0.12"}},
+			})
+
+			h.AssertEq(t, diff.Buildpacks, []ModuleDiff{
+				{ID: "new/bp", Change: DiffAdded, VersionsAfter: []string{"0.1.0"}},
+				{ID: "old/bp", Change: DiffRemoved, VersionsBefore: []string{"2.0.0"}},
+				{ID: "some/bp", Change: DiffChanged, VersionsBefore: []string{"1.0.0"}, VersionsAfter: []string{"1.1.0"}},
+			})
+			h.AssertEq(t, len(diff.Extensions), 0)
+
+			h.AssertNotNil(t, diff.Order)
+			h.AssertEq(t, diff.Order.Before[0].GroupDetectionOrder[1].FullName(), "old/bp@2.0.0")
+			h.AssertEq(t, diff.Order.After[0].GroupDetectionOrder[1].FullName(), "new/bp@0.1.0")
+			h.AssertNil(t, diff.OrderExtensions)
+
+			h.AssertEq(t, diff.RunImages, []RunImageConfigDiff{
+				{Image: "some/run:noble", Change: DiffAdded},
+				{Image: "other/run", Change: DiffChanged, MirrorsAdded: []string{"b.example.com/run"}, MirrorsRemoved: []string{"a.example.com/run"}},
+				{Image: "some/run:jammy", Change: DiffRemoved, MirrorsRemoved: []string{"mirror.example.com/run:jammy"}},
+			})
+
+			h.AssertEq(t, diff.Target, &TargetDiff{
+				Before: dist.Target{OS: "linux", Arch: "amd64", Distributions: []dist.Distribution{{Name: "ubuntu", Version: "22.04"}}},
+				After:  dist.Target{OS: "linux", Arch: "arm64", Distributions: []dist.Distribution{{Name: "ubuntu", Version: "24.04"}}},
+			})
+
+			h.AssertEq(t, diff.Env, []EnvDiff{
+				{Name: "CNB_USER_ID", Change: DiffChanged, Before: "1000", After: "1001"},
+				{Name: "NEW_VAR", Change: DiffAdded, After: "new"},
+				{Name: "OLD_VAR", Change: DiffRemoved, Before: "old"},
+			})
+		})
+
+		it("lists nothing for identical builders", func() {
+			expectBuilder(oldBuilder)
+			newBuilder := oldBuilder
+			newBuilder.name = "some/builder:v2"
+			expectBuilder(newBuilder)
+
+			diff, err := subject.DiffBuilders(context.TODO(), "some/builder:v1", "some/builder:v2", DiffBuildersOptions{Daemon: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, diff, &BuilderDiff{})
+		})
+
+		when("a builder doesn't exist", func() {
+			it("errors", func() {
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/builder:v1", image.FetchOptions{Daemon: false, PullPolicy: image.PullNever}).
+					Return(nil, image.ErrNotFound)
+
+				_, err := subject.DiffBuilders(context.TODO(), "some/builder:v1", "some/builder:v2", DiffBuildersOptions{})
+				h.AssertError(t, err, "builder 'some/builder:v1' cannot be found")
+			})
+		})
+	})
+}
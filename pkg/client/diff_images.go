@@ -0,0 +1,417 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/lifecycle/launch"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/buildpacks/lifecycle/platform/files"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// Kinds of change listed in an ImageDiff
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffImagesOptions define how app images are compared.
+type DiffImagesOptions struct {
+	// Daemon compares images in the daemon, rather than in a registry
+	Daemon bool
+}
+
+// ImageDiff describes how an app image differs from another one. Only what changed is listed.
+type ImageDiff struct {
+	// RunImage is nil when both images were built on the same run image
+	RunImage *RunImageDiff
+
+	// Buildpacks that were added, removed, or whose version or layers changed
+	Buildpacks []BuildpackDiff
+
+	// Processes that were added, removed or changed
+	Processes []ProcessDiff
+
+	// SBOM lists the packages that were added, removed or changed version.
+	// SBOMCompared is false when the SBOM of either image could not be read.
+	SBOM         []SBOMPackageDiff
+	SBOMCompared bool
+}
+
+// RunImageDiff is the run image of each image
+type RunImageDiff struct {
+	Before files.RunImageForRebase
+	After  files.RunImageForRebase
+}
+
+// BuildpackDiff describes how what a buildpack contributed to an image changed
+type BuildpackDiff struct {
+	ID string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change        string
+	VersionBefore string
+	VersionAfter  string
+
+	// Layers that were added, removed or changed
+	Layers []LayerDiff
+}
+
+// LayerDiff describes how a buildpack layer changed
+type LayerDiff struct {
+	Name string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change       string
+	DiffIDBefore string
+	DiffIDAfter  string
+
+	// Sizes are in bytes, 0 when the layer isn't in the image and -1 when the size isn't known,
+	// such as for layers of daemon images that haven't been exported
+	SizeBefore int64
+	SizeAfter  int64
+}
+
+// SizeDelta returns by how many bytes the layer grew, if the sizes of the layer in both images are known
+func (l LayerDiff) SizeDelta() (int64, bool) {
+	if l.SizeBefore < 0 || l.SizeAfter < 0 {
+		return 0, false
+	}
+	return l.SizeAfter - l.SizeBefore, true
+}
+
+// ProcessDiff describes how a process changed
+type ProcessDiff struct {
+	Type string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change        string
+	Before        *launch.Process
+	After         *launch.Process
+	DefaultBefore bool
+	DefaultAfter  bool
+}
+
+// SBOMPackageDiff describes how the versions of a package listed in the SBOM changed
+type SBOMPackageDiff struct {
+	Name string
+
+	// Change is one of DiffAdded, DiffRemoved or DiffChanged
+	Change         string
+	VersionsBefore []string
+	VersionsAfter  []string
+}
+
+// diffedImage is what is compared of an image
+type diffedImage struct {
+	info   *ImageInfo
+	layers layersMetadata
+	sizes  map[string]int64
+}
+
+// DiffImages compares app image nameB to app image nameA at the buildpack and layer level.
+func (c *Client) DiffImages(ctx context.Context, nameA, nameB string, opts DiffImagesOptions) (*ImageDiff, error) {
+	before, err := c.diffedImage(ctx, nameA, opts.Daemon)
+	if err != nil {
+		return nil, err
+	}
+	after, err := c.diffedImage(ctx, nameB, opts.Daemon)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ImageDiff{
+		Buildpacks: diffBuildpacks(before, after),
+		Processes:  diffProcesses(before.info.Processes, after.info.Processes),
+	}
+	if runImageChanged(before.info.Base, after.info.Base) {
+		diff.RunImage = &RunImageDiff{Before: before.info.Base, After: after.info.Base}
+	}
+
+	packagesBefore, errBefore := c.ReadSBOM(ctx, nameA, ReadSBOMOptions{Daemon: opts.Daemon})
+	packagesAfter, errAfter := c.ReadSBOM(ctx, nameB, ReadSBOMOptions{Daemon: opts.Daemon})
+	switch {
+	case errBefore != nil:
+		c.logger.Warnf("Skipping SBOM comparison: %s", errBefore)
+	case errAfter != nil:
+		c.logger.Warnf("Skipping SBOM comparison: %s", errAfter)
+	default:
+		diff.SBOM = diffSBOMPackages(packagesBefore, packagesAfter)
+		diff.SBOMCompared = true
+	}
+
+	return diff, nil
+}
+
+func runImageChanged(before, after files.RunImageForRebase) bool {
+	return before.Reference != after.Reference ||
+		before.TopLayer != after.TopLayer ||
+		before.Image != after.Image
+}
+
+func (c *Client) diffedImage(ctx context.Context, name string, daemon bool) (diffedImage, error) {
+	img, err := c.imageFetcher.Fetch(ctx, name, image.FetchOptions{Daemon: daemon, PullPolicy: image.PullNever})
+	if err != nil {
+		if errors.Cause(err) == image.ErrNotFound {
+			return diffedImage{}, errors.Wrapf(image.ErrNotFound, "image %s cannot be found", style.Symbol(name))
+		}
+		return diffedImage{}, err
+	}
+
+	info, err := imageInfo(img)
+	if err != nil {
+		return diffedImage{}, errors.Wrapf(err, "inspecting image %s", style.Symbol(name))
+	}
+
+	result := diffedImage{info: info, sizes: map[string]int64{}}
+	if _, err := dist.GetLabel(img, platform.LifecycleMetadataLabel, &result.layers); err != nil {
+		return diffedImage{}, err
+	}
+	for _, bp := range result.layers.Buildpacks {
+		for _, layer := range bp.Layers {
+			result.sizes[layer.SHA] = layerSize(img, layer.SHA)
+		}
+	}
+	return result, nil
+}
+
+// layerSize returns the size of a layer as stored by the image, or -1 if it isn't known
+func layerSize(img imgutil.Image, diffID string) int64 {
+	underlying := img.UnderlyingImage()
+	if underlying == nil {
+		return -1
+	}
+	hash, err := v1.NewHash(diffID)
+	if err != nil {
+		return -1
+	}
+	layer, err := underlying.LayerByDiffID(hash)
+	if err != nil {
+		return -1
+	}
+	size, err := layer.Size()
+	if err != nil || size < 0 {
+		return -1
+	}
+	return size
+}
+
+func diffBuildpacks(before, after diffedImage) []BuildpackDiff {
+	type buildpackState struct {
+		version string
+		layers  map[string]string
+	}
+	states := func(img diffedImage) (map[string]*buildpackState, []string) {
+		result := map[string]*buildpackState{}
+		var order []string
+		get := func(id string) *buildpackState {
+			if _, ok := result[id]; !ok {
+				result[id] = &buildpackState{layers: map[string]string{}}
+				order = append(order, id)
+			}
+			return result[id]
+		}
+		for _, bp := range img.info.Buildpacks {
+			get(bp.ID).version = bp.Version
+		}
+		for _, bp := range img.layers.Buildpacks {
+			state := get(bp.ID)
+			if state.version == "" {
+				state.version = bp.Version
+			}
+			for name, layer := range bp.Layers {
+				state.layers[name] = layer.SHA
+			}
+		}
+		return result, order
+	}
+	statesBefore, orderBefore := states(before)
+	statesAfter, orderAfter := states(after)
+
+	var diffs []BuildpackDiff
+	seen := map[string]bool{}
+	for _, id := range append(orderAfter, orderBefore...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		stateBefore, inBefore := statesBefore[id]
+		if !inBefore {
+			stateBefore = &buildpackState{}
+		}
+		stateAfter, inAfter := statesAfter[id]
+		if !inAfter {
+			stateAfter = &buildpackState{}
+		}
+
+		diff := BuildpackDiff{
+			ID:            id,
+			Change:        DiffChanged,
+			VersionBefore: stateBefore.version,
+			VersionAfter:  stateAfter.version,
+		}
+		switch {
+		case !inBefore:
+			diff.Change = DiffAdded
+		case !inAfter:
+			diff.Change = DiffRemoved
+		}
+
+		names := map[string]bool{}
+		for name := range stateBefore.layers {
+			names[name] = true
+		}
+		for name := range stateAfter.layers {
+			names[name] = true
+		}
+		for name := range names {
+			shaBefore, inBefore := stateBefore.layers[name]
+			shaAfter, inAfter := stateAfter.layers[name]
+			if shaBefore == shaAfter {
+				continue
+			}
+
+			layer := LayerDiff{Name: name, Change: DiffChanged, DiffIDBefore: shaBefore, DiffIDAfter: shaAfter}
+			if inBefore {
+				layer.SizeBefore = before.sizes[shaBefore]
+			} else {
+				layer.Change = DiffAdded
+			}
+			if inAfter {
+				layer.SizeAfter = after.sizes[shaAfter]
+			} else {
+				layer.Change = DiffRemoved
+			}
+			diff.Layers = append(diff.Layers, layer)
+		}
+		sort.Slice(diff.Layers, func(i, j int) bool {
+			return diff.Layers[i].Name < diff.Layers[j].Name
+		})
+
+		if diff.Change == DiffChanged && diff.VersionBefore == diff.VersionAfter && len(diff.Layers) == 0 {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func diffProcesses(before, after ProcessDetails) []ProcessDiff {
+	type processState struct {
+		process   launch.Process
+		isDefault bool
+	}
+	states := func(details ProcessDetails) (map[string]processState, []string) {
+		result := map[string]processState{}
+		var order []string
+		if details.DefaultProcess != nil {
+			result[details.DefaultProcess.Type] = processState{process: *details.DefaultProcess, isDefault: true}
+			order = append(order, details.DefaultProcess.Type)
+		}
+		for _, proc := range details.OtherProcesses {
+			result[proc.Type] = processState{process: proc}
+			order = append(order, proc.Type)
+		}
+		return result, order
+	}
+	statesBefore, orderBefore := states(before)
+	statesAfter, orderAfter := states(after)
+
+	var diffs []ProcessDiff
+	seen := map[string]bool{}
+	for _, processType := range append(orderAfter, orderBefore...) {
+		if seen[processType] {
+			continue
+		}
+		seen[processType] = true
+
+		stateBefore, inBefore := statesBefore[processType]
+		stateAfter, inAfter := statesAfter[processType]
+		diff := ProcessDiff{Type: processType, Change: DiffChanged, DefaultBefore: stateBefore.isDefault, DefaultAfter: stateAfter.isDefault}
+		if inBefore {
+			diff.Before = &stateBefore.process
+		} else {
+			diff.Change = DiffAdded
+		}
+		if inAfter {
+			diff.After = &stateAfter.process
+		} else {
+			diff.Change = DiffRemoved
+		}
+
+		if diff.Change == DiffChanged && stateBefore.isDefault == stateAfter.isDefault && processesEqual(stateBefore.process, stateAfter.process) {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func processesEqual(a, b launch.Process) bool {
+	return a.Direct == b.Direct &&
+		a.WorkingDirectory == b.WorkingDirectory &&
+		slices.Equal(a.Command.Entries, b.Command.Entries) &&
+		slices.Equal(a.Args, b.Args)
+}
+
+func diffSBOMPackages(before, after []SBOMPackage) []SBOMPackageDiff {
+	versions := func(packages []SBOMPackage) map[string][]string {
+		result := map[string][]string{}
+		for _, pkg := range packages {
+			version := pkg.Version
+			if version == "" {
+				version = "-"
+			}
+			if !contains(result[pkg.Name], version) {
+				result[pkg.Name] = append(result[pkg.Name], version)
+			}
+		}
+		for name := range result {
+			sort.Strings(result[name])
+		}
+		return result
+	}
+	versionsBefore := versions(before)
+	versionsAfter := versions(after)
+
+	names := map[string]bool{}
+	for name := range versionsBefore {
+		names[name] = true
+	}
+	for name := range versionsAfter {
+		names[name] = true
+	}
+
+	var diffs []SBOMPackageDiff
+	for name := range names {
+		diff := SBOMPackageDiff{
+			Name:           name,
+			Change:         DiffChanged,
+			VersionsBefore: versionsBefore[name],
+			VersionsAfter:  versionsAfter[name],
+		}
+		switch {
+		case diff.VersionsBefore == nil:
+			diff.Change = DiffAdded
+		case diff.VersionsAfter == nil:
+			diff.Change = DiffRemoved
+		case slices.Equal(diff.VersionsBefore, diff.VersionsAfter):
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestDiffImages(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "DiffImages", testDiffImages, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testDiffImages(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDockerClient := testmocks.NewMockAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	type appImage struct {
+		name       string
+		runImage   string
+		buildpacks string
+		layers     string
+		processes  string
+		// sbom is the contents of the Syft SBOM of 'some/bp', if any
+		sbom string
+	}
+
+	// expectImage makes an app image available from the daemon
+	expectImage := func(app appImage) {
+		img := testmocks.NewImage(app.name, "", nil)
+		h.AssertNil(t, img.SetLabel("io.buildpacks.stack.id", "some.stack.id"))
+
+		sbomMD := ""
+		if app.sbom != "" {
+			sbomDir := t.TempDir()
+			path := filepath.Join(sbomDir, "layers", "sbom", "launch", "some_bp", "sbom.syft.json")
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(path), 0755))
+			h.AssertNil(t, os.WriteFile(path, []byte(app.sbom), 0600))
+
+			layerPath := h.CreateTAR(t, sbomDir, ".", -1)
+			t.Cleanup(func() { os.Remove(layerPath) })
+			data, err := os.ReadFile(layerPath)
+			h.AssertNil(t, err)
+			sum := sha256.Sum256(data)
+			diffID := "sha256:" + hex.EncodeToString(sum[:])
+			h.AssertNil(t, img.AddLayerWithDiffID(layerPath, diffID))
+			sbomMD = fmt.Sprintf(`"sbom": {"sha": %q},`, diffID)
+		}
+
+		h.AssertNil(t, img.SetLabel("io.buildpacks.lifecycle.metadata", fmt.Sprintf(`{
+  %s
+  "runImage": {"topLayer": "sha256:top-%s", "reference": "%s"},
+  "buildpacks": [%s]
+}`, sbomMD, app.runImage, app.runImage, app.layers)))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.build.metadata", fmt.Sprintf(`{
+  "buildpacks": [%s],
+  "processes": [%s]
+}`, app.buildpacks, app.processes)))
+
+		mockImageFetcher.EXPECT().
+			Fetch(gomock.Any(), app.name, image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).
+			Return(img, nil).
+			AnyTimes()
+	}
+
+	when("#DiffImages", func() {
+		it("lists what changed between the images", func() {
+			expectImage(appImage{
+				name:       "some/app:v1",
+				runImage:   "some/run@sha256:aaa",
+				buildpacks: `{"id": "some/bp", "version": "1.0.0"}, {"id": "same/bp", "version": "1.0.0"}, {"id": "old/bp", "version": "2.0.0"}`,
+				layers: `{"key": "some/bp", "version": "1.0.0", "layers": {"deps": {"sha": "sha256:deps-1"}, "same": {"sha": "sha256:same"}, "gone": {"sha": "sha256:gone"}}},
+				         {"key": "same/bp", "version": "1.0.0", "layers": {"same": {"sha": "sha256:same-too"}}}`,
+				processes: `{"type": "web", "command": ["node", "server.js"], "direct": true}, {"type": "worker", "command": ["node", "worker.js"], "direct": true}`,
+				sbom:      `{"artifacts": [{"name": "express", "version": "4.18.2"}, {"name": "left-pad", "version": "1.0.0"}]}`,
+			})
+			expectImage(appImage{
+				name:       "some/app:v2",
+				runImage:   "some/run@sha256:bbb",
+				buildpacks: `{"id": "some/bp", "version": "1.1.0"}, {"id": "same/bp", "version": "1.0.0"}, {"id": "new/bp", "version": "0.1.0"}`,
+				layers: `{"key": "some/bp", "version": "1.1.0", "layers": {"deps": {"sha": "sha256:deps-2"}, "same": {"sha": "sha256:same"}, "added": {"sha": "sha256:added"}}},
+				         {"key": "same/bp", "version": "1.0.0", "layers": {"same": {"sha": "sha256:same-too"}}}`,
+				processes: `{"type": "web", "command": ["node", "index.js"], "direct": true}, {"type": "worker", "command": ["node", "worker.js"], "direct": true}`,
+				sbom:      `{"artifacts": [{"name": "express", "version": "4.19.2"}, {"name": "lodash", "version": "4.17.21"}]}`,
+			})
+
+			diff, err := subject.DiffImages(context.TODO(), "some/app:v1", "some/app:v2", DiffImagesOptions{Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertNotNil(t, diff.RunImage)
+			h.AssertEq(t, diff.RunImage.Before.Reference, "some/run@sha256:aaa")
+			h.AssertEq(t, diff.RunImage.After.Reference, "some/run@sha256:bbb")
+
+			h.AssertEq(t, diff.Buildpacks, []BuildpackDiff{
+				{
+					ID:            "some/bp",
+					Change:        DiffChanged,
+					VersionBefore: "1.0.0",
+					VersionAfter:  "1.1.0",
+					Layers: []LayerDiff{
+						{Name: "added", Change: DiffAdded, DiffIDAfter: "sha256:added", SizeAfter: -1},
+						{Name: "deps", Change: DiffChanged, DiffIDBefore: "sha256:deps-1", DiffIDAfter: "sha256:deps-2", SizeBefore: -1, SizeAfter: -1},
+						{Name: "gone", Change: DiffRemoved, DiffIDBefore: "sha256:gone", SizeBefore: -1},
+					},
+				},
+				{ID: "new/bp", Change: DiffAdded, VersionAfter: "0.1.0"},
+				{ID: "old/bp", Change: DiffRemoved, VersionBefore: "2.0.0"},
+			})
+
+			h.AssertEq(t, len(diff.Processes), 1)
+			h.AssertEq(t, diff.Processes[0].Type, "web")
+			h.AssertEq(t, diff.Processes[0].Change, DiffChanged)
+			h.AssertEq(t, diff.Processes[0].Before.Command.Entries, []string{"node", "server.js"})
+			h.AssertEq(t, diff.Processes[0].After.Command.Entries, []string{"node", "index.js"})
+
+			h.AssertTrue(t, diff.SBOMCompared)
+			h.AssertEq(t, diff.SBOM, []SBOMPackageDiff{
+				{Name: "express", Change: DiffChanged, VersionsBefore: []string{"4.18.2"}, VersionsAfter: []string{"4.19.2"}},
+				{Name: "left-pad", Change: DiffRemoved, VersionsBefore: []string{"1.0.0"}},
+				{Name: "lodash", Change: DiffAdded, VersionsAfter: []string{"4.17.21"}},
+			})
+		})
+
+		it("lists nothing for identical images", func() {
+			app := appImage{
+				runImage:   "some/run@sha256:aaa",
+				buildpacks: `{"id": "some/bp", "version": "1.0.0"}`,
+				layers:     `{"key": "some/bp", "version": "1.0.0", "layers": {"deps": {"sha": "sha256:deps"}}}`,
+				processes:  `{"type": "web", "command": ["node", "server.js"], "direct": true}`,
+				sbom:       `{"artifacts": [{"name": "express", "version": "4.18.2"}]}`,
+			}
+			app.name = "some/app:v1"
+			expectImage(app)
+			app.name = "some/app:v2"
+			expectImage(app)
+
+			diff, err := subject.DiffImages(context.TODO(), "some/app:v1", "some/app:v2", DiffImagesOptions{Daemon: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, diff, &ImageDiff{SBOMCompared: true})
+		})
+
+		when("an image has no SBOM", func() {
+			it("skips the SBOM comparison", func() {
+				expectImage(appImage{name: "some/app:v1", runImage: "some/run", sbom: `{"artifacts": []}`})
+				expectImage(appImage{name: "some/app:v2", runImage: "some/run"})
+
+				diff, err := subject.DiffImages(context.TODO(), "some/app:v1", "some/app:v2", DiffImagesOptions{Daemon: true})
+				h.AssertNil(t, err)
+				h.AssertFalse(t, diff.SBOMCompared)
+				h.AssertContains(t, out.String(), "Skipping SBOM comparison: could not find SBoM information on 'some/app:v2'")
+			})
+		})
+
+		when("an image doesn't exist", func() {
+			it("errors", func() {
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/app:v1", image.FetchOptions{Daemon: false, PullPolicy: image.PullNever}).
+					Return(nil, image.ErrNotFound)
+
+				_, err := subject.DiffImages(context.TODO(), "some/app:v1", "some/app:v2", DiffImagesOptions{})
+				h.AssertError(t, err, "image 'some/app:v1' cannot be found")
+			})
+		})
+	})
+}
@@ -19,6 +19,8 @@ type DockerClient interface {
 	Info(ctx context.Context, options dockerClient.InfoOptions) (dockerClient.SystemInfoResult, error)
 	ServerVersion(ctx context.Context, options dockerClient.ServerVersionOptions) (dockerClient.ServerVersionResult, error)
 	VolumeRemove(ctx context.Context, volumeID string, options dockerClient.VolumeRemoveOptions) (dockerClient.VolumeRemoveResult, error)
+	VolumeInspect(ctx context.Context, volumeID string, options dockerClient.VolumeInspectOptions) (dockerClient.VolumeInspectResult, error)
+	DiskUsage(ctx context.Context, options dockerClient.DiskUsageOptions) (dockerClient.DiskUsageResult, error)
 	ContainerCreate(ctx context.Context, options dockerClient.ContainerCreateOptions) (dockerClient.ContainerCreateResult, error)
 	CopyFromContainer(ctx context.Context, containerID string, options dockerClient.CopyFromContainerOptions) (dockerClient.CopyFromContainerResult, error)
 	ContainerInspect(ctx context.Context, containerID string, options dockerClient.ContainerInspectOptions) (dockerClient.ContainerInspectResult, error)
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"io"
 
 	"github.com/buildpacks/lifecycle/layers"
 	"github.com/buildpacks/lifecycle/platform"
@@ -19,7 +20,10 @@ type DownloadSBOMOptions struct {
 
 // Deserialize just the subset of fields we need to avoid breaking changes
 type sbomMetadata struct {
-	BOM *files.LayerMetadata `json:"sbom" toml:"sbom"`
+	BOM        *files.LayerMetadata `json:"sbom" toml:"sbom"`
+	Buildpacks []struct {
+		ID string `json:"key" toml:"key"`
+	} `json:"buildpacks" toml:"buildpacks"`
 }
 
 func (s *sbomMetadata) isMissing() bool {
@@ -37,29 +41,36 @@ const (
 // It reads the SBOM metadata of an image then
 // pulls the corresponding diffId, if it exists
 func (c *Client) DownloadSBOM(name string, options DownloadSBOMOptions) error {
-	img, err := c.imageFetcher.Fetch(context.Background(), name, image.FetchOptions{Daemon: options.Daemon, PullPolicy: image.PullNever})
+	rc, _, err := c.fetchSBOMLayer(context.Background(), name, options.Daemon, "download")
 	if err != nil {
-		if errors.Cause(err) == image.ErrNotFound {
-			c.logger.Warnf("if the image is saved on a registry run with the flag '--remote', for example: 'pack sbom download --remote %s'", name)
-			return errors.Wrapf(image.ErrNotFound, "image '%s' cannot be found", name)
-		}
 		return err
 	}
+	defer rc.Close()
 
+	return layers.Extract(rc, options.DestinationDir)
+}
+
+// fetchSBOMLayer returns the contents of the SBOM layer of an image along with its SBOM metadata.
+// command is the 'pack sbom' subcommand suggested when the image is missing from the daemon.
+func (c *Client) fetchSBOMLayer(ctx context.Context, name string, daemon bool, command string) (io.ReadCloser, sbomMetadata, error) {
 	var sbomMD sbomMetadata
+	img, err := c.imageFetcher.Fetch(ctx, name, image.FetchOptions{Daemon: daemon, PullPolicy: image.PullNever})
+	if err != nil {
+		if errors.Cause(err) == image.ErrNotFound {
+			c.logger.Warnf("if the image is saved on a registry run with the flag '--remote', for example: 'pack sbom %s --remote %s'", command, name)
+			return nil, sbomMD, errors.Wrapf(image.ErrNotFound, "image '%s' cannot be found", name)
+		}
+		return nil, sbomMD, err
+	}
+
 	if _, err := dist.GetLabel(img, platform.LifecycleMetadataLabel, &sbomMD); err != nil {
-		return err
+		return nil, sbomMD, err
 	}
 
 	if sbomMD.isMissing() {
-		return errors.Errorf("could not find SBoM information on '%s'", name)
+		return nil, sbomMD, errors.Errorf("could not find SBoM information on '%s'", name)
 	}
 
 	rc, err := img.GetLayer(sbomMD.BOM.SHA)
-	if err != nil {
-		return err
-	}
-	defer rc.Close()
-
-	return layers.Extract(rc, options.DestinationDir)
+	return rc, sbomMD, err
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver"
+	"github.com/buildpacks/imgutil"
 	"github.com/buildpacks/lifecycle/buildpack"
 	"github.com/buildpacks/lifecycle/launch"
 	"github.com/buildpacks/lifecycle/platform"
@@ -72,8 +73,9 @@ type ProcessDetails struct {
 
 // Deserialize just the subset of fields we need to avoid breaking changes
 type layersMetadata struct {
-	RunImage files.RunImageForRebase `json:"runImage" toml:"run-image"`
-	Stack    files.Stack             `json:"stack" toml:"stack"`
+	RunImage   files.RunImageForRebase    `json:"runImage" toml:"run-image"`
+	Stack      files.Stack                `json:"stack" toml:"stack"`
+	Buildpacks []buildpack.LayersMetadata `json:"buildpacks" toml:"buildpacks"`
 }
 
 const (
@@ -101,6 +103,10 @@ func (c *Client) InspectImage(name string, daemon bool) (*ImageInfo, error) {
 		return nil, err
 	}
 
+	return imageInfo(img)
+}
+
+func imageInfo(img imgutil.Image) (*ImageInfo, error) {
 	var layersMd layersMetadata
 	if _, err := dist.GetLabel(img, platform.LifecycleMetadataLabel, &layersMd); err != nil {
 		return nil, err
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/paths"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/lock"
+)
+
+// LockBuildpacksOptions define the buildpacks, extensions and lifecycle to lock
+type LockBuildpacksOptions struct {
+	// URIs of the buildpacks and extensions, as written in their config
+	URIs []string
+
+	// Lifecycle of a builder config. It's nil for package configs, which have none.
+	Lifecycle *pubbldr.LifecycleConfig
+
+	// Targets to lock the lifecycle tarballs of. They default to linux on the current architecture.
+	Targets []dist.Target
+
+	// The base directory to resolve relative URIs from
+	RelativeBaseDir string
+
+	// Name of the buildpack registry that registry buildpacks are located in
+	Registry string
+}
+
+// LockBuildpacks resolves the buildpacks, extensions and lifecycle of a config to the sha256 checksum of the
+// contents downloaded from their URI, or to the digest of their image, and returns them as a lockfile.
+// Buildpacks in local directories can't be locked, and are skipped.
+func (c *Client) LockBuildpacks(ctx context.Context, opts LockBuildpacksOptions) (lock.File, error) {
+	if c.mirror.IsOffline() {
+		return lock.File{}, errors.New("buildpacks can't be locked in offline mode")
+	}
+
+	lockfile := lock.File{}
+	for _, uri := range opts.URIs {
+		if err := c.lockURI(ctx, &lockfile, uri, opts); err != nil {
+			return lock.File{}, err
+		}
+	}
+
+	if opts.Lifecycle != nil {
+		if err := c.lockLifecycle(ctx, &lockfile, *opts.Lifecycle, opts); err != nil {
+			return lock.File{}, err
+		}
+	}
+	return lockfile, nil
+}
+
+func (c *Client) lockLifecycle(ctx context.Context, lockfile *lock.File, config pubbldr.LifecycleConfig, opts LockBuildpacksOptions) error {
+	if config.URI != "" {
+		return c.lockURI(ctx, lockfile, config.URI, opts)
+	}
+
+	version := config.Version
+	if version == "" {
+		version = builder.DefaultLifecycleVersion
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return errors.Wrapf(err, "%s must be a valid semver", style.Symbol("lifecycle.version"))
+	}
+
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = []dist.Target{{OS: "linux", Arch: runtime.GOARCH}}
+	}
+	for _, target := range targets {
+		if err := c.lockURI(ctx, lockfile, c.uriFromLifecycleVersion(*v, target.OS, target.Arch), opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) lockURI(ctx context.Context, lockfile *lock.File, uri string, opts LockBuildpacksOptions) error {
+	base, checksum := blob.SplitSHA256(uri)
+	locatorType, err := buildpack.GetLocatorType(base, opts.RelativeBaseDir, nil)
+	if err != nil {
+		return err
+	}
+
+	switch locatorType {
+	case buildpack.URILocator:
+		absURI, err := paths.FilePathToURI(base, opts.RelativeBaseDir)
+		if err != nil {
+			return errors.Wrapf(err, "making absolute: %s", style.Symbol(base))
+		}
+		if path, err := paths.URIToFilePath(absURI); err == nil && strings.HasPrefix(absURI, "file://") {
+			if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+				c.logger.Warnf("Skipping %s: buildpacks in directories can't be locked", style.Symbol(base))
+				return nil
+			}
+		}
+
+		// a checksum already given in the config is verified while downloading
+		downloadURI, err := blob.WithSHA256(absURI, checksum)
+		if err != nil {
+			return err
+		}
+		downloaded, err := c.downloader.Download(ctx, downloadURI)
+		if err != nil {
+			return errors.Wrapf(err, "downloading %s", style.Symbol(base))
+		}
+		sha256, err := blob.SHA256(downloaded)
+		if err != nil {
+			return err
+		}
+		c.logger.Infof("Locked %s to sha256 %s", style.Symbol(base), style.Symbol(sha256))
+		lockfile.Add(lock.Entry{URI: base, SHA256: sha256})
+	case buildpack.PackageLocator:
+		imageName, err := c.resolveDigest(ctx, buildpack.ParsePackageLocator(base))
+		if err != nil {
+			return err
+		}
+		c.logger.Infof("Locked %s to %s", style.Symbol(base), style.Symbol(imageName))
+		lockfile.Add(lock.Entry{URI: base, Image: imageName})
+	case buildpack.RegistryLocator:
+		registryCache, err := getRegistry(c.logger, c.keychain, opts.Registry, c.mirror)
+		if err != nil {
+			return errors.Wrapf(err, "lookup registry %s", style.Symbol(opts.Registry))
+		}
+		regBuildpack, err := registryCache.LocateBuildpack(base)
+		if err != nil {
+			return errors.Wrapf(err, "lookup buildpack %s", style.Symbol(base))
+		}
+		imageName, err := c.resolveDigest(ctx, regBuildpack.Address)
+		if err != nil {
+			return err
+		}
+		c.logger.Infof("Locked %s to %s", style.Symbol(base), style.Symbol(imageName))
+		lockfile.Add(lock.Entry{URI: base, Image: imageName})
+	default:
+		return errors.Errorf("%s can't be locked: only URIs, images and registry references can", style.Symbol(base))
+	}
+	return nil
+}
+
+// resolveDigest returns the reference by digest of the image imageName refers to
+func (c *Client) resolveDigest(ctx context.Context, imageName string) (string, error) {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing image reference %s", style.Symbol(imageName))
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.Name(), nil
+	}
+
+	desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(c.keychain))
+	if err != nil {
+		return "", errors.Wrapf(err, "resolving digest of image %s", style.Symbol(imageName))
+	}
+	return ref.Context().Digest(desc.Digest.String()).Name(), nil
+}
@@ -0,0 +1,107 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/lock"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLockBuildpacks(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "LockBuildpacks", testLockBuildpacks, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testLockBuildpacks(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *client.Client
+		mockController *gomock.Controller
+		localMirror    *mirror.Mirror
+		server         *ghttp.Server
+		out            bytes.Buffer
+		checksum       = fmt.Sprintf("%x", sha256.Sum256([]byte("some-buildpack")))
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		localMirror = mirror.New(filepath.Join(t.TempDir(), "mirror"))
+		server = ghttp.NewServer()
+
+		var err error
+		subject, err = client.NewClient(
+			client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+			client.WithDockerClient(testmocks.NewMockAPIClient(mockController)),
+			client.WithDownloader(blob.NewDownloader(logging.NewLogWithWriters(&out, &out), t.TempDir())),
+			client.WithMirror(localMirror),
+		)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+		mockController.Finish()
+	})
+
+	when("#LockBuildpacks", func() {
+		it("locks buildpacks downloaded from URIs to their sha256", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "some-buildpack"))
+			uri := server.URL() + "/buildpack.tgz"
+
+			lockfile, err := subject.LockBuildpacks(context.TODO(), client.LockBuildpacksOptions{URIs: []string{uri}})
+			h.AssertNil(t, err)
+			h.AssertEq(t, lockfile.Entries, []lock.Entry{{URI: uri, SHA256: checksum}})
+		})
+
+		it("locks lifecycles downloaded from URIs to their sha256", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "some-buildpack"))
+			uri := server.URL() + "/lifecycle.tgz"
+
+			lockfile, err := subject.LockBuildpacks(context.TODO(), client.LockBuildpacksOptions{
+				Lifecycle: &pubbldr.LifecycleConfig{URI: uri},
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, lockfile.Entries, []lock.Entry{{URI: uri, SHA256: checksum}})
+		})
+
+		it("errors when the sha256 of a URI doesn't match", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "other-buildpack"))
+			uri := server.URL() + "/buildpack.tgz"
+
+			_, err := subject.LockBuildpacks(context.TODO(), client.LockBuildpacksOptions{URIs: []string{uri + "#sha256=" + checksum}})
+			h.AssertError(t, err, "sha256 mismatch")
+		})
+
+		it("skips buildpacks in directories", func() {
+			lockfile, err := subject.LockBuildpacks(context.TODO(), client.LockBuildpacksOptions{URIs: []string{t.TempDir()}})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(lockfile.Entries), 0)
+			h.AssertContains(t, out.String(), "buildpacks in directories can't be locked")
+		})
+
+		it("errors in offline mode", func() {
+			localMirror.SetOffline(true)
+
+			_, err := subject.LockBuildpacks(context.TODO(), client.LockBuildpacksOptions{})
+			h.AssertError(t, err, "buildpacks can't be locked in offline mode")
+		})
+	})
+}
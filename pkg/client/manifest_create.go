@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 
 	"github.com/buildpacks/pack/internal/style"
@@ -29,41 +30,70 @@ type CreateManifestOptions struct {
 
 // CreateManifest implements commands.PackClient.
 func (c *Client) CreateManifest(ctx context.Context, opts CreateManifestOptions) (err error) {
+	_, err = c.createManifest(ctx, opts)
+	return err
+}
+
+// createManifest creates the image index, and returns its reference by digest when it's published
+func (c *Client) createManifest(ctx context.Context, opts CreateManifestOptions) (string, error) {
 	ops := parseOptsToIndexOptions(opts)
 
 	if c.indexFactory.Exists(opts.IndexRepoName) {
-		return fmt.Errorf("manifest list '%s' already exists in local storage; use 'pack manifest remove' to "+
+		return "", fmt.Errorf("manifest list '%s' already exists in local storage; use 'pack manifest remove' to "+
 			"remove it before creating a new manifest list with the same name", style.Symbol(opts.IndexRepoName))
 	}
 
 	index, err := c.indexFactory.CreateIndex(opts.IndexRepoName, ops...)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	for _, repoName := range opts.RepoNames {
 		if err = c.addManifestToIndex(ctx, repoName, index); err != nil {
-			return err
+			return "", err
 		}
 	}
 
 	if opts.Publish {
+		// the index is pushed as its manifest is, so the digest of the manifest is the digest of the pushed index
+		digest, err := indexDigest(opts.IndexRepoName, index)
+		if err != nil {
+			return "", err
+		}
+
 		// push to a registry without saving a local copy
 		ops = append(ops, imgutil.WithPurge(true))
 		if err = index.Push(ops...); err != nil {
-			return err
+			return "", err
 		}
 
 		c.logger.Infof("Successfully pushed manifest list %s to registry", style.Symbol(opts.IndexRepoName))
-		return nil
+		return digest, nil
 	}
 
 	if err = index.SaveDir(); err != nil {
-		return fmt.Errorf("manifest list %s could not be saved to local storage: %w", style.Symbol(opts.IndexRepoName), err)
+		return "", fmt.Errorf("manifest list %s could not be saved to local storage: %w", style.Symbol(opts.IndexRepoName), err)
 	}
 
 	c.logger.Infof("Successfully created manifest list %s", style.Symbol(opts.IndexRepoName))
-	return nil
+	return "", nil
+}
+
+// indexDigest returns the reference by digest of the index named repoName
+func indexDigest(repoName string, index imgutil.ImageIndex) (string, error) {
+	ref, err := name.ParseReference(repoName, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return "", err
+	}
+	hash, err := imgutil.NewTaggableIndex(manifest).Digest()
+	if err != nil {
+		return "", fmt.Errorf("computing digest of manifest list %s: %w", style.Symbol(repoName), err)
+	}
+	return ref.Context().Digest(hash.String()).String(), nil
 }
 
 func parseOptsToIndexOptions(opts CreateManifestOptions) (idxOpts []imgutil.IndexOption) {
@@ -10,6 +10,7 @@ import (
 	"github.com/buildpacks/imgutil"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/heroku/color"
 	"github.com/sclevine/spec"
@@ -160,6 +161,24 @@ func testCreateManifest(t *testing.T, when spec.G, it spec.S) {
 							h.AssertTrue(t, index.PushCalled)
 							h.AssertTrue(t, index.PurgeOption)
 						})
+
+						it("returns the digest of the pushed index", func() {
+							digest, err := subject.createManifest(
+								context.TODO(),
+								CreateManifestOptions{
+									IndexRepoName: indexRepoName,
+									RepoNames:     []string{"busybox:1.36-musl"},
+									Publish:       true,
+								},
+							)
+							h.AssertNil(t, err)
+
+							hash, err := index.ImageIndex.Digest()
+							h.AssertNil(t, err)
+							ref, err := name.ParseReference(indexRepoName)
+							h.AssertNil(t, err)
+							h.AssertEq(t, digest, ref.Context().Digest(hash.String()).String())
+						})
 					})
 				})
 			})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 
@@ -48,26 +49,36 @@ type NewBuildpackOptions struct {
 
 	// the targets this buildpack will work with
 	Targets []dist.Target
+
+	// The template to generate the buildpack from: the name of a built-in template (see BuildpackTemplates),
+	// a local template directory or the URL of a git repository holding one. Defaults to the bash template.
+	Template string
 }
 
 func (c *Client) NewBuildpack(ctx context.Context, opts NewBuildpackOptions) error {
-	err := createBuildpackTOML(opts.Path, opts.ID, opts.Version, opts.API, opts.Stacks, opts.Targets, c)
-	if err != nil {
-		return err
+	templateName := opts.Template
+	if templateName == "" {
+		templateName = BashTemplate
 	}
-	return createBashBuildpack(opts.Path, c)
-}
-
-func createBashBuildpack(path string, c *Client) error {
-	if err := createBinScript(path, "build", bashBinBuild, c); err != nil {
+	files, err := c.templateFiles(ctx, templateName, buildpackTemplates)
+	if err != nil {
 		return err
 	}
 
-	if err := createBinScript(path, "detect", bashBinDetect, c); err != nil {
+	// templates may provide their own buildpack.toml, so it is only generated when they don't
+	idParts := strings.Split(opts.ID, "/")
+	err = createTemplateFiles(opts.Path, files, templateData{
+		ID:      opts.ID,
+		Name:    idParts[len(idParts)-1],
+		Version: opts.Version,
+		API:     opts.API,
+		Targets: opts.Targets,
+	}, c)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return createBuildpackTOML(opts.Path, opts.ID, opts.Version, opts.API, opts.Stacks, opts.Targets, c)
 }
 
 func createBinScript(path, name, contents string, c *Client) error {
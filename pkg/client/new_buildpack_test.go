@@ -7,6 +7,8 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/heroku/color"
 	"github.com/pelletier/go-toml"
 	"github.com/sclevine/spec"
@@ -43,6 +45,12 @@ func testNewBuildpack(t *testing.T, when spec.G, it spec.S) {
 		h.AssertNil(t, os.RemoveAll(tmpDir))
 	})
 
+	readFile := func(path string) string {
+		contents, err := os.ReadFile(path)
+		h.AssertNil(t, err)
+		return string(contents)
+	}
+
 	when("#NewBuildpack", func() {
 		it("should create bash scripts", func() {
 			err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
@@ -74,6 +82,159 @@ func testNewBuildpack(t *testing.T, when spec.G, it spec.S) {
 			assertBuildpackToml(t, tmpDir, "example/my-cnb")
 		})
 
+		when("a built-in template is given", func() {
+			it("generates the go template", func() {
+				err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+					API:      "0.10",
+					Path:     tmpDir,
+					ID:       "example/my-cnb",
+					Version:  "0.0.0",
+					Template: client.GoTemplate,
+				})
+				h.AssertNil(t, err)
+
+				for _, file := range []string{"go.mod", "cmd/main/main.go", "buildpack/detect.go", "buildpack/build.go", "scripts/build.sh"} {
+					h.AssertPathExists(t, filepath.Join(tmpDir, file))
+				}
+				h.AssertContains(t, readFile(filepath.Join(tmpDir, "go.mod")), "module example/my-cnb")
+				h.AssertContains(t, readFile(filepath.Join(tmpDir, "cmd", "main", "main.go")), `"example/my-cnb/buildpack"`)
+				assertBuildpackToml(t, tmpDir, "example/my-cnb")
+			})
+
+			it("generates the package template with its targets", func() {
+				err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+					API:     "0.10",
+					Path:    tmpDir,
+					ID:      "example/my-cnb",
+					Version: "0.0.0",
+					Targets: []dist.Target{
+						{OS: "linux", Arch: "amd64"},
+						{OS: "linux", Arch: "arm", ArchVariant: "v7", Distributions: []dist.Distribution{{Name: "ubuntu", Version: "24.04"}}},
+					},
+					Template: client.PackageTemplate,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, readFile(filepath.Join(tmpDir, "package.toml")), `[buildpack]
+uri = "."
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+
+[[targets]]
+os = "linux"
+arch = "arm"
+variant = "v7"
+
+[[targets.distros]]
+name = "ubuntu"
+version = "24.04"
+`)
+				h.AssertPathExists(t, filepath.Join(tmpDir, "bin", "build"))
+			})
+		})
+
+		when("a template directory is given", func() {
+			var templateDir string
+
+			it.Before(func() {
+				templateDir = t.TempDir()
+				h.AssertNil(t, os.MkdirAll(filepath.Join(templateDir, "bin"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(templateDir, "bin", "build"), []byte("echo {{ .ID }}"), 0755))
+				h.AssertNil(t, os.WriteFile(filepath.Join(templateDir, "README.md.tmpl"), []byte(`# {{ .Name }} {{ .Version }}
+API {{ .API }}{{ range .Targets }}, {{ .OS }}/{{ .Arch }}{{ end }}`), 0644))
+			})
+
+			it("renders the '.tmpl' files and copies the others", func() {
+				err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+					API:      "0.10",
+					Path:     tmpDir,
+					ID:       "example/my-cnb",
+					Version:  "1.2.3",
+					Targets:  []dist.Target{{OS: "linux", Arch: "amd64"}},
+					Template: templateDir,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertEq(t, readFile(filepath.Join(tmpDir, "README.md")), "# my-cnb 1.2.3\nAPI 0.10, linux/amd64")
+				h.AssertEq(t, readFile(filepath.Join(tmpDir, "bin", "build")), "echo {{ .ID }}")
+				h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, "README.md.tmpl"))
+				assertBuildpackToml(t, tmpDir, "example/my-cnb")
+			})
+
+			it("uses the buildpack.toml of the template", func() {
+				h.AssertNil(t, os.WriteFile(filepath.Join(templateDir, "buildpack.toml.tmpl"), []byte(`api = "{{ .API }}"
+
+[buildpack]
+id = "{{ .ID }}"
+version = "{{ .Version }}"
+name = "Templated"
+`), 0644))
+
+				err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+					API:      "0.10",
+					Path:     tmpDir,
+					ID:       "example/my-cnb",
+					Version:  "1.2.3",
+					Template: templateDir,
+				})
+				h.AssertNil(t, err)
+
+				h.AssertContains(t, readFile(filepath.Join(tmpDir, "buildpack.toml")), `name = "Templated"`)
+			})
+
+			it("errors when a template file is invalid", func() {
+				h.AssertNil(t, os.WriteFile(filepath.Join(templateDir, "bad.tmpl"), []byte("{{ .Unknown }}"), 0644))
+
+				err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+					API:      "0.10",
+					Path:     tmpDir,
+					ID:       "example/my-cnb",
+					Version:  "1.2.3",
+					Template: templateDir,
+				})
+				h.AssertError(t, err, "rendering template file 'bad'")
+			})
+
+			when("the template directory is in a git repository", func() {
+				it("clones it", func() {
+					repository, err := git.PlainInit(templateDir, false)
+					h.AssertNil(t, err)
+					worktree, err := repository.Worktree()
+					h.AssertNil(t, err)
+					h.AssertNil(t, worktree.AddGlob("."))
+					_, err = worktree.Commit("template", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com"}})
+					h.AssertNil(t, err)
+
+					err = subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+						API:      "0.10",
+						Path:     tmpDir,
+						ID:       "example/my-cnb",
+						Version:  "1.2.3",
+						Template: "file://" + filepath.ToSlash(templateDir),
+					})
+					h.AssertNil(t, err)
+
+					h.AssertContains(t, readFile(filepath.Join(tmpDir, "README.md")), "# my-cnb 1.2.3")
+					h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, ".git"))
+				})
+			})
+		})
+
+		when("the template is unknown", func() {
+			it("errors", func() {
+				err := subject.NewBuildpack(context.TODO(), client.NewBuildpackOptions{
+					API:      "0.10",
+					Path:     tmpDir,
+					ID:       "example/my-cnb",
+					Version:  "1.2.3",
+					Template: "unknown",
+				})
+				h.AssertError(t, err, "template 'unknown' is neither one of bash, exec.d, go, package, a directory nor a git repository URL")
+			})
+		})
+
 		when("files exist", func() {
 			it.Before(func() {
 				var err error
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle/api"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+// DockerfilesTemplate is the name of the built-in extension template providing static Dockerfiles
+const DockerfilesTemplate = "dockerfiles"
+
+var (
+	extensionBinGenerate = `#!/usr/bin/env bash
+
+set -euo pipefail
+
+# The Dockerfiles written to the output directory extend the build and run images
+output_dir="${CNB_OUTPUT_DIR:-$1}"
+
+cat > "${output_dir}/build.Dockerfile" <<'EOF'
+` + extensionBuildDockerfile + `EOF
+
+cat > "${output_dir}/run.Dockerfile" <<'EOF'
+` + extensionRunDockerfile + `EOF
+`
+	extensionBuildDockerfile = `ARG base_image
+FROM ${base_image}
+
+USER root
+RUN echo "Extended by {{ .ID }}" > /extended-build.txt
+
+ARG user_id
+USER ${user_id}
+`
+	extensionRunDockerfile = `ARG base_image
+FROM ${base_image}
+
+USER root
+RUN echo "Extended by {{ .ID }}" > /extended-run.txt
+
+ARG user_id
+USER ${user_id}
+`
+)
+
+var extensionTemplates = map[string][]templateFile{
+	BashTemplate: {
+		{path: "bin/detect", contents: bashBinDetect, mode: 0755},
+		{path: "bin/generate", contents: extensionBinGenerate, mode: 0755},
+	},
+	DockerfilesTemplate: {
+		{path: "bin/detect", contents: bashBinDetect, mode: 0755},
+		{path: "generate/build.Dockerfile", contents: extensionBuildDockerfile, mode: 0644},
+		{path: "generate/run.Dockerfile", contents: extensionRunDockerfile, mode: 0644},
+	},
+}
+
+// ExtensionTemplates returns the names of the built-in extension templates
+func ExtensionTemplates() []string {
+	return templateNames(extensionTemplates)
+}
+
+type NewExtensionOptions struct {
+	// api compat version of the output extension artifact.
+	API string
+
+	// The base directory to generate assets
+	Path string
+
+	// The ID of the output extension artifact.
+	ID string
+
+	// version of the output extension artifact.
+	Version string
+
+	// the targets this extension will work with
+	Targets []dist.Target
+
+	// The template to generate the extension from: the name of a built-in template (see ExtensionTemplates),
+	// a local template directory or the URL of a git repository holding one. Defaults to the bash template,
+	// whose bin/generate writes a build.Dockerfile and a run.Dockerfile.
+	Template string
+}
+
+func (c *Client) NewExtension(ctx context.Context, opts NewExtensionOptions) error {
+	templateName := opts.Template
+	if templateName == "" {
+		templateName = BashTemplate
+	}
+	files, err := c.templateFiles(ctx, templateName, extensionTemplates)
+	if err != nil {
+		return err
+	}
+
+	// templates may provide their own extension.toml, so it is only generated when they don't
+	idParts := strings.Split(opts.ID, "/")
+	err = createTemplateFiles(opts.Path, files, templateData{
+		ID:      opts.ID,
+		Name:    idParts[len(idParts)-1],
+		Version: opts.Version,
+		API:     opts.API,
+		Targets: opts.Targets,
+	}, c)
+	if err != nil {
+		return err
+	}
+
+	return createExtensionTOML(opts.Path, opts.ID, opts.Version, opts.API, opts.Targets, c)
+}
+
+func createExtensionTOML(path, id, version, apiStr string, targets []dist.Target, c *Client) error {
+	api, err := api.NewVersion(apiStr)
+	if err != nil {
+		return err
+	}
+
+	extensionTOML := dist.ExtensionDescriptor{
+		WithAPI:     api,
+		WithTargets: targets,
+		WithInfo: dist.ModuleInfo{
+			ID:      id,
+			Version: version,
+		},
+	}
+
+	// The following line's comment is for gosec, it will ignore rule 301 in this case
+	// G301: Expect directory permissions to be 0750 or less
+	/* #nosec G301 */
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+
+	extensionTOMLPath := filepath.Join(path, "extension.toml")
+	if _, err := os.Stat(extensionTOMLPath); !os.IsNotExist(err) {
+		return nil
+	}
+
+	f, err := os.Create(extensionTOMLPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := toml.NewEncoder(f).Encode(extensionTOML); err != nil {
+		return err
+	}
+	if c != nil {
+		c.logger.Infof("    %s  extension.toml", style.Symbol("create"))
+	}
+	return nil
+}
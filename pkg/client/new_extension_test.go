@@ -0,0 +1,124 @@
+package client_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/dist"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestNewExtension(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "NewExtension", testNewExtension, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testNewExtension(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *client.Client
+		tmpDir  string
+	)
+
+	it.Before(func() {
+		var err error
+		tmpDir = t.TempDir()
+		subject, err = client.NewClient()
+		h.AssertNil(t, err)
+	})
+
+	readFile := func(path string) string {
+		contents, err := os.ReadFile(path)
+		h.AssertNil(t, err)
+		return string(contents)
+	}
+
+	when("#NewExtension", func() {
+		it("creates a generate script writing Dockerfiles", func() {
+			err := subject.NewExtension(context.TODO(), client.NewExtensionOptions{
+				API:     "0.10",
+				Path:    tmpDir,
+				ID:      "example/my-ext",
+				Version: "0.0.1",
+				Targets: []dist.Target{{OS: "linux", Arch: "amd64"}},
+			})
+			h.AssertNil(t, err)
+
+			for _, script := range []string{"detect", "generate"} {
+				info, err := os.Stat(filepath.Join(tmpDir, "bin", script))
+				h.AssertNil(t, err)
+				if runtime.GOOS != "windows" {
+					h.AssertTrue(t, info.Mode()&0100 != 0)
+				}
+			}
+			generate := readFile(filepath.Join(tmpDir, "bin", "generate"))
+			h.AssertContains(t, generate, `cat > "${output_dir}/build.Dockerfile" <<'EOF'`)
+			h.AssertContains(t, generate, `cat > "${output_dir}/run.Dockerfile" <<'EOF'`)
+			h.AssertContains(t, generate, `RUN echo "Extended by example/my-ext" > /extended-run.txt`)
+
+			var descriptor dist.ExtensionDescriptor
+			_, err = toml.DecodeFile(filepath.Join(tmpDir, "extension.toml"), &descriptor)
+			h.AssertNil(t, err)
+			h.AssertEq(t, descriptor.API().String(), "0.10")
+			h.AssertEq(t, descriptor.Info().ID, "example/my-ext")
+			h.AssertEq(t, descriptor.Info().Version, "0.0.1")
+			h.AssertEq(t, descriptor.Targets(), []dist.Target{{OS: "linux", Arch: "amd64"}})
+		})
+
+		it("creates static Dockerfiles with the dockerfiles template", func() {
+			err := subject.NewExtension(context.TODO(), client.NewExtensionOptions{
+				API:      "0.10",
+				Path:     tmpDir,
+				ID:       "example/my-ext",
+				Version:  "0.0.1",
+				Template: client.DockerfilesTemplate,
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, readFile(filepath.Join(tmpDir, "generate", "build.Dockerfile")), `ARG base_image
+FROM ${base_image}
+
+USER root
+RUN echo "Extended by example/my-ext" > /extended-build.txt
+
+ARG user_id
+USER ${user_id}
+`)
+			h.AssertPathExists(t, filepath.Join(tmpDir, "generate", "run.Dockerfile"))
+			h.AssertPathDoesNotExists(t, filepath.Join(tmpDir, "bin", "generate"))
+		})
+
+		it("doesn't clobber files that exist", func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(tmpDir, "extension.toml"), []byte("expected value"), 0644))
+
+			err := subject.NewExtension(context.TODO(), client.NewExtensionOptions{
+				API:     "0.10",
+				Path:    tmpDir,
+				ID:      "example/my-ext",
+				Version: "0.0.1",
+			})
+			h.AssertNil(t, err)
+			h.AssertEq(t, readFile(filepath.Join(tmpDir, "extension.toml")), "expected value")
+		})
+
+		it("errors for unknown templates", func() {
+			err := subject.NewExtension(context.TODO(), client.NewExtensionOptions{
+				API:      "0.10",
+				Path:     tmpDir,
+				ID:       "example/my-ext",
+				Version:  "0.0.1",
+				Template: "go",
+			})
+			h.AssertError(t, err, "template 'go' is neither one of bash, dockerfiles, a directory nor a git repository URL")
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/imgutil/fakes"
+	"github.com/buildpacks/imgutil/remote"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	cfg "github.com/buildpacks/pack/internal/config"
+	ifakes "github.com/buildpacks/pack/internal/fakes"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestOCIRegistry(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "OCIRegistry", testOCIRegistry, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testOCIRegistry(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *client.Client
+		mockController *gomock.Controller
+		server         *httptest.Server
+		indexRef       string
+		out            bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+		indexRef = strings.TrimPrefix(server.URL, "http://") + "/buildpacks/index:latest"
+
+		packHome := filepath.Join(t.TempDir(), "packHome")
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+		h.AssertNil(t, cfg.Write(cfg.Config{
+			Registries: []cfg.Registry{{Name: "internal", Type: "oci", URL: indexRef}},
+		}, filepath.Join(packHome, "config.toml")))
+
+		digest, err := name.NewDigest("example.com/foo@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566")
+		h.AssertNil(t, err)
+		buildpackImage := fakes.NewImage("example/foo-buildpack", "", remote.DigestIdentifier{Digest: digest})
+		h.AssertNil(t, buildpackImage.SetLabel("io.buildpacks.buildpackage.metadata", `{"id":"example/foo","version":"1.0.0"}`))
+		imageFetcher := ifakes.NewFakeImageFetcher()
+		imageFetcher.RemoteImages["example/foo-buildpack"] = buildpackImage
+
+		subject, err = client.NewClient(
+			client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+			client.WithDockerClient(testmocks.NewMockAPIClient(mockController)),
+			client.WithFetcher(imageFetcher),
+		)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		os.Unsetenv("PACK_HOME")
+		server.Close()
+		mockController.Finish()
+	})
+
+	when("the registry is of type oci", func() {
+		it.Before(func() {
+			h.AssertNil(t, subject.RegisterBuildpack(context.TODO(), client.RegisterBuildpackOptions{
+				ImageName: "example/foo-buildpack",
+				Type:      "oci",
+				URL:       indexRef,
+				Name:      "internal",
+			}))
+		})
+
+		it("registers buildpacks directly in the index", func() {
+			versions, err := subject.ListBuildpackVersions(client.ListBuildpackVersionsOptions{ID: "example/foo", Registry: "internal"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, versions, []client.RegistryBuildpack{{
+				ID:      "example/foo",
+				Version: "1.0.0",
+				Address: "example.com/foo@sha256:8c27fe111c11b722081701dfed3bd55e039b9ce92865473cf4cdfa918071c566",
+			}})
+		})
+
+		it("yanks buildpacks directly in the index", func() {
+			h.AssertNil(t, subject.YankBuildpack(client.YankBuildpackOptions{
+				ID:      "example/foo",
+				Version: "1.0.0",
+				Type:    "oci",
+				URL:     indexRef,
+				Yank:    true,
+			}))
+
+			versions, err := subject.ListBuildpackVersions(client.ListBuildpackVersionsOptions{ID: "example/foo", Registry: "internal", IncludeYanked: true})
+			h.AssertNil(t, err)
+			h.AssertEq(t, versions[0].Yanked, true)
+		})
+	})
+}
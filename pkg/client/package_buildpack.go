@@ -18,6 +18,7 @@ import (
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/lock"
 )
 
 const (
@@ -75,6 +76,13 @@ type PackageBuildpackOptions struct {
 
 	// Additional image tags to push to, each will contain contents identical to Image
 	AdditionalTags []string
+
+	// Lockfile pinning the buildpack or extension and the dependencies of the config
+	Lock lock.File
+
+	// PEM encoded private key to sign the package with. Images are signed in their registry, and require Publish
+	// to be true; files get a detached signature written next to them.
+	SignKey []byte
 }
 
 // PackageBuildpack packages buildpack(s) into either an image or file.
@@ -83,6 +91,10 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 		opts.Format = FormatImage
 	}
 
+	if err := validateSignKey(opts.SignKey, opts.Publish, opts.Format); err != nil {
+		return err
+	}
+
 	targets, err := c.processPackageBuildpackTargets(ctx, opts)
 	if err != nil {
 		return err
@@ -101,11 +113,17 @@ func (c *Client) PackageBuildpack(ctx context.Context, opts PackageBuildpackOpti
 
 	if opts.Publish && len(digests) > 1 {
 		// Image Index must be created only when we pushed to registry
-		return c.CreateManifest(ctx, CreateManifestOptions{
+		digest, err := c.createManifest(ctx, CreateManifestOptions{
 			IndexRepoName: opts.Name,
 			RepoNames:     digests,
 			Publish:       true,
 		})
+		if err != nil {
+			return err
+		}
+		if len(opts.SignKey) > 0 {
+			return c.signImage(ctx, digest, opts.SignKey)
+		}
 	}
 
 	return nil
@@ -142,6 +160,10 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 	if ok, platformRootFolder := buildpack.PlatformRootFolder(bpURI, target); ok {
 		bpURI = platformRootFolder
 	}
+	bpURI, err = pinURI(bpURI, opts.Config.Buildpack.SHA256, opts.Lock)
+	if err != nil {
+		return digest, errors.Wrap(err, "pinning buildpack")
+	}
 
 	mainBlob, err := c.downloadBuildpackFromURI(ctx, bpURI, opts.RelativeBaseDir)
 	if err != nil {
@@ -158,8 +180,13 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 	platform := target.ValuesAsPlatform()
 
 	for _, dep := range opts.Config.Dependencies {
+		depURI, err := pinURI(dep.URI, dep.SHA256, opts.Lock)
+		if err != nil {
+			return digest, errors.Wrap(err, "pinning dependency")
+		}
+
 		if multiArch {
-			locatorType, err := buildpack.GetLocatorType(dep.URI, opts.RelativeBaseDir, []dist.ModuleInfo{})
+			locatorType, err := buildpack.GetLocatorType(depURI, opts.RelativeBaseDir, []dist.ModuleInfo{})
 			if err != nil {
 				return digest, err
 			}
@@ -170,7 +197,7 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 		}
 
 		c.logger.Debugf("Downloading buildpack dependency for platform %s", platform)
-		mainBP, deps, err := c.buildpackDownloader.Download(ctx, dep.URI, buildpack.DownloadOptions{
+		mainBP, deps, err := c.buildpackDownloader.Download(ctx, depURI, buildpack.DownloadOptions{
 			RegistryName:    opts.Registry,
 			RelativeBaseDir: opts.RelativeBaseDir,
 			ImageName:       dep.ImageName,
@@ -201,6 +228,11 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 		if err != nil {
 			return digest, err
 		}
+		if len(opts.SignKey) > 0 {
+			if err := c.signFile(name, opts.SignKey); err != nil {
+				return digest, err
+			}
+		}
 	case FormatImage:
 		packageName := opts.Name
 		if multiArch && opts.AppendImageNameSuffix {
@@ -213,13 +245,20 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 		if err != nil {
 			return digest, errors.Wrapf(err, "saving image")
 		}
-		if multiArch {
-			// We need to keep the identifier to create the image index
+		if multiArch || len(opts.SignKey) > 0 {
+			// We need to keep the identifier to create the image index, and to sign the image
 			id, err := img.Identifier()
 			if err != nil {
 				return digest, errors.Wrapf(err, "determining image manifest digest")
 			}
-			digest = id.String()
+			if len(opts.SignKey) > 0 {
+				if err := c.signImage(ctx, id.String(), opts.SignKey); err != nil {
+					return digest, err
+				}
+			}
+			if multiArch {
+				digest = id.String()
+			}
 		}
 	default:
 		return digest, errors.Errorf("unknown format: %s", style.Symbol(opts.Format))
@@ -228,11 +267,15 @@ func (c *Client) packageBuildpackTarget(ctx context.Context, opts PackageBuildpa
 }
 
 func (c *Client) downloadBuildpackFromURI(ctx context.Context, uri, relativeBaseDir string) (blob.Blob, error) {
+	uri, checksum := blob.SplitSHA256(uri)
 	absPath, err := paths.FilePathToURI(uri, relativeBaseDir)
 	if err != nil {
 		return nil, errors.Wrapf(err, "making absolute: %s", style.Symbol(uri))
 	}
-	uri = absPath
+	uri, err = blob.WithSHA256(absPath, checksum)
+	if err != nil {
+		return nil, err
+	}
 
 	c.logger.Debugf("Downloading buildpack from URI: %s", style.Symbol(uri))
 	blob, err := c.downloader.Download(ctx, uri)
@@ -3,6 +3,9 @@ package client_test
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -31,6 +34,7 @@ import (
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/signature"
 	"github.com/buildpacks/pack/pkg/testmocks"
 	h "github.com/buildpacks/pack/testhelpers"
 )
@@ -171,6 +175,32 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("a sign key is given", func() {
+		it("fails for images that aren't published", func() {
+			privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			h.AssertNil(t, err)
+			signKey, err := os.ReadFile(h.WritePrivateKey(t, t.TempDir(), privateKey))
+			h.AssertNil(t, err)
+
+			err = subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+				Name:    "some/package",
+				Format:  client.FormatImage,
+				SignKey: signKey,
+			})
+			h.AssertError(t, err, "images can only be signed when they are published")
+		})
+
+		it("fails for invalid keys", func() {
+			err := subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+				Name:    "some/package",
+				Format:  client.FormatImage,
+				Publish: true,
+				SignKey: []byte("some-key"),
+			})
+			h.AssertError(t, err, "invalid sign key")
+		})
+	})
+
 	when("FormatImage", func() {
 		when("simple package for both OS formats (experimental only)", func() {
 			it("creates package image based on daemon OS", func() {
@@ -1336,6 +1366,32 @@ func testPackageBuildpack(t *testing.T, when spec.G, it spec.S) {
 					assertPackageBPFileHasBuildpacks(t, packagePath, []dist.BuildpackDescriptor{packageDescriptor, childDescriptor})
 				})
 
+				it("writes a detached signature when a sign key is given", func() {
+					packagePath := filepath.Join(tmpDir, "test.cnb")
+					privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+					h.AssertNil(t, err)
+					signKey, err := os.ReadFile(h.WritePrivateKey(t, tmpDir, privateKey))
+					h.AssertNil(t, err)
+
+					h.AssertNil(t, subject.PackageBuildpack(context.TODO(), client.PackageBuildpackOptions{
+						Name: packagePath,
+						Config: pubbldpkg.Config{
+							Platform:     dist.Platform{OS: "linux"},
+							Buildpack:    dist.BuildpackURI{URI: createBuildpack(packageDescriptor)},
+							Dependencies: []dist.ImageOrURI{{BuildpackURI: dist.BuildpackURI{URI: createBuildpack(childDescriptor)}}},
+						},
+						PullPolicy: image.PullAlways,
+						Format:     client.FormatFile,
+						SignKey:    signKey,
+					}))
+
+					data, err := os.ReadFile(packagePath)
+					h.AssertNil(t, err)
+					sig, err := os.ReadFile(packagePath + ".sig")
+					h.AssertNil(t, err)
+					h.AssertNil(t, signature.VerifyBlob(&privateKey.PublicKey, data, string(sig)))
+				})
+
 				when("dependency download fails", func() {
 					it("should error", func() {
 						bpURL := fmt.Sprintf("https://example.com/bp.%s.tgz", h.RandString(12))
@@ -19,6 +19,10 @@ func (c *Client) PackageExtension(ctx context.Context, opts PackageBuildpackOpti
 		opts.Format = FormatImage
 	}
 
+	if err := validateSignKey(opts.SignKey, opts.Publish, opts.Format); err != nil {
+		return err
+	}
+
 	targets, err := c.processPackageBuildpackTargets(ctx, opts)
 	if err != nil {
 		return err
@@ -37,11 +41,17 @@ func (c *Client) PackageExtension(ctx context.Context, opts PackageBuildpackOpti
 
 	if opts.Publish && len(digests) > 1 {
 		// Image Index must be created only when we pushed to registry
-		return c.CreateManifest(ctx, CreateManifestOptions{
+		digest, err := c.createManifest(ctx, CreateManifestOptions{
 			IndexRepoName: opts.Name,
 			RepoNames:     digests,
 			Publish:       true,
 		})
+		if err != nil {
+			return err
+		}
+		if len(opts.SignKey) > 0 {
+			return c.signImage(ctx, digest, opts.SignKey)
+		}
 	}
 
 	return nil
@@ -73,6 +83,10 @@ func (c *Client) packageExtensionTarget(ctx context.Context, opts PackageBuildpa
 	if ok, platformRootFolder := buildpack.PlatformRootFolder(exURI, target); ok {
 		exURI = platformRootFolder
 	}
+	exURI, err = pinURI(exURI, opts.Config.Extension.SHA256, opts.Lock)
+	if err != nil {
+		return digest, errors.Wrap(err, "pinning extension")
+	}
 
 	mainBlob, err := c.downloadBuildpackFromURI(ctx, exURI, opts.RelativeBaseDir)
 	if err != nil {
@@ -83,6 +97,9 @@ func (c *Client) packageExtensionTarget(ctx context.Context, opts PackageBuildpa
 	if err != nil {
 		return digest, errors.Wrapf(err, "creating extension from %s", style.Symbol(exURI))
 	}
+	if err := buildpack.ValidateExtension(ex, mainBlob, target, c.logger); err != nil {
+		return digest, err
+	}
 
 	packageBuilder.SetExtension(ex)
 
@@ -102,18 +119,30 @@ func (c *Client) packageExtensionTarget(ctx context.Context, opts PackageBuildpa
 		if err != nil {
 			return digest, err
 		}
+		if len(opts.SignKey) > 0 {
+			if err := c.signFile(name, opts.SignKey); err != nil {
+				return digest, err
+			}
+		}
 	case FormatImage:
 		img, err := packageBuilder.SaveAsImage(opts.Name, opts.Publish, target, opts.Labels, opts.AdditionalTags...)
 		if err != nil {
 			return digest, errors.Wrapf(err, "saving image")
 		}
-		if multiArch {
-			// We need to keep the identifier to create the image index
+		if multiArch || len(opts.SignKey) > 0 {
+			// We need to keep the identifier to create the image index, and to sign the image
 			id, err := img.Identifier()
 			if err != nil {
 				return digest, errors.Wrapf(err, "determining image manifest digest")
 			}
-			digest = id.String()
+			if len(opts.SignKey) > 0 {
+				if err := c.signImage(ctx, id.String(), opts.SignKey); err != nil {
+					return digest, err
+				}
+			}
+			if multiArch {
+				digest = id.String()
+			}
 		}
 	default:
 		return digest, errors.Errorf("unknown format: %s", style.Symbol(opts.Format))
@@ -124,6 +124,92 @@ func testPackageExtension(t *testing.T, when spec.G, it spec.S) {
 				h.AssertError(t, err, "creating extension")
 			})
 		})
+
+		when("extension can't be packaged", func() {
+			// extensionDir serves an extension directory with the given extension.toml and files
+			extensionDir := func(extensionTOML string, files ...string) string {
+				dir := t.TempDir()
+				h.AssertNil(t, os.WriteFile(filepath.Join(dir, "extension.toml"), []byte(extensionTOML), 0644))
+				for _, file := range files {
+					h.AssertNil(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0755))
+					h.AssertNil(t, os.WriteFile(filepath.Join(dir, file), []byte("contents"), 0755))
+				}
+				url := fmt.Sprintf("https://example.com/ex.%s.tgz", h.RandString(12))
+				mockDownloader.EXPECT().Download(gomock.Any(), url).Return(blob.NewBlob(dir), nil).AnyTimes()
+				return url
+			}
+
+			packageExtension := func(url string, target dist.Target) error {
+				return subject.PackageExtension(context.TODO(), client.PackageBuildpackOptions{
+					Name:    "some/extension",
+					Format:  client.FormatFile,
+					Config:  pubbldpkg.Config{Extension: dist.BuildpackURI{URI: url}},
+					Targets: []dist.Target{target},
+				})
+			}
+
+			it("errors when the Buildpack API doesn't support extensions", func() {
+				url := extensionDir(`api = "0.8"
+[extension]
+id = "some/ext"
+version = "1.0.0"
+`, "bin/generate")
+
+				h.AssertError(t, packageExtension(url, dist.Target{OS: "linux", Arch: "amd64"}),
+					"extension 'some/ext@1.0.0' declares Buildpack API 0.8, but extensions require Buildpack API 0.9 or later: update 'api' in extension.toml")
+			})
+
+			it("errors when the Buildpack API isn't supported", func() {
+				url := extensionDir(`api = "0.99"
+[extension]
+id = "some/ext"
+version = "1.0.0"
+`, "bin/generate")
+
+				h.AssertError(t, packageExtension(url, dist.Target{OS: "linux", Arch: "amd64"}),
+					"extension 'some/ext@1.0.0' declares Buildpack API 0.99, which isn't one of the supported APIs")
+			})
+
+			it("errors when the extension provides no Dockerfiles", func() {
+				url := extensionDir(`api = "0.10"
+[extension]
+id = "some/ext"
+version = "1.0.0"
+`, "bin/detect")
+
+				h.AssertError(t, packageExtension(url, dist.Target{OS: "linux", Arch: "amd64"}),
+					"extension 'some/ext@1.0.0' provides no Dockerfiles: add an executable 'bin/generate'")
+			})
+
+			it("errors when the package target isn't declared", func() {
+				url := extensionDir(`api = "0.10"
+[extension]
+id = "some/ext"
+version = "1.0.0"
+
+[[targets]]
+os = "linux"
+arch = "amd64"
+`, "generate/run.Dockerfile")
+
+				h.AssertError(t, packageExtension(url, dist.Target{OS: "linux", Arch: "arm64"}),
+					"extension 'some/ext@1.0.0' doesn't declare target 'linux/arm64', it declares linux/amd64")
+			})
+
+			it("errors when a declared target has no os", func() {
+				url := extensionDir(`api = "0.10"
+[extension]
+id = "some/ext"
+version = "1.0.0"
+
+[[targets]]
+arch = "amd64"
+`, "generate/build.Dockerfile")
+
+				h.AssertError(t, packageExtension(url, dist.Target{OS: "linux", Arch: "amd64"}),
+					"target #1 of extension 'some/ext@1.0.0' has no 'os': set it in extension.toml")
+			})
+		})
 	})
 
 	when("FormatImage", func() {
@@ -154,7 +240,7 @@ func testPackageExtension(t *testing.T, when spec.G, it spec.S) {
 						Config: pubbldpkg.Config{
 							Platform: dist.Platform{OS: daemonOS},
 							Extension: dist.BuildpackURI{URI: createExtension(dist.ExtensionDescriptor{
-								WithAPI:  api.MustParse("0.2"),
+								WithAPI:  api.MustParse("0.10"),
 								WithInfo: dist.ModuleInfo{ID: "ex.basic", Version: "2.3.4"},
 							})},
 						},
@@ -235,7 +321,7 @@ func testPackageExtension(t *testing.T, when spec.G, it spec.S) {
 						Config: pubbldpkg.Config{
 							Platform: dist.Platform{OS: imageOS},
 							Extension: dist.BuildpackURI{URI: createExtension(dist.ExtensionDescriptor{
-								WithAPI:  api.MustParse("0.2"),
+								WithAPI:  api.MustParse("0.10"),
 								WithInfo: dist.ModuleInfo{ID: "ex.basic", Version: "2.3.4"},
 							})},
 						},
@@ -256,7 +342,7 @@ func testPackageExtension(t *testing.T, when spec.G, it spec.S) {
 				Config: pubbldpkg.Config{
 					Platform: dist.Platform{OS: "linux"},
 					Extension: dist.BuildpackURI{URI: createExtension(dist.ExtensionDescriptor{
-						WithAPI:  api.MustParse("0.2"),
+						WithAPI:  api.MustParse("0.10"),
 						WithInfo: dist.ModuleInfo{ID: "ex.1", Version: "1.2.3"},
 					})},
 				},
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	pubbldr "github.com/buildpacks/pack/builder"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/policy"
+)
+
+// buildPolicyInputs returns the inputs of a build the policy is evaluated against before fetching anything: the
+// builder, the run image and the volumes. Buildpacks and extensions are evaluated as they're fetched, by
+// enforceModulePolicy, once their locators are resolved to the images or URLs they're downloaded from.
+func buildPolicyInputs(opts BuildOptions, builderName, runImageName string) policy.Inputs {
+	return policy.Inputs{
+		Builder:   builderName,
+		RunImages: []string{runImageName},
+		Volumes:   opts.ContainerConfig.Volumes,
+	}
+}
+
+// enforceModulePolicy enforces the policy on a buildpack or extension about to be downloaded. Registry locators are
+// resolved to the package image they refer to, which is returned as the locator to download, so the image evaluated
+// is the image downloaded.
+func (c *Client) enforceModulePolicy(p *policy.Policy, registryName, locator, relativeBaseDir string, builderModules []dist.ModuleInfo) (string, error) {
+	if p == nil {
+		return locator, nil
+	}
+
+	locatorType, err := buildpack.GetLocatorType(locator, relativeBaseDir, builderModules)
+	if err != nil {
+		// invalid locators fail when the module is downloaded
+		return locator, nil
+	}
+	if locatorType == buildpack.RegistryLocator {
+		resolver := &registryResolver{logger: c.logger, keychain: c.keychain, mirror: c.mirror}
+		address, err := resolver.Resolve(registryName, locator)
+		if err != nil {
+			return "", errors.Wrapf(err, "locating in registry: %s", style.Symbol(locator))
+		}
+		locator = "docker://" + address
+	}
+
+	var inputs policy.Inputs
+	addModulePolicyInput(&inputs, locator, relativeBaseDir, builderModules)
+	return locator, p.Enforce(c.logger, inputs)
+}
+
+// builderPolicyInputs returns the inputs of a builder creation the policy is evaluated against: the run images of
+// the builder config, and its buildpacks and extensions as the lockfile pins them. Buildpacks and extensions from a
+// registry are evaluated by enforceModulePolicy when they're downloaded, once resolved to their package images.
+func builderPolicyInputs(opts CreateBuilderOptions) policy.Inputs {
+	var inputs policy.Inputs
+	for _, runImage := range opts.Config.Run.Images {
+		inputs.RunImages = append(inputs.RunImages, runImage.Image)
+		inputs.RunImages = append(inputs.RunImages, runImage.Mirrors...)
+	}
+
+	var modules []pubbldr.ModuleConfig
+	modules = append(modules, opts.Config.Buildpacks...)
+	modules = append(modules, opts.Config.Extensions...)
+	for _, module := range modules {
+		if module.ImageName != "" {
+			inputs.BuildpackImages = append(inputs.BuildpackImages, module.ImageName)
+		}
+		if module.URI != "" {
+			locator, err := opts.Lock.Pin(module.URI)
+			if err != nil {
+				locator = module.URI
+			}
+			addModulePolicyInput(&inputs, locator, opts.RelativeBaseDir, nil)
+		}
+	}
+	return inputs
+}
+
+// addModulePolicyInput adds a buildpack or extension to the inputs when it's a package image or an http(s) URL.
+// Invalid locators are skipped: they fail when the module is fetched.
+func addModulePolicyInput(inputs *policy.Inputs, locator, relativeBaseDir string, builderModules []dist.ModuleInfo) {
+	if locator == "" {
+		return
+	}
+	locatorType, err := buildpack.GetLocatorType(locator, relativeBaseDir, builderModules)
+	if err != nil {
+		return
+	}
+
+	locator, _ = blob.SplitSHA256(locator)
+	switch locatorType {
+	case buildpack.PackageLocator:
+		inputs.BuildpackImages = append(inputs.BuildpackImages, buildpack.ParsePackageLocator(locator))
+	case buildpack.URILocator:
+		if strings.HasPrefix(locator, "http://") || strings.HasPrefix(locator, "https://") {
+			inputs.BuildpackURLs = append(inputs.BuildpackURLs, locator)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/buildpacks/imgutil/local"
+	"github.com/buildpacks/lifecycle/buildpack"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+)
+
+const (
+	// ProvenanceFileName is the name of the provenance statement written to the provenance output directory
+	ProvenanceFileName = "provenance.json"
+
+	InTotoStatementType         = "https://in-toto.io/Statement/v1"
+	SLSAProvenancePredicateType = "https://slsa.dev/provenance/v1"
+	// ProvenanceBuildType identifies builds run by pack in the provenance statement
+	ProvenanceBuildType = "https://buildpacks.io/pack/build/v1"
+	// ProvenanceArtifactType is the artifact type of the provenance statement when attached to an image
+	ProvenanceArtifactType = "application/vnd.in-toto+json"
+	// ProvenanceImageIDAnnotation annotates images in the daemon with their ID, which isn't a digest of their manifest
+	ProvenanceImageIDAnnotation = "imageId"
+)
+
+// ProvenanceStatement is an in-toto statement carrying SLSA provenance for an app image.
+type ProvenanceStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     SLSAProvenance       `json:"predicate"`
+}
+
+// ResourceDescriptor identifies an artifact by name and digest.
+type ResourceDescriptor struct {
+	Name        string            `json:"name,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SLSAProvenance is the SLSA v1 provenance predicate.
+type SLSAProvenance struct {
+	BuildDefinition ProvenanceBuildDefinition `json:"buildDefinition"`
+	RunDetails      ProvenanceRunDetails      `json:"runDetails"`
+}
+
+type ProvenanceBuildDefinition struct {
+	BuildType            string                       `json:"buildType"`
+	ExternalParameters   ProvenanceExternalParameters `json:"externalParameters"`
+	InternalParameters   ProvenanceInternalParameters `json:"internalParameters"`
+	ResolvedDependencies []ResourceDescriptor         `json:"resolvedDependencies"`
+}
+
+// ProvenanceExternalParameters are the inputs of the build provided by the user
+type ProvenanceExternalParameters struct {
+	Image    string             `json:"image"`
+	Builder  string             `json:"builder"`
+	RunImage string             `json:"runImage"`
+	Source   ResourceDescriptor `json:"source"`
+	// Env holds the names of the build-time env vars; values are omitted as they may contain secrets
+	Env     []string `json:"env,omitempty"`
+	Publish bool     `json:"publish"`
+}
+
+// ProvenanceInternalParameters are the inputs of the build resolved by pack
+type ProvenanceInternalParameters struct {
+	LifecycleVersion string `json:"lifecycleVersion"`
+	PlatformAPI      string `json:"platformApi"`
+}
+
+type ProvenanceRunDetails struct {
+	Builder  ResourceDescriptor `json:"builder"`
+	Metadata ProvenanceMetadata `json:"metadata"`
+}
+
+type ProvenanceMetadata struct {
+	StartedOn  time.Time `json:"startedOn"`
+	FinishedOn time.Time `json:"finishedOn"`
+}
+
+// provenanceInputs holds what build resolved for the provenance of the built image
+type provenanceInputs struct {
+	builderName      string
+	builderImage     imgutil.Image
+	ephemeralBuilder *builder.Builder
+	lifecycleVersion string
+	platformAPI      string
+	runImageName     string
+	runImage         imgutil.Image
+	appPath          string
+	fileFilter       func(string) bool
+	env              map[string]string
+	startedOn        time.Time
+}
+
+// generateProvenance writes the provenance statement of the built image to opts.ProvenanceDestinationDir and,
+// when the image was published, attaches it to the image as an OCI referrer
+func (c *Client) generateProvenance(ctx context.Context, opts BuildOptions, imageRef name.Reference, inputs provenanceInputs) error {
+	img, err := c.fetchBuiltImage(ctx, opts.Publish, imageRef, opts.InsecureRegistries)
+	if err != nil {
+		return err
+	}
+	id, err := img.Identifier()
+	if err != nil {
+		return fmt.Errorf("reading image sha: %w", err)
+	}
+
+	// the buildpacks that took part in the build, rather than every buildpack on the builder
+	var buildMD files.BuildMetadata
+	if _, err := dist.GetLabel(img, platform.BuildMetadataLabel, &buildMD); err != nil {
+		return err
+	}
+
+	statement, err := c.newProvenanceStatement(opts, imageRef, id, buildMD.Buildpacks, inputs)
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling provenance")
+	}
+
+	if err := os.MkdirAll(opts.ProvenanceDestinationDir, 0750); err != nil {
+		return errors.Wrapf(err, "creating provenance output dir %s", style.Symbol(opts.ProvenanceDestinationDir))
+	}
+	provenancePath := filepath.Join(opts.ProvenanceDestinationDir, ProvenanceFileName)
+	if err := os.WriteFile(provenancePath, contents, 0600); err != nil {
+		return errors.Wrapf(err, "writing provenance to %s", style.Symbol(provenancePath))
+	}
+	c.logger.Debugf("Wrote provenance to %s", style.Symbol(provenancePath))
+
+	if !opts.Publish {
+		return nil
+	}
+	return c.attachProvenance(ctx, imageRef, parseDigestFromImageID(id), contents, opts.InsecureRegistries)
+}
+
+func (c *Client) newProvenanceStatement(opts BuildOptions, imageRef name.Reference, id imgutil.Identifier, group []buildpack.GroupElement, inputs provenanceInputs) (ProvenanceStatement, error) {
+	sourceDigest, err := hashAppSource(inputs.appPath, inputs.fileFilter)
+	if err != nil {
+		return ProvenanceStatement{}, errors.Wrap(err, "hashing app source")
+	}
+
+	var envNames []string
+	for name := range inputs.env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	dependencies := []ResourceDescriptor{
+		imageDescriptor(ResourceDescriptor{Name: "builder", URI: inputs.builderName}, imageIdentifier(inputs.builderImage)),
+		imageDescriptor(ResourceDescriptor{Name: "run-image", URI: inputs.runImageName}, imageIdentifier(inputs.runImage)),
+		{Name: "lifecycle", Annotations: map[string]string{"version": inputs.lifecycleVersion}},
+	}
+
+	buildpackLayers := dist.ModuleLayers{}
+	if _, err := dist.GetLabel(inputs.ephemeralBuilder.Image(), dist.BuildpackLayersLabel, &buildpackLayers); err != nil {
+		return ProvenanceStatement{}, errors.Wrapf(err, "getting label %s", dist.BuildpackLayersLabel)
+	}
+	dependencies = append(dependencies, buildpackDependencies(buildpackLayers, group)...)
+
+	return ProvenanceStatement{
+		Type: InTotoStatementType,
+		Subject: []ResourceDescriptor{
+			imageDescriptor(ResourceDescriptor{Name: imageRef.Context().Name()}, id),
+		},
+		PredicateType: SLSAProvenancePredicateType,
+		Predicate: SLSAProvenance{
+			BuildDefinition: ProvenanceBuildDefinition{
+				BuildType: ProvenanceBuildType,
+				ExternalParameters: ProvenanceExternalParameters{
+					Image:    imageRef.Name(),
+					Builder:  inputs.builderName,
+					RunImage: inputs.runImageName,
+					Source: ResourceDescriptor{
+						URI:    inputs.appPath,
+						Digest: digestSet(sourceDigest),
+					},
+					Env:     envNames,
+					Publish: opts.Publish,
+				},
+				InternalParameters: ProvenanceInternalParameters{
+					LifecycleVersion: inputs.lifecycleVersion,
+					PlatformAPI:      inputs.platformAPI,
+				},
+				ResolvedDependencies: dependencies,
+			},
+			RunDetails: ProvenanceRunDetails{
+				Builder: ResourceDescriptor{
+					URI:         "https://github.com/buildpacks/pack",
+					Annotations: map[string]string{"version": c.version},
+				},
+				Metadata: ProvenanceMetadata{
+					StartedOn:  inputs.startedOn.UTC(),
+					FinishedOn: time.Now().UTC(),
+				},
+			},
+		},
+	}, nil
+}
+
+// buildpackDependencies lists the buildpacks of the group on the builder, sorted by id and version
+func buildpackDependencies(buildpackLayers dist.ModuleLayers, group []buildpack.GroupElement) []ResourceDescriptor {
+	var dependencies []ResourceDescriptor
+	for _, bp := range group {
+		if info, ok := buildpackLayers[bp.ID][bp.Version]; ok {
+			dependency := ResourceDescriptor{
+				Name:   fmt.Sprintf("%s@%s", bp.ID, bp.Version),
+				URI:    fmt.Sprintf("urn:cnb:buildpack:%s@%s", bp.ID, bp.Version),
+				Digest: digestSet(info.LayerDiffID),
+			}
+			if info.Homepage != "" {
+				dependency.Annotations = map[string]string{"homepage": info.Homepage}
+			}
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].Name < dependencies[j].Name
+	})
+	return dependencies
+}
+
+// attachProvenance pushes the provenance statement as an OCI artifact referring to the image with the given digest
+func (c *Client) attachProvenance(ctx context.Context, imageRef name.Reference, digest string, statement []byte, insecureRegistries []string) error {
+	var nameOpts []name.Option
+	for _, registry := range insecureRegistries {
+		if registry == imageRef.Context().RegistryStr() {
+			nameOpts = append(nameOpts, name.Insecure)
+		}
+	}
+	subjectRef, err := name.NewDigest(fmt.Sprintf("%s@%s", imageRef.Context().Name(), digest), nameOpts...)
+	if err != nil {
+		return errors.Wrapf(err, "parsing image reference")
+	}
+	remoteOpts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(c.keychain)}
+
+	subject, err := remote.Head(subjectRef, remoteOpts...)
+	if err != nil {
+		return errors.Wrapf(err, "fetching descriptor of %s", style.Symbol(subjectRef.String()))
+	}
+
+	// registries without support for artifactType report the config media type as the artifact type
+	config := static.NewLayer([]byte("{}"), ProvenanceArtifactType)
+	layer := static.NewLayer(statement, ProvenanceArtifactType)
+	for _, blob := range []v1.Layer{config, layer} {
+		if err := remote.WriteLayer(subjectRef.Context(), blob, remoteOpts...); err != nil {
+			return errors.Wrap(err, "uploading provenance")
+		}
+	}
+
+	configDesc, err := partialDescriptor(config)
+	if err != nil {
+		return err
+	}
+	layerDesc, err := partialDescriptor(layer)
+	if err != nil {
+		return err
+	}
+	manifest, err := json.Marshal(artifactManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		ArtifactType:  ProvenanceArtifactType,
+		Config:        configDesc,
+		Layers:        []v1.Descriptor{layerDesc},
+		Subject:       &v1.Descriptor{MediaType: subject.MediaType, Size: subject.Size, Digest: subject.Digest},
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshalling provenance manifest")
+	}
+
+	manifestDigest, _, err := v1.SHA256(bytes.NewReader(manifest))
+	if err != nil {
+		return err
+	}
+	artifactRef := subjectRef.Context().Digest(manifestDigest.String())
+	if err := remote.Put(artifactRef, rawManifest{raw: manifest, mediaType: types.OCIManifestSchema1}, remoteOpts...); err != nil {
+		return errors.Wrap(err, "pushing provenance")
+	}
+	c.logger.Infof("Attached provenance %s to %s", style.Symbol(manifestDigest.String()), style.Symbol(subjectRef.String()))
+	return nil
+}
+
+// artifactManifest is an OCI image manifest with an artifact type, which v1.Manifest lacks
+type artifactManifest struct {
+	SchemaVersion int64           `json:"schemaVersion"`
+	MediaType     types.MediaType `json:"mediaType"`
+	ArtifactType  string          `json:"artifactType"`
+	Config        v1.Descriptor   `json:"config"`
+	Layers        []v1.Descriptor `json:"layers"`
+	Subject       *v1.Descriptor  `json:"subject,omitempty"`
+}
+
+type rawManifest struct {
+	raw       []byte
+	mediaType types.MediaType
+}
+
+func (m rawManifest) RawManifest() ([]byte, error) {
+	return m.raw, nil
+}
+
+func (m rawManifest) MediaType() (types.MediaType, error) {
+	return m.mediaType, nil
+}
+
+func partialDescriptor(layer v1.Layer) (v1.Descriptor, error) {
+	digest, err := layer.Digest()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	mediaType, err := layer.MediaType()
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+	return v1.Descriptor{MediaType: mediaType, Size: size, Digest: digest}, nil
+}
+
+// hashAppSource returns the sha256 digest of the app files that are added to the build, or of the app archive
+func hashAppSource(appPath string, fileFilter func(string) bool) (string, error) {
+	fi, err := os.Stat(appPath)
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	if !fi.IsDir() {
+		if err := hashFile(hasher, appPath); err != nil {
+			return "", err
+		}
+		return "sha256:" + hex.EncodeToString(hasher.Sum(nil)), nil
+	}
+
+	err = filepath.Walk(appPath, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(appPath, file)
+		if err != nil {
+			return err
+		}
+		if relPath == "." || (fileFilter != nil && !fileFilter(relPath)) {
+			return nil
+		}
+
+		fmt.Fprintf(hasher, "%s\x00%o\x00", filepath.ToSlash(relPath), fi.Mode())
+		switch {
+		case fi.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(hasher, target); err != nil {
+				return err
+			}
+		case fi.Mode().IsRegular():
+			if err := hashFile(hasher, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// imageIdentifier returns the identifier of img, or nil if it is unknown
+func imageIdentifier(img imgutil.Image) imgutil.Identifier {
+	if img == nil {
+		return nil
+	}
+	id, err := img.Identifier()
+	if err != nil {
+		return nil
+	}
+	return id
+}
+
+// imageDescriptor identifies the image of descriptor by the digest of its manifest. Images in the daemon are only
+// known by their ID, which is the digest of their config: it's annotated as such rather than given as their digest.
+func imageDescriptor(descriptor ResourceDescriptor, id imgutil.Identifier) ResourceDescriptor {
+	if id == nil {
+		return descriptor
+	}
+	digest := parseDigestFromImageID(id)
+	if digest == "sha256:" {
+		return descriptor
+	}
+	if _, isDaemonID := id.(local.IDIdentifier); isDaemonID {
+		descriptor.Annotations = map[string]string{ProvenanceImageIDAnnotation: digest}
+		return descriptor
+	}
+	descriptor.Digest = digestSet(digest)
+	return descriptor
+}
+
+// digestSet converts an '<algorithm>:<hex>' digest to an in-toto digest set
+func digestSet(digest string) map[string]string {
+	algorithm, encoded, ok := strings.Cut(digest, ":")
+	if !ok || encoded == "" {
+		return nil
+	}
+	return map[string]string{algorithm: encoded}
+}
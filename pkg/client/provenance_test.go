@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buildpacks/imgutil/local"
+	imgutilremote "github.com/buildpacks/imgutil/remote"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestProvenance(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Provenance", testProvenance, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testProvenance(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *Client
+		out     bytes.Buffer
+	)
+
+	it.Before(func() {
+		subject = &Client{
+			logger:   logging.NewLogWithWriters(&out, &out),
+			keychain: authn.DefaultKeychain,
+		}
+	})
+
+	when("#attachProvenance", func() {
+		var statement = []byte(`{"_type": "https://in-toto.io/Statement/v1"}`)
+
+		// pushImage pushes a random image to a registry and returns its reference and digest
+		pushImage := func(handler *httptest.Server) (name.Reference, v1.Hash) {
+			ref, err := name.ParseReference(strings.TrimPrefix(handler.URL, "http://") + "/some/app:latest")
+			h.AssertNil(t, err)
+			img, err := random.Image(1024, 1)
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.Write(ref, img))
+			digest, err := img.Digest()
+			h.AssertNil(t, err)
+			return ref, digest
+		}
+
+		assertAttached := func(ref name.Reference, digest v1.Hash) {
+			referrers, err := remote.Referrers(ref.Context().Digest(digest.String()))
+			h.AssertNil(t, err)
+			manifest, err := referrers.IndexManifest()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(manifest.Manifests), 1)
+			h.AssertEq(t, manifest.Manifests[0].ArtifactType, ProvenanceArtifactType)
+
+			artifact, err := remote.Image(ref.Context().Digest(manifest.Manifests[0].Digest.String()))
+			h.AssertNil(t, err)
+			layers, err := artifact.Layers()
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(layers), 1)
+			rc, err := layers[0].Uncompressed()
+			h.AssertNil(t, err)
+			defer rc.Close()
+			contents, err := io.ReadAll(rc)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), string(statement))
+		}
+
+		it("attaches the statement to the image as a referrer", func() {
+			server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0)), registry.WithReferrersSupport(true)))
+			defer server.Close()
+			ref, digest := pushImage(server)
+
+			h.AssertNil(t, subject.attachProvenance(context.TODO(), ref, digest.String(), statement, nil))
+
+			assertAttached(ref, digest)
+			h.AssertContains(t, out.String(), "Attached provenance")
+		})
+
+		when("the registry doesn't support the referrers API", func() {
+			it("attaches the statement using the referrers tag", func() {
+				server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+				defer server.Close()
+				ref, digest := pushImage(server)
+
+				h.AssertNil(t, subject.attachProvenance(context.TODO(), ref, digest.String(), statement, nil))
+
+				assertAttached(ref, digest)
+			})
+		})
+
+		when("the image doesn't exist", func() {
+			it("errors", func() {
+				server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+				defer server.Close()
+				ref, err := name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/some/app:latest")
+				h.AssertNil(t, err)
+
+				err = subject.attachProvenance(context.TODO(), ref, "sha256:"+strings.Repeat("a", 64), statement, nil)
+				h.AssertNotNil(t, err)
+				h.AssertContains(t, err.Error(), "fetching descriptor of")
+			})
+		})
+	})
+
+	when("#hashAppSource", func() {
+		var appDir string
+
+		it.Before(func() {
+			appDir = t.TempDir()
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "app.js"), []byte("console.log('hi')"), 0600))
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "debug.log"), []byte("some-output"), 0600))
+		})
+
+		it("changes when the app changes", func() {
+			before, err := hashAppSource(appDir, nil)
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "app.js"), []byte("console.log('hello')"), 0600))
+
+			after, err := hashAppSource(appDir, nil)
+			h.AssertNil(t, err)
+			h.AssertNotEq(t, before, after)
+		})
+
+		it("ignores the files excluded from the build", func() {
+			notLogs := func(path string) bool { return filepath.Ext(path) != ".log" }
+			before, err := hashAppSource(appDir, notLogs)
+			h.AssertNil(t, err)
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "debug.log"), []byte("other-output"), 0600))
+
+			after, err := hashAppSource(appDir, notLogs)
+			h.AssertNil(t, err)
+			h.AssertEq(t, before, after)
+		})
+	})
+
+	when("#imageDescriptor", func() {
+		const hex = "363c754893f0efe22480b4359a5956cf3bd3ce22742fc576973c61348308c2e4"
+
+		it("identifies registry images by digest", func() {
+			digest, err := name.NewDigest("example.io/some/app@sha256:" + hex)
+			h.AssertNil(t, err)
+
+			descriptor := imageDescriptor(ResourceDescriptor{Name: "some-image"}, imgutilremote.DigestIdentifier{Digest: digest})
+			h.AssertEq(t, descriptor, ResourceDescriptor{Name: "some-image", Digest: map[string]string{"sha256": hex}})
+		})
+
+		it("annotates images in the daemon with their ID, which isn't a digest", func() {
+			descriptor := imageDescriptor(ResourceDescriptor{Name: "some-image"}, local.IDIdentifier{ImageID: hex})
+			h.AssertEq(t, descriptor, ResourceDescriptor{Name: "some-image", Annotations: map[string]string{"imageId": "sha256:" + hex}})
+		})
+
+		it("leaves images that can't be identified unidentified", func() {
+			h.AssertEq(t, imageDescriptor(ResourceDescriptor{Name: "some-image"}, nil), ResourceDescriptor{Name: "some-image"})
+		})
+	})
+}
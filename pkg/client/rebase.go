@@ -16,6 +16,7 @@ import (
 	"github.com/buildpacks/pack/internal/style"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/policy"
 )
 
 // RebaseOptions is a configuration struct that controls image rebase behavior.
@@ -50,6 +51,9 @@ type RebaseOptions struct {
 
 	// Image reference to use as the previous image for rebase.
 	PreviousImage string
+
+	// Policy the run image is evaluated against before rebasing, if any.
+	Policy *policy.Policy
 }
 
 // Rebase updates the run image layers in an app image.
@@ -123,6 +127,12 @@ func (c *Client) Rebase(ctx context.Context, opts RebaseOptions) error {
 		return errors.New("run image must be specified")
 	}
 
+	if opts.Policy != nil {
+		if err := opts.Policy.Enforce(c.logger, policy.Inputs{RunImages: []string{runImageName}}); err != nil {
+			return err
+		}
+	}
+
 	baseImage, err := c.imageFetcher.Fetch(ctx, runImageName, fetchOptions)
 	if err != nil {
 		return err
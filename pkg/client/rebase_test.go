@@ -16,6 +16,7 @@ import (
 	ifakes "github.com/buildpacks/pack/internal/fakes"
 	"github.com/buildpacks/pack/pkg/image"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/policy"
 	h "github.com/buildpacks/pack/testhelpers"
 )
 
@@ -109,6 +110,29 @@ func testRebase(t *testing.T, when spec.G, it spec.S) {
 				})
 			})
 
+			when("a policy is provided", func() {
+				it("fails when the run image violates it", func() {
+					err := subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						Policy:   &policy.Policy{RunImages: policy.ImageRule{Allowed: []string{"registry.example.com"}}},
+					})
+					h.AssertError(t, err, "run image 'some/run' isn't from an allowed registry or repository (run-images.allowed)")
+					h.AssertEq(t, fakeAppImage.Base(), "")
+				})
+
+				it("only warns in audit-only mode", func() {
+					h.AssertNil(t, subject.Rebase(context.TODO(), RebaseOptions{
+						RepoName: "some/app",
+						Policy: &policy.Policy{
+							AuditOnly: true,
+							RunImages: policy.ImageRule{Allowed: []string{"registry.example.com"}},
+						},
+					}))
+					h.AssertEq(t, fakeAppImage.Base(), "some/run")
+					h.AssertContains(t, out.String(), "Warning: Policy violation: run image 'some/run' isn't from an allowed registry or repository (run-images.allowed)")
+				})
+			})
+
 			when("run image is NOT provided by the user", func() {
 				when("the image has a label with a run image specified", func() {
 					it("uses the run image provided in the App image label", func() {
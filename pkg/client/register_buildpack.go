@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/registry"
 	"github.com/buildpacks/pack/pkg/buildpack"
 	"github.com/buildpacks/pack/pkg/dist"
@@ -79,7 +80,7 @@ func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOp
 
 		return cmd.Start()
 	case "git":
-		registryCache, err := getRegistry(c.logger, opts.Name)
+		registryCache, err := getRegistry(c.logger, c.keychain, opts.Name, nil)
 		if err != nil {
 			return err
 		}
@@ -92,6 +93,15 @@ func (c *Client) RegisterBuildpack(ctx context.Context, opts RegisterBuildpackOp
 		if err := registry.GitCommit(buildpack, username, registryCache); err != nil {
 			return err
 		}
+	case "oci":
+		registryCache, err := newRegistryCache(c.logger, c.keychain, config.Registry{Name: opts.Name, Type: opts.Type, URL: opts.URL}, nil)
+		if err != nil {
+			return err
+		}
+
+		if err := registryCache.AddBuildpack(buildpack); err != nil {
+			return err
+		}
 	}
 
 	return nil
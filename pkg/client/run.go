@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/lifecycle/launch"
+	dcontainer "github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/container"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/image"
+)
+
+// RunOptions define how an app image is run.
+type RunOptions struct {
+	// Image is the app image to run
+	Image string
+
+	// Process is the type of the process to start.
+	// When empty the default process of the image is started.
+	Process string
+
+	// Args are passed to the process, replacing the arguments defined by the buildpack.
+	Args []string
+
+	// Env sets environment variables in the container, overriding the values set by the image.
+	Env map[string]string
+
+	// Ports are published on the host, in the form '[[host-ip:]host-port:]container-port[/protocol]'.
+	// When no host port is provided, a random port is chosen by the daemon.
+	Ports []string
+
+	// PullPolicy decides whether the image is pulled when it is missing from, or outdated in, the daemon.
+	PullPolicy image.PullPolicy
+
+	// Stdout and Stderr receive the output of the app. They default to the writer of the client's logger.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run starts a container from an app image and streams its output until the app exits or ctx is cancelled.
+// The container is removed afterwards.
+func (c *Client) Run(ctx context.Context, opts RunOptions) error {
+	exposedPorts, portBindings, err := parsePortSpecs(opts.Ports)
+	if err != nil {
+		return err
+	}
+
+	img, err := c.imageFetcher.Fetch(ctx, opts.Image, image.FetchOptions{Daemon: true, PullPolicy: opts.PullPolicy})
+	if err != nil {
+		return errors.Wrapf(err, "fetching image %s", style.Symbol(opts.Image))
+	}
+
+	var entrypoint []string
+	if opts.Process != "" {
+		info, err := c.InspectImage(opts.Image, true)
+		if err != nil {
+			return errors.Wrapf(err, "inspecting image %s", style.Symbol(opts.Image))
+		}
+		if err := validateProcessType(opts.Process, info.Processes); err != nil {
+			return err
+		}
+
+		imageOS, err := img.OS()
+		if err != nil {
+			return errors.Wrap(err, "reading image os")
+		}
+		if imageOS == "windows" {
+			entrypoint = []string{windowsEntrypointPrefix + opts.Process + ".exe"}
+		} else {
+			entrypoint = []string{entrypointPrefix + opts.Process}
+		}
+	}
+
+	var env []string
+	for key, value := range opts.Env {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(env)
+
+	ctr, err := c.docker.ContainerCreate(ctx, dockerClient.ContainerCreateOptions{
+		Config: &dcontainer.Config{
+			Image:        opts.Image,
+			Entrypoint:   entrypoint,
+			Cmd:          opts.Args,
+			Env:          env,
+			ExposedPorts: exposedPorts,
+		},
+		HostConfig: &dcontainer.HostConfig{
+			PortBindings: portBindings,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating container")
+	}
+	defer c.docker.ContainerRemove(context.Background(), ctr.ID, dockerClient.ContainerRemoveOptions{Force: true})
+
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = c.logger.Writer()
+	}
+	if stderr == nil {
+		stderr = c.logger.Writer()
+	}
+
+	c.logger.Debugf("Starting container %s from %s", style.Symbol(ctr.ID), style.Symbol(opts.Image))
+	err = container.RunWithHandler(ctx, c.docker, ctr.ID, container.DefaultHandler(stdout, stderr))
+	if ctx.Err() != nil {
+		// interrupted, the container is stopped when it is removed
+		return nil
+	}
+	return err
+}
+
+func validateProcessType(processType string, processes ProcessDetails) error {
+	all := processes.OtherProcesses
+	if processes.DefaultProcess != nil {
+		all = append([]launch.Process{*processes.DefaultProcess}, all...)
+	}
+
+	var types []string
+	for _, proc := range all {
+		if proc.Type == processType {
+			return nil
+		}
+		types = append(types, proc.Type)
+	}
+
+	if len(types) == 0 {
+		return errors.Errorf("process type %s not found, the image defines no processes", style.Symbol(processType))
+	}
+	return errors.Errorf("process type %s not found, must be one of: %s", style.Symbol(processType), strings.Join(types, ", "))
+}
+
+// parsePortSpecs parses port mappings in the form '[[host-ip:]host-port:]container-port[/protocol]'
+func parsePortSpecs(specs []string) (network.PortSet, network.PortMap, error) {
+	if len(specs) == 0 {
+		return nil, nil, nil
+	}
+
+	exposed := network.PortSet{}
+	bindings := network.PortMap{}
+	for _, spec := range specs {
+		var hostIP, hostPort, containerPort string
+		parts := strings.Split(spec, ":")
+		switch len(parts) {
+		case 1:
+			containerPort = parts[0]
+		case 2:
+			hostPort, containerPort = parts[0], parts[1]
+		default:
+			hostIP = strings.Join(parts[:len(parts)-2], ":")
+			hostPort, containerPort = parts[len(parts)-2], parts[len(parts)-1]
+		}
+
+		port, err := network.ParsePort(containerPort)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid port mapping %s", style.Symbol(spec))
+		}
+
+		binding := network.PortBinding{HostPort: hostPort}
+		if hostIP != "" {
+			if binding.HostIP, err = netip.ParseAddr(strings.Trim(hostIP, "[]")); err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid port mapping %s", style.Symbol(spec))
+			}
+		}
+
+		exposed[port] = struct{}{}
+		bindings[port] = append(bindings[port], binding)
+	}
+	return exposed, bindings, nil
+}
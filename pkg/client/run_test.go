@@ -0,0 +1,187 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	dcontainer "github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestRun(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Run", testRun, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testRun(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockDockerClient *testmocks.MockAPIClient
+		mockController   *gomock.Controller
+		appImage         *testmocks.MockImage
+		out              bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDockerClient = testmocks.NewMockAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		appImage = testmocks.NewImage("some/app", "", nil)
+		h.AssertNil(t, appImage.SetLabel("io.buildpacks.lifecycle.metadata", `{}`))
+		h.AssertNil(t, appImage.SetLabel("io.buildpacks.build.metadata", `{
+  "processes": [
+    {"type": "web", "command": "/start/web"},
+    {"type": "worker", "command": "/start/worker"}
+  ],
+  "launcher": {"version": "0.20.0"}
+}`))
+		h.AssertNil(t, appImage.SetEnv("CNB_PLATFORM_API", "0.12"))
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	// expectContainerRun expects the container to be started, writing output to stdout, and to exit with statusCode
+	expectContainerRun := func(created *dockerClient.ContainerCreateOptions, output string, statusCode int64) {
+		mockDockerClient.EXPECT().
+			ContainerCreate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, opts dockerClient.ContainerCreateOptions) (dockerClient.ContainerCreateResult, error) {
+				*created = opts
+				return dockerClient.ContainerCreateResult{ID: "some-container"}, nil
+			})
+
+		waitResult := make(chan dcontainer.WaitResponse, 1)
+		waitResult <- dcontainer.WaitResponse{StatusCode: statusCode}
+		mockDockerClient.EXPECT().
+			ContainerWait(gomock.Any(), "some-container", gomock.Any()).
+			Return(dockerClient.ContainerWaitResult{Result: waitResult, Error: make(chan error)})
+
+		mockDockerClient.EXPECT().
+			ContainerAttach(gomock.Any(), "some-container", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ dockerClient.ContainerAttachOptions) (dockerClient.ContainerAttachResult, error) {
+				server, conn := net.Pipe()
+				go func() {
+					_, _ = stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte(output))
+					server.Close()
+				}()
+				return dockerClient.ContainerAttachResult{HijackedResponse: dockerClient.NewHijackedResponse(conn, "")}, nil
+			})
+
+		mockDockerClient.EXPECT().
+			ContainerStart(gomock.Any(), "some-container", gomock.Any()).
+			Return(dockerClient.ContainerStartResult{}, nil)
+		mockDockerClient.EXPECT().
+			ContainerRemove(gomock.Any(), "some-container", dockerClient.ContainerRemoveOptions{Force: true}).
+			Return(dockerClient.ContainerRemoveResult{}, nil)
+	}
+
+	when("#Run", func() {
+		it("starts the default process and streams its output", func() {
+			mockImageFetcher.EXPECT().
+				Fetch(gomock.Any(), "some/app", image.FetchOptions{Daemon: true, PullPolicy: image.PullIfNotPresent}).
+				Return(appImage, nil)
+			var created dockerClient.ContainerCreateOptions
+			expectContainerRun(&created, "hello from the app\n", 0)
+
+			h.AssertNil(t, subject.Run(context.TODO(), RunOptions{
+				Image:      "some/app",
+				Env:        map[string]string{"PORT": "8080", "DEBUG": "true"},
+				Ports:      []string{"8080:8080"},
+				PullPolicy: image.PullIfNotPresent,
+			}))
+
+			h.AssertContains(t, out.String(), "hello from the app")
+			h.AssertEq(t, created.Config.Image, "some/app")
+			h.AssertEq(t, len(created.Config.Entrypoint), 0)
+			h.AssertEq(t, created.Config.Env, []string{"DEBUG=true", "PORT=8080"})
+			h.AssertEq(t, len(created.HostConfig.PortBindings), 1)
+			h.AssertEq(t, created.HostConfig.PortBindings[network.MustParsePort("8080/tcp")][0].HostPort, "8080")
+		})
+
+		when("a process type is provided", func() {
+			it("starts that process", func() {
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(appImage, nil).Times(2)
+				var created dockerClient.ContainerCreateOptions
+				expectContainerRun(&created, "working\n", 0)
+
+				h.AssertNil(t, subject.Run(context.TODO(), RunOptions{
+					Image:   "some/app",
+					Process: "worker",
+					Args:    []string{"--verbose"},
+				}))
+
+				h.AssertEq(t, created.Config.Entrypoint, []string{"/cnb/process/worker"})
+				h.AssertEq(t, created.Config.Cmd, []string{"--verbose"})
+			})
+
+			when("the image doesn't define the process type", func() {
+				it("errors with the available process types", func() {
+					mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(appImage, nil).Times(2)
+
+					err := subject.Run(context.TODO(), RunOptions{Image: "some/app", Process: "cron"})
+					h.AssertError(t, err, "process type 'cron' not found, must be one of: web, worker")
+				})
+			})
+		})
+
+		when("the app exits with an error", func() {
+			it("returns the status code", func() {
+				mockImageFetcher.EXPECT().Fetch(gomock.Any(), "some/app", gomock.Any()).Return(appImage, nil)
+				var created dockerClient.ContainerCreateOptions
+				expectContainerRun(&created, "crashed\n", 3)
+
+				err := subject.Run(context.TODO(), RunOptions{Image: "some/app"})
+				h.AssertError(t, err, "failed with status code: 3")
+			})
+		})
+
+		when("a port mapping is invalid", func() {
+			it("errors", func() {
+				err := subject.Run(context.TODO(), RunOptions{Image: "some/app", Ports: []string{"8080:http"}})
+				h.AssertError(t, err, "invalid port mapping '8080:http'")
+			})
+		})
+	})
+
+	when("#parsePortSpecs", func() {
+		it("parses host addresses, host ports and protocols", func() {
+			exposed, bindings, err := parsePortSpecs([]string{"127.0.0.1:8080:80", "53/udp", "[::1]:9090:9090"})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, len(exposed), 3)
+
+			binding := bindings[network.MustParsePort("80/tcp")][0]
+			h.AssertEq(t, binding.HostIP.String(), "127.0.0.1")
+			h.AssertEq(t, binding.HostPort, "8080")
+
+			binding = bindings[network.MustParsePort("53/udp")][0]
+			h.AssertEq(t, binding.HostIP.IsValid(), false)
+			h.AssertEq(t, binding.HostPort, "")
+
+			binding = bindings[network.MustParsePort("9090/tcp")][0]
+			h.AssertEq(t, binding.HostIP.String(), "::1")
+			h.AssertEq(t, binding.HostPort, "9090")
+		})
+	})
+}
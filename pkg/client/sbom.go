@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buildpacks/lifecycle/launch"
+	"github.com/buildpacks/lifecycle/layers"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// SBOMPackage is a package listed in the SBOM of an image
+type SBOMPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+	// Buildpack is the ID of the buildpack that contributed the SBOM listing the package
+	Buildpack string `json:"buildpack"`
+	// Layer is empty when the SBOM describes the whole buildpack rather than one of its layers
+	Layer string `json:"layer,omitempty"`
+	// Scope is either launch, build or cache
+	Scope string `json:"scope"`
+}
+
+// ReadSBOMOptions define how the SBOM of an image is read.
+type ReadSBOMOptions struct {
+	// Daemon reads the SBOM of an image in the daemon, rather than in a registry
+	Daemon bool
+}
+
+// MergeSBOMOptions define how the SBOM documents of an image are merged.
+type MergeSBOMOptions struct {
+	ReadSBOMOptions
+
+	// Format of the merged document, either cyclonedx or spdx
+	Format string
+
+	// Output receives the merged document
+	Output io.Writer
+}
+
+var sbomScopes = map[string]bool{"launch": true, "build": true, "cache": true}
+
+// ReadSBOM reads the CycloneDX, SPDX and Syft documents written by each buildpack to the SBOM layer of an image,
+// and returns the packages they list. Packages listed in several formats by the same SBOM are returned once.
+func (c *Client) ReadSBOM(ctx context.Context, name string, opts ReadSBOMOptions) ([]SBOMPackage, error) {
+	rc, sbomMD, err := c.fetchSBOMLayer(ctx, name, opts.Daemon, "show")
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	tmpDir, err := os.MkdirTemp("", "pack.sbom.")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := layers.Extract(rc, tmpDir); err != nil {
+		return nil, errors.Wrap(err, "extracting SBOM layer")
+	}
+
+	// the SBOM directories are named after the escaped buildpack IDs
+	buildpackIDs := map[string]string{}
+	for _, bp := range sbomMD.Buildpacks {
+		buildpackIDs[launch.EscapeID(bp.ID)] = bp.ID
+	}
+
+	seen := map[SBOMPackage]bool{}
+	var packages []SBOMPackage
+	err = filepath.Walk(tmpDir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		format, ok := sbom.FormatOf(path)
+		if fi.IsDir() || !ok {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(tmpDir, path)
+		if err != nil {
+			return err
+		}
+		source, ok := sbomSource(relPath, buildpackIDs)
+		if !ok {
+			c.logger.Debugf("Skipping SBOM %s outside of a buildpack SBOM directory", style.Symbol(relPath))
+			return nil
+		}
+
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		found, err := sbom.Decode(format, f)
+		if err != nil {
+			c.logger.Warnf("Skipping SBOM %s: %s", style.Symbol(relPath), err)
+			return nil
+		}
+
+		for _, pkg := range found {
+			entry := SBOMPackage{
+				Name:      pkg.Name,
+				Version:   pkg.Version,
+				PURL:      pkg.PURL,
+				Buildpack: source.Buildpack,
+				Layer:     source.Layer,
+				Scope:     source.Scope,
+			}
+			if !seen[entry] {
+				seen[entry] = true
+				packages = append(packages, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SBOM layer")
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.Buildpack != b.Buildpack {
+			return a.Buildpack < b.Buildpack
+		}
+		if a.Layer != b.Layer {
+			return a.Layer < b.Layer
+		}
+		return a.Scope < b.Scope
+	})
+	return packages, nil
+}
+
+// MergeSBOM writes a single CycloneDX or SPDX document listing all the packages in the SBOM of an image.
+// A package contributed by several buildpacks or layers is listed once, attributed to each of them.
+func (c *Client) MergeSBOM(ctx context.Context, name string, opts MergeSBOMOptions) error {
+	format := sbom.Format(opts.Format)
+	if format != sbom.FormatCycloneDX && format != sbom.FormatSPDX {
+		return errors.Errorf("unsupported SBOM format %s; must be one of: %s, %s", style.Symbol(opts.Format), sbom.FormatCycloneDX, sbom.FormatSPDX)
+	}
+
+	packages, err := c.ReadSBOM(ctx, name, opts.ReadSBOMOptions)
+	if err != nil {
+		return err
+	}
+
+	doc := sbom.Document{
+		Name:        name,
+		Created:     time.Now(),
+		ToolName:    "pack",
+		ToolVersion: c.version,
+	}
+	index := map[sbom.Package]int{}
+	for _, pkg := range packages {
+		key := sbom.Package{Name: pkg.Name, Version: pkg.Version, PURL: pkg.PURL}
+		i, ok := index[key]
+		if !ok {
+			i = len(doc.Packages)
+			index[key] = i
+			doc.Packages = append(doc.Packages, sbom.AttributedPackage{Package: key})
+		}
+		doc.Packages[i].Sources = append(doc.Packages[i].Sources, sbom.Source{Buildpack: pkg.Buildpack, Layer: pkg.Layer, Scope: pkg.Scope})
+	}
+
+	return sbom.Encode(opts.Output, format, doc)
+}
+
+// sbomSource returns where an SBOM file is from, based on its path in the SBOM layer:
+// '.../sbom/<scope>/<escaped buildpack id>/[<layer>/]sbom.<ext>'
+func sbomSource(relPath string, buildpackIDs map[string]string) (sbom.Source, bool) {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	for i := 1; i <= len(parts)-3; i++ {
+		if parts[i-1] != "sbom" || !sbomScopes[parts[i]] {
+			continue
+		}
+
+		rest := parts[i+1 : len(parts)-1]
+		if len(rest) > 2 {
+			return sbom.Source{}, false
+		}
+		source := sbom.Source{Scope: parts[i], Buildpack: rest[0]}
+		if id, ok := buildpackIDs[rest[0]]; ok {
+			source.Buildpack = id
+		}
+		if len(rest) == 2 {
+			source.Layer = rest[1]
+		}
+		return source, true
+	}
+	return sbom.Source{}, false
+}
@@ -0,0 +1,262 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSBOM(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SBOM", testSBOM, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSBOM(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockImageFetcher *testmocks.MockImageFetcher
+		mockController   *gomock.Controller
+		out              bytes.Buffer
+		sbomDir          string
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockImageFetcher = testmocks.NewMockImageFetcher(mockController)
+		mockDockerClient := testmocks.NewMockAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithFetcher(mockImageFetcher), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		sbomDir = t.TempDir()
+		writeSBOM := func(path, contents string) {
+			path = filepath.Join(sbomDir, "layers", "sbom", filepath.FromSlash(path))
+			h.AssertNil(t, os.MkdirAll(filepath.Dir(path), 0755))
+			h.AssertNil(t, os.WriteFile(path, []byte(contents), 0600))
+		}
+		writeSBOM("launch/paketo-buildpacks_node-engine/node/sbom.cdx.json", `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [
+    {"type": "application", "name": "node", "version": "20.1.0", "purl": "pkg:generic/node@20.1.0",
+     "components": [{"type": "library", "name": "npm", "version": "9.6.4", "purl": "pkg:npm/npm@9.6.4"}]}
+  ]
+}`)
+		writeSBOM("launch/paketo-buildpacks_node-engine/node/sbom.syft.json", `{
+  "artifacts": [{"name": "node", "version": "20.1.0", "purl": "pkg:generic/node@20.1.0"}]
+}`)
+		writeSBOM("launch/paketo-buildpacks_npm-install/sbom.spdx.json", `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {"SPDXID": "SPDXRef-express", "name": "express", "versionInfo": "4.18.2",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/express@4.18.2"}]},
+    {"SPDXID": "SPDXRef-npm", "name": "npm", "versionInfo": "9.6.4",
+     "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:npm/npm@9.6.4"}]}
+  ]
+}`)
+		writeSBOM("build/paketo-buildpacks_npm-install/sbom.cdx.json", `not json`)
+	})
+
+	it.After(func() {
+		mockController.Finish()
+	})
+
+	// expectImage returns an image with an SBOM layer made of the files in sbomDir
+	expectImage := func(daemon bool) {
+		layerPath := h.CreateTAR(t, sbomDir, ".", -1)
+		t.Cleanup(func() { os.Remove(layerPath) })
+		data, err := os.ReadFile(layerPath)
+		h.AssertNil(t, err)
+		sum := sha256.Sum256(data)
+		diffID := "sha256:" + hex.EncodeToString(sum[:])
+
+		img := testmocks.NewImage("some/image", "", nil)
+		h.AssertNil(t, img.AddLayerWithDiffID(layerPath, diffID))
+		h.AssertNil(t, img.SetLabel("io.buildpacks.lifecycle.metadata", fmt.Sprintf(`{
+  "sbom": {"sha": "%s"},
+  "buildpacks": [{"key": "paketo-buildpacks/node-engine"}, {"key": "paketo-buildpacks/npm-install"}]
+}`, diffID)))
+
+		mockImageFetcher.EXPECT().
+			Fetch(gomock.Any(), "some/image", image.FetchOptions{Daemon: daemon, PullPolicy: image.PullNever}).
+			Return(img, nil)
+	}
+
+	when("#ReadSBOM", func() {
+		it("lists the packages of each buildpack and layer", func() {
+			expectImage(true)
+
+			packages, err := subject.ReadSBOM(context.TODO(), "some/image", ReadSBOMOptions{Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, packages, []SBOMPackage{
+				{Name: "express", Version: "4.18.2", PURL: "pkg:npm/express@4.18.2", Buildpack: "paketo-buildpacks/npm-install", Scope: "launch"},
+				{Name: "node", Version: "20.1.0", PURL: "pkg:generic/node@20.1.0", Buildpack: "paketo-buildpacks/node-engine", Layer: "node", Scope: "launch"},
+				{Name: "npm", Version: "9.6.4", PURL: "pkg:npm/npm@9.6.4", Buildpack: "paketo-buildpacks/node-engine", Layer: "node", Scope: "launch"},
+				{Name: "npm", Version: "9.6.4", PURL: "pkg:npm/npm@9.6.4", Buildpack: "paketo-buildpacks/npm-install", Scope: "launch"},
+			})
+		})
+
+		it("warns about SBOMs that can't be read", func() {
+			expectImage(true)
+
+			_, err := subject.ReadSBOM(context.TODO(), "some/image", ReadSBOMOptions{Daemon: true})
+			h.AssertNil(t, err)
+
+			h.AssertContains(t, out.String(), "Skipping SBOM 'layers/sbom/build/paketo-buildpacks_npm-install/sbom.cdx.json'")
+		})
+
+		it("reads the SBOM of remote images", func() {
+			expectImage(false)
+
+			packages, err := subject.ReadSBOM(context.TODO(), "some/image", ReadSBOMOptions{})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(packages), 4)
+		})
+
+		when("the image doesn't exist", func() {
+			it("suggests reading it from a registry", func() {
+				mockImageFetcher.EXPECT().
+					Fetch(gomock.Any(), "some/image", image.FetchOptions{Daemon: true, PullPolicy: image.PullNever}).
+					Return(nil, image.ErrNotFound)
+
+				_, err := subject.ReadSBOM(context.TODO(), "some/image", ReadSBOMOptions{Daemon: true})
+				h.AssertError(t, err, "image 'some/image' cannot be found")
+				h.AssertContains(t, out.String(), "'pack sbom show --remote some/image'")
+			})
+		})
+	})
+
+	when("#MergeSBOM", func() {
+		it("writes one CycloneDX document attributing each package to its buildpacks", func() {
+			expectImage(true)
+			var merged bytes.Buffer
+
+			h.AssertNil(t, subject.MergeSBOM(context.TODO(), "some/image", MergeSBOMOptions{
+				ReadSBOMOptions: ReadSBOMOptions{Daemon: true},
+				Format:          "cyclonedx",
+				Output:          &merged,
+			}))
+
+			var doc struct {
+				BOMFormat  string `json:"bomFormat"`
+				Components []struct {
+					Name       string `json:"name"`
+					Properties []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"properties"`
+				} `json:"components"`
+			}
+			h.AssertNil(t, json.Unmarshal(merged.Bytes(), &doc))
+			h.AssertEq(t, doc.BOMFormat, "CycloneDX")
+			h.AssertEq(t, len(doc.Components), 3)
+			h.AssertEq(t, doc.Components[2].Name, "npm")
+
+			var buildpacks []string
+			for _, property := range doc.Components[2].Properties {
+				if property.Name == "io.buildpacks.buildpack" {
+					buildpacks = append(buildpacks, property.Value)
+				}
+			}
+			h.AssertEq(t, buildpacks, []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/npm-install"})
+		})
+
+		it("writes one SPDX document", func() {
+			expectImage(true)
+			var merged bytes.Buffer
+
+			h.AssertNil(t, subject.MergeSBOM(context.TODO(), "some/image", MergeSBOMOptions{
+				ReadSBOMOptions: ReadSBOMOptions{Daemon: true},
+				Format:          "spdx",
+				Output:          &merged,
+			}))
+
+			h.AssertContains(t, merged.String(), `"spdxVersion": "SPDX-2.3"`)
+			h.AssertContains(t, merged.String(), `"referenceLocator": "pkg:npm/express@4.18.2"`)
+		})
+
+		when("the format is not supported", func() {
+			it("errors", func() {
+				err := subject.MergeSBOM(context.TODO(), "some/image", MergeSBOMOptions{Format: "syft"})
+				h.AssertError(t, err, "unsupported SBOM format 'syft'; must be one of: cyclonedx, spdx")
+			})
+		})
+	})
+
+	when("#ScanSBOM", func() {
+		var dbDir string
+
+		it.Before(func() {
+			dbDir = t.TempDir()
+			h.AssertNil(t, os.WriteFile(filepath.Join(dbDir, "GHSA-npm.json"), []byte(`{
+  "id": "GHSA-npm",
+  "summary": "npm is vulnerable",
+  "affected": [{"package": {"ecosystem": "npm", "name": "npm"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "9.0.0"}, {"fixed": "9.8.0"}]}]}],
+  "database_specific": {"severity": "LOW"}
+}`), 0600))
+			h.AssertNil(t, os.WriteFile(filepath.Join(dbDir, "GHSA-express.json"), []byte(`{
+  "id": "GHSA-express",
+  "affected": [{"package": {"ecosystem": "npm", "name": "express"}, "versions": ["4.18.2"]}],
+  "database_specific": {"severity": "HIGH"}
+}`), 0600))
+		})
+
+		it("reports the vulnerabilities of each buildpack layer, most severe first", func() {
+			expectImage(true)
+
+			vulnerabilities, err := subject.ScanSBOM(context.TODO(), "some/image", ScanSBOMOptions{
+				ReadSBOMOptions: ReadSBOMOptions{Daemon: true},
+				DatabaseDir:     dbDir,
+			})
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, vulnerabilities, []SBOMVulnerability{
+				{
+					ID:           "GHSA-npm",
+					Summary:      "npm is vulnerable",
+					Severity:     "low",
+					FixedVersion: "9.8.0",
+					Package:      SBOMPackage{Name: "npm", Version: "9.6.4", PURL: "pkg:npm/npm@9.6.4", Buildpack: "paketo-buildpacks/node-engine", Layer: "node", Scope: "launch"},
+				},
+				{
+					ID:       "GHSA-express",
+					Severity: "high",
+					Package:  SBOMPackage{Name: "express", Version: "4.18.2", PURL: "pkg:npm/express@4.18.2", Buildpack: "paketo-buildpacks/npm-install", Scope: "launch"},
+				},
+				{
+					ID:           "GHSA-npm",
+					Summary:      "npm is vulnerable",
+					Severity:     "low",
+					FixedVersion: "9.8.0",
+					Package:      SBOMPackage{Name: "npm", Version: "9.6.4", PURL: "pkg:npm/npm@9.6.4", Buildpack: "paketo-buildpacks/npm-install", Scope: "launch"},
+				},
+			})
+		})
+
+		when("no database is provided", func() {
+			it("errors", func() {
+				_, err := subject.ScanSBOM(context.TODO(), "some/image", ScanSBOMOptions{})
+				h.AssertError(t, err, "a vulnerability database directory must be provided")
+			})
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/sbom"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// ScanSBOMOptions define how the SBOM of an image is scanned for vulnerabilities.
+type ScanSBOMOptions struct {
+	ReadSBOMOptions
+
+	// DatabaseDir is a directory of vulnerabilities in the OSV format
+	DatabaseDir string
+}
+
+// SBOMVulnerability is a vulnerability affecting a package in the SBOM of an image
+type SBOMVulnerability struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	// Severity is one of unknown, low, medium, high or critical
+	Severity string `json:"severity"`
+	// FixedVersion is the first version of the package that isn't affected, if known
+	FixedVersion string      `json:"fixedVersion,omitempty"`
+	Package      SBOMPackage `json:"package"`
+}
+
+// ScanSBOM matches the purls of the packages in the SBOM of an image against an offline vulnerability database.
+// Vulnerabilities are returned grouped by buildpack and layer, most severe first.
+func (c *Client) ScanSBOM(ctx context.Context, name string, opts ScanSBOMOptions) ([]SBOMVulnerability, error) {
+	if opts.DatabaseDir == "" {
+		return nil, errors.New("a vulnerability database directory must be provided")
+	}
+	db, err := sbom.LoadDatabase(opts.DatabaseDir)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debugf("Loaded vulnerabilities of %d packages from %s", db.Size(), style.Symbol(opts.DatabaseDir))
+
+	packages, err := c.ReadSBOM(ctx, name, opts.ReadSBOMOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var vulnerabilities []SBOMVulnerability
+	for _, pkg := range packages {
+		if pkg.PURL == "" {
+			c.logger.Debugf("Skipping package %s without purl", style.Symbol(pkg.Name))
+			continue
+		}
+		for _, found := range db.Match(sbom.Package{Name: pkg.Name, Version: pkg.Version, PURL: pkg.PURL}) {
+			vulnerabilities = append(vulnerabilities, SBOMVulnerability{
+				ID:           found.ID,
+				Aliases:      found.Aliases,
+				Summary:      found.Summary,
+				Severity:     found.Severity.String(),
+				FixedVersion: found.FixedVersion,
+				Package:      pkg,
+			})
+		}
+	}
+
+	sort.Slice(vulnerabilities, func(i, j int) bool {
+		a, b := vulnerabilities[i], vulnerabilities[j]
+		if a.Package.Buildpack != b.Package.Buildpack {
+			return a.Package.Buildpack < b.Package.Buildpack
+		}
+		if a.Package.Layer != b.Package.Layer {
+			return a.Package.Layer < b.Package.Layer
+		}
+		if a.Severity != b.Severity {
+			sa, _ := sbom.ParseSeverity(a.Severity)
+			sb, _ := sbom.ParseSeverity(b.Severity)
+			return sa > sb
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Package.Name < b.Package.Name
+	})
+	return vulnerabilities, nil
+}
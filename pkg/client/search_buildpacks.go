@@ -0,0 +1,89 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// RegistryBuildpack is a version of a buildpack in a buildpack registry
+type RegistryBuildpack struct {
+	// ID of the buildpack, as `<namespace>/<name>`
+	ID string `json:"id"`
+
+	// Version of the buildpack
+	Version string `json:"version"`
+
+	// Yanked is true when the version was yanked from the registry
+	Yanked bool `json:"yanked"`
+
+	// Address of the buildpack image, by digest
+	Address string `json:"address,omitempty"`
+}
+
+// SearchBuildpacksOptions is a configuration struct that controls the search of a buildpack registry
+type SearchBuildpacksOptions struct {
+	// Query the ids of the buildpacks must contain. An empty query matches every buildpack.
+	Query string
+
+	// Name of the buildpack registry to search
+	Registry string
+
+	// Consider yanked versions
+	IncludeYanked bool
+}
+
+// ListBuildpackVersionsOptions is a configuration struct that controls the listing of the versions of a buildpack
+type ListBuildpackVersionsOptions struct {
+	// ID of the buildpack, as `<namespace>/<name>`
+	ID string
+
+	// Name of the buildpack registry the buildpack is in
+	Registry string
+
+	// List yanked versions
+	IncludeYanked bool
+}
+
+// SearchBuildpacks returns the latest version of each buildpack of the registry whose id contains the query,
+// sorted by id
+func (c *Client) SearchBuildpacks(opts SearchBuildpacksOptions) ([]RegistryBuildpack, error) {
+	registryCache, err := getRegistry(c.logger, c.keychain, opts.Registry, c.mirror)
+	if err != nil {
+		return nil, errors.Wrapf(err, "lookup registry %s", style.Symbol(opts.Registry))
+	}
+
+	results, err := registryCache.Search(opts.Query, opts.IncludeYanked)
+	if err != nil {
+		return nil, errors.Wrapf(err, "searching registry %s", style.Symbol(opts.Registry))
+	}
+	return toRegistryBuildpacks(results), nil
+}
+
+// ListBuildpackVersions returns the versions of a buildpack in the registry, sorted from the newest to the oldest
+func (c *Client) ListBuildpackVersions(opts ListBuildpackVersionsOptions) ([]RegistryBuildpack, error) {
+	registryCache, err := getRegistry(c.logger, c.keychain, opts.Registry, c.mirror)
+	if err != nil {
+		return nil, errors.Wrapf(err, "lookup registry %s", style.Symbol(opts.Registry))
+	}
+
+	versions, err := registryCache.Versions(opts.ID, opts.IncludeYanked)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing versions of buildpack %s", style.Symbol(opts.ID))
+	}
+	return toRegistryBuildpacks(versions), nil
+}
+
+func toRegistryBuildpacks(buildpacks []registry.Buildpack) []RegistryBuildpack {
+	result := []RegistryBuildpack{}
+	for _, bp := range buildpacks {
+		result = append(result, RegistryBuildpack{
+			ID:      bp.Namespace + "/" + bp.Name,
+			Version: bp.Version,
+			Yanked:  bp.Yanked,
+			Address: bp.Address,
+		})
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+package client_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	cfg "github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSearchBuildpacks(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SearchBuildpacks", testSearchBuildpacks, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testSearchBuildpacks(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *client.Client
+		mockController *gomock.Controller
+		out            bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		tmpDir := t.TempDir()
+
+		packHome := filepath.Join(tmpDir, "packHome")
+		h.AssertNil(t, os.Setenv("PACK_HOME", packHome))
+		registryFixture := h.CreateRegistryFixture(t, tmpDir, filepath.Join("testdata", "registry"))
+		h.AssertNil(t, cfg.Write(cfg.Config{
+			Registries: []cfg.Registry{{Name: "some-registry", Type: "github", URL: registryFixture}},
+		}, filepath.Join(packHome, "config.toml")))
+
+		var err error
+		subject, err = client.NewClient(
+			client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+			client.WithDockerClient(testmocks.NewMockAPIClient(mockController)),
+		)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		os.Unsetenv("PACK_HOME")
+		mockController.Finish()
+	})
+
+	when("#SearchBuildpacks", func() {
+		it("returns the latest version of the matching buildpacks", func() {
+			results, err := subject.SearchBuildpacks(client.SearchBuildpacksOptions{Query: "foo", Registry: "some-registry"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(results), 1)
+			h.AssertEq(t, results[0].ID, "example/foo")
+			h.AssertEq(t, results[0].Version, "1.2.0")
+		})
+
+		it("errors for unknown registries", func() {
+			_, err := subject.SearchBuildpacks(client.SearchBuildpacksOptions{Query: "foo", Registry: "other-registry"})
+			h.AssertError(t, err, "registry 'other-registry' is not defined in your config file")
+		})
+	})
+
+	when("#ListBuildpackVersions", func() {
+		it("returns the versions of the buildpack, newest first", func() {
+			versions, err := subject.ListBuildpackVersions(client.ListBuildpackVersionsOptions{ID: "example/foo", Registry: "some-registry"})
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(versions), 3)
+			h.AssertEq(t, versions[0].Version, "1.2.0")
+			h.AssertEq(t, versions[2].Version, "1.0.0")
+		})
+	})
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/signature"
+)
+
+// verifiedImage is an image whose signature verified in its registry
+type verifiedImage struct {
+	// digest is the reference by digest of the signed manifest or index
+	digest name.Digest
+
+	// ids are the hex digests a copy of the image may be identified by: the digests of the signed manifest, or of the
+	// manifests of the signed index, and of their configs, which daemons identify images by
+	ids []string
+}
+
+// verifySignature verifies the signature of an image in its registry with a PEM encoded public key
+func (c *Client) verifySignature(ctx context.Context, imageName string, key []byte, insecureRegistries []string) (verifiedImage, error) {
+	if c.mirror.IsOffline() {
+		return verifiedImage{}, errors.New("signatures can't be verified in offline mode")
+	}
+
+	publicKey, err := signature.LoadPublicKey(key)
+	if err != nil {
+		return verifiedImage{}, err
+	}
+
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return verifiedImage{}, errors.Wrapf(err, "parsing image reference %s", style.Symbol(imageName))
+	}
+	for _, registry := range insecureRegistries {
+		if ref.Context().RegistryStr() == registry {
+			if ref, err = name.ParseReference(imageName, name.WeakValidation, name.Insecure); err != nil {
+				return verifiedImage{}, errors.Wrapf(err, "parsing image reference %s", style.Symbol(imageName))
+			}
+		}
+	}
+
+	opts := []remote.Option{remote.WithAuthFromKeychain(c.keychain), remote.WithContext(ctx)}
+	digest, err := signature.Verify(ctx, ref, publicKey, opts...)
+	if err != nil {
+		return verifiedImage{}, err
+	}
+	c.logger.Debugf("Verified the signature of %s", style.Symbol(digest.Name()))
+
+	ids, err := imageIDs(digest, opts...)
+	if err != nil {
+		return verifiedImage{}, err
+	}
+	return verifiedImage{digest: digest, ids: ids}, nil
+}
+
+// imageIDs returns the hex digests of the manifests digest refers to, and of their configs
+func imageIDs(digest name.Digest, opts ...remote.Option) ([]string, error) {
+	desc, err := remote.Get(digest, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching manifest of image %s", style.Symbol(digest.Name()))
+	}
+
+	var images []v1.Image
+	ids := []string{desc.Digest.Hex}
+	if desc.MediaType.IsIndex() {
+		index, err := desc.ImageIndex()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading index of image %s", style.Symbol(digest.Name()))
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading index of image %s", style.Symbol(digest.Name()))
+		}
+		for _, m := range manifest.Manifests {
+			if !m.MediaType.IsImage() {
+				continue
+			}
+			img, err := index.Image(m.Digest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading manifest %s of image %s", style.Symbol(m.Digest.String()), style.Symbol(digest.Name()))
+			}
+			ids = append(ids, m.Digest.Hex)
+			images = append(images, img)
+		}
+	} else {
+		img, err := desc.Image()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading image %s", style.Symbol(digest.Name()))
+		}
+		images = append(images, img)
+	}
+
+	for _, img := range images {
+		config, err := img.ConfigName()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading config of image %s", style.Symbol(digest.Name()))
+		}
+		ids = append(ids, config.Hex)
+	}
+	return ids, nil
+}
+
+// check errors unless img, as fetched from the daemon or the registry, is the image whose signature verified.
+// A copy with the same tag in the daemon, or a tag repointed since the verification, is a different image.
+func (v verifiedImage) check(img imgutil.Image) error {
+	identifier, err := img.Identifier()
+	if err != nil {
+		return errors.Wrapf(err, "identifying image %s", style.Symbol(img.Name()))
+	}
+	if identifier == nil {
+		return errors.Errorf("image %s can't be identified", style.Symbol(img.Name()))
+	}
+	id := identifier.String()
+	if i := strings.LastIndex(id, "@"); i >= 0 {
+		id = id[i+1:]
+	}
+	id = strings.TrimPrefix(id, "sha256:")
+	for _, verified := range v.ids {
+		if id == verified {
+			return nil
+		}
+	}
+	return errors.Errorf("image %s isn't the image %s whose signature was verified", style.Symbol(img.Name()), style.Symbol(v.digest.Name()))
+}
+
+// validateSignKey checks that the PEM encoded private key can sign the artifact about to be created,
+// before creating it: images are signed in their registry, so they must be published.
+func validateSignKey(key []byte, publish bool, format string) error {
+	if len(key) == 0 {
+		return nil
+	}
+	if _, err := signature.LoadPrivateKey(key); err != nil {
+		return errors.Wrap(err, "invalid sign key")
+	}
+	if format == FormatImage && !publish {
+		return errors.New("images can only be signed when they are published")
+	}
+	return nil
+}
+
+// signImage pushes a signature of a published image, made with a PEM encoded private key, to the registry of the image.
+// Images referenced by digest are signed as is; tags are resolved to the digest they point to in the registry.
+func (c *Client) signImage(ctx context.Context, imageName string, key []byte) error {
+	signer, err := signature.LoadPrivateKey(key)
+	if err != nil {
+		return errors.Wrap(err, "invalid sign key")
+	}
+
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return errors.Wrapf(err, "parsing image reference %s", style.Symbol(imageName))
+	}
+	opts := []remote.Option{remote.WithAuthFromKeychain(c.keychain)}
+	digest, isDigest := ref.(name.Digest)
+	if isDigest {
+		err = signature.SignDigest(ctx, digest, signer, opts...)
+	} else {
+		digest, err = signature.Sign(ctx, ref, signer, opts...)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "signing image %s", style.Symbol(imageName))
+	}
+	c.logger.Infof("Signed image %s", style.Symbol(digest.Name()))
+	return nil
+}
+
+// signFile writes a detached signature of a file, made with a PEM encoded private key, next to the file as <file>.sig
+func (c *Client) signFile(path string, key []byte) error {
+	signer, err := signature.LoadPrivateKey(key)
+	if err != nil {
+		return errors.Wrap(err, "invalid sign key")
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", style.Symbol(path))
+	}
+	sig, err := signature.SignBlob(signer, data)
+	if err != nil {
+		return errors.Wrapf(err, "signing %s", style.Symbol(path))
+	}
+	if err := os.WriteFile(path+".sig", []byte(sig), 0644); err != nil {
+		return errors.Wrapf(err, "writing signature of %s", style.Symbol(path))
+	}
+	c.logger.Infof("Wrote signature of %s to %s", style.Symbol(path), style.Symbol(path+".sig"))
+	return nil
+}
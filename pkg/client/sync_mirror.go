@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/builder"
+	"github.com/buildpacks/pack/internal/config"
+	"github.com/buildpacks/pack/internal/registry"
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/mirror"
+)
+
+// SyncMirrorOptions define the artifacts to fetch into the local mirror
+type SyncMirrorOptions struct {
+	// Config lists the artifacts to mirror
+	Config mirror.Config
+
+	// Registry is the name of the buildpack registry that registry buildpacks are located in
+	Registry string
+}
+
+// SyncMirror fetches the builders, images, lifecycles, buildpacks and registry indexes listed in the mirror config
+// into the local mirror, for pack to resolve them from when offline.
+func (c *Client) SyncMirror(ctx context.Context, opts SyncMirrorOptions) error {
+	if c.mirror.IsOffline() {
+		return errors.New("the mirror can't be synced in offline mode")
+	}
+
+	platforms, err := opts.Config.PlatformList()
+	if err != nil {
+		return err
+	}
+
+	s := &mirrorSync{client: c, platforms: platforms}
+	for _, img := range opts.Config.Images {
+		if _, err := s.image(ctx, img); err != nil {
+			return err
+		}
+	}
+	for _, b := range opts.Config.Builders {
+		if err := s.builder(ctx, b); err != nil {
+			return err
+		}
+	}
+	for _, version := range opts.Config.Lifecycles {
+		if err := s.lifecycle(ctx, version); err != nil {
+			return err
+		}
+	}
+	for _, bp := range opts.Config.Buildpacks {
+		if err := s.buildpack(ctx, bp, opts.Registry); err != nil {
+			return err
+		}
+	}
+	for _, registryName := range opts.Config.Registries {
+		if _, err := s.registry(registryName); err != nil {
+			return err
+		}
+	}
+
+	c.logger.Infof("Mirror at %s is up to date", style.Symbol(c.mirror.Dir()))
+	return nil
+}
+
+type mirrorSync struct {
+	client    *Client
+	platforms []v1.Platform
+}
+
+// image mirrors the image referenced by ref for each platform, and returns the images mirrored
+func (s *mirrorSync) image(ctx context.Context, ref string) ([]v1.Image, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing image reference %s", style.Symbol(ref))
+	}
+
+	var (
+		images   []v1.Image
+		mirrored []v1.Platform
+	)
+	for _, platform := range s.platforms {
+		img, err := remote.Image(parsed,
+			remote.WithContext(ctx),
+			remote.WithAuthFromKeychain(s.client.keychain),
+			remote.WithPlatform(platform),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching image %s", style.Symbol(ref))
+		}
+
+		// an image that isn't an index is returned whatever the platform requested, so its own platform is checked
+		configFile, err := img.ConfigFile()
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading config of image %s", style.Symbol(ref))
+		}
+		imgPlatform := v1.Platform{OS: configFile.OS, Architecture: configFile.Architecture, Variant: configFile.Variant}
+		if !imgPlatform.Satisfies(platform) || containsPlatform(mirrored, imgPlatform) {
+			s.client.logger.Debugf("Skipping image %s for platform %s", style.Symbol(ref), style.Symbol(platform.String()))
+			continue
+		}
+
+		s.client.logger.Infof("Mirroring image %s for %s", style.Symbol(ref), style.Symbol(imgPlatform.String()))
+		if err := s.client.mirror.AddImage(ref, img, imgPlatform); err != nil {
+			return nil, errors.Wrapf(err, "adding image %s to the mirror", style.Symbol(ref))
+		}
+		mirrored = append(mirrored, imgPlatform)
+		images = append(images, img)
+	}
+
+	if len(images) == 0 {
+		return nil, errors.Errorf("image %s has none of the platforms to mirror", style.Symbol(ref))
+	}
+	return images, nil
+}
+
+// builder mirrors a builder along with its run images and the image of its lifecycle
+func (s *mirrorSync) builder(ctx context.Context, ref string) error {
+	images, err := s.image(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	related := map[string]bool{}
+	var refs []string
+	add := func(ref string) {
+		if ref != "" && !related[ref] {
+			related[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	for _, img := range images {
+		configFile, err := img.ConfigFile()
+		if err != nil {
+			return errors.Wrapf(err, "reading config of builder %s", style.Symbol(ref))
+		}
+		label, ok := configFile.Config.Labels[builder.MetadataLabel]
+		if !ok {
+			return errors.Errorf("image %s is not a builder: it has no label %s", style.Symbol(ref), style.Symbol(builder.MetadataLabel))
+		}
+		var metadata builder.Metadata
+		if err := json.Unmarshal([]byte(label), &metadata); err != nil {
+			return errors.Wrapf(err, "parsing label %s of builder %s", style.Symbol(builder.MetadataLabel), style.Symbol(ref))
+		}
+
+		for _, runImage := range metadata.RunImages {
+			add(runImage.Image)
+		}
+		add(metadata.Stack.RunImage.Image)
+		if metadata.Lifecycle.Version != nil {
+			add(lifecycleImageName(&metadata.Lifecycle.Version.Version))
+		}
+	}
+
+	for _, ref := range refs {
+		if _, err := s.image(ctx, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lifecycle mirrors the lifecycle tarballs of version for each platform, and the lifecycle image
+func (s *mirrorSync) lifecycle(ctx context.Context, version string) error {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return errors.Wrapf(err, "lifecycle version %s must be a valid semver", style.Symbol(version))
+	}
+
+	for _, platform := range s.platforms {
+		if err := s.blob(ctx, s.client.uriFromLifecycleVersion(*v, platform.OS, platform.Architecture)); err != nil {
+			return err
+		}
+	}
+	_, err = s.image(ctx, lifecycleImageName(v))
+	return err
+}
+
+// buildpack mirrors a buildpack by URI, image or registry reference
+func (s *mirrorSync) buildpack(ctx context.Context, uri, registryName string) error {
+	locatorType, err := buildpack.GetLocatorType(uri, "", nil)
+	if err != nil {
+		return err
+	}
+
+	switch locatorType {
+	case buildpack.URILocator:
+		if !isHTTPURI(uri) {
+			return errors.Errorf("buildpack %s can't be mirrored: only http(s) URIs, images and registry references are", style.Symbol(uri))
+		}
+		return s.blob(ctx, uri)
+	case buildpack.PackageLocator:
+		_, err := s.image(ctx, buildpack.ParsePackageLocator(uri))
+		return err
+	case buildpack.RegistryLocator:
+		registryCache, err := s.registry(registryName)
+		if err != nil {
+			return err
+		}
+		regBuildpack, err := registryCache.LocateBuildpack(uri)
+		if err != nil {
+			return errors.Wrapf(err, "locating buildpack %s", style.Symbol(uri))
+		}
+		_, err = s.image(ctx, regBuildpack.Address)
+		return err
+	default:
+		return errors.Errorf("buildpack %s can't be mirrored: only http(s) URIs, images and registry references are", style.Symbol(uri))
+	}
+}
+
+// registry mirrors the index of the registry named registryName, and returns its cache in the mirror
+func (s *mirrorSync) registry(registryName string) (registry.Cache, error) {
+	cfg, err := getConfig()
+	if err != nil {
+		return registry.Cache{}, err
+	}
+	reg, err := lookupRegistry(cfg, registryName)
+	if err != nil {
+		return registry.Cache{}, err
+	}
+
+	if err := os.MkdirAll(s.client.mirror.RegistriesDir(), 0750); err != nil {
+		return registry.Cache{}, errors.Wrapf(err, "creating %s", style.Symbol(s.client.mirror.RegistriesDir()))
+	}
+	var registryCache registry.Cache
+	if reg.Type == "oci" {
+		registryCache, err = registry.NewOCIRegistryCache(s.client.logger, s.client.mirror.RegistriesDir(), reg.URL, s.client.keychain)
+	} else {
+		registryCache, err = registry.NewRegistryCache(s.client.logger, s.client.mirror.RegistriesDir(), reg.URL)
+	}
+	if err != nil {
+		return registry.Cache{}, err
+	}
+
+	s.client.logger.Infof("Mirroring registry %s", style.Symbol(reg.URL))
+	if err := registryCache.Refresh(); err != nil {
+		return registry.Cache{}, errors.Wrapf(err, "mirroring registry %s", style.Symbol(reg.URL))
+	}
+	return registryCache, nil
+}
+
+func (s *mirrorSync) blob(ctx context.Context, uri string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "downloading %s", style.Symbol(uri))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("could not download from %s, code http status %s", style.Symbol(uri), style.SymbolF("%d", resp.StatusCode))
+	}
+
+	s.client.logger.Infof("Mirroring %s", style.Symbol(uri))
+	if err := s.client.mirror.AddBlob(uri, resp.Body); err != nil {
+		return errors.Wrapf(err, "adding %s to the mirror", style.Symbol(uri))
+	}
+	return nil
+}
+
+func lifecycleImageName(version *semver.Version) string {
+	return fmt.Sprintf("%s:%s", config.DefaultLifecycleImageRepo, version.String())
+}
+
+func containsPlatform(platforms []v1.Platform, platform v1.Platform) bool {
+	for _, p := range platforms {
+		if p.Equals(platform) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPURI(uri string) bool {
+	parsed, err := url.Parse(uri)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https")
+}
@@ -0,0 +1,99 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/client"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSyncMirror(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "SyncMirror", testSyncMirror, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testSyncMirror(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject        *client.Client
+		mockController *gomock.Controller
+		localMirror    *mirror.Mirror
+		server         *ghttp.Server
+		out            bytes.Buffer
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		localMirror = mirror.New(filepath.Join(t.TempDir(), "mirror"))
+		server = ghttp.NewServer()
+
+		var err error
+		subject, err = client.NewClient(
+			client.WithLogger(logging.NewLogWithWriters(&out, &out)),
+			client.WithDockerClient(testmocks.NewMockAPIClient(mockController)),
+			client.WithMirror(localMirror),
+		)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+		mockController.Finish()
+	})
+
+	when("#SyncMirror", func() {
+		it("mirrors buildpacks downloaded from http(s) URIs", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "some-buildpack"))
+			uri := server.URL() + "/buildpack.tgz"
+
+			h.AssertNil(t, subject.SyncMirror(context.TODO(), client.SyncMirrorOptions{
+				Config: mirror.Config{Buildpacks: []string{uri}},
+			}))
+
+			path, err := localMirror.Blob(uri)
+			h.AssertNil(t, err)
+			contents, err := os.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "some-buildpack")
+			h.AssertContains(t, out.String(), "Mirror at")
+		})
+
+		it("errors when a buildpack can't be downloaded", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, ""))
+			uri := server.URL() + "/buildpack.tgz"
+
+			err := subject.SyncMirror(context.TODO(), client.SyncMirrorOptions{
+				Config: mirror.Config{Buildpacks: []string{uri}},
+			})
+			h.AssertError(t, err, "http status '404'")
+		})
+
+		it("errors for buildpacks that can't be mirrored", func() {
+			err := subject.SyncMirror(context.TODO(), client.SyncMirrorOptions{
+				Config: mirror.Config{Buildpacks: []string{"file:///some/buildpack"}},
+			})
+			h.AssertError(t, err, "buildpack 'file:///some/buildpack' can't be mirrored")
+		})
+
+		it("errors in offline mode", func() {
+			localMirror.SetOffline(true)
+
+			err := subject.SyncMirror(context.TODO(), client.SyncMirrorOptions{})
+			h.AssertError(t, err, "the mirror can't be synced in offline mode")
+		})
+	})
+}
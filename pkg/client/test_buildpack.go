@@ -0,0 +1,336 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/lifecycle/platform/files"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/archive"
+	"github.com/buildpacks/pack/pkg/blob"
+	"github.com/buildpacks/pack/pkg/buildpack"
+	"github.com/buildpacks/pack/pkg/events"
+)
+
+// BuildpackTestImageName is the prefix of the app image name used when testing a buildpack without an image name.
+// Each of these tests gets its own image name and cache volumes, which are removed once it's done.
+const BuildpackTestImageName = "pack.local/buildpack-test"
+
+// BuildpackTestConfigFile is the name of the file declaring the expectations of a buildpack test
+const BuildpackTestConfigFile = "test.toml"
+
+// TestBuildpackOptions configure a buildpack test.
+type TestBuildpackOptions struct {
+	// BuildpackPath is the directory of the buildpack under test.
+	BuildpackPath string
+
+	// ConfigPath is the test.toml declaring the expectations. It defaults to test.toml
+	// in the app directory. When it doesn't exist, detection and the build are expected to pass.
+	ConfigPath string
+
+	// BuildOptions configure the build of the fixture app given as AppPath. The buildpack
+	// under test is the only buildpack of the build.
+	BuildOptions BuildOptions
+}
+
+// BuildpackTestConfig holds the expectations of a buildpack test.
+type BuildpackTestConfig struct {
+	// Detect is whether the buildpack is expected to pass detection, it defaults to true
+	Detect *bool `toml:"detect"`
+	// Layers are the names of the layers the buildpack is expected to contribute
+	Layers []string `toml:"layers"`
+	// Env holds the values expected in the env directories of the layers of the buildpack
+	Env map[string]string `toml:"env"`
+	// Processes are the launch processes the buildpack is expected to define
+	Processes []BuildpackTestProcess `toml:"processes"`
+}
+
+// BuildpackTestProcess is a launch process expected from the buildpack under test.
+type BuildpackTestProcess struct {
+	Type string `toml:"type"`
+	// Command is the command and arguments of the process, separated by spaces. It isn't checked when empty.
+	Command string `toml:"command"`
+}
+
+// BuildpackTestResult describes the outcome of a buildpack test.
+type BuildpackTestResult struct {
+	// Buildpack is the buildpack under test, as id@version
+	Buildpack string
+	App       string
+	Cases     []BuildpackTestCase
+	Duration  time.Duration
+}
+
+// BuildpackTestCase is an expectation checked by a buildpack test.
+type BuildpackTestCase struct {
+	Name string
+	// Failure describes why the expectation wasn't met, it is empty if it was
+	Failure string
+	// Skipped describes why the expectation couldn't be checked, it is empty if it was
+	Skipped string
+}
+
+// Failures returns the number of expectations that weren't met
+func (r BuildpackTestResult) Failures() int {
+	var failures int
+	for _, testCase := range r.Cases {
+		if testCase.Failure != "" {
+			failures++
+		}
+	}
+	return failures
+}
+
+// Skipped returns the number of expectations that couldn't be checked
+func (r BuildpackTestResult) Skipped() int {
+	var skipped int
+	for _, testCase := range r.Cases {
+		if testCase.Skipped != "" {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// TestBuildpack runs the detect and build phases of the buildpack in BuildpackPath against a fixture app,
+// then checks the outcome against the expectations declared in test.toml. Expectations that aren't met are
+// reported as failures of the result, an error is only returned if the test couldn't be run.
+func (c *Client) TestBuildpack(ctx context.Context, opts TestBuildpackOptions) (*BuildpackTestResult, error) {
+	bpPath, err := filepath.Abs(opts.BuildpackPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving buildpack path %s", style.Symbol(opts.BuildpackPath))
+	}
+	bp, err := buildpack.FromBuildpackRootBlob(blob.NewBlob(bpPath), archive.DefaultTarWriterFactory(), c.logger)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading buildpack from %s", style.Symbol(opts.BuildpackPath))
+	}
+	descriptor := bp.Descriptor()
+
+	if opts.BuildOptions.AppPath == "" {
+		return nil, errors.New("a fixture app must be provided")
+	}
+	configPath := opts.ConfigPath
+	if configPath == "" {
+		configPath = filepath.Join(opts.BuildOptions.AppPath, BuildpackTestConfigFile)
+	}
+	config, err := readBuildpackTestConfig(configPath, opts.ConfigPath == "")
+	if err != nil {
+		return nil, err
+	}
+
+	destinationDir, err := os.MkdirTemp("", "pack.buildpack-test")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temp dir")
+	}
+	defer os.RemoveAll(destinationDir)
+
+	partial := &partialRun{build: true, destinationDir: destinationDir}
+	buildOpts := opts.BuildOptions
+	if buildOpts.Image == "" {
+		// runs don't share caches, so that a buildpack doesn't pass thanks to the layers of another run
+		id := randString(10)
+		buildOpts.Image = fmt.Sprintf("%s/%s", BuildpackTestImageName, id)
+		volumes := ephemeralVolumeCaches(&buildOpts.Cache, "buildpack-test-"+id)
+		defer c.removeVolumes(volumes)
+		partial.ephemeralCaches = true
+	}
+	buildOpts.Publish = false
+	buildOpts.Buildpacks = []string{bpPath}
+
+	phaseErrors := map[string]string{}
+	var mu sync.Mutex
+	handler := buildOpts.EventHandler
+	buildOpts.EventHandler = func(e events.Event) {
+		if e.Type == events.PhaseFinished {
+			mu.Lock()
+			phaseErrors[e.Phase] = e.Error
+			mu.Unlock()
+		}
+		if handler != nil {
+			handler(e)
+		}
+	}
+
+	started := time.Now()
+	buildErr := c.build(ctx, buildOpts, partial)
+
+	result := &BuildpackTestResult{
+		Buildpack: descriptor.Info().FullName(),
+		App:       opts.BuildOptions.AppPath,
+		Duration:  time.Since(started),
+	}
+
+	detectErr, detectRan := phaseErrors["detector"]
+	if !detectRan && buildErr != nil {
+		return result, errors.Wrap(buildErr, "running detect")
+	}
+
+	expectDetect := config.Detect == nil || *config.Detect
+	detectCase := BuildpackTestCase{Name: "detect"}
+	switch {
+	case expectDetect && detectErr != "":
+		detectCase.Failure = fmt.Sprintf("expected detection to pass, but it failed: %s", detectErr)
+	case !expectDetect && detectErr == "":
+		detectCase.Failure = "expected detection to fail, but it passed"
+	}
+	result.Cases = append(result.Cases, detectCase)
+	if !expectDetect {
+		return result, nil
+	}
+
+	buildCase := BuildpackTestCase{Name: "build"}
+	switch {
+	case detectErr != "":
+		buildCase.Skipped = "detection failed"
+	case buildErr != nil:
+		buildCase.Failure = fmt.Sprintf("expected the build to pass, but it failed: %s", buildErr)
+	}
+	result.Cases = append(result.Cases, buildCase)
+
+	layersDir := filepath.Join(destinationDir, "layers")
+	checks := config.checks(layersDir, descriptor.EscapedID(), descriptor.Info().ID)
+	for _, check := range checks {
+		testCase := BuildpackTestCase{Name: check.name}
+		switch {
+		case buildCase.Skipped != "":
+			testCase.Skipped = buildCase.Skipped
+		case buildCase.Failure != "":
+			testCase.Skipped = "the build failed"
+		default:
+			testCase.Failure = check.run()
+		}
+		result.Cases = append(result.Cases, testCase)
+	}
+
+	return result, nil
+}
+
+// readBuildpackTestConfig reads the expectations of a buildpack test from path. If optional is set,
+// a missing file results in the default expectations.
+func readBuildpackTestConfig(path string, optional bool) (BuildpackTestConfig, error) {
+	config := BuildpackTestConfig{}
+	if _, err := os.Stat(path); os.IsNotExist(err) && optional {
+		return config, nil
+	}
+
+	md, err := toml.DecodeFile(path, &config)
+	if err != nil {
+		return config, errors.Wrapf(err, "reading buildpack test config %s", style.Symbol(path))
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var keys []string
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return config, errors.Errorf("unknown keys in buildpack test config %s: %s", style.Symbol(path), strings.Join(keys, ", "))
+	}
+	for i, process := range config.Processes {
+		if process.Type == "" {
+			return config, errors.Errorf("process #%d in buildpack test config %s has no type", i+1, style.Symbol(path))
+		}
+	}
+	return config, nil
+}
+
+type buildpackTestCheck struct {
+	name string
+	// run returns why the check failed, or an empty string if it passed
+	run func() string
+}
+
+// checks returns the expectations on the layers dir written by the build, in the order they are declared
+func (t BuildpackTestConfig) checks(layersDir, escapedID, id string) []buildpackTestCheck {
+	bpLayersDir := filepath.Join(layersDir, escapedID)
+
+	var checks []buildpackTestCheck
+	for _, layer := range t.Layers {
+		checks = append(checks, buildpackTestCheck{
+			name: "layer " + layer,
+			run: func() string {
+				if _, err := os.Stat(filepath.Join(bpLayersDir, layer+".toml")); err != nil {
+					return fmt.Sprintf("expected layer %s to be contributed", style.Symbol(layer))
+				}
+				return ""
+			},
+		})
+	}
+
+	var names []string
+	for name := range t.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := t.Env[name]
+		checks = append(checks, buildpackTestCheck{
+			name: "env " + name,
+			run: func() string {
+				values := layerEnvValues(bpLayersDir, name)
+				for _, actual := range values {
+					if actual == value {
+						return ""
+					}
+				}
+				if len(values) == 0 {
+					return fmt.Sprintf("expected %s to be set to %s, but it isn't set", style.Symbol(name), style.Symbol(value))
+				}
+				return fmt.Sprintf("expected %s to be set to %s, but it is set to %s", style.Symbol(name), style.Symbol(value), style.Symbol(strings.Join(values, "', '")))
+			},
+		})
+	}
+
+	for _, expected := range t.Processes {
+		checks = append(checks, buildpackTestCheck{
+			name: "process " + expected.Type,
+			run: func() string {
+				metadata := files.BuildMetadata{}
+				if _, err := toml.DecodeFile(filepath.Join(layersDir, "config", "metadata.toml"), &metadata); err != nil {
+					return fmt.Sprintf("reading build metadata: %s", err)
+				}
+				for _, process := range metadata.Processes {
+					if process.BuildpackID != id || process.Type != expected.Type {
+						continue
+					}
+					command := strings.Join(append(append([]string{}, process.Command.Entries...), process.Args...), " ")
+					if expected.Command != "" && command != expected.Command {
+						return fmt.Sprintf("expected process %s to run %s, but it runs %s", style.Symbol(expected.Type), style.Symbol(expected.Command), style.Symbol(command))
+					}
+					return ""
+				}
+				return fmt.Sprintf("expected process %s to be defined", style.Symbol(expected.Type))
+			},
+		})
+	}
+	return checks
+}
+
+// layerEnvValues returns the values of an environment variable in the env directories of every layer in
+// bpLayersDir, whatever the modifier
+func layerEnvValues(bpLayersDir, name string) []string {
+	var values []string
+	for _, envDir := range []string{"env", "env.build", "env.launch"} {
+		for _, fileName := range []string{name, name + ".default", name + ".override", name + ".append", name + ".prepend"} {
+			matches, err := filepath.Glob(filepath.Join(bpLayersDir, "*", envDir, fileName))
+			if err != nil {
+				continue
+			}
+			for _, match := range matches {
+				contents, err := os.ReadFile(filepath.Clean(match))
+				if err != nil {
+					continue
+				}
+				values = append(values, string(contents))
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,290 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+	ignore "github.com/sabhiram/go-gitignore"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/events"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+)
+
+const (
+	defaultWatchDebounce     = 500 * time.Millisecond
+	defaultWatchPollInterval = time.Second
+)
+
+// WatchBuildOptions define how an app is built and rebuilt when its source changes.
+type WatchBuildOptions struct {
+	BuildOptions
+
+	// Debounce is how long the app directory must remain unchanged before a rebuild starts.
+	Debounce time.Duration
+
+	// PollInterval is how often the app directory is checked for changes, every second by default.
+	PollInterval time.Duration
+
+	// CycleHandler, when set, is called after each build with its outcome.
+	CycleHandler func(WatchCycle)
+}
+
+// WatchCycle describes a single build run by WatchBuild.
+type WatchCycle struct {
+	// Number starts at 1 for the initial build
+	Number int
+	// Changed lists the paths, relative to the app directory, whose changes triggered the build
+	Changed []string
+	// Rebuilt lists the app image layers that were exported anew
+	Rebuilt []string
+	// Reused lists the app image layers that were reused from the previous image
+	Reused   []string
+	Duration time.Duration
+	// Err is the build error, if the build failed
+	Err error
+}
+
+// WatchBuild builds an app image, then watches the app directory and rebuilds the image each time the files that
+// would be included in the app change. All builds share the same network and caches.
+// It returns once ctx is cancelled; failed builds are reported to the CycleHandler rather than stopping the watch.
+func (c *Client) WatchBuild(ctx context.Context, opts WatchBuildOptions) error {
+	return c.watchBuild(ctx, opts, c.Build)
+}
+
+func (c *Client) watchBuild(ctx context.Context, opts WatchBuildOptions, build func(context.Context, BuildOptions) error) error {
+	appPath, err := c.processAppPath(opts.AppPath)
+	if err != nil {
+		return errors.Wrapf(err, "invalid app path '%s'", opts.AppPath)
+	}
+	if fi, err := os.Stat(appPath); err != nil || !fi.IsDir() {
+		return errors.Errorf("app path %s must be a directory to watch it", style.Symbol(opts.AppPath))
+	}
+
+	fileFilter, err := getFileFilter(opts.ProjectDescriptor)
+	if err != nil {
+		return err
+	}
+	excludedDir := getExcludedDirFilter(opts.ProjectDescriptor)
+	snapshotApp := func() (map[string]fileState, error) {
+		return snapshotAppDir(appPath, fileFilter, excludedDir)
+	}
+
+	if opts.Debounce <= 0 {
+		opts.Debounce = defaultWatchDebounce
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultWatchPollInterval
+	}
+
+	if opts.ContainerConfig.Network == "" {
+		network, err := c.createWatchNetwork(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.docker.NetworkRemove(context.Background(), network, dockerClient.NetworkRemoveOptions{})
+		opts.ContainerConfig.Network = network
+	}
+
+	snapshot, err := snapshotApp()
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	for number := 1; ; number++ {
+		cycle := c.runWatchCycle(ctx, opts.BuildOptions, build)
+		if ctx.Err() != nil {
+			return nil
+		}
+		cycle.Number = number
+		cycle.Changed = changed
+		if opts.CycleHandler != nil {
+			opts.CycleHandler(cycle)
+		}
+		// the cache only needs to be cleared once
+		opts.ClearCache = false
+
+		c.logger.Infof("Watching %s for changes", style.Symbol(appPath))
+		snapshot, changed, err = waitForChanges(ctx, snapshotApp, snapshot, opts.PollInterval, opts.Debounce)
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		c.logger.Infof("Detected changes to %s, rebuilding", summarizePaths(changed))
+	}
+}
+
+func (c *Client) runWatchCycle(ctx context.Context, opts BuildOptions, build func(context.Context, BuildOptions) error) WatchCycle {
+	var (
+		mu    sync.Mutex
+		cycle WatchCycle
+	)
+	// the layers are collected apart from the event handler, so the build is logged as it would be without watching
+	opts.layerHandler = func(e events.Event) {
+		if e.Cache {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch e.Type {
+		case events.LayerAdded:
+			cycle.Rebuilt = append(cycle.Rebuilt, e.Layer)
+		case events.LayerReused:
+			cycle.Reused = append(cycle.Reused, e.Layer)
+		}
+	}
+
+	start := time.Now()
+	cycle.Err = build(ctx, opts)
+	cycle.Duration = time.Since(start)
+	return cycle
+}
+
+func (c *Client) createWatchNetwork(ctx context.Context) (string, error) {
+	driver := "bridge"
+	if info, err := c.docker.Info(ctx, dockerClient.InfoOptions{}); err == nil && info.Info.OSType == "windows" {
+		driver = "nat"
+	}
+
+	network := fmt.Sprintf("pack.watch-network-%x", randString(10))
+	if _, err := c.docker.NetworkCreate(ctx, network, dockerClient.NetworkCreateOptions{Driver: driver}); err != nil {
+		return "", errors.Wrapf(err, "failed to create ephemeral %s network", driver)
+	}
+	c.logger.Debugf("Created ephemeral %s network %s for all builds", driver, style.Symbol(network))
+	return network, nil
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+}
+
+// getExcludedDirFilter returns a filter matching the directories of the app whose contents are all excluded from the
+// build by the project descriptor, so that they aren't walked. It returns nil when files may be re-included by
+// negated exclusions, or are selected by inclusions.
+func getExcludedDirFilter(descriptor projectTypes.Descriptor) func(string) bool {
+	if len(descriptor.Build.Exclude) == 0 {
+		return nil
+	}
+	for _, pattern := range descriptor.Build.Exclude {
+		if strings.HasPrefix(strings.TrimSpace(pattern), "!") {
+			return nil
+		}
+	}
+	excludes := ignore.CompileIgnoreLines(descriptor.Build.Exclude...)
+	return func(dir string) bool {
+		// with a trailing slash, a directory matches the patterns matching everything in it, like 'dir/' or 'dir/**'
+		return excludes.MatchesPath(dir + "/")
+	}
+}
+
+// snapshotAppDir records the state of the files in appPath that pass fileFilter, the same way they are selected
+// when the app is added to the build container. Directories matching excludedDir aren't walked.
+func snapshotAppDir(appPath string, fileFilter, excludedDir func(string) bool) (map[string]fileState, error) {
+	snapshot := map[string]fileState{}
+	err := filepath.Walk(appPath, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// removed while walking
+				return nil
+			}
+			return err
+		}
+		relPath, err := filepath.Rel(appPath, file)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if relPath != "." && excludedDir != nil && excludedDir(filepath.ToSlash(relPath)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fileFilter != nil && !fileFilter(relPath) {
+			return nil
+		}
+		snapshot[filepath.ToSlash(relPath)] = fileState{modTime: fi.ModTime(), size: fi.Size(), mode: fi.Mode()}
+		return nil
+	})
+	return snapshot, errors.Wrapf(err, "reading app directory %s", style.Symbol(appPath))
+}
+
+// waitForChanges polls the app directory with snapshotApp until it differs from previous, then waits until it stops
+// changing for debounce
+func waitForChanges(ctx context.Context, snapshotApp func() (map[string]fileState, error), previous map[string]fileState, interval, debounce time.Duration) (map[string]fileState, []string, error) {
+	var (
+		pending    bool
+		current    = previous
+		lastChange time.Time
+		ticker     = time.NewTicker(interval)
+	)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return current, nil, nil
+		case <-ticker.C:
+		}
+
+		next, err := snapshotApp()
+		if err != nil {
+			return current, nil, err
+		}
+		if len(diffSnapshots(current, next)) > 0 {
+			pending = true
+			current = next
+			lastChange = time.Now()
+			continue
+		}
+
+		if pending && time.Since(lastChange) >= debounce {
+			if paths := diffSnapshots(previous, current); len(paths) > 0 {
+				return current, paths, nil
+			}
+			// the changes were reverted
+			pending = false
+		}
+	}
+}
+
+func diffSnapshots(before, after map[string]fileState) []string {
+	var paths []string
+	for path, state := range after {
+		if prev, ok := before[path]; !ok || prev != state {
+			paths = append(paths, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func summarizePaths(paths []string) string {
+	const maxListed = 3
+	var listed []string
+	for _, path := range paths {
+		if len(listed) == maxListed {
+			return fmt.Sprintf("%s and %d more", strings.Join(listed, ", "), len(paths)-maxListed)
+		}
+		listed = append(listed, style.Symbol(path))
+	}
+	return strings.Join(listed, ", ")
+}
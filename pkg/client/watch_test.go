@@ -0,0 +1,253 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/heroku/color"
+	"github.com/moby/moby/api/types/system"
+	dockerClient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/events"
+	"github.com/buildpacks/pack/pkg/logging"
+	projectTypes "github.com/buildpacks/pack/pkg/project/types"
+	"github.com/buildpacks/pack/pkg/testmocks"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestWatchBuild(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "WatchBuild", testWatchBuild, spec.Sequential(), spec.Report(report.Terminal{}))
+}
+
+func testWatchBuild(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject          *Client
+		mockController   *gomock.Controller
+		mockDockerClient *testmocks.MockAPIClient
+		out              bytes.Buffer
+		appDir           string
+		ctx              context.Context
+		cancel           context.CancelFunc
+		builds           []BuildOptions
+		cycles           []WatchCycle
+		opts             WatchBuildOptions
+	)
+
+	it.Before(func() {
+		mockController = gomock.NewController(t)
+		mockDockerClient = testmocks.NewMockAPIClient(mockController)
+
+		var err error
+		subject, err = NewClient(WithLogger(logging.NewLogWithWriters(&out, &out)), WithDockerClient(mockDockerClient))
+		h.AssertNil(t, err)
+
+		appDir = t.TempDir()
+		h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "app.js"), []byte("console.log('hi')"), 0600))
+
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		builds = nil
+		cycles = nil
+		opts = WatchBuildOptions{
+			BuildOptions: BuildOptions{
+				AppPath:         appDir,
+				Image:           "my/app",
+				ClearCache:      true,
+				ContainerConfig: ContainerConfig{Network: "some-network"},
+			},
+			Debounce:     20 * time.Millisecond,
+			PollInterval: 5 * time.Millisecond,
+		}
+	})
+
+	it.After(func() {
+		cancel()
+		mockController.Finish()
+	})
+
+	// buildAndChange records each build and stops watching after the given number of builds.
+	// After each of the earlier builds it runs change, so the app is rebuilt.
+	buildAndChange := func(stopAfter int, change func()) func(context.Context, BuildOptions) error {
+		opts.CycleHandler = func(cycle WatchCycle) {
+			cycles = append(cycles, cycle)
+			if len(cycles) == stopAfter {
+				cancel()
+				return
+			}
+			change()
+		}
+		return func(_ context.Context, buildOpts BuildOptions) error {
+			builds = append(builds, buildOpts)
+			for _, e := range []events.Event{
+				{Type: events.LayerAdded, Layer: "my/buildpack:app"},
+				{Type: events.LayerReused, Layer: "my/buildpack:deps"},
+				{Type: events.LayerAdded, Layer: "my/buildpack:cache", Cache: true},
+			} {
+				if buildOpts.EventHandler != nil {
+					buildOpts.EventHandler(e)
+				}
+				buildOpts.layerHandler(e)
+			}
+			return nil
+		}
+	}
+
+	writeApp := func(name, contents string) func() {
+		return func() {
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, name), []byte(contents), 0600))
+		}
+	}
+
+	it("rebuilds when the app changes", func() {
+		build := buildAndChange(2, writeApp("app.js", "console.log('hello')"))
+
+		h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+		h.AssertEq(t, len(builds), 2)
+		h.AssertEq(t, cycles[0].Number, 1)
+		h.AssertEq(t, len(cycles[0].Changed), 0)
+		h.AssertEq(t, cycles[1].Number, 2)
+		h.AssertEq(t, cycles[1].Changed, []string{"app.js"})
+		h.AssertContains(t, out.String(), "Detected changes to 'app.js', rebuilding")
+	})
+
+	it("reports which layers were rebuilt and reused", func() {
+		build := buildAndChange(1, func() {})
+
+		h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+		h.AssertEq(t, cycles[0].Rebuilt, []string{"my/buildpack:app"})
+		h.AssertEq(t, cycles[0].Reused, []string{"my/buildpack:deps"})
+	})
+
+	it("doesn't set an event handler to collect the layers", func() {
+		build := buildAndChange(1, func() {})
+
+		h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+		h.AssertTrue(t, builds[0].EventHandler == nil)
+	})
+
+	it("only clears the cache for the first build", func() {
+		build := buildAndChange(2, writeApp("app.js", "console.log('hello')"))
+
+		h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+		h.AssertEq(t, builds[0].ClearCache, true)
+		h.AssertEq(t, builds[1].ClearCache, false)
+	})
+
+	it("passes events on to the event handler", func() {
+		var received []events.Event
+		opts.EventHandler = func(e events.Event) {
+			received = append(received, e)
+		}
+		build := buildAndChange(1, func() {})
+
+		h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+		h.AssertEq(t, len(received), 3)
+	})
+
+	when("files are excluded by the project descriptor", func() {
+		it("ignores changes to them", func() {
+			opts.ProjectDescriptor = projectTypes.Descriptor{Build: projectTypes.Build{Exclude: []string{"*.log"}}}
+			build := buildAndChange(2, func() {
+				writeApp("debug.log", "some-output")()
+				time.Sleep(50 * time.Millisecond)
+				writeApp("app.js", "console.log('hello')")()
+			})
+
+			h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+			h.AssertEq(t, cycles[1].Changed, []string{"app.js"})
+		})
+
+		it("doesn't walk excluded directories", func() {
+			h.AssertNil(t, os.MkdirAll(filepath.Join(appDir, "node_modules", "dep"), 0700))
+			h.AssertNil(t, os.WriteFile(filepath.Join(appDir, "node_modules", "dep", "index.js"), []byte("module.exports = {}"), 0600))
+			descriptor := projectTypes.Descriptor{Build: projectTypes.Build{Exclude: []string{"node_modules/"}}}
+			fileFilter, err := getFileFilter(descriptor)
+			h.AssertNil(t, err)
+
+			var filtered []string
+			snapshot, err := snapshotAppDir(appDir, func(path string) bool {
+				filtered = append(filtered, filepath.ToSlash(path))
+				return fileFilter(path)
+			}, getExcludedDirFilter(descriptor))
+			h.AssertNil(t, err)
+
+			h.AssertEq(t, filtered, []string{"app.js"})
+			h.AssertEq(t, len(snapshot), 1)
+		})
+
+		it("walks excluded directories when files may be re-included", func() {
+			descriptor := projectTypes.Descriptor{Build: projectTypes.Build{Exclude: []string{"node_modules/", "!node_modules/keep.js"}}}
+			h.AssertTrue(t, getExcludedDirFilter(descriptor) == nil)
+		})
+	})
+
+	when("a build fails", func() {
+		it("reports the failure and keeps watching", func() {
+			opts.CycleHandler = func(cycle WatchCycle) {
+				cycles = append(cycles, cycle)
+				if len(cycles) == 2 {
+					cancel()
+					return
+				}
+				writeApp("app.js", "console.log('fixed')")()
+			}
+			build := func(_ context.Context, _ BuildOptions) error {
+				if len(cycles) == 0 {
+					return errors.New("some-build-error")
+				}
+				return nil
+			}
+
+			h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+			h.AssertError(t, cycles[0].Err, "some-build-error")
+			h.AssertNil(t, cycles[1].Err)
+		})
+	})
+
+	when("no network is provided", func() {
+		it("creates one network for all builds", func() {
+			opts.ContainerConfig.Network = ""
+			mockDockerClient.EXPECT().Info(gomock.Any(), gomock.Any()).Return(dockerClient.SystemInfoResult{Info: system.Info{OSType: "linux"}}, nil)
+			var network string
+			mockDockerClient.EXPECT().
+				NetworkCreate(gomock.Any(), gomock.Any(), dockerClient.NetworkCreateOptions{Driver: "bridge"}).
+				DoAndReturn(func(_ context.Context, name string, _ dockerClient.NetworkCreateOptions) (dockerClient.NetworkCreateResult, error) {
+					network = name
+					return dockerClient.NetworkCreateResult{}, nil
+				})
+			mockDockerClient.EXPECT().NetworkRemove(gomock.Any(), gomock.Any(), gomock.Any()).Return(dockerClient.NetworkRemoveResult{}, nil)
+			build := buildAndChange(2, writeApp("app.js", "console.log('hello')"))
+
+			h.AssertNil(t, subject.watchBuild(ctx, opts, build))
+
+			h.AssertContains(t, network, "pack.watch-network-")
+			h.AssertEq(t, builds[0].ContainerConfig.Network, network)
+			h.AssertEq(t, builds[1].ContainerConfig.Network, network)
+		})
+	})
+
+	when("the app path is a file", func() {
+		it("errors", func() {
+			opts.AppPath = filepath.Join(appDir, "app.js")
+
+			err := subject.watchBuild(ctx, opts, nil)
+			h.AssertNotNil(t, err)
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"runtime"
 
+	"github.com/buildpacks/pack/internal/config"
 	"github.com/buildpacks/pack/internal/registry"
 )
 
@@ -24,10 +25,6 @@ func (c *Client) YankBuildpack(opts YankBuildpackOptions) error {
 	if err != nil {
 		return err
 	}
-	issueURL, err := registry.GetIssueURL(opts.URL)
-	if err != nil {
-		return err
-	}
 
 	buildpack := registry.Buildpack{
 		Namespace: namespace,
@@ -36,6 +33,19 @@ func (c *Client) YankBuildpack(opts YankBuildpackOptions) error {
 		Yanked:    opts.Yank,
 	}
 
+	if opts.Type == "oci" {
+		registryCache, err := newRegistryCache(c.logger, c.keychain, config.Registry{Type: opts.Type, URL: opts.URL}, nil)
+		if err != nil {
+			return err
+		}
+		return registryCache.YankBuildpack(buildpack)
+	}
+
+	issueURL, err := registry.GetIssueURL(opts.URL)
+	if err != nil {
+		return err
+	}
+
 	issue, err := registry.CreateGithubIssue(buildpack)
 	if err != nil {
 		return err
@@ -15,6 +15,8 @@ const (
 
 type BuildpackURI struct {
 	URI string `toml:"uri"`
+	// SHA256 is the checksum the module downloaded from URI must match
+	SHA256 string `toml:"sha256,omitempty"`
 }
 
 type ImageRef struct {
@@ -0,0 +1,80 @@
+// Package events defines the structured events emitted while building an app image.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	// PhaseStarted is emitted when a lifecycle phase container is about to run.
+	PhaseStarted Type = "phase-started"
+	// PhaseFinished is emitted when a lifecycle phase container exits, whether or not it succeeded.
+	PhaseFinished Type = "phase-finished"
+	// BuildpackDetected is emitted for each buildpack result reported by the detector.
+	BuildpackDetected Type = "buildpack-detected"
+	// GroupSelected is emitted once the detector has selected a group of buildpacks, read from the group.toml it writes.
+	GroupSelected Type = "group-selected"
+	// LayerRestored is emitted when layer data or metadata is restored.
+	LayerRestored Type = "layer-restored"
+	// LayerReused is emitted when a layer from a previous image or the cache is reused.
+	LayerReused Type = "layer-reused"
+	// LayerAdded is emitted when a new layer is exported.
+	LayerAdded Type = "layer-added"
+	// CacheMissed is emitted when no layer cache could be found.
+	CacheMissed Type = "cache-missed"
+	// BuildFinished is emitted once the app image has been exported.
+	BuildFinished Type = "build-finished"
+)
+
+const (
+	ResultPass  = "pass"
+	ResultFail  = "fail"
+	ResultSkip  = "skip"
+	ResultError = "error"
+)
+
+const (
+	SourceAppImage = "app-image"
+	SourceCache    = "cache"
+)
+
+// Event describes something that happened during a build. Only the fields relevant to the event Type are populated.
+// The detector only logs the result of each buildpack at debug level. Otherwise, BuildpackDetected events are only
+// emitted for the buildpacks of the selected group, as passing, without a Group or Output.
+type Event struct {
+	Type       Type      `json:"type"`
+	Time       time.Time `json:"time"`
+	Phase      string    `json:"phase,omitempty"`
+	DurationMS int64     `json:"durationMs,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Group      int       `json:"group,omitempty"`
+	Buildpack  string    `json:"buildpack,omitempty"`
+	Buildpacks []string  `json:"buildpacks,omitempty"`
+	Result     string    `json:"result,omitempty"`
+	Output     string    `json:"output,omitempty"`
+	Layer      string    `json:"layer,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	Cache      bool      `json:"cache,omitempty"`
+	Image      string    `json:"image,omitempty"`
+	Digest     string    `json:"digest,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+}
+
+// Handler receives build events. Handlers may be called concurrently when phases run in parallel.
+type Handler func(Event)
+
+// NewJSONLHandler returns a Handler that writes each event as a single line of JSON to w
+func NewJSONLHandler(w io.Writer) Handler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = encoder.Encode(e)
+	}
+}
@@ -0,0 +1,34 @@
+package events_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/events"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestEvents(t *testing.T) {
+	spec.Run(t, "Events", testEvents, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testEvents(t *testing.T, when spec.G, it spec.S) {
+	when("#NewJSONLHandler", func() {
+		it("writes each event on its own line", func() {
+			var buf bytes.Buffer
+			handler := events.NewJSONLHandler(&buf)
+			eventTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			handler(events.Event{Type: events.PhaseFinished, Time: eventTime, Phase: "builder", DurationMS: 1500})
+			handler(events.Event{Type: events.BuildFinished, Time: eventTime, Image: "some/image", Digest: "sha256:abc", Tags: []string{"some/image:tag"}})
+
+			h.AssertEq(t, buf.String(),
+				`{"type":"phase-finished","time":"2024-01-02T03:04:05Z","phase":"builder","durationMs":1500}`+"\n"+
+					`{"type":"build-finished","time":"2024-01-02T03:04:05Z","image":"some/image","digest":"sha256:abc","tags":["some/image:tag"]}`+"\n")
+		})
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/buildpacks/lifecycle/auth"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/moby/moby/client"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ import (
 	"github.com/buildpacks/pack/internal/term"
 	"github.com/buildpacks/pack/pkg/dist"
 	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
 )
 
 // FetcherOption is a type of function that mutate settings on the client.
@@ -51,6 +53,13 @@ func WithKeychain(keychain authn.Keychain) FetcherOption {
 	}
 }
 
+// WithMirror supply the local mirror that images are fetched from in offline mode.
+func WithMirror(m *mirror.Mirror) FetcherOption {
+	return func(c *Fetcher) {
+		c.mirror = m
+	}
+}
+
 type DockerClient interface {
 	local.DockerClient
 	ImagePull(ctx context.Context, ref string, options client.ImagePullOptions) (client.ImagePullResponse, error)
@@ -61,6 +70,7 @@ type Fetcher struct {
 	logger          logging.Logger
 	registryMirrors map[string]string
 	keychain        authn.Keychain
+	mirror          *mirror.Mirror
 }
 
 type FetchOptions struct {
@@ -88,6 +98,10 @@ func NewFetcher(logger logging.Logger, docker DockerClient, opts ...FetcherOptio
 var ErrNotFound = errors.New("not found")
 
 func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions) (imgutil.Image, error) {
+	if f.mirror.IsOffline() {
+		return f.fetchMirrorImage(ctx, name, options)
+	}
+
 	name, err := pname.TranslateRegistry(name, f.registryMirrors, f.logger)
 	if err != nil {
 		return nil, err
@@ -136,6 +150,9 @@ func (f *Fetcher) Fetch(ctx context.Context, name string, options FetchOptions)
 }
 
 func (f *Fetcher) CheckReadAccess(repo string, options FetchOptions) bool {
+	if f.mirror.IsOffline() {
+		return f.checkMirrorReadAccess(repo, options)
+	}
 	if !options.Daemon || options.PullPolicy == PullAlways {
 		return f.checkRemoteReadAccess(repo)
 	}
@@ -215,16 +232,20 @@ func (f *Fetcher) fetchRemoteImage(name string, target *dist.Target, insecureReg
 }
 
 func (f *Fetcher) fetchLayoutImage(name string, options LayoutOption) (imgutil.Image, error) {
-	var (
-		image imgutil.Image
-		err   error
-	)
-
 	v1Image, err := remote.NewV1Image(name, f.keychain)
 	if err != nil {
 		return nil, err
 	}
 
+	return saveLayoutImage(v1Image, options)
+}
+
+func saveLayoutImage(v1Image v1.Image, options LayoutOption) (imgutil.Image, error) {
+	var (
+		image imgutil.Image
+		err   error
+	)
+
 	if options.Sparse {
 		image, err = sparse.NewImage(options.Path, v1Image)
 	} else {
@@ -247,7 +268,8 @@ func (f *Fetcher) fetchLayoutImage(name string, options LayoutOption) (imgutil.I
 // This ensures that multi-platform images are always resolved to the correct platform-specific manifest.
 func (f *Fetcher) FetchForPlatform(ctx context.Context, name string, options FetchOptions) (imgutil.Image, error) {
 	// If no target is specified, fall back to regular fetch
-	if options.Target == nil {
+	// In offline mode, the mirror already holds the image of each platform, so there's no digest to resolve
+	if options.Target == nil || f.mirror.IsOffline() {
 		return f.Fetch(ctx, name, options)
 	}
 
@@ -0,0 +1,123 @@
+package image
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/buildpacks/imgutil"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/mirror"
+)
+
+// fetchMirrorImage fetches an image without network access: images missing from the daemon are loaded into it
+// from the mirror, as pulling them would
+func (f *Fetcher) fetchMirrorImage(ctx context.Context, name string, options FetchOptions) (imgutil.Image, error) {
+	platform := mirrorPlatform(options.Target)
+
+	if (options.LayoutOption != LayoutOption{}) {
+		v1Image, err := f.mirror.Image(name, platform)
+		if err != nil {
+			return nil, err
+		}
+		return saveLayoutImage(v1Image, options.LayoutOption)
+	}
+
+	if !options.Daemon {
+		return nil, errors.Errorf("image %s can't be fetched from a registry in offline mode: use the docker daemon instead of publishing", style.Symbol(name))
+	}
+
+	if options.PullPolicy != PullAlways {
+		img, err := f.fetchDaemonImage(name)
+		if errors.Is(err, ErrNotFound) && name != loadedName(name) {
+			// the image was loaded from the mirror before
+			img, err = f.fetchDaemonImage(loadedName(name))
+		}
+		if err == nil || !errors.Is(err, ErrNotFound) || options.PullPolicy == PullNever {
+			return img, err
+		}
+	}
+
+	v1Image, err := f.mirror.Image(name, platform)
+	if err != nil {
+		if options.PullPolicy == PullAlways && errors.Is(err, mirror.ErrNotMirrored) {
+			// the image can't be refreshed from the mirror, but might have been pulled before going offline
+			if img, daemonErr := f.fetchDaemonImage(name); daemonErr == nil {
+				return img, nil
+			}
+		}
+		return nil, err
+	}
+
+	f.logger.Debugf("Loading image %s from the mirror", style.Symbol(name))
+	if err := f.loadImage(ctx, loadedName(name), v1Image); err != nil {
+		return nil, errors.Wrapf(err, "loading image %s from the mirror", style.Symbol(name))
+	}
+	// the image is named as loaded, so that the daemon resolves its name when it's passed on, e.g. to the lifecycle
+	return f.fetchDaemonImage(loadedName(name))
+}
+
+// loadedName returns the name an image is loaded from the mirror into the daemon as: the daemon doesn't load images
+// by digest, so these are tagged with the digest instead.
+func loadedName(imageName string) string {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return imageName
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.Context().Tag(strings.TrimPrefix(digest.DigestStr(), "sha256:")).String()
+	}
+	return imageName
+}
+
+// loadImage loads v1Image into the daemon, tagged tagName
+func (f *Fetcher) loadImage(ctx context.Context, tagName string, v1Image v1.Image) error {
+	tag, err := name.NewTag(tagName, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(tarball.Write(tag, v1Image, pw))
+	}()
+
+	result, err := f.docker.ImageLoad(ctx, pr, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	defer result.Close()
+	_, err = io.Copy(io.Discard, result)
+	return err
+}
+
+func (f *Fetcher) checkMirrorReadAccess(repo string, options FetchOptions) bool {
+	if _, err := f.fetchDaemonImage(repo); err == nil {
+		return true
+	}
+	if repo != loadedName(repo) {
+		if _, err := f.fetchDaemonImage(loadedName(repo)); err == nil {
+			return true
+		}
+	}
+	if _, err := f.mirror.Image(repo, mirrorPlatform(options.Target)); err != nil {
+		f.logger.Debugf("image %s isn't available offline, error: %s", repo, err.Error())
+		return false
+	}
+	return true
+}
+
+func mirrorPlatform(target *dist.Target) *v1.Platform {
+	if target == nil {
+		return nil
+	}
+	return &v1.Platform{OS: target.OS, Architecture: target.Arch, Variant: target.ArchVariant}
+}
@@ -0,0 +1,84 @@
+package image_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/dist"
+	"github.com/buildpacks/pack/pkg/image"
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/mirror"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestOfflineFetcher(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "OfflineFetcher", testOfflineFetcher, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testOfflineFetcher(t *testing.T, when spec.G, it spec.S) {
+	var (
+		imageFetcher *image.Fetcher
+		localMirror  *mirror.Mirror
+		mirrored     v1.Image
+		tmpDir       string
+		outBuf       bytes.Buffer
+	)
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+		localMirror = mirror.New(filepath.Join(tmpDir, "mirror"))
+
+		var err error
+		mirrored, err = random.Image(100, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, localMirror.AddImage("example/run:1.0", mirrored, v1.Platform{OS: "linux", Architecture: "arm64"}))
+		localMirror.SetOffline(true)
+
+		// no docker client: nothing offline may reach a registry
+		imageFetcher = image.NewFetcher(logging.NewLogWithWriters(&outBuf, &outBuf), nil, image.WithMirror(localMirror))
+	})
+
+	when("#Fetch", func() {
+		when("a layout is requested", func() {
+			it("writes the image from the mirror", func() {
+				layoutPath := filepath.Join(tmpDir, "layout")
+				img, err := imageFetcher.Fetch(context.TODO(), "example/run:1.0", image.FetchOptions{
+					LayoutOption: image.LayoutOption{Path: layoutPath},
+					Target:       &dist.Target{OS: "linux", Arch: "arm64"},
+				})
+				h.AssertNil(t, err)
+
+				layers, err := mirrored.Layers()
+				h.AssertNil(t, err)
+				expectedDiffID, err := layers[0].DiffID()
+				h.AssertNil(t, err)
+				topLayer, err := img.TopLayer()
+				h.AssertNil(t, err)
+				h.AssertEq(t, topLayer, expectedDiffID.String())
+			})
+
+			it("errors when the image isn't mirrored for the target", func() {
+				_, err := imageFetcher.Fetch(context.TODO(), "example/run:1.0", image.FetchOptions{
+					LayoutOption: image.LayoutOption{Path: filepath.Join(tmpDir, "layout")},
+					Target:       &dist.Target{OS: "linux", Arch: "amd64"},
+				})
+				h.AssertError(t, err, "image 'example/run:1.0' isn't in the mirror")
+			})
+		})
+
+		it("errors when the daemon isn't used", func() {
+			_, err := imageFetcher.Fetch(context.TODO(), "example/run:1.0", image.FetchOptions{Daemon: false})
+			h.AssertError(t, err, "image 'example/run:1.0' can't be fetched from a registry in offline mode")
+		})
+	})
+}
@@ -0,0 +1,95 @@
+// Package lock reads and writes lockfiles, which pin the buildpacks, extensions and lifecycles of builder and
+// package configs to the exact contents they resolved to, for their builders and packages to be reproducible.
+package lock
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/blob"
+)
+
+const header = "# Generated by 'pack buildpack lock'. Regenerate it rather than editing it.\n\n"
+
+// File is a lockfile
+type File struct {
+	Entries []Entry `toml:"entries"`
+}
+
+// Entry pins a URI, as written in a config, either to the sha256 checksum of the contents downloaded from it,
+// or to the image it resolved to, by digest
+type Entry struct {
+	URI    string `toml:"uri"`
+	SHA256 string `toml:"sha256,omitempty"`
+	Image  string `toml:"image,omitempty"`
+}
+
+// Read reads the lockfile at path
+func Read(path string) (File, error) {
+	file := File{}
+	md, err := toml.DecodeFile(path, &file)
+	if err != nil {
+		return file, errors.Wrapf(err, "reading lockfile %s", style.Symbol(path))
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var keys []string
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return file, errors.Errorf("unknown keys in lockfile %s: %s", style.Symbol(path), strings.Join(keys, ", "))
+	}
+	for _, entry := range file.Entries {
+		if entry.URI == "" || (entry.SHA256 == "") == (entry.Image == "") {
+			return file, errors.Errorf("invalid entry %s in lockfile %s: it must have a uri, and either a sha256 or an image", style.Symbol(entry.URI), style.Symbol(path))
+		}
+	}
+	return file, nil
+}
+
+// Write writes the lockfile to path, with its entries sorted by URI
+func (f File) Write(path string) error {
+	sort.Slice(f.Entries, func(i, j int) bool {
+		return f.Entries[i].URI < f.Entries[j].URI
+	})
+
+	buf := bytes.NewBufferString(header)
+	if err := toml.NewEncoder(buf).Encode(f); err != nil {
+		return errors.Wrap(err, "encoding lockfile")
+	}
+	return os.WriteFile(filepath.Clean(path), buf.Bytes(), 0644)
+}
+
+// Add adds entry to the lockfile, replacing the entry of the same URI
+func (f *File) Add(entry Entry) {
+	for i := range f.Entries {
+		if f.Entries[i].URI == entry.URI {
+			f.Entries[i] = entry
+			return
+		}
+	}
+	f.Entries = append(f.Entries, entry)
+}
+
+// Pin returns uri pinned to what it resolved to when the lockfile was generated: a URI ending with the sha256
+// checksum of its contents, or a `docker://` reference to an image by digest. A URI that isn't in the lockfile
+// is returned as is.
+func (f File) Pin(uri string) (string, error) {
+	base, _ := blob.SplitSHA256(uri)
+	for _, entry := range f.Entries {
+		if entry.URI != base {
+			continue
+		}
+		if entry.Image != "" {
+			return "docker://" + entry.Image, nil
+		}
+		return blob.WithSHA256(uri, entry.SHA256)
+	}
+	return uri, nil
+}
@@ -0,0 +1,114 @@
+package lock_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/lock"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestLock(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Lock", testLock, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testLock(t *testing.T, when spec.G, it spec.S) {
+	var (
+		tmpDir   string
+		checksum = strings.Repeat("ab", 32)
+	)
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+	})
+
+	when("#Write", func() {
+		it("writes a lockfile that can be read back", func() {
+			path := filepath.Join(tmpDir, "builder.lock.toml")
+			file := lock.File{}
+			file.Add(lock.Entry{URI: "https://example.com/b.tgz", SHA256: checksum})
+			file.Add(lock.Entry{URI: "docker://example/a", Image: "index.docker.io/example/a@sha256:" + checksum})
+			h.AssertNil(t, file.Write(path))
+
+			contents, err := os.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertContains(t, string(contents), "# Generated by 'pack buildpack lock'")
+
+			read, err := lock.Read(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, read.Entries, []lock.Entry{
+				{URI: "docker://example/a", Image: "index.docker.io/example/a@sha256:" + checksum},
+				{URI: "https://example.com/b.tgz", SHA256: checksum},
+			})
+		})
+	})
+
+	when("#Read", func() {
+		it("errors on unknown keys", func() {
+			path := filepath.Join(tmpDir, "builder.lock.toml")
+			h.AssertNil(t, os.WriteFile(path, []byte("[[entries]]\nuri = \"a\"\nsha = \"b\"\n"), 0600))
+
+			_, err := lock.Read(path)
+			h.AssertError(t, err, "unknown keys in lockfile")
+		})
+
+		it("errors on entries without a sha256 or an image", func() {
+			path := filepath.Join(tmpDir, "builder.lock.toml")
+			h.AssertNil(t, os.WriteFile(path, []byte("[[entries]]\nuri = \"a\"\n"), 0600))
+
+			_, err := lock.Read(path)
+			h.AssertError(t, err, "invalid entry 'a'")
+		})
+	})
+
+	when("#Add", func() {
+		it("replaces the entry of the same uri", func() {
+			file := lock.File{}
+			file.Add(lock.Entry{URI: "a", SHA256: checksum})
+			file.Add(lock.Entry{URI: "a", Image: "example/a@sha256:" + checksum})
+			h.AssertEq(t, file.Entries, []lock.Entry{{URI: "a", Image: "example/a@sha256:" + checksum}})
+		})
+	})
+
+	when("#Pin", func() {
+		var file lock.File
+
+		it.Before(func() {
+			file = lock.File{Entries: []lock.Entry{
+				{URI: "https://example.com/bp.tgz", SHA256: checksum},
+				{URI: "example/bp:1.0", Image: "index.docker.io/example/bp@sha256:" + checksum},
+			}}
+		})
+
+		it("pins uris to their sha256", func() {
+			uri, err := file.Pin("https://example.com/bp.tgz")
+			h.AssertNil(t, err)
+			h.AssertEq(t, uri, "https://example.com/bp.tgz#sha256="+checksum)
+		})
+
+		it("pins images to their digest", func() {
+			uri, err := file.Pin("example/bp:1.0")
+			h.AssertNil(t, err)
+			h.AssertEq(t, uri, "docker://index.docker.io/example/bp@sha256:"+checksum)
+		})
+
+		it("errors when the sha256 of the uri conflicts with the lockfile", func() {
+			_, err := file.Pin("https://example.com/bp.tgz#sha256=" + strings.Repeat("0", 64))
+			h.AssertError(t, err, "doesn't match the sha256")
+		})
+
+		it("returns uris that aren't locked as is", func() {
+			uri, err := file.Pin("https://example.com/other.tgz")
+			h.AssertNil(t, err)
+			h.AssertEq(t, uri, "https://example.com/other.tgz")
+		})
+	})
+}
@@ -36,10 +36,11 @@ var _ Logger = (*LogWithWriters)(nil)
 type LogWithWriters struct {
 	sync.Mutex
 	log.Logger
-	wantTime bool
-	clock    func() time.Time
-	out      io.Writer
-	errOut   io.Writer
+	wantTime   bool
+	wantStderr bool
+	clock      func() time.Time
+	out        io.Writer
+	errOut     io.Writer
 }
 
 // NewLogWithWriters creates a logger to be used with pack CLI.
@@ -93,7 +94,7 @@ func (lw *LogWithWriters) WriterForLevel(level Level) io.Writer {
 		return io.Discard
 	}
 
-	if level == ErrorLevel {
+	if level == ErrorLevel || lw.wantStderr {
 		return newLogWriter(lw.errOut, lw.clock, lw.wantTime)
 	}
 
@@ -110,6 +111,16 @@ func (lw *LogWithWriters) WantTime(f bool) {
 	lw.wantTime = f
 }
 
+// WantStderr sends log entries of every level to the error writer, leaving Writer to the output of the command
+func (lw *LogWithWriters) WantStderr(f bool) {
+	lw.wantStderr = f
+}
+
+// UsesStderr returns whether log entries of every level are sent to the error writer
+func (lw *LogWithWriters) UsesStderr() bool {
+	return lw.wantStderr
+}
+
 // WantQuiet reduces the number of logs returned
 func (lw *LogWithWriters) WantQuiet(f bool) {
 	if f {
@@ -170,6 +170,34 @@ func testLogWithWriters(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("stderr is set to true", func() {
+		it.Before(func() {
+			logger.WantStderr(true)
+		})
+
+		it("logs every message to error writer", func() {
+			logger.Info("info_")
+			logger.Warn("warn_")
+			logger.Error("error_")
+
+			h.AssertEq(t, fOut(), "")
+			output := fErr()
+			h.AssertContains(t, output, "info_\n")
+			h.AssertContains(t, output, "warn_\n")
+			h.AssertContains(t, output, "error_\n")
+		})
+
+		it("will return correct writers", func() {
+			h.AssertSameInstance(t, logger.Writer(), outCons)
+			assertLogWriterHasOut(t, logger.WriterForLevel(logging.InfoLevel), errCons)
+		})
+
+		it("reports it", func() {
+			h.AssertTrue(t, logger.UsesStderr())
+			h.AssertTrue(t, logging.UsesStderr(logger))
+		})
+	})
+
 	when("verbose is set to true", func() {
 		it.Before(func() {
 			logger.WantVerbose(true)
@@ -50,6 +50,31 @@ func GetWriterForLevel(logger Logger, level Level) io.Writer {
 	return logger.Writer()
 }
 
+type isRedirectable interface {
+	WantStderr(f bool)
+	UsesStderr() bool
+}
+
+// UseStderr sends the log entries of a pack logger to its error writer, so that its Writer only receives the
+// output of the command, such as a JSON document. Loggers that can't be redirected are left as they are.
+//
+// See isRedirectable
+func UseStderr(logger Logger) {
+	if r, ok := logger.(isRedirectable); ok {
+		r.WantStderr(true)
+	}
+}
+
+// UsesStderr returns whether a pack logger sends its log entries to its error writer
+//
+// See UseStderr
+func UsesStderr(logger Logger) bool {
+	if r, ok := logger.(isRedirectable); ok {
+		return r.UsesStderr()
+	}
+	return false
+}
+
 // IsQuiet defines whether a pack logger is set to quiet mode
 func IsQuiet(logger Logger) bool {
 	if writer := GetWriterForLevel(logger, InfoLevel); writer == io.Discard {
@@ -0,0 +1,47 @@
+// Package metrics defines the timing and resource metrics recorded for each lifecycle phase of a build.
+package metrics
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the file metrics are written to alongside report.toml
+const FileName = "build-metrics.json"
+
+// Phase holds the metrics recorded for a single lifecycle phase container
+type Phase struct {
+	Name       string    `json:"name"`
+	StartTime  time.Time `json:"startTime"`
+	DurationMS int64     `json:"durationMs"`
+	// ExitCode is the exit code of the phase container, or -1 if the container did not exit
+	ExitCode int `json:"exitCode"`
+	// BytesCopiedIn is the number of bytes copied into the phase container, e.g. the app source
+	BytesCopiedIn int64 `json:"bytesCopiedIn"`
+	// BytesCopiedOut is the number of bytes copied out of the phase container, e.g. the SBOM and report.toml
+	BytesCopiedOut int64  `json:"bytesCopiedOut"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Build holds the metrics recorded for every phase run during a build, in the order they started
+type Build struct {
+	StartTime  time.Time `json:"startTime"`
+	DurationMS int64     `json:"durationMs"`
+	Phases     []Phase   `json:"phases"`
+}
+
+// WriteToDir writes the metrics as JSON to FileName in dir, creating dir if needed
+func (b Build) WriteToDir(dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, FileName), append(data, '\n'), 0600)
+}
@@ -0,0 +1,46 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/metrics"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMetrics(t *testing.T) {
+	spec.Run(t, "Metrics", testMetrics, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMetrics(t *testing.T, when spec.G, it spec.S) {
+	when("#WriteToDir", func() {
+		it("writes the metrics as json, creating the directory", func() {
+			dir := filepath.Join(t.TempDir(), "some", "report-dir")
+			startTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			buildMetrics := metrics.Build{
+				StartTime:  startTime,
+				DurationMS: 3000,
+				Phases: []metrics.Phase{
+					{Name: "analyzer", StartTime: startTime, DurationMS: 1000, ExitCode: 0},
+					{Name: "detector", StartTime: startTime, DurationMS: 2000, ExitCode: 20, BytesCopiedIn: 4096, Error: "failed with status code: 20"},
+				},
+			}
+
+			h.AssertNil(t, buildMetrics.WriteToDir(dir))
+
+			contents, err := os.ReadFile(filepath.Join(dir, metrics.FileName))
+			h.AssertNil(t, err)
+
+			var written metrics.Build
+			h.AssertNil(t, json.Unmarshal(contents, &written))
+			h.AssertEq(t, written, buildMetrics)
+			h.AssertContains(t, string(contents), `"exitCode": 20`)
+		})
+	})
+}
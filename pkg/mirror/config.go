@@ -0,0 +1,66 @@
+package mirror
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// Config is a mirror.toml file, listing the artifacts to add to the mirror
+type Config struct {
+	// Platforms are the os/arch[/variant] platforms to mirror images for. They default to linux on the current architecture.
+	Platforms []string `toml:"platforms"`
+	// Builders are mirrored along with their run images and lifecycle image
+	Builders []string `toml:"builders"`
+	// Images are mirrored as is, for instance run images or images of packaged buildpacks
+	Images []string `toml:"images"`
+	// Lifecycles are versions of the lifecycle, whose tarballs and image are mirrored
+	Lifecycles []string `toml:"lifecycles"`
+	// Buildpacks are URIs of buildpacks: http(s) URIs are mirrored as blobs, and image and registry references as images
+	Buildpacks []string `toml:"buildpacks"`
+	// Registries are names of the buildpack registries, as configured in pack, whose index is mirrored
+	Registries []string `toml:"registries"`
+}
+
+// ReadConfig reads a mirror config from path
+func ReadConfig(path string) (Config, error) {
+	config := Config{}
+	md, err := toml.DecodeFile(path, &config)
+	if err != nil {
+		return config, errors.Wrapf(err, "reading mirror config %s", style.Symbol(path))
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var keys []string
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return config, errors.Errorf("unknown keys in mirror config %s: %s", style.Symbol(path), strings.Join(keys, ", "))
+	}
+	if _, err := config.PlatformList(); err != nil {
+		return config, errors.Wrapf(err, "reading mirror config %s", style.Symbol(path))
+	}
+	return config, nil
+}
+
+// PlatformList returns the platforms to mirror images for
+func (c Config) PlatformList() ([]v1.Platform, error) {
+	if len(c.Platforms) == 0 {
+		return []v1.Platform{{OS: "linux", Architecture: runtime.GOARCH}}, nil
+	}
+
+	var platforms []v1.Platform
+	for _, p := range c.Platforms {
+		platform, err := v1.ParsePlatform(p)
+		if err != nil || platform.OS == "" || platform.Architecture == "" {
+			return nil, fmt.Errorf("invalid platform %s: must be os/arch[/variant]", style.Symbol(p))
+		}
+		platforms = append(platforms, *platform)
+	}
+	return platforms, nil
+}
@@ -0,0 +1,185 @@
+// Package mirror provides a local directory of images, blobs and registry indexes, which pack resolves them from
+// instead of the network when it runs offline.
+package mirror
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// RefNameAnnotation is the annotation of the images of the mirror holding the reference they were mirrored from
+const RefNameAnnotation = "org.opencontainers.image.ref.name"
+
+// ErrNotMirrored is returned when an image or a blob isn't in the mirror
+var ErrNotMirrored = errors.New("not mirrored")
+
+// Mirror is a local directory holding images as an OCI layout, downloaded blobs by URI and clones of registry indexes.
+type Mirror struct {
+	dir     string
+	offline bool
+}
+
+// New returns the mirror in dir, which is created when the first artifact is added
+func New(dir string) *Mirror {
+	return &Mirror{dir: dir}
+}
+
+// Dir returns the directory of the mirror
+func (m *Mirror) Dir() string {
+	return m.dir
+}
+
+// SetOffline sets whether pack resolves images, blobs and registry indexes from the mirror instead of the network
+func (m *Mirror) SetOffline(offline bool) {
+	m.offline = offline
+}
+
+// IsOffline returns whether pack is offline. A nil mirror is never offline.
+func (m *Mirror) IsOffline() bool {
+	return m != nil && m.offline
+}
+
+// ImagesDir returns the OCI layout directory holding the images of the mirror
+func (m *Mirror) ImagesDir() string {
+	return filepath.Join(m.dir, "images")
+}
+
+// BlobsDir returns the directory holding the blobs of the mirror, named by the sha256 of their URI
+func (m *Mirror) BlobsDir() string {
+	return filepath.Join(m.dir, "blobs")
+}
+
+// RegistriesDir returns the directory holding the clones of registry indexes
+func (m *Mirror) RegistriesDir() string {
+	return filepath.Join(m.dir, "registries")
+}
+
+// AddImage adds img, for platform, to the mirror as the image referenced by ref. It replaces the image
+// previously mirrored for the reference and platform.
+func (m *Mirror) AddImage(ref string, img v1.Image, platform v1.Platform) error {
+	refName, err := normalize(ref)
+	if err != nil {
+		return err
+	}
+
+	path, err := m.layout()
+	if err != nil {
+		return err
+	}
+
+	return path.ReplaceImage(img,
+		func(desc v1.Descriptor) bool {
+			return desc.Annotations[RefNameAnnotation] == refName && desc.Platform != nil && desc.Platform.Equals(platform)
+		},
+		layout.WithAnnotations(map[string]string{RefNameAnnotation: refName}),
+		layout.WithPlatform(platform),
+	)
+}
+
+// Image returns the image referenced by ref for platform. If platform is nil, the first image mirrored for the
+// reference is returned. A reference by digest also matches an image of that digest mirrored by tag.
+func (m *Mirror) Image(ref string, platform *v1.Platform) (v1.Image, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing image reference %s", style.Symbol(ref))
+	}
+	digest, isDigest := parsed.(name.Digest)
+
+	path, err := layout.FromPath(m.ImagesDir())
+	if err != nil {
+		return nil, m.notMirrored("image", ref)
+	}
+	index, err := path.ImageIndex()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading mirror %s", style.Symbol(m.ImagesDir()))
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading mirror %s", style.Symbol(m.ImagesDir()))
+	}
+
+	for _, desc := range manifest.Manifests {
+		matchesRef := desc.Annotations[RefNameAnnotation] == parsed.Name() ||
+			(isDigest && desc.Digest.String() == digest.DigestStr())
+		if !matchesRef {
+			continue
+		}
+		if platform != nil && desc.Platform != nil && !desc.Platform.Satisfies(*platform) {
+			continue
+		}
+		return index.Image(desc.Digest)
+	}
+	return nil, m.notMirrored("image", ref)
+}
+
+// AddBlob adds the contents of the blob downloaded from uri to the mirror
+func (m *Mirror) AddBlob(uri string, contents io.Reader) error {
+	if err := os.MkdirAll(m.BlobsDir(), 0750); err != nil {
+		return errors.Wrapf(err, "creating %s", style.Symbol(m.BlobsDir()))
+	}
+
+	// blobs are written to a temporary file first, so that an interrupted sync doesn't leave a partial blob
+	file, err := os.CreateTemp(m.BlobsDir(), "blob")
+	if err != nil {
+		return errors.Wrap(err, "creating blob")
+	}
+	defer os.Remove(file.Name())
+	if _, err := io.Copy(file, contents); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "writing blob of %s", style.Symbol(uri))
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), m.blobPath(uri))
+}
+
+// Blob returns the path of the blob downloaded from uri
+func (m *Mirror) Blob(uri string) (string, error) {
+	path := m.blobPath(uri)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", m.notMirrored("blob", uri)
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+func (m *Mirror) blobPath(uri string) string {
+	return filepath.Join(m.BlobsDir(), fmt.Sprintf("%x", sha256.Sum256([]byte(uri))))
+}
+
+func (m *Mirror) layout() (layout.Path, error) {
+	if path, err := layout.FromPath(m.ImagesDir()); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(m.ImagesDir(), 0750); err != nil {
+		return "", errors.Wrapf(err, "creating %s", style.Symbol(m.ImagesDir()))
+	}
+	return layout.Write(m.ImagesDir(), empty.Index)
+}
+
+func (m *Mirror) notMirrored(kind, ref string) error {
+	return errors.Wrapf(ErrNotMirrored, "%s %s isn't in the mirror at %s: add it to the mirror config and run 'pack mirror sync'",
+		kind, style.Symbol(ref), style.Symbol(m.dir))
+}
+
+func normalize(ref string) (string, error) {
+	parsed, err := name.ParseReference(ref, name.WeakValidation)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing image reference %s", style.Symbol(ref))
+	}
+	return parsed.Name(), nil
+}
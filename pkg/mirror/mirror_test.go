@@ -0,0 +1,214 @@
+package mirror_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/mirror"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestMirror(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Mirror", testMirror, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testMirror(t *testing.T, when spec.G, it spec.S) {
+	var (
+		subject *mirror.Mirror
+		tmpDir  string
+		amd64   = v1.Platform{OS: "linux", Architecture: "amd64"}
+		arm64   = v1.Platform{OS: "linux", Architecture: "arm64"}
+	)
+
+	it.Before(func() {
+		tmpDir = t.TempDir()
+		subject = mirror.New(filepath.Join(tmpDir, "mirror"))
+	})
+
+	when("#IsOffline", func() {
+		it("is false for a nil mirror", func() {
+			var m *mirror.Mirror
+			h.AssertFalse(t, m.IsOffline())
+		})
+
+		it("is set with SetOffline", func() {
+			h.AssertFalse(t, subject.IsOffline())
+			subject.SetOffline(true)
+			h.AssertTrue(t, subject.IsOffline())
+		})
+	})
+
+	when("#Image", func() {
+		var amd64Image, arm64Image v1.Image
+
+		it.Before(func() {
+			var err error
+			amd64Image, err = random.Image(100, 1)
+			h.AssertNil(t, err)
+			arm64Image, err = random.Image(100, 1)
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, subject.AddImage("example/builder:1.0", amd64Image, amd64))
+			h.AssertNil(t, subject.AddImage("example/builder:1.0", arm64Image, arm64))
+		})
+
+		it("returns the image mirrored for the platform", func() {
+			img, err := subject.Image("index.docker.io/example/builder:1.0", &arm64)
+			h.AssertNil(t, err)
+			assertSameImage(t, img, arm64Image)
+
+			img, err = subject.Image("example/builder:1.0", &amd64)
+			h.AssertNil(t, err)
+			assertSameImage(t, img, amd64Image)
+		})
+
+		it("returns the first image mirrored without a platform", func() {
+			img, err := subject.Image("example/builder:1.0", nil)
+			h.AssertNil(t, err)
+			assertSameImage(t, img, amd64Image)
+		})
+
+		it("returns the image of a digest", func() {
+			digest, err := arm64Image.Digest()
+			h.AssertNil(t, err)
+
+			img, err := subject.Image("example/builder@"+digest.String(), nil)
+			h.AssertNil(t, err)
+			assertSameImage(t, img, arm64Image)
+		})
+
+		it("replaces the image mirrored before for the reference and platform", func() {
+			newImage, err := random.Image(100, 1)
+			h.AssertNil(t, err)
+			h.AssertNil(t, subject.AddImage("example/builder:1.0", newImage, amd64))
+
+			img, err := subject.Image("example/builder:1.0", &amd64)
+			h.AssertNil(t, err)
+			assertSameImage(t, img, newImage)
+		})
+
+		it("errors when the image isn't mirrored", func() {
+			_, err := subject.Image("example/builder:2.0", nil)
+			h.AssertTrue(t, errors.Is(err, mirror.ErrNotMirrored))
+			h.AssertError(t, err, "image 'example/builder:2.0' isn't in the mirror")
+			h.AssertError(t, err, "run 'pack mirror sync'")
+
+			_, err = subject.Image("example/builder:1.0", &v1.Platform{OS: "windows", Architecture: "amd64"})
+			h.AssertTrue(t, errors.Is(err, mirror.ErrNotMirrored))
+		})
+
+		it("errors when nothing is mirrored", func() {
+			_, err := mirror.New(filepath.Join(tmpDir, "empty")).Image("example/builder:1.0", nil)
+			h.AssertTrue(t, errors.Is(err, mirror.ErrNotMirrored))
+		})
+	})
+
+	when("#Blob", func() {
+		it("returns the path of a mirrored blob", func() {
+			h.AssertNil(t, subject.AddBlob("https://example.com/lifecycle.tgz", strings.NewReader("some-contents")))
+
+			path, err := subject.Blob("https://example.com/lifecycle.tgz")
+			h.AssertNil(t, err)
+			contents, err := os.ReadFile(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, string(contents), "some-contents")
+		})
+
+		it("errors when the blob isn't mirrored", func() {
+			_, err := subject.Blob("https://example.com/other.tgz")
+			h.AssertTrue(t, errors.Is(err, mirror.ErrNotMirrored))
+			h.AssertError(t, err, "blob 'https://example.com/other.tgz' isn't in the mirror")
+		})
+
+		it("doesn't leave a partial blob when writing fails", func() {
+			err := subject.AddBlob("https://example.com/broken.tgz", io.MultiReader(strings.NewReader("some"), &failingReader{}))
+			h.AssertNotNil(t, err)
+
+			_, err = subject.Blob("https://example.com/broken.tgz")
+			h.AssertTrue(t, errors.Is(err, mirror.ErrNotMirrored))
+			entries, err := os.ReadDir(subject.BlobsDir())
+			h.AssertNil(t, err)
+			h.AssertEq(t, len(entries), 0)
+		})
+	})
+
+	when("#ReadConfig", func() {
+		var configPath string
+
+		it.Before(func() {
+			configPath = filepath.Join(tmpDir, "mirror.toml")
+		})
+
+		it("reads the artifacts to mirror", func() {
+			h.AssertNil(t, os.WriteFile(configPath, []byte(`
+platforms = ["linux/amd64", "linux/arm64/v8"]
+builders = ["example/builder:1.0"]
+images = ["example/run:1.0"]
+lifecycles = ["0.20.0"]
+buildpacks = ["urn:cnb:registry:example/node@1.0.0"]
+registries = ["official"]
+`), 0600))
+
+			config, err := mirror.ReadConfig(configPath)
+			h.AssertNil(t, err)
+			h.AssertEq(t, config.Builders, []string{"example/builder:1.0"})
+			h.AssertEq(t, config.Images, []string{"example/run:1.0"})
+			h.AssertEq(t, config.Lifecycles, []string{"0.20.0"})
+			h.AssertEq(t, config.Buildpacks, []string{"urn:cnb:registry:example/node@1.0.0"})
+			h.AssertEq(t, config.Registries, []string{"official"})
+
+			platforms, err := config.PlatformList()
+			h.AssertNil(t, err)
+			h.AssertEq(t, platforms, []v1.Platform{amd64, {OS: "linux", Architecture: "arm64", Variant: "v8"}})
+		})
+
+		it("defaults the platforms to linux on the current architecture", func() {
+			platforms, err := mirror.Config{}.PlatformList()
+			h.AssertNil(t, err)
+			h.AssertEq(t, platforms, []v1.Platform{{OS: "linux", Architecture: runtime.GOARCH}})
+		})
+
+		it("errors for unknown keys", func() {
+			h.AssertNil(t, os.WriteFile(configPath, []byte(`builder = "example/builder:1.0"`), 0600))
+
+			_, err := mirror.ReadConfig(configPath)
+			h.AssertError(t, err, "unknown keys in mirror config")
+			h.AssertError(t, err, "builder")
+		})
+
+		it("errors for invalid platforms", func() {
+			h.AssertNil(t, os.WriteFile(configPath, []byte(`platforms = ["linux"]`), 0600))
+
+			_, err := mirror.ReadConfig(configPath)
+			h.AssertError(t, err, "invalid platform 'linux': must be os/arch[/variant]")
+		})
+	})
+}
+
+func assertSameImage(t *testing.T, actual, expected v1.Image) {
+	t.Helper()
+	actualDigest, err := actual.Digest()
+	h.AssertNil(t, err)
+	expectedDigest, err := expected.Digest()
+	h.AssertNil(t, err)
+	h.AssertEq(t, actualDigest, expectedDigest)
+}
+
+type failingReader struct{}
+
+func (r *failingReader) Read(_ []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
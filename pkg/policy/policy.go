@@ -0,0 +1,217 @@
+// Package policy reads policy files, which restrict the builders, run images, buildpacks and volumes builds,
+// builder creations and rebases may use, and evaluates the resolved inputs of these operations against them.
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+	"github.com/buildpacks/pack/pkg/logging"
+)
+
+// Policy restricts the inputs of builds, builder creations and rebases
+type Policy struct {
+	// AuditOnly reports violations as warnings, rather than failing
+	AuditOnly bool `toml:"audit-only"`
+
+	Builders   ImageRule     `toml:"builders"`
+	RunImages  ImageRule     `toml:"run-images"`
+	Buildpacks BuildpackRule `toml:"buildpacks"`
+	Build      BuildRule     `toml:"build"`
+}
+
+// ImageRule restricts the images of a kind
+type ImageRule struct {
+	// Allowed registries, or repository prefixes, the images must come from. Any image is allowed when it's empty.
+	Allowed []string `toml:"allowed"`
+
+	// RequireDigest requires the images to be referenced by digest
+	RequireDigest bool `toml:"require-digest"`
+}
+
+// BuildpackRule restricts the buildpacks and extensions that aren't on the builder
+type BuildpackRule struct {
+	// Allowed registries, or repository prefixes, buildpack package images must come from
+	Allowed []string `toml:"allowed"`
+
+	// RequireDigest requires buildpack package images to be referenced by digest
+	RequireDigest bool `toml:"require-digest"`
+
+	// ForbidURLs forbids buildpacks downloaded from http(s) URLs
+	ForbidURLs bool `toml:"forbid-urls"`
+}
+
+// BuildRule restricts the containers builds run in
+type BuildRule struct {
+	// ForbidWritableVolumes forbids volumes mounted read-write
+	ForbidWritableVolumes bool `toml:"forbid-writable-volumes"`
+
+	// ForbidTrustedBuilder forbids running all the lifecycle phases in the builder, as a trusted builder does
+	ForbidTrustedBuilder bool `toml:"forbid-trusted-builder"`
+}
+
+// Inputs are the resolved inputs of an operation
+type Inputs struct {
+	Builder         string
+	RunImages       []string
+	BuildpackImages []string
+	BuildpackURLs   []string
+	Volumes         []string
+
+	// TrustedBuilder is the builder of a build when it's trusted
+	TrustedBuilder string
+}
+
+// Violation is a rule of the policy an input breaks
+type Violation struct {
+	// Rule broken, as `<table>.<key>` of the policy file
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s (%s)", v.Message, v.Rule)
+}
+
+// Read reads the policy file at path
+func Read(path string) (Policy, error) {
+	policy := Policy{}
+	md, err := toml.DecodeFile(path, &policy)
+	if err != nil {
+		return policy, errors.Wrapf(err, "reading policy %s", style.Symbol(path))
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		var keys []string
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return policy, errors.Errorf("unknown keys in policy %s: %s", style.Symbol(path), strings.Join(keys, ", "))
+	}
+	return policy, nil
+}
+
+// Evaluate returns the violations of the policy by the inputs
+func (p Policy) Evaluate(inputs Inputs) []Violation {
+	var violations []Violation
+	if inputs.Builder != "" {
+		violations = append(violations, p.Builders.evaluate("builders", "builder", inputs.Builder)...)
+	}
+	for _, runImage := range inputs.RunImages {
+		violations = append(violations, p.RunImages.evaluate("run-images", "run image", runImage)...)
+	}
+	for _, bpImage := range inputs.BuildpackImages {
+		rule := ImageRule{Allowed: p.Buildpacks.Allowed, RequireDigest: p.Buildpacks.RequireDigest}
+		violations = append(violations, rule.evaluate("buildpacks", "buildpack", bpImage)...)
+	}
+	if p.Buildpacks.ForbidURLs {
+		for _, url := range inputs.BuildpackURLs {
+			violations = append(violations, Violation{
+				Rule:    "buildpacks.forbid-urls",
+				Message: fmt.Sprintf("buildpack %s is downloaded from a URL", style.Symbol(url)),
+			})
+		}
+	}
+	if p.Build.ForbidTrustedBuilder && inputs.TrustedBuilder != "" {
+		violations = append(violations, Violation{
+			Rule:    "build.forbid-trusted-builder",
+			Message: fmt.Sprintf("builder %s is trusted", style.Symbol(inputs.TrustedBuilder)),
+		})
+	}
+	if p.Build.ForbidWritableVolumes {
+		for _, volume := range inputs.Volumes {
+			if isWritable(volume) {
+				violations = append(violations, Violation{
+					Rule:    "build.forbid-writable-volumes",
+					Message: fmt.Sprintf("volume %s is mounted read-write", style.Symbol(volume)),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// Enforce evaluates the inputs, and fails when they violate the policy. In audit-only mode, violations are logged
+// as warnings instead.
+func (p Policy) Enforce(logger logging.Logger, inputs Inputs) error {
+	violations := p.Evaluate(inputs)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if p.AuditOnly {
+		for _, violation := range violations {
+			logger.Warnf("Policy violation: %s", violation)
+		}
+		return nil
+	}
+
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, "  - "+violation.String())
+	}
+	return errors.Errorf("policy violated:\n%s", strings.Join(messages, "\n"))
+}
+
+func (r ImageRule) evaluate(table, kind, imageName string) []Violation {
+	ref, err := name.ParseReference(imageName, name.WeakValidation)
+	if err != nil {
+		return []Violation{{Rule: table, Message: fmt.Sprintf("%s %s isn't a valid image reference", kind, style.Symbol(imageName))}}
+	}
+
+	var violations []Violation
+	if len(r.Allowed) > 0 && !isAllowed(ref, r.Allowed) {
+		violations = append(violations, Violation{
+			Rule:    table + ".allowed",
+			Message: fmt.Sprintf("%s %s isn't from an allowed registry or repository", kind, style.Symbol(imageName)),
+		})
+	}
+	if _, isDigest := ref.(name.Digest); r.RequireDigest && !isDigest {
+		violations = append(violations, Violation{
+			Rule:    table + ".require-digest",
+			Message: fmt.Sprintf("%s %s isn't pinned by digest", kind, style.Symbol(imageName)),
+		})
+	}
+	return violations
+}
+
+func isAllowed(ref name.Reference, allowed []string) bool {
+	registry := ref.Context().RegistryStr()
+	repository := ref.Context().Name()
+	for _, entry := range allowed {
+		entry = strings.TrimSuffix(normalize(entry), "/")
+		if entry == registry || entry == repository || strings.HasPrefix(repository, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize makes entries for Docker Hub match the registry and repository names of Docker Hub references
+func normalize(entry string) string {
+	if entry == "docker.io" {
+		return name.DefaultRegistry
+	}
+	if rest, ok := strings.CutPrefix(entry, "docker.io/"); ok {
+		return name.DefaultRegistry + "/" + rest
+	}
+	return entry
+}
+
+// isWritable returns whether a volume, as '<host path>:<target path>[:<options>]', is mounted read-write
+func isWritable(volume string) bool {
+	parts := strings.Split(volume, ":")
+	if len(parts) < 3 {
+		return false
+	}
+	for _, option := range strings.Split(parts[len(parts)-1], ",") {
+		if option == "rw" {
+			return true
+		}
+	}
+	return false
+}
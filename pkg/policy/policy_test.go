@@ -0,0 +1,178 @@
+package policy_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/heroku/color"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/logging"
+	"github.com/buildpacks/pack/pkg/policy"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestPolicy(t *testing.T) {
+	color.Disable(true)
+	defer color.Disable(false)
+	spec.Run(t, "Policy", testPolicy, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testPolicy(t *testing.T, when spec.G, it spec.S) {
+	var digest = "sha256:" + strings.Repeat("ab", 32)
+
+	when("#Read", func() {
+		it("reads a policy file", func() {
+			path := filepath.Join(t.TempDir(), "policy.toml")
+			h.AssertNil(t, os.WriteFile(path, []byte(`audit-only = true
+
+[builders]
+allowed = ["registry.example.com"]
+
+[run-images]
+require-digest = true
+
+[buildpacks]
+forbid-urls = true
+
+[build]
+forbid-writable-volumes = true
+forbid-trusted-builder = true
+`), 0600))
+
+			read, err := policy.Read(path)
+			h.AssertNil(t, err)
+			h.AssertEq(t, read, policy.Policy{
+				AuditOnly:  true,
+				Builders:   policy.ImageRule{Allowed: []string{"registry.example.com"}},
+				RunImages:  policy.ImageRule{RequireDigest: true},
+				Buildpacks: policy.BuildpackRule{ForbidURLs: true},
+				Build:      policy.BuildRule{ForbidWritableVolumes: true, ForbidTrustedBuilder: true},
+			})
+		})
+
+		it("errors on unknown keys", func() {
+			path := filepath.Join(t.TempDir(), "policy.toml")
+			h.AssertNil(t, os.WriteFile(path, []byte("[builders]\nallow = [\"registry.example.com\"]\n"), 0600))
+
+			_, err := policy.Read(path)
+			h.AssertError(t, err, "unknown keys in policy")
+			h.AssertError(t, err, "builders.allow")
+		})
+	})
+
+	when("#Evaluate", func() {
+		it("returns no violations for an empty policy", func() {
+			violations := policy.Policy{}.Evaluate(policy.Inputs{
+				Builder:       "some/builder",
+				RunImages:     []string{"some/run"},
+				BuildpackURLs: []string{"https://example.com/bp.tgz"},
+				Volumes:       []string{"/tmp:/workspace/tmp:rw"},
+			})
+			h.AssertEq(t, len(violations), 0)
+		})
+
+		when("images are restricted to registries", func() {
+			var p = policy.Policy{
+				Builders:   policy.ImageRule{Allowed: []string{"registry.example.com", "docker.io/paketobuildpacks"}},
+				Buildpacks: policy.BuildpackRule{Allowed: []string{"registry.example.com/buildpacks/"}},
+			}
+
+			it("allows images from the registries or repositories", func() {
+				h.AssertEq(t, len(p.Evaluate(policy.Inputs{Builder: "registry.example.com/some/builder:1.0"})), 0)
+				h.AssertEq(t, len(p.Evaluate(policy.Inputs{Builder: "paketobuildpacks/builder-jammy-base"})), 0)
+				h.AssertEq(t, len(p.Evaluate(policy.Inputs{BuildpackImages: []string{"registry.example.com/buildpacks/node"}})), 0)
+			})
+
+			it("reports images from other registries or repositories", func() {
+				violations := p.Evaluate(policy.Inputs{
+					Builder:         "registry.example.com.evil.io/some/builder",
+					BuildpackImages: []string{"registry.example.com/other/node", "gcr.io/buildpacks/node"},
+				})
+				h.AssertEq(t, len(violations), 3)
+				h.AssertEq(t, violations[0].Rule, "builders.allowed")
+				h.AssertContains(t, violations[0].Message, "builder 'registry.example.com.evil.io/some/builder' isn't from an allowed registry")
+				h.AssertEq(t, violations[1].Rule, "buildpacks.allowed")
+				h.AssertEq(t, violations[2].Rule, "buildpacks.allowed")
+			})
+		})
+
+		it("reports run images that aren't pinned by digest", func() {
+			p := policy.Policy{RunImages: policy.ImageRule{RequireDigest: true}}
+
+			violations := p.Evaluate(policy.Inputs{RunImages: []string{"some/run:latest", "some/run@" + digest}})
+			h.AssertEq(t, violations, []policy.Violation{{
+				Rule:    "run-images.require-digest",
+				Message: "run image 'some/run:latest' isn't pinned by digest",
+			}})
+		})
+
+		it("reports buildpacks from URLs", func() {
+			p := policy.Policy{Buildpacks: policy.BuildpackRule{ForbidURLs: true}}
+
+			violations := p.Evaluate(policy.Inputs{BuildpackURLs: []string{"https://example.com/bp.tgz"}})
+			h.AssertEq(t, violations, []policy.Violation{{
+				Rule:    "buildpacks.forbid-urls",
+				Message: "buildpack 'https://example.com/bp.tgz' is downloaded from a URL",
+			}})
+		})
+
+		it("reports trusted builders", func() {
+			p := policy.Policy{Build: policy.BuildRule{ForbidTrustedBuilder: true}}
+
+			h.AssertEq(t, len(p.Evaluate(policy.Inputs{Builder: "some/builder"})), 0)
+			violations := p.Evaluate(policy.Inputs{TrustedBuilder: "some/builder"})
+			h.AssertEq(t, violations, []policy.Violation{{
+				Rule:    "build.forbid-trusted-builder",
+				Message: "builder 'some/builder' is trusted",
+			}})
+		})
+
+		it("reports volumes mounted read-write", func() {
+			p := policy.Policy{Build: policy.BuildRule{ForbidWritableVolumes: true}}
+
+			violations := p.Evaluate(policy.Inputs{Volumes: []string{
+				"/tmp:/workspace/tmp",
+				"/cache:/workspace/cache:ro",
+				"/out:/workspace/out:rw,z",
+			}})
+			h.AssertEq(t, violations, []policy.Violation{{
+				Rule:    "build.forbid-writable-volumes",
+				Message: "volume '/out:/workspace/out:rw,z' is mounted read-write",
+			}})
+		})
+	})
+
+	when("#Enforce", func() {
+		var (
+			outBuf bytes.Buffer
+			logger logging.Logger
+			inputs = policy.Inputs{Builder: "other.io/some/builder"}
+		)
+
+		it.Before(func() {
+			outBuf = bytes.Buffer{}
+			logger = logging.NewLogWithWriters(&outBuf, &outBuf)
+		})
+
+		it("errors with the violations", func() {
+			p := policy.Policy{Builders: policy.ImageRule{Allowed: []string{"registry.example.com"}, RequireDigest: true}}
+
+			err := p.Enforce(logger, inputs)
+			h.AssertError(t, err, "policy violated")
+			h.AssertError(t, err, "builder 'other.io/some/builder' isn't from an allowed registry or repository (builders.allowed)")
+			h.AssertError(t, err, "builder 'other.io/some/builder' isn't pinned by digest (builders.require-digest)")
+		})
+
+		it("only warns in audit-only mode", func() {
+			p := policy.Policy{AuditOnly: true, Builders: policy.ImageRule{Allowed: []string{"registry.example.com"}}}
+
+			h.AssertNil(t, p.Enforce(logger, inputs))
+			h.AssertContains(t, outBuf.String(), "Warning: Policy violation: builder 'other.io/some/builder' isn't from an allowed registry or repository (builders.allowed)")
+		})
+	})
+}
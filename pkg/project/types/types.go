@@ -14,6 +14,7 @@ type Buildpack struct {
 	ID      string   `toml:"id"`
 	Version string   `toml:"version"`
 	URI     string   `toml:"uri"`
+	SHA256  string   `toml:"sha256"`
 	Script  Script   `toml:"script"`
 	ExecEnv []string `toml:"exec-env"`
 }
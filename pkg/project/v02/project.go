@@ -52,6 +52,7 @@ type Buildpack struct {
 	ID      string       `toml:"id"`
 	Version string       `toml:"version"`
 	URI     string       `toml:"uri"`
+	SHA256  string       `toml:"sha256"`
 	Script  types.Script `toml:"script"`
 }
 
@@ -113,6 +114,7 @@ func mapToBuildPackDescriptor(v2BuildPack Buildpack) types.Buildpack {
 		ID:      v2BuildPack.ID,
 		Version: v2BuildPack.Version,
 		URI:     v2BuildPack.URI,
+		SHA256:  v2BuildPack.SHA256,
 		Script:  v2BuildPack.Script,
 		ExecEnv: []string{}, // schema v2 doesn't handle execution environments variables
 	}
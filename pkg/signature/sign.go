@@ -0,0 +1,156 @@
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+// LoadPrivateKey parses a PEM encoded, unencrypted, PKCS #8, EC or PKCS #1 private key
+func LoadPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key isn't PEM encoded")
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		if strings.Contains(block.Type, "ENCRYPTED") {
+			return nil, errors.Errorf("encrypted private keys aren't supported: found %s; export the key unencrypted", style.Symbol(block.Type))
+		}
+		return nil, errors.Errorf("unsupported private key type %s", style.Symbol(block.Type))
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
+
+	switch signer := key.(type) {
+	case *ecdsa.PrivateKey, *rsa.PrivateKey, ed25519.PrivateKey:
+		return signer.(crypto.Signer), nil
+	default:
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// Sign signs the image ref refers to with signer, and returns the reference by digest of the signed image
+func Sign(ctx context.Context, ref name.Reference, signer crypto.Signer, opts ...remote.Option) (name.Digest, error) {
+	desc, err := remote.Head(ref, append(opts, remote.WithContext(ctx))...)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "resolving digest of image %s", style.Symbol(ref.Name()))
+	}
+	digest := ref.Context().Digest(desc.Digest.String())
+	return digest, SignDigest(ctx, digest, signer, opts...)
+}
+
+// SignDigest pushes a signature of the image with the given digest, made with signer, to the signature tag of the
+// image. Signatures already pushed there are kept.
+func SignDigest(ctx context.Context, digest name.Digest, signer crypto.Signer, opts ...remote.Option) error {
+	opts = append(opts, remote.WithContext(ctx))
+
+	hash, err := v1.NewHash(digest.DigestStr())
+	if err != nil {
+		return errors.Wrapf(err, "parsing digest of image %s", style.Symbol(digest.Name()))
+	}
+	payload, err := json.Marshal(Payload{Critical: Critical{
+		Identity: Identity{DockerReference: digest.Context().Name()},
+		Image:    Image{DockerManifestDigest: hash.String()},
+		Type:     payloadType,
+	}})
+	if err != nil {
+		return errors.Wrap(err, "creating signature payload")
+	}
+	sig, err := signPayload(signer, payload)
+	if err != nil {
+		return err
+	}
+
+	sigTag := SignatureTag(digest.Context(), hash)
+	sigImage, err := remote.Image(sigTag, opts...)
+	if err != nil {
+		var terr *transport.Error
+		if !errors.As(err, &terr) || terr.StatusCode != http.StatusNotFound {
+			return errors.Wrapf(err, "fetching signatures of image %s", style.Symbol(digest.Name()))
+		}
+		sigImage = mutate.MediaType(empty.Image, types.OCIManifestSchema1)
+	}
+
+	sigImage, err = mutate.Append(sigImage, mutate.Addendum{
+		Layer:       static.NewLayer(payload, PayloadMediaType),
+		Annotations: map[string]string{SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "adding signature")
+	}
+	if err := remote.Write(sigTag, sigImage, opts...); err != nil {
+		return errors.Wrapf(err, "pushing signature of image %s", style.Symbol(digest.Name()))
+	}
+	return nil
+}
+
+// SignBlob returns the base64 encoded signature of data made with signer, as `cosign sign-blob` writes it
+func SignBlob(signer crypto.Signer, data []byte) (string, error) {
+	sig, err := signPayload(signer, data)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// VerifyBlob checks that sig, a base64 encoded signature as SignBlob returns it, is a signature of data made with
+// the private key of publicKey
+func VerifyBlob(publicKey crypto.PublicKey, data []byte, sig string) error {
+	rawSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(sig))
+	if err != nil {
+		return errors.Wrap(err, "decoding signature")
+	}
+	return verifySignature(publicKey, data, rawSig)
+}
+
+func signPayload(signer crypto.Signer, payload []byte) ([]byte, error) {
+	var (
+		sig []byte
+		err error
+	)
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		sig, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	} else {
+		hash := sha256.Sum256(payload)
+		sig, err = signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "signing")
+	}
+	return sig, nil
+}
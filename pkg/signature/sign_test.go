@@ -0,0 +1,117 @@
+package signature_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/signature"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSign(t *testing.T) {
+	spec.Run(t, "Sign", testSign, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSign(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server     *httptest.Server
+		imageRef   name.Reference
+		privateKey *ecdsa.PrivateKey
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+		var err error
+		imageRef, err = name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/some/package:latest")
+		h.AssertNil(t, err)
+		img, err := random.Image(10, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(imageRef, img))
+
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		h.AssertNil(t, err)
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#LoadPrivateKey", func() {
+		it("loads PEM encoded PKCS #8 keys", func() {
+			data, err := os.ReadFile(h.WritePrivateKey(t, t.TempDir(), privateKey))
+			h.AssertNil(t, err)
+
+			signer, err := signature.LoadPrivateKey(data)
+			h.AssertNil(t, err)
+			h.AssertTrue(t, privateKey.Equal(signer))
+		})
+
+		it("errors for encrypted keys", func() {
+			data := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("some-key")})
+
+			_, err := signature.LoadPrivateKey(data)
+			h.AssertError(t, err, "encrypted private keys aren't supported")
+		})
+	})
+
+	when("#Sign", func() {
+		it("pushes a signature that verifies with the public key", func() {
+			signed, err := signature.Sign(context.TODO(), imageRef, privateKey)
+			h.AssertNil(t, err)
+
+			verified, err := signature.Verify(context.TODO(), imageRef, &privateKey.PublicKey)
+			h.AssertNil(t, err)
+			h.AssertEq(t, verified.Name(), signed.Name())
+		})
+
+		it("keeps the signatures pushed before", func() {
+			otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			h.AssertNil(t, err)
+			_, err = signature.Sign(context.TODO(), imageRef, otherKey)
+			h.AssertNil(t, err)
+			_, err = signature.Sign(context.TODO(), imageRef, privateKey)
+			h.AssertNil(t, err)
+
+			_, err = signature.Verify(context.TODO(), imageRef, &otherKey.PublicKey)
+			h.AssertNil(t, err)
+			_, err = signature.Verify(context.TODO(), imageRef, &privateKey.PublicKey)
+			h.AssertNil(t, err)
+		})
+	})
+
+	when("#SignBlob", func() {
+		it("returns a signature that verifies with the public key", func() {
+			sig, err := signature.SignBlob(privateKey, []byte("some-blob"))
+			h.AssertNil(t, err)
+
+			h.AssertNil(t, signature.VerifyBlob(&privateKey.PublicKey, []byte("some-blob"), sig))
+			h.AssertError(t, signature.VerifyBlob(&privateKey.PublicKey, []byte("other-blob"), sig), "invalid signature")
+		})
+
+		it("signs with ed25519 keys", func() {
+			publicKey, edKey, err := ed25519.GenerateKey(rand.Reader)
+			h.AssertNil(t, err)
+
+			sig, err := signature.SignBlob(edKey, []byte("some-blob"))
+			h.AssertNil(t, err)
+			h.AssertNil(t, signature.VerifyBlob(publicKey, []byte("some-blob"), sig))
+		})
+	})
+}
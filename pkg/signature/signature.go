@@ -0,0 +1,180 @@
+// Package signature signs and verifies image signatures in the format cosign stores them in a registry: a
+// `sha256-<digest>.sig` tag, next to the image, whose layers are simple signing payloads naming the digest of the
+// image, with the signature of the payload as an annotation.
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/pkg/errors"
+
+	"github.com/buildpacks/pack/internal/style"
+)
+
+const (
+	// PayloadMediaType is the media type of the layers holding simple signing payloads
+	PayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// SignatureAnnotation is the annotation of a payload layer holding the base64 encoded signature of the payload
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	payloadType = "cosign container image signature"
+)
+
+// Payload is a simple signing payload, naming the image that was signed
+type Payload struct {
+	Critical Critical          `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+type Critical struct {
+	Identity Identity `json:"identity"`
+	Image    Image    `json:"image"`
+	Type     string   `json:"type"`
+}
+
+type Identity struct {
+	DockerReference string `json:"docker-reference"`
+}
+
+type Image struct {
+	DockerManifestDigest string `json:"docker-manifest-digest"`
+}
+
+// LoadPublicKey parses a PEM encoded ECDSA, RSA or ed25519 public key, like the ones `cosign generate-key-pair`
+// writes to cosign.pub
+func LoadPublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("public key isn't PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing public key")
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, errors.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// SignatureTag returns the tag the signatures of the image with the given digest are stored at
+func SignatureTag(repo name.Repository, digest v1.Hash) name.Tag {
+	return repo.Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+}
+
+// Verify checks that the image ref refers to was signed with the private key of publicKey, and returns
+// the reference by digest of the verified image
+func Verify(ctx context.Context, ref name.Reference, publicKey crypto.PublicKey, opts ...remote.Option) (name.Digest, error) {
+	opts = append(opts, remote.WithContext(ctx))
+
+	desc, err := remote.Head(ref, opts...)
+	if err != nil {
+		return name.Digest{}, errors.Wrapf(err, "resolving digest of image %s", style.Symbol(ref.Name()))
+	}
+	digest := ref.Context().Digest(desc.Digest.String())
+
+	sigTag := SignatureTag(ref.Context(), desc.Digest)
+	sigImage, err := remote.Image(sigTag, opts...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return name.Digest{}, errors.Errorf("image %s isn't signed", style.Symbol(digest.Name()))
+		}
+		return name.Digest{}, errors.Wrapf(err, "fetching signatures of image %s", style.Symbol(digest.Name()))
+	}
+
+	manifest, err := sigImage.Manifest()
+	if err != nil {
+		return name.Digest{}, errors.Wrap(err, "reading signature manifest")
+	}
+	for _, layer := range manifest.Layers {
+		sig, ok := layer.Annotations[SignatureAnnotation]
+		if !ok || layer.MediaType != PayloadMediaType {
+			continue
+		}
+		payload, err := readPayload(sigImage, layer.Digest)
+		if err != nil {
+			return name.Digest{}, err
+		}
+		if verifyPayload(payload, sig, publicKey, desc.Digest) == nil {
+			return digest, nil
+		}
+	}
+	return name.Digest{}, errors.Errorf("no signature of image %s matches the public key", style.Symbol(digest.Name()))
+}
+
+func readPayload(sigImage v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := sigImage.LayerByDigest(digest)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading signature payload")
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading signature payload")
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func verifyPayload(payload []byte, sig string, publicKey crypto.PublicKey, digest v1.Hash) error {
+	rawSig, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return errors.Wrap(err, "decoding signature")
+	}
+	if err := verifySignature(publicKey, payload, rawSig); err != nil {
+		return err
+	}
+
+	var p Payload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return errors.Wrap(err, "parsing signature payload")
+	}
+	if p.Critical.Type != payloadType {
+		return errors.Errorf("unsupported signature payload type %s", style.Symbol(p.Critical.Type))
+	}
+	// the signature is only valid for the image it names: a tag pointing at another image must not verify
+	if !strings.EqualFold(p.Critical.Image.DockerManifestDigest, digest.String()) {
+		return errors.Errorf("signature is for image %s", style.Symbol(p.Critical.Image.DockerManifestDigest))
+	}
+	return nil
+}
+
+func verifySignature(publicKey crypto.PublicKey, payload, sig []byte) error {
+	hash := sha256.Sum256(payload)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hash[:], sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported public key type %T", publicKey)
+	}
+}
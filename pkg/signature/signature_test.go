@@ -0,0 +1,116 @@
+package signature_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	"github.com/buildpacks/pack/pkg/signature"
+	h "github.com/buildpacks/pack/testhelpers"
+)
+
+func TestSignature(t *testing.T) {
+	spec.Run(t, "Signature", testSignature, spec.Parallel(), spec.Report(report.Terminal{}))
+}
+
+func testSignature(t *testing.T, when spec.G, it spec.S) {
+	var (
+		server     *httptest.Server
+		imageRef   name.Reference
+		digest     v1.Hash
+		privateKey *ecdsa.PrivateKey
+		publicKey  crypto.PublicKey
+	)
+
+	it.Before(func() {
+		server = httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+		var err error
+		imageRef, err = name.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/some/builder:latest")
+		h.AssertNil(t, err)
+		img, err := random.Image(10, 1)
+		h.AssertNil(t, err)
+		h.AssertNil(t, remote.Write(imageRef, img))
+		digest, err = img.Digest()
+		h.AssertNil(t, err)
+
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		h.AssertNil(t, err)
+		publicKey = &privateKey.PublicKey
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	when("#LoadPublicKey", func() {
+		it("loads PEM encoded public keys", func() {
+			der, err := x509.MarshalPKIXPublicKey(publicKey)
+			h.AssertNil(t, err)
+
+			key, err := signature.LoadPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+			h.AssertNil(t, err)
+			h.AssertTrue(t, privateKey.PublicKey.Equal(key))
+		})
+
+		it("errors when the key isn't PEM encoded", func() {
+			_, err := signature.LoadPublicKey([]byte("some-key"))
+			h.AssertError(t, err, "public key isn't PEM encoded")
+		})
+	})
+
+	when("#Verify", func() {
+		it("returns the digest of images signed with the key", func() {
+			h.SignImage(t, imageRef, digest, privateKey)
+
+			verified, err := signature.Verify(context.TODO(), imageRef, publicKey)
+			h.AssertNil(t, err)
+			h.AssertEq(t, verified.DigestStr(), digest.String())
+		})
+
+		it("errors when the image isn't signed", func() {
+			_, err := signature.Verify(context.TODO(), imageRef, publicKey)
+			h.AssertError(t, err, "isn't signed")
+		})
+
+		it("errors when the image was signed with another key", func() {
+			otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			h.AssertNil(t, err)
+			h.SignImage(t, imageRef, digest, otherKey)
+
+			_, err = signature.Verify(context.TODO(), imageRef, publicKey)
+			h.AssertError(t, err, "matches the public key")
+		})
+
+		it("errors when the signature names another image", func() {
+			other, err := random.Image(10, 1)
+			h.AssertNil(t, err)
+			otherDigest, err := other.Digest()
+			h.AssertNil(t, err)
+			h.SignImage(t, imageRef, otherDigest, privateKey)
+			// the signature of the other image is at the signature tag of this image
+			sigImage, err := remote.Image(signature.SignatureTag(imageRef.Context(), otherDigest))
+			h.AssertNil(t, err)
+			h.AssertNil(t, remote.Write(signature.SignatureTag(imageRef.Context(), digest), sigImage))
+
+			_, err = signature.Verify(context.TODO(), imageRef, publicKey)
+			h.AssertError(t, err, "matches the public key")
+		})
+	})
+}
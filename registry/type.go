@@ -3,9 +3,11 @@ package registry
 const (
 	TypeGit    = "git"
 	TypeGitHub = "github"
+	TypeOCI    = "oci"
 )
 
 var Types = []string{
 	TypeGit,
 	TypeGitHub,
+	TypeOCI,
 }
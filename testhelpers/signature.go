@@ -0,0 +1,45 @@
+package testhelpers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/buildpacks/pack/pkg/signature"
+)
+
+// SignImage pushes a cosign signature of the image with the given digest, made with key, next to the image
+func SignImage(t *testing.T, ref name.Reference, digest v1.Hash, key *ecdsa.PrivateKey) {
+	t.Helper()
+
+	AssertNil(t, signature.SignDigest(context.TODO(), ref.Context().Digest(digest.String()), key))
+}
+
+// WritePrivateKey writes the PEM encoded key to a file in dir, and returns its path
+func WritePrivateKey(t *testing.T, dir string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	AssertNil(t, err)
+	path := filepath.Join(dir, "cosign.key")
+	AssertNil(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return path
+}
+
+// WritePublicKey writes the PEM encoded public key of key to a file in dir, and returns its path
+func WritePublicKey(t *testing.T, dir string, key *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	AssertNil(t, err)
+	path := filepath.Join(dir, "cosign.pub")
+	AssertNil(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	return path
+}